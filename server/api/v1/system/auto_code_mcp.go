@@ -6,7 +6,9 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/mcp/client"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -49,7 +51,7 @@ func (a *AutoCodeTemplateApi) MCPList(c *gin.Context) {
 
 	baseUrl := fmt.Sprintf("http://127.0.0.1:%d%s", global.GVA_CONFIG.System.Addr, global.GVA_CONFIG.MCP.SSEPath)
 
-	testClient, err := client.NewClient(baseUrl, "testClient", "v1.0.0", global.GVA_CONFIG.MCP.Name)
+	testClient, err := client.NewClient(baseUrl, "testClient", "v1.0.0", global.GVA_CONFIG.MCP.Name, transport.WithHeaders(map[string]string{"x-token": utils.GetToken(c)}))
 	defer testClient.Close()
 	toolsRequest := mcp.ListToolsRequest{}
 
@@ -61,10 +63,18 @@ func (a *AutoCodeTemplateApi) MCPList(c *gin.Context) {
 		return
 	}
 
+	// 启用Streamable HTTP时优先推荐 客户端不支持时仍可使用SSE地址
+	serverUrl := baseUrl
+	if global.GVA_CONFIG.MCP.StreamPath != "" {
+		serverUrl = fmt.Sprintf("http://127.0.0.1:%d%s", global.GVA_CONFIG.System.Addr, global.GVA_CONFIG.MCP.StreamPath)
+	}
 	mcpServerConfig := map[string]interface{}{
 		"mcpServers": map[string]interface{}{
-			global.GVA_CONFIG.MCP.Name: map[string]string{
-				"url": baseUrl,
+			global.GVA_CONFIG.MCP.Name: map[string]interface{}{
+				"url": serverUrl,
+				"headers": map[string]string{
+					"Authorization": "Bearer <个人中心创建的个人访问令牌 工具以该用户的身份与权限调用>",
+				},
 			},
 		},
 	}
@@ -98,7 +108,7 @@ func (a *AutoCodeTemplateApi) MCPTest(c *gin.Context) {
 
 	// 创建MCP客户端
 	baseUrl := fmt.Sprintf("http://127.0.0.1:%d%s", global.GVA_CONFIG.System.Addr, global.GVA_CONFIG.MCP.SSEPath)
-	testClient, err := client.NewClient(baseUrl, "testClient", "v1.0.0", global.GVA_CONFIG.MCP.Name)
+	testClient, err := client.NewClient(baseUrl, "testClient", "v1.0.0", global.GVA_CONFIG.MCP.Name, transport.WithHeaders(map[string]string{"x-token": utils.GetToken(c)}))
 	if err != nil {
 		response.FailWithMessage("创建MCP客户端失败:"+err.Error(), c)
 		return
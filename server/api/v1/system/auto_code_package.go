@@ -98,3 +98,24 @@ func (a *AutoCodePackageApi) Templates(c *gin.Context) {
 	}
 	response.OkWithDetailed(data, "获取成功", c)
 }
+
+// InstallTemplatePack
+// @Tags      AutoCodePackage
+// @Summary   安装外部模板包
+// @Security  ApiKeyAuth
+// @accept    multipart/form-data
+// @Produce   application/json
+// @Param     pack  formData  file                                                       false  "模板包zip压缩包"
+// @Param     path  formData  string                                                     false  "服务器上的模板包目录 未上传压缩包时使用"
+// @Success   200   {object}  response.Response{data=map[string]interface{},msg=string}  "安装模板包成功"
+// @Router    /autoCode/installTemplatePack [post]
+func (a *AutoCodePackageApi) InstallTemplatePack(c *gin.Context) {
+	header, _ := c.FormFile("pack")
+	manifest, err := autoCodePackageService.InstallTemplatePack(c.Request.Context(), header, c.PostForm("path"))
+	if err != nil {
+		global.GVA_LOG.Error("安装失败!", zap.Error(err))
+		response.FailWithMessage("安装失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(gin.H{"manifest": manifest}, "安装成功", c)
+}
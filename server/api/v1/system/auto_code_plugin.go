@@ -5,8 +5,11 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"strconv"
 )
 
 type AutoCodePluginApi struct{}
@@ -17,8 +20,10 @@ type AutoCodePluginApi struct{}
 // @Security  ApiKeyAuth
 // @accept    multipart/form-data
 // @Produce   application/json
-// @Param     plug  formData  file                                              true  "this is a test file"
-// @Success   200   {object}  response.Response{data=[]interface{},msg=string}  "安装插件成功"
+// @Param     plug     formData  file                                                     true   "插件安装包"
+// @Param     preview  formData  bool                                                     false  "只返回将写入的文件与数据 不安装"
+// @Param     authorityId  formData  int                                                  false  "插件菜单与API授权的角色 默认为当前用户角色"
+// @Success   200      {object}  response.Response{data=systemRes.PluginInstall,msg=string}  "安装插件成功"
 // @Router    /autoCode/installPlugin [post]
 func (a *AutoCodePluginApi) Install(c *gin.Context) {
 	header, err := c.FormFile("plug")
@@ -26,28 +31,99 @@ func (a *AutoCodePluginApi) Install(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	web, server, err := autoCodePluginService.Install(header)
-	webStr := "web插件安装成功"
-	serverStr := "server插件安装成功"
-	if web == -1 {
-		webStr = "web端插件未成功安装，请按照文档自行解压安装，如果为纯后端插件请忽略此条提示"
+	preview, _ := strconv.ParseBool(c.PostForm("preview"))
+	authorityId := utils.GetUserAuthorityId(c)
+	if value := c.PostForm("authorityId"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			response.FailWithMessage("角色ID不合法", c)
+			return
+		}
+		authorityId = uint(id)
 	}
-	if server == -1 {
-		serverStr = "server端插件未成功安装，请按照文档自行解压安装，如果为纯前端插件请忽略此条提示"
+	var result systemRes.PluginInstall
+	result, err = autoCodePluginService.Install(c.Request.Context(), header, preview, authorityId)
+	if err != nil {
+		global.GVA_LOG.Error("安装插件失败!", zap.String("plugin", result.Plugin), zap.Error(err))
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if preview {
+		response.OkWithDetailed(result, "校验通过", c)
+		return
 	}
+	response.OkWithDetailed(result, "安装插件成功, 请按照插件文档完成注册后重启服务", c)
+}
+
+// Uninstall
+// @Tags      AutoCodePlugin
+// @Summary   卸载插件
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.UninstallPlugin        true  "插件名称, 是否删除插件的表"
+// @Success   200   {object}  response.Response{msg=string}  "卸载插件成功"
+// @Router    /autoCode/uninstallPlugin [post]
+func (a *AutoCodePluginApi) Uninstall(c *gin.Context) {
+	var info request.UninstallPlugin
+	err := c.ShouldBindJSON(&info)
 	if err != nil {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	response.OkWithData([]interface{}{
-		gin.H{
-			"code": web,
-			"msg":  webStr,
-		},
-		gin.H{
-			"code": server,
-			"msg":  serverStr,
-		}}, c)
+	err = autoCodePluginService.Uninstall(c.Request.Context(), info)
+	if err != nil {
+		global.GVA_LOG.Error("卸载插件失败!", zap.Error(err))
+		response.FailWithMessage("卸载插件失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("卸载插件成功, 请重启服务", c)
+}
+
+// GetPluginList
+// @Tags      AutoCodePlugin
+// @Summary   获取已安装的插件及状态
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200   {object}  response.Response{data=[]system.SysPlugin,msg=string}  "获取成功"
+// @Router    /autoCode/getPluginList [get]
+func (a *AutoCodePluginApi) GetPluginList(c *gin.Context) {
+	list, err := pluginService.GetPluginList(c.Request.Context())
+	if err != nil {
+		global.GVA_LOG.Error("获取插件列表失败!", zap.Error(err))
+		response.FailWithMessage("获取插件列表失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(list, "获取成功", c)
+}
+
+// SetPluginState
+// @Tags      AutoCodePlugin
+// @Summary   启用或停用插件
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.SetPluginState         true  "插件名称, 是否启用"
+// @Success   200   {object}  response.Response{msg=string}  "设置成功"
+// @Router    /autoCode/setPluginState [post]
+func (a *AutoCodePluginApi) SetPluginState(c *gin.Context) {
+	var info request.SetPluginState
+	err := c.ShouldBindJSON(&info)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = pluginService.SetPluginEnabled(c.Request.Context(), info.Name, info.Enabled)
+	if err != nil {
+		global.GVA_LOG.Error("设置插件状态失败!", zap.Error(err))
+		response.FailWithMessage("设置插件状态失败:"+err.Error(), c)
+		return
+	}
+	if info.Enabled {
+		response.OkWithMessage("插件已启用", c)
+		return
+	}
+	response.OkWithMessage("插件已停用", c)
 }
 
 // Packaged
@@ -56,12 +132,23 @@ func (a *AutoCodePluginApi) Install(c *gin.Context) {
 // @Security  ApiKeyAuth
 // @accept    application/json
 // @Produce   application/json
-// @Param     plugName  query    string  true  "插件名称"
+// @Param     plugName  query    string  false  "插件名称"
+// @Param     data  body      request.PubPlug  false  "插件名称, 打包的菜单/API/字典/参数"
 // @Success   200   {object}  response.Response{data=map[string]interface{},msg=string}  "打包插件成功"
 // @Router    /autoCode/pubPlug [post]
 func (a *AutoCodePluginApi) Packaged(c *gin.Context) {
-	plugName := c.Query("plugName")
-	zipPath, err := autoCodePluginService.PubPlug(plugName)
+	var info request.PubPlug
+	if c.Request.ContentLength > 0 {
+		err := c.ShouldBindJSON(&info)
+		if err != nil {
+			response.FailWithMessage(err.Error(), c)
+			return
+		}
+	}
+	if info.PlugName == "" {
+		info.PlugName = c.Query("plugName")
+	}
+	zipPath, err := autoCodePluginService.PubPlug(c.Request.Context(), info)
 	if err != nil {
 		global.GVA_LOG.Error("打包失败!", zap.Error(err))
 		response.FailWithMessage("打包失败"+err.Error(), c)
@@ -13,7 +13,7 @@ type AutoCodeTemplateApi struct{}
 
 // Preview
 // @Tags      AutoCodeTemplate
-// @Summary   预览创建后的代码
+// @Summary   预览创建后的代码, 重新生成(merge)时返回三方合并后的代码和冲突
 // @Security  ApiKeyAuth
 // @accept    application/json
 // @Produce   application/json
@@ -38,12 +38,12 @@ func (a *AutoCodeTemplateApi) Preview(c *gin.Context) {
 		return
 	}
 	info.PackageT = utils.FirstUpper(info.Package)
-	autoCode, err := autoCodeTemplateService.Preview(c.Request.Context(), info)
+	autoCode, conflicts, err := autoCodeTemplateService.Preview(c.Request.Context(), info)
 	if err != nil {
 		global.GVA_LOG.Error(err.Error(), zap.Error(err))
 		response.FailWithMessage("预览失败:"+err.Error(), c)
 	} else {
-		response.OkWithDetailed(gin.H{"autoCode": autoCode}, "预览成功", c)
+		response.OkWithDetailed(gin.H{"autoCode": autoCode, "conflicts": conflicts}, "预览成功", c)
 	}
 }
 
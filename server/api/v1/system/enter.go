@@ -23,6 +23,13 @@ type ApiGroup struct {
 	AutoCodeTemplateApi
 	SysParamsApi
 	ConfigManagerApi
+	AuditChainApi
+	AuditExportApi
+	EntityVersionApi
+	OperationRecordArchiveApi
+	I18nApi
+	FeatureFlagApi
+	AccessTokenApi
 }
 
 var (
@@ -45,5 +52,13 @@ var (
 	autoCodePackageService  = service.ServiceGroupApp.SystemServiceGroup.AutoCodePackage
 	autoCodeHistoryService  = service.ServiceGroupApp.SystemServiceGroup.AutoCodeHistory
 	autoCodeTemplateService = service.ServiceGroupApp.SystemServiceGroup.AutoCodeTemplate
+	auditChainService       = service.ServiceGroupApp.SystemServiceGroup.AuditChainService
+	auditExportService      = service.ServiceGroupApp.SystemServiceGroup.AuditExportService
+	entityVersionService    = service.ServiceGroupApp.SystemServiceGroup.EntityVersionService
+	recordArchiveService    = service.ServiceGroupApp.SystemServiceGroup.OperationRecordArchiveService
+	i18nService             = service.ServiceGroupApp.SystemServiceGroup.I18nService
+	featureFlagService      = service.ServiceGroupApp.SystemServiceGroup.FeatureFlagService
+	pluginService           = service.ServiceGroupApp.SystemServiceGroup.PluginService
+	accessTokenService      = service.ServiceGroupApp.SystemServiceGroup.AccessTokenService
 	// configManagerService 在使用时延迟初始化，避免循环依赖
 )
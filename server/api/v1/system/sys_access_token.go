@@ -0,0 +1,79 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AccessTokenApi struct{}
+
+// CreateAccessToken
+// @Tags      AccessToken
+// @Summary   创建个人访问令牌
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      systemReq.CreateAccessToken                                       true  "令牌名称, 每分钟请求上限, 过期时间"
+// @Success   200   {object}  response.Response{data=systemRes.CreateAccessToken,msg=string}  "创建成功 明文令牌只返回一次"
+// @Router    /accessToken/createAccessToken [post]
+func (a *AccessTokenApi) CreateAccessToken(c *gin.Context) {
+	var info systemReq.CreateAccessToken
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	var result systemRes.CreateAccessToken
+	result, err := accessTokenService.CreateAccessToken(c.Request.Context(), utils.GetUserID(c), info)
+	if err != nil {
+		global.GVA_LOG.Error("创建失败!", zap.Error(err))
+		response.FailWithMessage("创建失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(result, "创建成功, 请立即复制令牌 关闭后将无法再次查看", c)
+}
+
+// GetAccessTokenList
+// @Tags      AccessToken
+// @Summary   获取当前用户的个人访问令牌
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200   {object}  response.Response{data=[]system.SysAccessToken,msg=string}  "获取成功"
+// @Router    /accessToken/getAccessTokenList [get]
+func (a *AccessTokenApi) GetAccessTokenList(c *gin.Context) {
+	list, err := accessTokenService.GetAccessTokenList(utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(list, "获取成功", c)
+}
+
+// DeleteAccessToken
+// @Tags      AccessToken
+// @Summary   吊销个人访问令牌
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.GetById                true  "令牌ID"
+// @Success   200   {object}  response.Response{msg=string}  "吊销成功"
+// @Router    /accessToken/deleteAccessToken [delete]
+func (a *AccessTokenApi) DeleteAccessToken(c *gin.Context) {
+	var info request.GetById
+	if err := c.ShouldBindJSON(&info); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := accessTokenService.DeleteAccessToken(c.Request.Context(), utils.GetUserID(c), info.Uint()); err != nil {
+		global.GVA_LOG.Error("吊销失败!", zap.Error(err))
+		response.FailWithMessage("吊销失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("吊销成功", c)
+}
@@ -36,7 +36,7 @@ func (s *SystemApiApi) CreateApi(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = apiService.CreateApi(api)
+	err = apiService.CreateApi(c.Request.Context(), api)
 	if err != nil {
 		global.GVA_LOG.Error("创建失败!", zap.Error(err))
 		response.FailWithMessage("创建失败", c)
@@ -157,7 +157,7 @@ func (s *SystemApiApi) DeleteApi(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = apiService.DeleteApi(api)
+	err = apiService.DeleteApi(c.Request.Context(), api)
 	if err != nil {
 		global.GVA_LOG.Error("删除失败!", zap.Error(err))
 		response.FailWithMessage("删除失败", c)
@@ -252,7 +252,7 @@ func (s *SystemApiApi) UpdateApi(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = apiService.UpdateApi(api)
+	err = apiService.UpdateApi(c.Request.Context(), api)
 	if err != nil {
 		global.GVA_LOG.Error("修改失败!", zap.Error(err))
 		response.FailWithMessage("修改失败", c)
@@ -296,7 +296,7 @@ func (s *SystemApiApi) DeleteApisByIds(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = apiService.DeleteApisByIds(ids)
+	err = apiService.DeleteApisByIds(c.Request.Context(), ids)
 	if err != nil {
 		global.GVA_LOG.Error("删除失败!", zap.Error(err))
 		response.FailWithMessage("删除失败", c)
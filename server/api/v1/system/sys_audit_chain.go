@@ -0,0 +1,103 @@
+package system
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AuditChainApi struct{}
+
+// GetAuditChainInfo
+// @Tags      AuditChain
+// @Summary   获取参与哈希链的表和检查点签名公钥
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200  {object}  response.Response{data=map[string]interface{},msg=string}  "获取成功"
+// @Router    /auditChain/getAuditChainInfo [get]
+func (a *AuditChainApi) GetAuditChainInfo(c *gin.Context) {
+	response.OkWithDetailed(gin.H{
+		"enable":    global.GVA_CONFIG.AuditChain.Enable,
+		"tables":    auditChainService.Tables(),
+		"publicKey": auditChainService.PublicKey(),
+	}, "获取成功", c)
+}
+
+// VerifyAuditChain
+// @Tags      AuditChain
+// @Summary   校验审计表哈希链 返回第一处断链
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     systemReq.AuditChainSearch                                true  "表名"
+// @Success   200   {object}  response.Response{data=hashchain.VerifyResult,msg=string}  "校验完成"
+// @Router    /auditChain/verify [get]
+func (a *AuditChainApi) VerifyAuditChain(c *gin.Context) {
+	var req systemReq.AuditChainSearch
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	result, err := auditChainService.Verify(req.Table)
+	if err != nil {
+		global.GVA_LOG.Error("校验失败!", zap.Error(err))
+		response.FailWithMessage("校验失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(result, "校验完成", c)
+}
+
+// CreateAuditCheckpoint
+// @Tags      AuditChain
+// @Summary   为审计表当前链尾生成签名检查点
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      systemReq.AuditChainSearch                                     true  "表名"
+// @Success   200   {object}  response.Response{data=system.SysAuditCheckpoint,msg=string}  "创建成功"
+// @Router    /auditChain/createCheckpoint [post]
+func (a *AuditChainApi) CreateAuditCheckpoint(c *gin.Context) {
+	var req systemReq.AuditChainSearch
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	checkpoint, err := auditChainService.CreateCheckpoint(req.Table)
+	if err != nil {
+		global.GVA_LOG.Error("创建检查点失败!", zap.Error(err))
+		response.FailWithMessage("创建检查点失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(checkpoint, "创建成功", c)
+}
+
+// ExportAuditChain
+// @Tags      AuditChain
+// @Summary   导出审计表哈希链归档 用于离线校验
+// @Security  ApiKeyAuth
+// @Produce   application/octet-stream
+// @Param     data  query  systemReq.AuditChainSearch  true  "表名"
+// @Router    /auditChain/export [get]
+func (a *AuditChainApi) ExportAuditChain(c *gin.Context) {
+	var req systemReq.AuditChainSearch
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	name := fmt.Sprintf("%s_chain_%s.jsonl", req.Table, time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Disposition", "attachment; filename="+name)
+	c.Header("Content-Transfer-Encoding", "binary")
+	if err := auditChainService.Export(req.Table, c.Writer); err != nil {
+		global.GVA_LOG.Error("导出失败!", zap.Error(err))
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "application/json")
+			c.Header("Content-Disposition", "")
+			response.FailWithMessage("导出失败:"+err.Error(), c)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AuditExportApi struct{}
+
+// GetAuditExportStatus
+// @Tags      AuditExport
+// @Summary   获取SIEM投递目标及各事件来源的投递游标
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200  {object}  response.Response{data=map[string]interface{},msg=string}  "获取成功"
+// @Router    /auditExport/getAuditExportStatus [get]
+func (a *AuditExportApi) GetAuditExportStatus(c *gin.Context) {
+	cursors, err := auditExportService.GetExportStatus()
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	sinks := make([]gin.H, 0, len(global.GVA_CONFIG.Siem.Sinks))
+	for _, s := range global.GVA_CONFIG.Siem.Sinks {
+		sinks = append(sinks, gin.H{"name": s.Name, "type": s.Type, "format": s.Format, "eventTypes": s.EventTypes})
+	}
+	response.OkWithDetailed(gin.H{
+		"enable":  global.GVA_CONFIG.Siem.Enable,
+		"sinks":   sinks,
+		"cursors": cursors,
+	}, "获取成功", c)
+}
+
+// TriggerAuditExport
+// @Tags      AuditExport
+// @Summary   立即投递一次审计事件到SIEM
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200  {object}  response.Response{msg=string}  "投递完成"
+// @Router    /auditExport/export [post]
+func (a *AuditExportApi) TriggerAuditExport(c *gin.Context) {
+	if err := auditExportService.Export(); err != nil {
+		global.GVA_LOG.Error("投递失败!", zap.Error(err))
+		response.FailWithMessage("投递失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("投递完成", c)
+}
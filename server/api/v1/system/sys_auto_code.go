@@ -106,6 +106,58 @@ func (autoApi *AutoCodeApi) GetColumn(c *gin.Context) {
 	}
 }
 
+// GetRelations
+// @Tags      AutoCode
+// @Summary   根据外键推断表的关联关系
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     businessDB  query     string                                                     false  "业务库"
+// @Param     dbName      query     string                                                     false  "数据库名"
+// @Param     tableName   query     string                                                     true   "表名"
+// @Success   200         {object}  response.Response{data=map[string]interface{},msg=string}  "获取当前表的关联关系"
+// @Router    /autoCode/getRelations [get]
+func (autoApi *AutoCodeApi) GetRelations(c *gin.Context) {
+	businessDB := c.Query("businessDB")
+	dbName := c.Query("dbName")
+	if dbName == "" {
+		dbName = *global.GVA_ACTIVE_DBNAME
+		if businessDB != "" {
+			for _, db := range global.GVA_CONFIG.DBList {
+				if db.AliasName == businessDB {
+					dbName = db.Dbname
+				}
+			}
+		}
+	}
+	tableName := c.Query("tableName")
+	relations, err := autoCodeService.GetRelations(businessDB, dbName, tableName)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+	} else {
+		response.OkWithDetailed(gin.H{"relations": relations}, "获取成功", c)
+	}
+}
+
+// GenerateTypeScript
+// @Tags      AutoCode
+// @Summary   根据swagger文档为全部已注册的api生成TypeScript客户端
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Success   200  {object}  response.Response{data=map[string]interface{},msg=string}  "生成的文件路径、代码以及swagger文档中没有定义而跳过的api"
+// @Router    /autoCode/generateTypeScript [post]
+func (autoApi *AutoCodeApi) GenerateTypeScript(c *gin.Context) {
+	path, code, skipped, err := autoCodeService.GenerateTypeScript(c.Request.Context())
+	if err != nil {
+		global.GVA_LOG.Error("生成失败!", zap.Error(err))
+		response.FailWithMessage("生成失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(gin.H{"path": path, "code": code, "skipped": skipped}, "生成成功", c)
+}
+
 func (autoApi *AutoCodeApi) LLMAuto(c *gin.Context) {
 	var llm common.JSONMap
 	err := c.ShouldBindJSON(&llm)
@@ -4,6 +4,7 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/i18n"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -26,7 +27,7 @@ func (s *DictionaryApi) CreateSysDictionary(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = dictionaryService.CreateSysDictionary(dictionary)
+	err = dictionaryService.CreateSysDictionary(c.Request.Context(), dictionary)
 	if err != nil {
 		global.GVA_LOG.Error("创建失败!", zap.Error(err))
 		response.FailWithMessage("创建失败", c)
@@ -51,7 +52,7 @@ func (s *DictionaryApi) DeleteSysDictionary(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = dictionaryService.DeleteSysDictionary(dictionary)
+	err = dictionaryService.DeleteSysDictionary(c.Request.Context(), dictionary)
 	if err != nil {
 		global.GVA_LOG.Error("删除失败!", zap.Error(err))
 		response.FailWithMessage("删除失败", c)
@@ -76,7 +77,7 @@ func (s *DictionaryApi) UpdateSysDictionary(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = dictionaryService.UpdateSysDictionary(&dictionary)
+	err = dictionaryService.UpdateSysDictionary(c.Request.Context(), &dictionary)
 	if err != nil {
 		global.GVA_LOG.Error("更新失败!", zap.Error(err))
 		response.FailWithMessage("更新失败", c)
@@ -107,6 +108,7 @@ func (s *DictionaryApi) FindSysDictionary(c *gin.Context) {
 		response.FailWithMessage("字典未创建或未开启", c)
 		return
 	}
+	i18nService.TranslateDictionary(i18n.Locale(c), &sysDictionary)
 	response.OkWithDetailed(gin.H{"resysDictionary": sysDictionary}, "查询成功", c)
 }
 
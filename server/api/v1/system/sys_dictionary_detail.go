@@ -6,6 +6,7 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/i18n"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -28,10 +29,10 @@ func (s *DictionaryDetailApi) CreateSysDictionaryDetail(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = dictionaryDetailService.CreateSysDictionaryDetail(detail)
+	err = dictionaryDetailService.CreateSysDictionaryDetail(c.Request.Context(), detail)
 	if err != nil {
 		global.GVA_LOG.Error("创建失败!", zap.Error(err))
-		response.FailWithMessage("创建失败", c)
+		response.FailWithMessage("创建失败:"+err.Error(), c)
 		return
 	}
 	response.OkWithMessage("创建成功", c)
@@ -53,10 +54,10 @@ func (s *DictionaryDetailApi) DeleteSysDictionaryDetail(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = dictionaryDetailService.DeleteSysDictionaryDetail(detail)
+	err = dictionaryDetailService.DeleteSysDictionaryDetail(c.Request.Context(), detail)
 	if err != nil {
 		global.GVA_LOG.Error("删除失败!", zap.Error(err))
-		response.FailWithMessage("删除失败", c)
+		response.FailWithMessage("删除失败:"+err.Error(), c)
 		return
 	}
 	response.OkWithMessage("删除成功", c)
@@ -78,7 +79,7 @@ func (s *DictionaryDetailApi) UpdateSysDictionaryDetail(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = dictionaryDetailService.UpdateSysDictionaryDetail(&detail)
+	err = dictionaryDetailService.UpdateSysDictionaryDetail(c.Request.Context(), &detail)
 	if err != nil {
 		global.GVA_LOG.Error("更新失败!", zap.Error(err))
 		response.FailWithMessage("更新失败", c)
@@ -146,3 +147,147 @@ func (s *DictionaryDetailApi) GetSysDictionaryDetailList(c *gin.Context) {
 		PageSize: pageInfo.PageSize,
 	}, "获取成功", c)
 }
+
+// GetDictionaryTreeList
+// @Tags      SysDictionaryDetail
+// @Summary   按字典ID获取树形字典详情
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     sysDictionaryID  query     int                                                       true  "字典ID"
+// @Success   200              {object}  response.Response{data=[]system.SysDictionaryDetail,msg=string}  "获取成功"
+// @Router    /sysDictionaryDetail/getDictionaryTreeList [get]
+func (s *DictionaryDetailApi) GetDictionaryTreeList(c *gin.Context) {
+	var req request.SysDictionaryDetailChildren
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, err := dictionaryDetailService.GetDictionaryTreeList(req.SysDictionaryID)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(list, "获取成功", c)
+}
+
+// GetDictionaryTreeListByType
+// @Tags      SysDictionaryDetail
+// @Summary   按字典type获取树形字典详情
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     type  query     string                                                    true  "字典type"
+// @Success   200   {object}  response.Response{data=[]system.SysDictionaryDetail,msg=string}  "获取成功"
+// @Router    /sysDictionaryDetail/getDictionaryTreeListByType [get]
+func (s *DictionaryDetailApi) GetDictionaryTreeListByType(c *gin.Context) {
+	t := c.Query("type")
+	if t == "" {
+		response.FailWithMessage("参数错误", c)
+		return
+	}
+	list, err := dictionaryDetailService.GetDictionaryTreeListByType(t)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	i18nService.TranslateDictionaryDetails(i18n.Locale(c), t, list)
+	response.OkWithDetailed(list, "获取成功", c)
+}
+
+// GetDictionaryDetailsByParent
+// @Tags      SysDictionaryDetail
+// @Summary   获取某个父级下的直接子级
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  query     request.SysDictionaryDetailChildren                       true  "字典ID, 父级ID"
+// @Success   200   {object}  response.Response{data=[]system.SysDictionaryDetail,msg=string}  "获取成功"
+// @Router    /sysDictionaryDetail/getDictionaryDetailsByParent [get]
+func (s *DictionaryDetailApi) GetDictionaryDetailsByParent(c *gin.Context) {
+	var req request.SysDictionaryDetailChildren
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, err := dictionaryDetailService.GetDictionaryDetailsByParent(req)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(list, "获取成功", c)
+}
+
+// GetDictionaryPath
+// @Tags      SysDictionaryDetail
+// @Summary   按字典type和字典值获取从顶层到该详情的路径
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  query     request.SysDictionaryDetailPath                           true  "字典type, 字典值"
+// @Success   200   {object}  response.Response{data=[]system.SysDictionaryDetail,msg=string}  "获取成功"
+// @Router    /sysDictionaryDetail/getDictionaryPath [get]
+func (s *DictionaryDetailApi) GetDictionaryPath(c *gin.Context) {
+	var req request.SysDictionaryDetailPath
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	path, err := dictionaryDetailService.GetDictionaryPathByTypeValue(req.Type, req.Value)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	i18nService.TranslateDictionaryDetails(i18n.Locale(c), req.Type, path)
+	response.OkWithDetailed(path, "获取成功", c)
+}
+
+// MoveSysDictionaryDetail
+// @Tags      SysDictionaryDetail
+// @Summary   移动字典详情到新的父级和位置
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.SysDictionaryDetailMove  true  "字典详情ID, 新的父级ID, 位置"
+// @Success   200   {object}  response.Response{msg=string}    "移动成功"
+// @Router    /sysDictionaryDetail/moveSysDictionaryDetail [put]
+func (s *DictionaryDetailApi) MoveSysDictionaryDetail(c *gin.Context) {
+	var req request.SysDictionaryDetailMove
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := dictionaryDetailService.MoveSysDictionaryDetail(c.Request.Context(), req); err != nil {
+		global.GVA_LOG.Error("移动失败!", zap.Error(err))
+		response.FailWithMessage("移动失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("移动成功", c)
+}
+
+// SortSysDictionaryDetail
+// @Tags      SysDictionaryDetail
+// @Summary   调整同级字典详情的顺序
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.SysDictionaryDetailSort  true  "字典ID, 父级ID, 按新顺序排列的ID"
+// @Success   200   {object}  response.Response{msg=string}    "排序成功"
+// @Router    /sysDictionaryDetail/sortSysDictionaryDetail [put]
+func (s *DictionaryDetailApi) SortSysDictionaryDetail(c *gin.Context) {
+	var req request.SysDictionaryDetailSort
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := dictionaryDetailService.SortSysDictionaryDetail(c.Request.Context(), req); err != nil {
+		global.GVA_LOG.Error("排序失败!", zap.Error(err))
+		response.FailWithMessage("排序失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("排序成功", c)
+}
@@ -0,0 +1,97 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type EntityVersionApi struct{}
+
+// GetVersionedTables
+// @Tags      EntityVersion
+// @Summary   获取已开启变更历史的表
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200  {object}  response.Response{data=[]string,msg=string}  "获取成功"
+// @Router    /entityVersion/getVersionedTables [get]
+func (a *EntityVersionApi) GetVersionedTables(c *gin.Context) {
+	response.OkWithDetailed(entityVersionService.Tables(), "获取成功", c)
+}
+
+// GetEntityVersionList
+// @Tags      EntityVersion
+// @Summary   分页获取实体变更历史
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     systemReq.EntityVersionSearch                        true  "表名, 主键, 页码, 每页大小"
+// @Success   200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router    /entityVersion/getEntityVersionList [get]
+func (a *EntityVersionApi) GetEntityVersionList(c *gin.Context) {
+	var pageInfo systemReq.EntityVersionSearch
+	if err := c.ShouldBindQuery(&pageInfo); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := entityVersionService.GetEntityVersionList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// DiffEntityVersion
+// @Tags      EntityVersion
+// @Summary   比较实体两个版本的字段差异
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     systemReq.EntityVersionDiff                                     true  "表名, 主键, 起始版本, 目标版本"
+// @Success   200   {object}  response.Response{data=[]versioning.FieldChange,msg=string}  "获取成功"
+// @Router    /entityVersion/diff [get]
+func (a *EntityVersionApi) DiffEntityVersion(c *gin.Context) {
+	var req systemReq.EntityVersionDiff
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	changes, err := entityVersionService.DiffEntityVersion(req)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(changes, "获取成功", c)
+}
+
+// RestoreEntityVersion
+// @Tags      EntityVersion
+// @Summary   将实体恢复到指定版本
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.GetById                true  "版本记录ID"
+// @Success   200   {object}  response.Response{msg=string}  "恢复成功"
+// @Router    /entityVersion/restore [post]
+func (a *EntityVersionApi) RestoreEntityVersion(c *gin.Context) {
+	var req request.GetById
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := entityVersionService.RestoreEntityVersion(c.Request.Context(), req.Uint()); err != nil {
+		global.GVA_LOG.Error("恢复失败!", zap.Error(err))
+		response.FailWithMessage("恢复失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("恢复成功", c)
+}
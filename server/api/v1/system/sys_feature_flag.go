@@ -0,0 +1,179 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type FeatureFlagApi struct{}
+
+// CreateFeatureFlag
+// @Tags      FeatureFlag
+// @Summary   创建功能开关
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      system.SysFeatureFlag          true  "开关键, 名称, 类型, 总开关, 取值, 定向规则"
+// @Success   200   {object}  response.Response{msg=string}  "创建成功"
+// @Router    /featureFlag/createFeatureFlag [post]
+func (a *FeatureFlagApi) CreateFeatureFlag(c *gin.Context) {
+	var flag system.SysFeatureFlag
+	if err := c.ShouldBindJSON(&flag); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := featureFlagService.CreateFeatureFlag(c.Request.Context(), &flag); err != nil {
+		global.GVA_LOG.Error("创建失败!", zap.Error(err))
+		response.FailWithMessage("创建失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("创建成功", c)
+}
+
+// UpdateFeatureFlag
+// @Tags      FeatureFlag
+// @Summary   更新功能开关
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      system.SysFeatureFlag          true  "ID, 开关键, 名称, 类型, 总开关, 取值, 定向规则"
+// @Success   200   {object}  response.Response{msg=string}  "更新成功"
+// @Router    /featureFlag/updateFeatureFlag [put]
+func (a *FeatureFlagApi) UpdateFeatureFlag(c *gin.Context) {
+	var flag system.SysFeatureFlag
+	if err := c.ShouldBindJSON(&flag); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := featureFlagService.UpdateFeatureFlag(c.Request.Context(), flag); err != nil {
+		global.GVA_LOG.Error("更新失败!", zap.Error(err))
+		response.FailWithMessage("更新失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("更新成功", c)
+}
+
+// DeleteFeatureFlag
+// @Tags      FeatureFlag
+// @Summary   删除功能开关
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.IdsReq                 true  "ID列表"
+// @Success   200   {object}  response.Response{msg=string}  "删除成功"
+// @Router    /featureFlag/deleteFeatureFlag [delete]
+func (a *FeatureFlagApi) DeleteFeatureFlag(c *gin.Context) {
+	var ids request.IdsReq
+	if err := c.ShouldBindJSON(&ids); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := featureFlagService.DeleteFeatureFlag(c.Request.Context(), ids.Ids); err != nil {
+		global.GVA_LOG.Error("删除失败!", zap.Error(err))
+		response.FailWithMessage("删除失败", c)
+		return
+	}
+	response.OkWithMessage("删除成功", c)
+}
+
+// FindFeatureFlag
+// @Tags      FeatureFlag
+// @Summary   用id查询功能开关
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     request.GetById                                         true  "主键ID"
+// @Success   200   {object}  response.Response{data=system.SysFeatureFlag,msg=string}  "查询成功"
+// @Router    /featureFlag/findFeatureFlag [get]
+func (a *FeatureFlagApi) FindFeatureFlag(c *gin.Context) {
+	var idInfo request.GetById
+	if err := c.ShouldBindQuery(&idInfo); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	flag, err := featureFlagService.GetFeatureFlag(idInfo.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("查询失败!", zap.Error(err))
+		response.FailWithMessage("查询失败", c)
+		return
+	}
+	response.OkWithData(flag, c)
+}
+
+// GetFeatureFlagList
+// @Tags      FeatureFlag
+// @Summary   分页获取功能开关
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     systemReq.SysFeatureFlagSearch                          true  "开关键, 总开关, 页码, 每页大小"
+// @Success   200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router    /featureFlag/getFeatureFlagList [get]
+func (a *FeatureFlagApi) GetFeatureFlagList(c *gin.Context) {
+	var pageInfo systemReq.SysFeatureFlagSearch
+	if err := c.ShouldBindQuery(&pageInfo); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := featureFlagService.GetFeatureFlagList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// GetFeatureFlagAuditList
+// @Tags      FeatureFlag
+// @Summary   分页获取功能开关变更审计
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     systemReq.SysFeatureFlagAuditSearch                     true  "开关键, 页码, 每页大小"
+// @Success   200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router    /featureFlag/getFeatureFlagAuditList [get]
+func (a *FeatureFlagApi) GetFeatureFlagAuditList(c *gin.Context) {
+	var pageInfo systemReq.SysFeatureFlagAuditSearch
+	if err := c.ShouldBindQuery(&pageInfo); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := featureFlagService.GetFeatureFlagAuditList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// GetEvaluatedFlags
+// @Tags      FeatureFlag
+// @Summary   获取当前用户的功能开关求值结果
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200  {object}  response.Response{data=[]featureflag.Result,msg=string}  "获取成功"
+// @Router    /featureFlag/getEvaluatedFlags [get]
+func (a *FeatureFlagApi) GetEvaluatedFlags(c *gin.Context) {
+	results, err := featureFlagService.EvaluateAll(utils.FeatureSubject(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(results, "获取成功", c)
+}
@@ -0,0 +1,189 @@
+package system
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/i18n"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type I18nApi struct{}
+
+// GetLocales
+// @Tags      SysTranslation
+// @Summary   获取可用语言和当前请求语言
+// @Produce   application/json
+// @Success   200  {object}  response.Response{data=map[string]interface{},msg=string}  "获取成功"
+// @Router    /sysTranslation/getLocales [get]
+func (a *I18nApi) GetLocales(c *gin.Context) {
+	response.OkWithDetailed(gin.H{
+		"locales": i18n.Supported(),
+		"default": i18n.DefaultLocale(),
+		"current": i18n.Locale(c),
+	}, "获取成功", c)
+}
+
+// CreateTranslation
+// @Tags      SysTranslation
+// @Summary   创建翻译
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      system.SysTranslation          true  "语言, 命名空间, 翻译键, 译文"
+// @Success   200   {object}  response.Response{msg=string}  "创建成功"
+// @Router    /sysTranslation/createTranslation [post]
+func (a *I18nApi) CreateTranslation(c *gin.Context) {
+	var t system.SysTranslation
+	if err := c.ShouldBindJSON(&t); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := i18nService.CreateTranslation(c.Request.Context(), &t); err != nil {
+		global.GVA_LOG.Error("创建失败!", zap.Error(err))
+		response.FailWithMessage("创建失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("创建成功", c)
+}
+
+// UpdateTranslation
+// @Tags      SysTranslation
+// @Summary   更新翻译
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      system.SysTranslation          true  "ID, 语言, 命名空间, 翻译键, 译文"
+// @Success   200   {object}  response.Response{msg=string}  "更新成功"
+// @Router    /sysTranslation/updateTranslation [put]
+func (a *I18nApi) UpdateTranslation(c *gin.Context) {
+	var t system.SysTranslation
+	if err := c.ShouldBindJSON(&t); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := i18nService.UpdateTranslation(c.Request.Context(), t); err != nil {
+		global.GVA_LOG.Error("更新失败!", zap.Error(err))
+		response.FailWithMessage("更新失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("更新成功", c)
+}
+
+// DeleteTranslation
+// @Tags      SysTranslation
+// @Summary   删除翻译
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.IdsReq                 true  "ID列表"
+// @Success   200   {object}  response.Response{msg=string}  "删除成功"
+// @Router    /sysTranslation/deleteTranslation [delete]
+func (a *I18nApi) DeleteTranslation(c *gin.Context) {
+	var ids request.IdsReq
+	if err := c.ShouldBindJSON(&ids); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err := i18nService.DeleteTranslation(c.Request.Context(), ids.Ids); err != nil {
+		global.GVA_LOG.Error("删除失败!", zap.Error(err))
+		response.FailWithMessage("删除失败", c)
+		return
+	}
+	response.OkWithMessage("删除成功", c)
+}
+
+// GetTranslationList
+// @Tags      SysTranslation
+// @Summary   分页获取翻译
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     systemReq.SysTranslationSearch                          true  "语言, 命名空间, 翻译键, 页码, 每页大小"
+// @Success   200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router    /sysTranslation/getTranslationList [get]
+func (a *I18nApi) GetTranslationList(c *gin.Context) {
+	var pageInfo systemReq.SysTranslationSearch
+	if err := c.ShouldBindQuery(&pageInfo); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := i18nService.GetTranslationList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// ImportTranslations
+// @Tags      SysTranslation
+// @Summary   导入翻译文件
+// @Security  ApiKeyAuth
+// @accept    multipart/form-data
+// @Produce   application/json
+// @Param     file  formData  file                                                  true  "翻译文件 {locale, translations:{命名空间:{键:译文}}}"
+// @Success   200   {object}  response.Response{data=map[string]int,msg=string}  "导入成功"
+// @Router    /sysTranslation/importTranslations [post]
+func (a *I18nApi) ImportTranslations(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		global.GVA_LOG.Error("接收文件失败!", zap.Error(err))
+		response.FailWithMessage("接收文件失败", c)
+		return
+	}
+	f, err := header.Open()
+	if err != nil {
+		response.FailWithMessage("接收文件失败", c)
+		return
+	}
+	defer f.Close()
+	var file systemReq.TranslationFile
+	if err = json.NewDecoder(f).Decode(&file); err != nil {
+		response.FailWithMessage("翻译文件格式错误:"+err.Error(), c)
+		return
+	}
+	count, err := i18nService.ImportTranslations(c.Request.Context(), file)
+	if err != nil {
+		global.GVA_LOG.Error("导入失败!", zap.Error(err))
+		response.FailWithMessage("导入失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(gin.H{"count": count}, "导入成功", c)
+}
+
+// ExportTranslations
+// @Tags      SysTranslation
+// @Summary   导出翻译文件
+// @Security  ApiKeyAuth
+// @Produce   application/octet-stream
+// @Param     locale  query     string  true  "语言"
+// @Success   200     {file}    file    "翻译文件"
+// @Router    /sysTranslation/exportTranslations [get]
+func (a *I18nApi) ExportTranslations(c *gin.Context) {
+	file, err := i18nService.ExportTranslations(c.Query("locale"))
+	if err != nil {
+		global.GVA_LOG.Error("导出失败!", zap.Error(err))
+		response.FailWithMessage("导出失败:"+err.Error(), c)
+		return
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		response.FailWithMessage("导出失败", c)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+file.Locale+".json")
+	c.Header("success", "true")
+	c.Data(http.StatusOK, "application/json", data)
+}
@@ -8,6 +8,7 @@ import (
 	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
 	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/i18n"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -33,6 +34,7 @@ func (a *AuthorityMenuApi) GetMenu(c *gin.Context) {
 	if menus == nil {
 		menus = []system.SysMenu{}
 	}
+	i18nService.TranslateMenus(i18n.Locale(c), menus)
 	response.OkWithDetailed(systemRes.SysMenusResponse{Menus: menus}, "获取成功", c)
 }
 
@@ -52,6 +54,7 @@ func (a *AuthorityMenuApi) GetBaseMenuTree(c *gin.Context) {
 		response.FailWithMessage("获取失败", c)
 		return
 	}
+	i18nService.TranslateBaseMenus(i18n.Locale(c), menus)
 	response.OkWithDetailed(systemRes.SysBaseMenusResponse{Menus: menus}, "获取成功", c)
 }
 
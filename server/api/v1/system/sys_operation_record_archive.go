@@ -0,0 +1,109 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type OperationRecordArchiveApi struct{}
+
+// GetArchiveList
+// @Tags      SysOperationRecordArchive
+// @Summary   分页获取操作记录归档列表
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     request.PageInfo                                        true  "页码, 每页大小"
+// @Success   200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router    /sysOperationRecordArchive/getArchiveList [get]
+func (a *OperationRecordArchiveApi) GetArchiveList(c *gin.Context) {
+	var pageInfo request.PageInfo
+	if err := c.ShouldBindQuery(&pageInfo); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := recordArchiveService.GetArchiveList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败", c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// QueryArchive
+// @Tags      SysOperationRecordArchive
+// @Summary   按条件查询归档文件中的操作记录
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     systemReq.OperationRecordArchiveQuery                   true  "归档ID, 查询条件, 页码, 每页大小"
+// @Success   200   {object}  response.Response{data=response.PageResult,msg=string}  "查询成功"
+// @Router    /sysOperationRecordArchive/queryArchive [get]
+func (a *OperationRecordArchiveApi) QueryArchive(c *gin.Context) {
+	var req systemReq.OperationRecordArchiveQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := recordArchiveService.QueryArchive(req)
+	if err != nil {
+		global.GVA_LOG.Error("查询失败!", zap.Error(err))
+		response.FailWithMessage("查询失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, "查询成功", c)
+}
+
+// RestoreArchive
+// @Tags      SysOperationRecordArchive
+// @Summary   将归档中的操作记录恢复到数据库
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      request.GetById                                         true  "归档ID"
+// @Success   200   {object}  response.Response{data=map[string]interface{},msg=string}  "恢复成功"
+// @Router    /sysOperationRecordArchive/restoreArchive [post]
+func (a *OperationRecordArchiveApi) RestoreArchive(c *gin.Context) {
+	var req request.GetById
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	restored, err := recordArchiveService.RestoreArchive(req.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("恢复失败!", zap.Error(err))
+		response.FailWithMessage("恢复失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(gin.H{"restored": restored}, "恢复成功", c)
+}
+
+// ApplyRetention
+// @Tags      SysOperationRecordArchive
+// @Summary   立即执行操作记录保留策略
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200  {object}  response.Response{data=map[string]interface{},msg=string}  "执行成功"
+// @Router    /sysOperationRecordArchive/applyRetention [post]
+func (a *OperationRecordArchiveApi) ApplyRetention(c *gin.Context) {
+	archived, deleted, err := recordArchiveService.ApplyRetention()
+	if err != nil {
+		global.GVA_LOG.Error("执行失败!", zap.Error(err))
+		response.FailWithMessage("执行失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(gin.H{"archived": archived, "deleted": deleted}, "执行成功", c)
+}
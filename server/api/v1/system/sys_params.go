@@ -5,6 +5,7 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/i18n"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -27,7 +28,7 @@ func (sysParamsApi *SysParamsApi) CreateSysParams(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = sysParamsService.CreateSysParams(&sysParams)
+	err = sysParamsService.CreateSysParams(c.Request.Context(), &sysParams)
 	if err != nil {
 		global.GVA_LOG.Error("创建失败!", zap.Error(err))
 		response.FailWithMessage("创建失败:"+err.Error(), c)
@@ -47,7 +48,7 @@ func (sysParamsApi *SysParamsApi) CreateSysParams(c *gin.Context) {
 // @Router /sysParams/deleteSysParams [delete]
 func (sysParamsApi *SysParamsApi) DeleteSysParams(c *gin.Context) {
 	ID := c.Query("ID")
-	err := sysParamsService.DeleteSysParams(ID)
+	err := sysParamsService.DeleteSysParams(c.Request.Context(), ID)
 	if err != nil {
 		global.GVA_LOG.Error("删除失败!", zap.Error(err))
 		response.FailWithMessage("删除失败:"+err.Error(), c)
@@ -66,7 +67,7 @@ func (sysParamsApi *SysParamsApi) DeleteSysParams(c *gin.Context) {
 // @Router /sysParams/deleteSysParamsByIds [delete]
 func (sysParamsApi *SysParamsApi) DeleteSysParamsByIds(c *gin.Context) {
 	IDs := c.QueryArray("IDs[]")
-	err := sysParamsService.DeleteSysParamsByIds(IDs)
+	err := sysParamsService.DeleteSysParamsByIds(c.Request.Context(), IDs)
 	if err != nil {
 		global.GVA_LOG.Error("批量删除失败!", zap.Error(err))
 		response.FailWithMessage("批量删除失败:"+err.Error(), c)
@@ -81,17 +82,17 @@ func (sysParamsApi *SysParamsApi) DeleteSysParamsByIds(c *gin.Context) {
 // @Security ApiKeyAuth
 // @accept application/json
 // @Produce application/json
-// @Param data body system.SysParams true "更新参数"
+// @Param data body systemReq.SysParamsUpdate true "更新参数"
 // @Success 200 {object} response.Response{msg=string} "更新成功"
 // @Router /sysParams/updateSysParams [put]
 func (sysParamsApi *SysParamsApi) UpdateSysParams(c *gin.Context) {
-	var sysParams system.SysParams
+	var sysParams systemReq.SysParamsUpdate
 	err := c.ShouldBindJSON(&sysParams)
 	if err != nil {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
-	err = sysParamsService.UpdateSysParams(sysParams)
+	err = sysParamsService.UpdateSysParams(c.Request.Context(), sysParams)
 	if err != nil {
 		global.GVA_LOG.Error("更新失败!", zap.Error(err))
 		response.FailWithMessage("更新失败:"+err.Error(), c)
@@ -167,5 +168,36 @@ func (sysParamsApi *SysParamsApi) GetSysParam(c *gin.Context) {
 		response.FailWithMessage("获取失败:"+err.Error(), c)
 		return
 	}
+	i18nService.TranslateParam(i18n.Locale(c), &params)
 	response.OkWithDetailed(params, "获取成功", c)
 }
+
+// GetSysParamsHistory 分页获取参数变更历史 数据来自实体变更历史
+// @Tags SysParams
+// @Summary 分页获取参数变更历史
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data query systemReq.SysParamsHistorySearch true "参数键, 页码, 每页大小"
+// @Success 200 {object} response.Response{data=response.PageResult,msg=string} "获取成功"
+// @Router /sysParams/getSysParamsHistory [get]
+func (sysParamsApi *SysParamsApi) GetSysParamsHistory(c *gin.Context) {
+	var pageInfo systemReq.SysParamsHistorySearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := sysParamsService.GetSysParamsHistory(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
@@ -0,0 +1,9 @@
+package config
+
+// AuditChain 审计表防篡改哈希链配置
+type AuditChain struct {
+	Enable         bool   `mapstructure:"enable" json:"enable" yaml:"enable"`                            // 是否开启哈希链
+	CheckpointSpec string `mapstructure:"checkpoint-spec" json:"checkpoint-spec" yaml:"checkpoint-spec"` // 签名检查点生成周期 cron表达式
+	SigningKey     string `mapstructure:"signing-key" json:"signing-key" yaml:"signing-key"`             // ed25519 私钥种子(base64, 32字节) 未配置时不生成检查点
+	PublicKey      string `mapstructure:"public-key" json:"public-key" yaml:"public-key"`                // 受信任的检查点签名公钥(base64) 供 audit-verify 离线校验
+}
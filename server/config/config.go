@@ -11,6 +11,8 @@ type Server struct {
 	Captcha   Captcha `mapstructure:"captcha" json:"captcha" yaml:"captcha"`
 	// auto
 	AutoCode Autocode `mapstructure:"autocode" json:"autocode" yaml:"autocode"`
+	// 插件安装包校验
+	PluginPackage PluginPackage `mapstructure:"plugin-package" json:"plugin-package" yaml:"plugin-package"`
 	// gorm
 	Mysql  Mysql           `mapstructure:"mysql" json:"mysql" yaml:"mysql"`
 	Mssql  Mssql           `mapstructure:"mssql" json:"mssql" yaml:"mssql"`
@@ -38,6 +40,18 @@ type Server struct {
 	// MCP配置
 	MCP MCP `mapstructure:"mcp" json:"mcp" yaml:"mcp"`
 
+	// 操作记录写入配置
+	OperationRecord OperationRecord `mapstructure:"operation-record" json:"operation-record" yaml:"operation-record"`
+
+	// 审计防篡改配置
+	AuditChain AuditChain `mapstructure:"audit-chain" json:"audit-chain" yaml:"audit-chain"`
+
+	// SIEM 导出配置
+	Siem Siem `mapstructure:"siem" json:"siem" yaml:"siem"`
+
+	// 多语言配置
+	I18n I18n `mapstructure:"i18n" json:"i18n" yaml:"i18n"`
+
 	// NFC Relay 配置
 	NfcRelay NfcRelay `mapstructure:"nfc-relay" json:"nfc-relay" yaml:"nfc-relay"`
 }
@@ -0,0 +1,7 @@
+package config
+
+// I18n 多语言配置
+type I18n struct {
+	Default string   `mapstructure:"default" json:"default" yaml:"default"` // 默认语言 无法从用户配置和 Accept-Language 确定时使用
+	Locales []string `mapstructure:"locales" json:"locales" yaml:"locales"` // 可用语言
+}
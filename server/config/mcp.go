@@ -5,5 +5,7 @@ type MCP struct {
 	Version     string `mapstructure:"version" json:"version" yaml:"version"`                // MCP版本
 	SSEPath     string `mapstructure:"sse_path" json:"sse_path" yaml:"sse_path"`             // SSE路径
 	MessagePath string `mapstructure:"message_path" json:"message_path" yaml:"message_path"` // 消息路径
+	StreamPath  string `mapstructure:"stream_path" json:"stream_path" yaml:"stream_path"`    // Streamable HTTP路径 为空时不启用
 	UrlPrefix   string `mapstructure:"url_prefix" json:"url_prefix" yaml:"url_prefix"`       // URL前缀
+	RateLimit   int    `mapstructure:"rate_limit" json:"rate_limit" yaml:"rate_limit"`       // 每个令牌每分钟的请求上限 个人访问令牌可单独设置 0不限制
 }
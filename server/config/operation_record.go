@@ -0,0 +1,34 @@
+package config
+
+// OperationRecord 操作记录写入配置
+type OperationRecord struct {
+	Async          bool   `mapstructure:"async" json:"async" yaml:"async"`                               // 是否异步批量写入
+	QueueSize      int    `mapstructure:"queue-size" json:"queue-size" yaml:"queue-size"`                // 队列容量
+	BatchSize      int    `mapstructure:"batch-size" json:"batch-size" yaml:"batch-size"`                // 单批写入条数
+	FlushInterval  string `mapstructure:"flush-interval" json:"flush-interval" yaml:"flush-interval"`    // 最长刷写间隔 如 1s
+	OverflowPolicy string `mapstructure:"overflow-policy" json:"overflow-policy" yaml:"overflow-policy"` // 队列满时策略 block|drop-oldest|spill
+	SpillPath      string `mapstructure:"spill-path" json:"spill-path" yaml:"spill-path"`                // 溢出文件路径
+	CloseTimeout   string `mapstructure:"close-timeout" json:"close-timeout" yaml:"close-timeout"`       // 停机时刷写剩余记录的最长时间 如 10s
+
+	Retention OperationRecordRetention `mapstructure:"retention" json:"retention" yaml:"retention"` // 保留与归档策略
+}
+
+// OperationRecordRetention 操作记录保留策略 过期记录先归档再删除
+type OperationRecordRetention struct {
+	Enable      bool                           `mapstructure:"enable" json:"enable" yaml:"enable"`                   // 是否开启 开启后 ClearDB 任务不再清理操作记录
+	Spec        string                         `mapstructure:"spec" json:"spec" yaml:"spec"`                         // 执行周期 cron表达式
+	Keep        string                         `mapstructure:"keep" json:"keep" yaml:"keep"`                         // 默认保留时长 如 90d
+	Archive     bool                           `mapstructure:"archive" json:"archive" yaml:"archive"`                // 删除前是否归档
+	Storage     string                         `mapstructure:"storage" json:"storage" yaml:"storage"`                // 归档存储 local|oss oss 使用 system.oss-type 配置的存储
+	ArchivePath string                         `mapstructure:"archive-path" json:"archive-path" yaml:"archive-path"` // 本地归档目录
+	BatchSize   int                            `mapstructure:"batch-size" json:"batch-size" yaml:"batch-size"`       // 单个归档文件最多条数
+	RestoreHold string                         `mapstructure:"restore-hold" json:"restore-hold" yaml:"restore-hold"` // 恢复的归档在库中保留时长
+	Rules       []OperationRecordRetentionRule `mapstructure:"rules" json:"rules" yaml:"rules"`                      // 按路径/方法的保留规则 按顺序匹配第一条 开启审计哈希链时不可配置
+}
+
+// OperationRecordRetentionRule 保留规则
+type OperationRecordRetentionRule struct {
+	Path   string `mapstructure:"path" json:"path" yaml:"path"`       // 请求路径 支持通配符 如 /sysParams/*
+	Method string `mapstructure:"method" json:"method" yaml:"method"` // 请求方法 为空匹配全部
+	Keep   string `mapstructure:"keep" json:"keep" yaml:"keep"`       // 保留时长 如 7d
+}
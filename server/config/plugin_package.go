@@ -0,0 +1,10 @@
+package config
+
+// PluginPackage 插件安装包校验配置
+type PluginPackage struct {
+	RequireSignature bool     `mapstructure:"require-signature" json:"require-signature" yaml:"require-signature"` // 是否只允许安装已签名的插件 配置了受信任公钥时始终校验签名
+	TrustedKeys      []string `mapstructure:"trusted-keys" json:"trusted-keys" yaml:"trusted-keys"`                // 受信任的签名公钥(base64 ed25519)
+	SigningKey       string   `mapstructure:"signing-key" json:"signing-key" yaml:"signing-key"`                   // 打包插件时使用的签名私钥种子(base64, 32字节) 为空时不签名
+	MaxSize          int64    `mapstructure:"max-size" json:"max-size" yaml:"max-size"`                            // 解压后总大小上限(MB) 0为默认50MB
+	MaxFiles         int      `mapstructure:"max-files" json:"max-files" yaml:"max-files"`                         // 文件数量上限 0为默认2000
+}
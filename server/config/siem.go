@@ -0,0 +1,25 @@
+package config
+
+// Siem 审计事件导出到 SIEM 的配置
+type Siem struct {
+	Enable    bool       `mapstructure:"enable" json:"enable" yaml:"enable"`             // 是否开启导出
+	Spec      string     `mapstructure:"spec" json:"spec" yaml:"spec"`                   // 拉取周期 cron表达式 如 @every 10s
+	BatchSize int        `mapstructure:"batch-size" json:"batch-size" yaml:"batch-size"` // 单次每个来源最多拉取条数
+	Settle    string     `mapstructure:"settle" json:"settle" yaml:"settle"`             // 只投递创建于该时长之前的记录 需大于最长的写入事务时长 如 5s
+	Sinks     []SiemSink `mapstructure:"sinks" json:"sinks" yaml:"sinks"`                // 投递目标
+}
+
+// SiemSink 单个投递目标
+type SiemSink struct {
+	Name       string            `mapstructure:"name" json:"name" yaml:"name"`                      // 名称 唯一 用于持久化游标
+	Type       string            `mapstructure:"type" json:"type" yaml:"type"`                      // tcp|udp|http|file
+	Format     string            `mapstructure:"format" json:"format" yaml:"format"`                // cef|syslog|jsonl
+	Address    string            `mapstructure:"address" json:"address" yaml:"address"`             // tcp/udp 地址 host:port
+	URL        string            `mapstructure:"url" json:"url" yaml:"url"`                         // http 批量投递地址
+	Headers    map[string]string `mapstructure:"headers" json:"headers" yaml:"headers"`             // http 额外请求头
+	Path       string            `mapstructure:"path" json:"path" yaml:"path"`                      // 文件路径
+	MaxSize    int               `mapstructure:"max-size" json:"max-size" yaml:"max-size"`          // 单文件最大MB 超出后轮转
+	MaxBackups int               `mapstructure:"max-backups" json:"max-backups" yaml:"max-backups"` // 保留的轮转文件数
+	Timeout    string            `mapstructure:"timeout" json:"timeout" yaml:"timeout"`             // 网络超时 如 10s
+	EventTypes []string          `mapstructure:"event-types" json:"event-types" yaml:"event-types"` // 事件类型过滤 支持通配符 如 operation.* nfc.*
+}
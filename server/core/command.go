@@ -0,0 +1,100 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/core/internal"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/hashchain"
+	"github.com/spf13/viper"
+)
+
+// commands 服务端二进制支持的子命令 `server <command> [flags]`
+var commands = map[string]func(args []string) int{
+	"audit-verify":  auditVerifyCommand,
+	"gen":           genCommand,
+	"plugin-keygen": pluginKeygenCommand,
+	"plugin-sign":   pluginSignCommand,
+	"plugin-verify": pluginVerifyCommand,
+}
+
+// RunCommand 若命令行第一个参数为已注册的子命令则执行并返回 true
+func RunCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		return false
+	}
+	os.Exit(command(args[1:]))
+	return true
+}
+
+// auditVerifyCommand 离线校验 /auditChain/export 导出的哈希链归档
+// 公钥取自 -pub 或配置文件中的 audit-chain.public-key 不使用归档中自带的公钥
+func auditVerifyCommand(args []string) int {
+	fs := flag.NewFlagSet("audit-verify", flag.ContinueOnError)
+	file := fs.String("f", "", "哈希链归档文件路径")
+	pub := fs.String("pub", "", "受信任的检查点签名公钥(base64) 为空时读取配置文件中的 audit-chain.public-key")
+	configFile := fs.String("c", "", "配置文件路径 为空时依次使用环境变量 "+internal.ConfigEnv+" 与 "+internal.ConfigDefaultFile)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "用法: server audit-verify -f <archive.jsonl> [-pub <base64公钥> | -c <config.yaml>]")
+		return 2
+	}
+	key, err := auditVerifyKey(*pub, *configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer f.Close()
+	result, err := hashchain.VerifyArchive(f, key)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "校验失败:", err)
+		return 2
+	}
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+	if !result.Valid {
+		return 1
+	}
+	return 0
+}
+
+// auditVerifyKey 受信任的检查点签名公钥 优先使用命令行参数 其次为配置文件
+func auditVerifyKey(pub string, configFile string) (ed25519.PublicKey, error) {
+	if pub != "" {
+		return hashchain.ParsePublicKey(pub)
+	}
+	if configFile == "" {
+		configFile = os.Getenv(internal.ConfigEnv)
+	}
+	if configFile == "" {
+		configFile = internal.ConfigDefaultFile
+	}
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.SetConfigType("yaml")
+	var cfg config.AuditChain
+	if err := v.ReadInConfig(); err == nil {
+		if err = v.UnmarshalKey("audit-chain", &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.PublicKey == "" {
+		return nil, fmt.Errorf("未指定受信任的检查点签名公钥, 请使用 -pub 或在 %s 中配置 audit-chain.public-key", configFile)
+	}
+	return hashchain.ParsePublicKey(cfg.PublicKey)
+}
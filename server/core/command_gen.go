@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/core/internal"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/initialize"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/merge"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// genSpecExts 模块描述文件的后缀 json为web端导出的格式
+var genSpecExts = []string{".yaml", ".yml", ".json"}
+
+// genCommand 根据模块描述文件生成代码 与web端的预览/创建使用同一套服务
+// 描述文件字段与 request.AutoCode 的json字段一致 -f 为目录时依次处理目录下的全部描述文件
+func genCommand(args []string) int {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	file := flags.String("f", "", "模块描述文件(yaml/json)或包含描述文件的目录")
+	config := flags.String("c", "", "配置文件路径")
+	dryRun := flags.Bool("dry-run", false, "只输出与现有文件的差异 不写入文件")
+	regenerate := flags.Bool("merge", false, "重新生成已存在的模块 与本地修改三方合并")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "用法: server gen -f <module.yaml|目录> [-c config.yaml] [--dry-run] [--merge]")
+		return 2
+	}
+	specs, err := genSpecFiles(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *config != "" {
+		_ = os.Setenv(internal.ConfigEnv, *config)
+	}
+	global.GVA_VP = Viper()
+	global.GVA_LOG = Zap()
+	global.GVA_DB = initialize.Gorm()
+	if global.GVA_DB == nil {
+		fmt.Fprintln(os.Stderr, "数据库未初始化 请先完成初始化")
+		return 2
+	}
+	initialize.DBList()
+
+	code := 0
+	for _, spec := range specs {
+		info, err := loadGenSpec(spec)
+		if err == nil {
+			info.Merge = info.Merge || *regenerate
+			if *dryRun {
+				err = genDiff(os.Stdout, info)
+			} else {
+				err = system.AutoCodeTemplate.Create(context.Background(), info)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] 生成失败: %v\n", spec, err)
+			code = 1
+			continue
+		}
+		if !*dryRun {
+			fmt.Printf("[%s] 生成成功: %s/%s\n", spec, info.Package, info.StructName)
+		}
+	}
+	return code
+}
+
+// genSpecFiles 描述文件列表 目录下按路径排序
+func genSpecFiles(path string) ([]string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		return []string{path}, nil
+	}
+	var specs []string
+	err = filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && slices.Contains(genSpecExts, strings.ToLower(filepath.Ext(path))) {
+			specs = append(specs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, errors.Errorf("目录[%s]中没有模块描述文件!", path)
+	}
+	sort.Strings(specs)
+	return specs, nil
+}
+
+// loadGenSpec 读取描述文件并完成与web端创建时相同的校验和预处理
+func loadGenSpec(path string) (info request.AutoCode, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	var spec any
+	if err = yaml.Unmarshal(content, &spec); err != nil {
+		return info, errors.Wrap(err, "解析描述文件失败!")
+	}
+	// 经json转换 描述文件的字段名与 request.AutoCode 的json字段保持一致
+	content, err = json.Marshal(spec)
+	if err != nil {
+		return info, errors.Wrap(err, "解析描述文件失败!")
+	}
+	if err = json.Unmarshal(content, &info); err != nil {
+		return info, errors.Wrap(err, "描述文件格式错误!")
+	}
+	if err = utils.Verify(info, utils.AutoCodeVerify); err != nil {
+		return info, err
+	}
+	if err = info.Pretreatment(); err != nil {
+		return info, err
+	}
+	info.PackageT = utils.FirstUpper(info.Package)
+	return info, nil
+}
+
+// genDiff 输出生成结果与现有文件的差异 以及重新生成时未处理的合并冲突
+func genDiff(out io.Writer, info request.AutoCode) error {
+	files, conflicts, err := system.AutoCodeTemplate.PreviewFiles(context.Background(), info)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		current, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		name, err := filepath.Rel(global.GVA_CONFIG.AutoCode.Root, path)
+		if err != nil {
+			name = path
+		}
+		fmt.Fprint(out, merge.Unified(filepath.ToSlash(name), string(current), files[path]))
+	}
+	for path, list := range conflicts {
+		fmt.Fprintf(out, "# %s 存在%d处合并冲突 需在web端处理或在描述文件的resolutions中指定\n", path, len(list))
+	}
+	return nil
+}
@@ -27,6 +27,8 @@ func RunServer() {
 	// 从db加载jwt数据
 	if global.GVA_DB != nil {
 		system.LoadAll()
+		// 启动操作记录异步写入 回放溢出文件不占用首个请求
+		system.OperationRecordServiceApp.StartOperationRecordWriter()
 	}
 
 	Router := initialize.Routers()
@@ -41,8 +43,9 @@ func RunServer() {
 	默认自动化文档地址:http://127.0.0.1%s/swagger/index.html
 	默认MCP SSE地址:http://127.0.0.1%s%s
 	默认MCP Message地址:http://127.0.0.1%s%s
+	默认MCP Streamable HTTP地址:http://127.0.0.1%s%s
 	默认前端文件运行地址:http://127.0.0.1:8080
 	
-`, address, address, global.GVA_CONFIG.MCP.SSEPath, address, global.GVA_CONFIG.MCP.MessagePath)
+`, address, address, global.GVA_CONFIG.MCP.SSEPath, address, global.GVA_CONFIG.MCP.MessagePath, address, global.GVA_CONFIG.MCP.StreamPath)
 	initServer(address, Router, 10*time.Minute, 10*time.Minute)
 }
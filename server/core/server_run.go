@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/service/system"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -93,4 +94,12 @@ func initServer(address string, router *gin.Engine, readTimeout, writeTimeout ti
 	}
 
 	zap.L().Info("WEB服务已关闭")
+
+	// 服务停止后刷写剩余的操作记录
+	if err := system.OperationRecordServiceApp.CloseOperationRecordWriter(); err != nil {
+		zap.L().Error("操作记录队列刷写超时", zap.Error(err))
+	}
+	if err := system.AuditExportServiceApp.Close(); err != nil {
+		zap.L().Error("关闭SIEM投递目标失败", zap.Error(err))
+	}
 }
@@ -3,6 +3,7 @@ package global
 import (
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"gorm.io/gorm"
 )
 
@@ -12,3 +13,27 @@ type GVA_MODEL struct {
 	UpdatedAt time.Time      // 更新时间
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // 删除时间
 }
+
+// GVA_MONGO_MODEL MongoDB模型的默认字段 嵌入时需加上 `bson:",inline"`
+// 实现了qmgo的 field.DefaultFieldHook 插入和替换时自动填充主键与时间
+type GVA_MONGO_MODEL struct {
+	ID        primitive.ObjectID `json:"ID" bson:"_id,omitempty"`              // 主键ID
+	CreatedAt time.Time          `json:"CreatedAt" bson:"createdAt,omitempty"` // 创建时间 更新时为空则不覆盖
+	UpdatedAt time.Time          `json:"UpdatedAt" bson:"updatedAt"`           // 更新时间
+}
+
+func (m *GVA_MONGO_MODEL) DefaultId() {
+	if m.ID.IsZero() {
+		m.ID = primitive.NewObjectID()
+	}
+}
+
+func (m *GVA_MONGO_MODEL) DefaultCreateAt() {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+}
+
+func (m *GVA_MONGO_MODEL) DefaultUpdateAt() {
+	m.UpdatedAt = time.Now()
+}
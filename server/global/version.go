@@ -0,0 +1,4 @@
+package global
+
+// GVA_VERSION 核心版本 插件清单通过core声明兼容的核心版本范围
+const GVA_VERSION = "2.8.2"
@@ -49,10 +49,16 @@ func (e *ensureTables) MigrateTable(ctx context.Context) (context.Context, error
 		sysModel.SysBaseMenuBtn{},
 		sysModel.SysAuthorityBtn{},
 		sysModel.SysAutoCodePackage{},
+		sysModel.SysAutoCodeSnapshot{},
 		sysModel.SysExportTemplate{},
 		sysModel.Condition{},
 		sysModel.JoinTemplate{},
 		sysModel.SysParams{},
+		sysModel.SysTranslation{},
+		sysModel.SysFeatureFlag{},
+		sysModel.SysPlugin{},
+		sysModel.SysPluginResource{},
+		sysModel.SysAccessToken{},
 
 		adapter.CasbinRule{},
 
@@ -6,31 +6,50 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/example"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 func Gorm() *gorm.DB {
+	var db *gorm.DB
 	switch global.GVA_CONFIG.System.DbType {
 	case "mysql":
 		global.GVA_ACTIVE_DBNAME = &global.GVA_CONFIG.Mysql.Dbname
-		return GormMysql()
+		db = GormMysql()
 	case "pgsql":
 		global.GVA_ACTIVE_DBNAME = &global.GVA_CONFIG.Pgsql.Dbname
-		return GormPgSql()
+		db = GormPgSql()
 	case "oracle":
 		global.GVA_ACTIVE_DBNAME = &global.GVA_CONFIG.Oracle.Dbname
-		return GormOracle()
+		db = GormOracle()
 	case "mssql":
 		global.GVA_ACTIVE_DBNAME = &global.GVA_CONFIG.Mssql.Dbname
-		return GormMssql()
+		db = GormMssql()
 	case "sqlite":
 		global.GVA_ACTIVE_DBNAME = &global.GVA_CONFIG.Sqlite.Dbname
-		return GormSqlite()
+		db = GormSqlite()
 	default:
 		global.GVA_ACTIVE_DBNAME = &global.GVA_CONFIG.Mysql.Dbname
-		return GormMysql()
+		db = GormMysql()
+	}
+	RegisterGormPlugins(db)
+	return db
+}
+
+// RegisterGormPlugins 注册gorm插件
+func RegisterGormPlugins(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	// 审计表防篡改哈希链
+	if err := service.ServiceGroupApp.SystemServiceGroup.AuditChainService.RegisterPlugin(db); err != nil {
+		global.GVA_LOG.Error("register audit chain plugin failed", zap.Error(err))
+	}
+	// 实体变更历史 业务模型如需记录可追加 RegisterModels(&example.ExaCustomer{})
+	if err := service.ServiceGroupApp.SystemServiceGroup.EntityVersionService.RegisterPlugin(db); err != nil {
+		global.GVA_LOG.Error("register entity version plugin failed", zap.Error(err))
 	}
 }
 
@@ -52,6 +71,7 @@ func RegisterTables() {
 		system.SysBaseMenuBtn{},
 		system.SysAuthorityBtn{},
 		system.SysAutoCodePackage{},
+		system.SysAutoCodeSnapshot{},
 		system.SysExportTemplate{},
 		system.Condition{},
 		system.JoinTemplate{},
@@ -59,6 +79,15 @@ func RegisterTables() {
 		system.ConfigChangeHistory{},
 		system.ConfigBackup{},
 		system.ConfigValidationResult{},
+		system.SysAuditCheckpoint{},
+		system.SysAuditExportCursor{},
+		system.SysEntityVersion{},
+		system.SysOperationRecordArchive{},
+		system.SysTranslation{},
+		system.SysFeatureFlag{},
+		system.SysPlugin{},
+		system.SysPluginResource{},
+		system.SysAccessToken{},
 
 		example.ExaFile{},
 		example.ExaCustomer{},
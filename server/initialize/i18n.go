@@ -0,0 +1,14 @@
+package initialize
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"go.uber.org/zap"
+)
+
+// I18n 加载数据库中维护的翻译
+func I18n() {
+	if err := service.ServiceGroupApp.SystemServiceGroup.I18nService.Reload(); err != nil {
+		global.GVA_LOG.Error("load translations failed", zap.Error(err))
+	}
+}
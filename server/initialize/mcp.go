@@ -12,14 +12,20 @@ func McpRun() *server.SSEServer {
 	s := server.NewMCPServer(
 		config.Name,
 		config.Version,
+		server.WithToolFilter(mcpTool.ToolFilter),                // 工具列表只返回角色被授权的工具
+		server.WithToolHandlerMiddleware(mcpTool.ToolMiddleware), // 调用前校验工具权限
+		server.WithHooks(mcpTool.Hooks()),                        // 资源与提示词列表只返回角色被授权的条目 并登记SSE会话所有者
 	)
 
 	global.GVA_MCP_SERVER = s
 
 	mcpTool.RegisterAllTools(s)
+	mcpTool.RegisterAllResources(s)
+	mcpTool.RegisterAllPrompts(s)
 
 	return server.NewSSEServer(s,
 		server.WithSSEEndpoint(config.SSEPath),
 		server.WithMessageEndpoint(config.MessagePath),
-		server.WithBaseURL(config.UrlPrefix))
+		server.WithBaseURL(config.UrlPrefix),
+		server.WithHTTPContextFunc(mcpTool.ContextFunc)) // 工具以McpAuth鉴权得到的用户身份调用
 }
@@ -19,6 +19,11 @@ var Mongo = new(mongo)
 
 type (
 	mongo struct{}
+	// MongoModel 需要在启动时创建索引的MongoDB模型 代码生成器生成的模型会注册到 bizMongoModel 中
+	MongoModel interface {
+		CollectionName() string
+		Indexes() []options.IndexModel
+	}
 	Index struct {
 		V    any      `bson:"v"`
 		Ns   any      `bson:"ns"`
@@ -71,6 +76,25 @@ func (m *mongo) Initialization() error {
 	if err != nil {
 		return err
 	}
+	err = m.ModelIndexes(ctx, bizMongoModel()...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ModelIndexes 创建模型声明的索引 索引已存在时MongoDB不会重复创建
+func (m *mongo) ModelIndexes(ctx context.Context, models ...MongoModel) error {
+	for _, model := range models {
+		indexes := model.Indexes()
+		if len(indexes) == 0 {
+			continue
+		}
+		err := global.GVA_MONGO.Database.Collection(model.CollectionName()).CreateIndexes(ctx, indexes)
+		if err != nil {
+			return errors.Wrapf(err, "创建[%s]的索引失败!", model.CollectionName())
+		}
+	}
 	return nil
 }
 
@@ -95,7 +119,7 @@ func (m *mongo) CreateIndexes(ctx context.Context, name string, indexes [][]stri
 		length1 := len(indexes[i])
 		keys := make([]string, 0, length1)
 		for j := 0; j < length1; j++ {
-			if indexes[i][i][0] == '-' {
+			if indexes[i][j][0] == '-' {
 				keys = append(keys, indexes[i][j], "-1")
 				continue
 			}
@@ -0,0 +1,5 @@
+package initialize
+
+func bizMongoModel() []MongoModel {
+	return []MongoModel{}
+}
@@ -1,10 +1,17 @@
 package initialize
 
 import (
+	"context"
+
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/v2"
 	"github.com/gin-gonic/gin"
 )
 
+// pluginsV2 bizPluginV2 中登记的v2插件
+var pluginsV2 []plugin.Plugin
+
 func InstallPlugin(PrivateGroup *gin.RouterGroup, PublicRouter *gin.RouterGroup, engine *gin.Engine) {
 	if global.GVA_DB == nil {
 		global.GVA_LOG.Info("项目暂未初始化，无法安装插件，初始化后重启项目即可完成插件安装")
@@ -12,4 +19,11 @@ func InstallPlugin(PrivateGroup *gin.RouterGroup, PublicRouter *gin.RouterGroup,
 	}
 	bizPluginV1(PrivateGroup, PublicRouter)
 	bizPluginV2(engine)
+	system.PluginServiceApp.RegisterPlugins(context.Background(), engine, pluginsV2...)
+	pluginsV2 = nil
+}
+
+// PluginInitV2 登记v2插件 全部登记后按依赖顺序统一注册 插件之间的注册顺序与登记顺序无关
+func PluginInitV2(_ *gin.Engine, plugins ...plugin.Plugin) {
+	pluginsV2 = append(pluginsV2, plugins...)
 }
@@ -35,6 +35,7 @@ func Reload() error {
 	if global.GVA_DB != nil {
 		// 确保数据库表结构是最新的
 		RegisterTables()
+		I18n()
 	}
 
 	// 重新初始化定时任务
@@ -6,6 +6,7 @@ import (
 
 	"github.com/flipped-aurora/gin-vue-admin/server/docs"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	mcpTool "github.com/flipped-aurora/gin-vue-admin/server/mcp"
 	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
 	"github.com/flipped-aurora/gin-vue-admin/server/router"
 	"github.com/gin-gonic/gin"
@@ -47,14 +48,20 @@ func Routers() *gin.Engine {
 	sseServer := McpRun()
 
 	// 注册mcp服务
-	Router.GET(global.GVA_CONFIG.MCP.SSEPath, func(c *gin.Context) {
+	Router.GET(global.GVA_CONFIG.MCP.SSEPath, middleware.McpAuth(), func(c *gin.Context) {
 		sseServer.SSEHandler().ServeHTTP(c.Writer, c.Request)
 	})
 
-	Router.POST(global.GVA_CONFIG.MCP.MessagePath, func(c *gin.Context) {
+	Router.POST(global.GVA_CONFIG.MCP.MessagePath, middleware.McpAuth(), func(c *gin.Context) {
 		sseServer.MessageHandler().ServeHTTP(c.Writer, c.Request)
 	})
 
+	if global.GVA_CONFIG.MCP.StreamPath != "" {
+		streamServer := mcpTool.NewStreamableHTTPServer(global.GVA_MCP_SERVER)
+		Router.POST(global.GVA_CONFIG.MCP.StreamPath, middleware.McpAuth(), gin.WrapH(streamServer))
+		Router.GET(global.GVA_CONFIG.MCP.StreamPath, middleware.McpAuth(), gin.WrapH(streamServer))
+	}
+
 	systemRouter := router.RouterGroupApp.System
 	exampleRouter := router.RouterGroupApp.Example
 	// 如果想要不使用nginx代理前端网页，可以修改 web/.env.production 下的
@@ -78,7 +85,8 @@ func Routers() *gin.Engine {
 	PublicGroup := Router.Group(global.GVA_CONFIG.System.RouterPrefix)
 	PrivateGroup := Router.Group(global.GVA_CONFIG.System.RouterPrefix)
 
-	PrivateGroup.Use(middleware.JWTAuth()).Use(middleware.CasbinHandler())
+	PublicGroup.Use(middleware.I18n())
+	PrivateGroup.Use(middleware.JWTAuth()).Use(middleware.I18n()).Use(middleware.CasbinHandler()).Use(middleware.RequestActor())
 
 	{
 		// 健康监测
@@ -116,6 +124,13 @@ func Routers() *gin.Engine {
 		systemRouter.InitAuthorityBtnRouterRouter(PrivateGroup)             // 按钮权限管理
 		systemRouter.InitSysExportTemplateRouter(PrivateGroup, PublicGroup) // 导出模板
 		systemRouter.InitSysParamsRouter(PrivateGroup, PublicGroup)         // 参数管理
+		systemRouter.InitAuditChainRouter(PrivateGroup)                     // 审计防篡改哈希链
+		systemRouter.InitAuditExportRouter(PrivateGroup)                    // 审计事件SIEM投递
+		systemRouter.InitEntityVersionRouter(PrivateGroup)                  // 实体变更历史
+		systemRouter.InitOperationRecordArchiveRouter(PrivateGroup)         // 操作记录归档
+		systemRouter.InitI18nRouter(PrivateGroup, PublicGroup)              // 多语言翻译
+		systemRouter.InitFeatureFlagRouter(PrivateGroup)                    // 功能开关
+		systemRouter.InitAccessTokenRouter(PrivateGroup)                    // 个人访问令牌
 		//systemRouter.InitConfigManagerRouter(PrivateGroup)                  // 配置管理
 		exampleRouter.InitCustomerRouter(PrivateGroup)                 // 客户路由
 		exampleRouter.InitFileUploadAndDownloadRouter(PrivateGroup)    // 文件上传下载功能路由
@@ -129,7 +144,7 @@ func Routers() *gin.Engine {
 	// 注册业务路由
 	initBizRouter(PrivateGroup, PublicGroup)
 
-	global.GVA_ROUTERS = Router.Routes()
+	global.GVA_ROUTERS = append(Router.Routes(), mcpTool.Routes()...) // MCP工具按API授权
 
 	global.GVA_LOG.Info("router register success")
 	return Router
@@ -1,7 +1,9 @@
 package initialize
 
 import (
+	"context"
 	"fmt"
+	"github.com/flipped-aurora/gin-vue-admin/server/service/system"
 	"github.com/flipped-aurora/gin-vue-admin/server/task"
 
 	"github.com/robfig/cron/v3"
@@ -24,6 +26,55 @@ func Timer() {
 			fmt.Println("add timer error:", err)
 		}
 
+		// 审计哈希链签名检查点 未配置签名私钥时不生成
+		if global.GVA_CONFIG.AuditChain.Enable && global.GVA_CONFIG.AuditChain.CheckpointSpec != "" && global.GVA_CONFIG.AuditChain.SigningKey == "" {
+			fmt.Println("audit-chain.signing-key 未配置, 不生成审计哈希链签名检查点")
+		} else if global.GVA_CONFIG.AuditChain.Enable && global.GVA_CONFIG.AuditChain.CheckpointSpec != "" {
+			_, err = global.GVA_Timer.AddTaskByFunc("AuditChainCheckpoint", global.GVA_CONFIG.AuditChain.CheckpointSpec, func() {
+				err := system.AuditChainServiceApp.CreateCheckpoints()
+				if err != nil {
+					fmt.Println("timer error:", err)
+				}
+			}, "定时生成审计哈希链签名检查点", option...)
+			if err != nil {
+				fmt.Println("add timer error:", err)
+			}
+		}
+
+		// 审计事件投递到SIEM
+		if global.GVA_CONFIG.Siem.Enable && global.GVA_CONFIG.Siem.Spec != "" {
+			_, err = global.GVA_Timer.AddTaskByFunc("AuditExport", global.GVA_CONFIG.Siem.Spec, func() {
+				err := system.AuditExportServiceApp.Export()
+				if err != nil {
+					fmt.Println("timer error:", err)
+				}
+			}, "定时投递审计事件到SIEM", option...)
+			if err != nil {
+				fmt.Println("add timer error:", err)
+			}
+		}
+
+		// 操作记录按保留规则归档清理
+		if global.GVA_CONFIG.OperationRecord.Retention.Enable && global.GVA_CONFIG.OperationRecord.Retention.Spec != "" {
+			_, err = global.GVA_Timer.AddTaskByFunc("OperationRecordRetention", global.GVA_CONFIG.OperationRecord.Retention.Spec, func() {
+				_, _, err := system.OperationRecordArchiveServiceApp.ApplyRetention()
+				if err != nil {
+					fmt.Println("timer error:", err)
+				}
+			}, "定时归档并清理过期的操作记录", option...)
+			if err != nil {
+				fmt.Println("add timer error:", err)
+			}
+		}
+
+		// 同步其他实例对插件的启停 暂停或恢复插件的定时任务
+		_, err = global.GVA_Timer.AddTaskByFunc("PluginState", "@every 1m", func() {
+			system.PluginServiceApp.SyncPluginTimers(context.Background())
+		}, "定时同步插件启停状态到插件定时任务", option...)
+		if err != nil {
+			fmt.Println("add timer error:", err)
+		}
+
 		// 其他定时任务定在这里 参考上方使用方法
 
 		//_, err := global.GVA_Timer.AddTaskByFunc("定时任务标识", "corn表达式", func() {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/flipped-aurora/gin-vue-admin/server/core"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/initialize"
@@ -33,6 +35,10 @@ import (
 var realtimeService *service.RealtimeDataService
 
 func main() {
+	// 子命令 如 server audit-verify -f archive.jsonl
+	if core.RunCommand(os.Args[1:]) {
+		return
+	}
 	// 初始化系统
 	initializeSystem()
 	// 运行服务器
@@ -54,6 +60,7 @@ func initializeSystem() {
 	initialize.SetupHandlers() // 注册全局函数
 	if global.GVA_DB != nil {
 		initialize.RegisterTables() // 初始化表
+		initialize.I18n()           // 加载翻译
 	}
 
 	//// 设置全局NFC中继Hub变量
@@ -0,0 +1,46 @@
+package mcpTool
+
+import (
+	"context"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&GetApiList{})
+}
+
+// GetApiList 分页查询API 对应 POST /api/getApiList
+type GetApiList struct{}
+
+func (t *GetApiList) New() mcp.Tool {
+	return mcp.NewTool("getApiList",
+		mcp.WithDescription("分页查询系统注册的API 可按路径/描述/分组/请求方法筛选"),
+		mcp.WithNumber("page", mcp.Description("页码 默认1")),
+		mcp.WithNumber("pageSize", mcp.Description("每页条数 默认10 最大100")),
+		mcp.WithString("path", mcp.Description("API路径 模糊匹配")),
+		mcp.WithString("description", mcp.Description("API描述 模糊匹配")),
+		mcp.WithString("apiGroup", mcp.Description("API分组")),
+		mcp.WithString("method", mcp.Description("请求方法"), mcp.Enum("GET", "POST", "PUT", "DELETE", "MCP")),
+		mcp.WithString("orderKey", mcp.Description("排序字段"), mcp.Enum("id", "path", "api_group", "description", "method")),
+		mcp.WithBoolean("desc", mcp.Description("是否降序")),
+	)
+}
+
+func (t *GetApiList) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/api/getApiList", Method: "POST"}, func(*systemReq.CustomClaims) (any, error) {
+		var info systemReq.SearchApiParams
+		if err := request.BindArguments(&info); err != nil {
+			return nil, err
+		}
+		pageDefault(&info.PageInfo)
+		list, total, err := systemService.ApiServiceApp.GetAPIInfoList(info.SysApi, info.PageInfo, info.OrderKey, info.Desc)
+		if err != nil {
+			return nil, err
+		}
+		return response.PageResult{List: list, Total: total, Page: info.Page, PageSize: info.PageSize}, nil
+	})
+}
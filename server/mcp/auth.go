@@ -0,0 +1,106 @@
+package mcpTool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/versioning"
+	"github.com/google/uuid"
+)
+
+type callerKey struct{}
+
+// Caller MCP请求的调用方 由传输层从HTTP请求中解析
+type Caller struct {
+	Claims    *systemReq.CustomClaims // 登录用户 未携带或令牌无效时为nil
+	AuthErr   string                  // 令牌无效的原因
+	TokenKey  string                  // 限流键 个人访问令牌为令牌ID JWT为令牌摘要
+	RateLimit int                     // 每分钟请求上限 0不限制
+	IP        string                  // 客户端IP
+	Agent     string                  // 客户端User-Agent
+}
+
+// WithCaller 将调用方写入上下文
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	ctx = context.WithValue(ctx, callerKey{}, caller)
+	if caller.Claims != nil {
+		ctx = versioning.WithActor(ctx, versioning.Actor{UserID: caller.Claims.BaseClaims.ID, Username: caller.Claims.Username, RequestID: uuid.NewString()})
+	}
+	return ctx
+}
+
+// CallerFromContext 获取上下文中的调用方
+func CallerFromContext(ctx context.Context) Caller {
+	caller, _ := ctx.Value(callerKey{}).(Caller)
+	return caller
+}
+
+// Authenticate 校验请求头x-token或Authorization: Bearer携带的JWT或个人访问令牌 JWT的校验与JWTAuth中间件一致
+// ip为传输层解析的客户端IP 不从请求头中读取 避免调用方伪造
+func Authenticate(r *http.Request, ip string) Caller {
+	caller := Caller{IP: ip, Agent: r.UserAgent(), RateLimit: global.GVA_CONFIG.MCP.RateLimit}
+	token := requestToken(r)
+	switch {
+	case token == "":
+		caller.AuthErr = "未登录或非法访问"
+	case strings.HasPrefix(token, system.AccessTokenPrefix):
+		claims, accessToken, err := systemService.AccessTokenServiceApp.AuthenticateAccessToken(token)
+		if err != nil {
+			caller.AuthErr = err.Error()
+			break
+		}
+		caller.Claims = claims
+		caller.TokenKey = "pat:" + strconv.Itoa(int(accessToken.ID))
+		if accessToken.RateLimit > 0 {
+			caller.RateLimit = accessToken.RateLimit
+		}
+	case utils.IsBlacklist(token):
+		caller.AuthErr = "您的帐户异地登陆或令牌失效"
+	default:
+		claims, err := utils.NewJWT().ParseToken(token)
+		if errors.Is(err, utils.TokenExpired) {
+			caller.AuthErr = "授权已过期"
+		} else if err != nil {
+			caller.AuthErr = err.Error()
+		} else {
+			sum := sha256.Sum256([]byte(token))
+			caller.Claims = claims
+			caller.TokenKey = "jwt:" + hex.EncodeToString(sum[:8])
+		}
+	}
+	return caller
+}
+
+// ContextFunc 传输层鉴权时已写入调用方则直接使用 否则按请求头重新解析 客户端IP取连接的远端地址
+func ContextFunc(ctx context.Context, r *http.Request) context.Context {
+	if _, ok := ctx.Value(callerKey{}).(Caller); ok {
+		return ctx
+	}
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return WithCaller(ctx, Authenticate(r, ip))
+}
+
+func requestToken(r *http.Request) string {
+	if token := r.Header.Get("x-token"); token != "" {
+		return token
+	}
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
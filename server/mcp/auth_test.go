@@ -0,0 +1,133 @@
+package mcpTool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/songzhibin97/gkit/cache/local_cache"
+)
+
+// setupJWT 使用测试签名密钥与黑名单 测试结束后恢复
+func setupJWT(t *testing.T) {
+	t.Helper()
+	oldJWT, oldRateLimit, oldCache := global.GVA_CONFIG.JWT, global.GVA_CONFIG.MCP.RateLimit, global.BlackCache
+	global.GVA_CONFIG.JWT.SigningKey = "mcp-test"
+	global.GVA_CONFIG.JWT.ExpiresTime = "1d"
+	global.GVA_CONFIG.JWT.BufferTime = "1d"
+	global.GVA_CONFIG.MCP.RateLimit = 60
+	global.BlackCache = local_cache.NewCache()
+	t.Cleanup(func() {
+		global.GVA_CONFIG.JWT, global.GVA_CONFIG.MCP.RateLimit, global.BlackCache = oldJWT, oldRateLimit, oldCache
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	db := setupMcpTestDB(t, &system.SysUser{}, &system.SysAccessToken{})
+	setupJWT(t)
+	users := []system.SysUser{
+		{UUID: uuid.New(), Username: "admin", AuthorityId: 888, Enable: 1},
+		{UUID: uuid.New(), Username: "frozen", AuthorityId: 888, Enable: 2},
+	}
+	if err := db.Omit("Authorities", "Authority").Create(&users).Error; err != nil {
+		t.Fatalf("准备用户失败: %v", err)
+	}
+	pat := func(userID uint, rateLimit int) string {
+		result, err := systemService.AccessTokenServiceApp.CreateAccessToken(context.Background(), userID, systemReq.CreateAccessToken{Name: "mcp", RateLimit: rateLimit})
+		if err != nil {
+			t.Fatalf("创建个人访问令牌失败: %v", err)
+		}
+		return result.Token
+	}
+	expiredPat := pat(users[0].ID, 0)
+	if err := db.Model(&system.SysAccessToken{}).Where("hint = ?", expiredPat[:len(system.AccessTokenPrefix)+4]).Update("expires_at", time.Now().Add(-time.Hour)).Error; err != nil {
+		t.Fatalf("设置令牌过期失败: %v", err)
+	}
+	j := utils.NewJWT()
+	token := func(expiresAt time.Time) string {
+		claims := j.CreateClaims(systemReq.BaseClaims{UUID: users[0].UUID, ID: users[0].ID, Username: "admin", AuthorityId: 888})
+		claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+		token, err := j.CreateToken(claims)
+		if err != nil {
+			t.Fatalf("签发JWT失败: %v", err)
+		}
+		return token
+	}
+	blacklisted := token(time.Now().Add(2 * time.Hour)) // 与其他JWT的过期时间不同 避免签出相同的令牌
+	global.BlackCache.SetDefault(blacklisted, struct{}{})
+
+	tests := []struct {
+		name          string
+		header        string
+		token         string
+		wantErr       string
+		wantUser      string
+		wantKey       string // 限流键前缀
+		wantRateLimit int
+	}{
+		{name: "未携带令牌", wantErr: "未登录或非法访问"},
+		{name: "个人访问令牌", header: "Authorization", token: pat(users[0].ID, 0), wantUser: "admin", wantKey: "pat:", wantRateLimit: 60},
+		{name: "个人访问令牌单独限流", header: "x-token", token: pat(users[0].ID, 5), wantUser: "admin", wantKey: "pat:", wantRateLimit: 5},
+		{name: "已过期的个人访问令牌", header: "x-token", token: expiredPat, wantErr: "令牌已过期"},
+		{name: "已吊销的个人访问令牌", header: "x-token", token: system.AccessTokenPrefix + "revoked", wantErr: "令牌无效或已被吊销"},
+		{name: "冻结用户的个人访问令牌", header: "x-token", token: pat(users[1].ID, 0), wantErr: "令牌所属用户不存在或已被冻结"},
+		{name: "JWT", header: "x-token", token: token(time.Now().Add(time.Hour)), wantUser: "admin", wantKey: "jwt:", wantRateLimit: 60},
+		{name: "Bearer携带JWT", header: "Authorization", token: token(time.Now().Add(time.Hour)), wantUser: "admin", wantKey: "jwt:", wantRateLimit: 60},
+		{name: "已过期的JWT", header: "x-token", token: token(time.Now().Add(-time.Hour)), wantErr: "授权已过期"},
+		{name: "黑名单中的JWT", header: "x-token", token: blacklisted, wantErr: "您的帐户异地登陆或令牌失效"},
+		{name: "签名错误的JWT", header: "x-token", token: "a.b.c", wantErr: utils.TokenMalformed.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			r.Header.Set("X-Forwarded-For", "1.2.3.4")
+			switch tt.header {
+			case "Authorization":
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			case "x-token":
+				r.Header.Set("x-token", tt.token)
+			}
+			caller := Authenticate(r, "10.0.0.1")
+			if caller.IP != "10.0.0.1" {
+				t.Errorf("Authenticate() IP = %s, 期望使用传输层解析的IP", caller.IP)
+			}
+			if tt.wantErr != "" {
+				if caller.Claims != nil || caller.AuthErr != tt.wantErr {
+					t.Errorf("Authenticate() AuthErr = %s, want %s", caller.AuthErr, tt.wantErr)
+				}
+				return
+			}
+			if caller.Claims == nil {
+				t.Fatalf("Authenticate() AuthErr = %s", caller.AuthErr)
+			}
+			if caller.Claims.Username != tt.wantUser || !strings.HasPrefix(caller.TokenKey, tt.wantKey) || caller.RateLimit != tt.wantRateLimit {
+				t.Errorf("Authenticate() user=%s key=%s rateLimit=%d", caller.Claims.Username, caller.TokenKey, caller.RateLimit)
+			}
+		})
+	}
+}
+
+func TestContextFunc(t *testing.T) {
+	setupMcpTestDB(t)
+	setupJWT(t)
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	r.RemoteAddr = "10.0.0.2:5678"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	if caller := CallerFromContext(ContextFunc(context.Background(), r)); caller.IP != "10.0.0.2" {
+		t.Errorf("未经传输层鉴权时 IP = %s, 期望连接的远端地址", caller.IP)
+	}
+	ctx := WithCaller(context.Background(), Caller{IP: "10.0.0.1"})
+	if caller := CallerFromContext(ContextFunc(ctx, r)); caller.IP != "10.0.0.1" {
+		t.Errorf("传输层已写入调用方时 IP = %s, 期望 10.0.0.1", caller.IP)
+	}
+}
@@ -0,0 +1,28 @@
+package mcpTool
+
+import (
+	"context"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&GetAuthorityList{})
+}
+
+// GetAuthorityList 查询当前用户可管理的角色树 对应 POST /authority/getAuthorityList
+type GetAuthorityList struct{}
+
+func (t *GetAuthorityList) New() mcp.Tool {
+	return mcp.NewTool("getAuthorityList",
+		mcp.WithDescription("查询当前用户可管理的角色 以树形结构返回"),
+	)
+}
+
+func (t *GetAuthorityList) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/authority/getAuthorityList", Method: "POST"}, func(claims *systemReq.CustomClaims) (any, error) {
+		return systemService.AuthorityServiceApp.GetAuthorityInfoList(claims.AuthorityId)
+	})
+}
@@ -0,0 +1,53 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&GetDictionaryList{})
+	RegisterTool(&FindDictionary{})
+}
+
+// GetDictionaryList 查询全部字典 对应 GET /sysDictionary/getSysDictionaryList
+type GetDictionaryList struct{}
+
+func (t *GetDictionaryList) New() mcp.Tool {
+	return mcp.NewTool("getDictionaryList",
+		mcp.WithDescription("查询全部字典的类型、名称与状态 不含字典详情"),
+	)
+}
+
+func (t *GetDictionaryList) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/sysDictionary/getSysDictionaryList", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		return systemService.DictionaryServiceApp.GetSysDictionaryInfoList()
+	})
+}
+
+// FindDictionary 按字典类型查询字典及其详情 对应 GET /sysDictionary/findSysDictionary
+type FindDictionary struct{}
+
+func (t *FindDictionary) New() mcp.Tool {
+	return mcp.NewTool("findDictionary",
+		mcp.WithDescription("按字典类型查询已启用的字典及其全部字典详情"),
+		mcp.WithString("type", mcp.Required(), mcp.Description("字典类型 如 gender")),
+	)
+}
+
+func (t *FindDictionary) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/sysDictionary/findSysDictionary", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		dictionaryType, err := request.RequireString("type")
+		if err != nil {
+			return nil, err
+		}
+		if dictionaryType == "" {
+			return nil, errors.New("参数错误：type 不能为空")
+		}
+		return systemService.DictionaryServiceApp.GetSysDictionary(dictionaryType, 0, nil)
+	})
+}
@@ -14,18 +14,83 @@ type McpTool interface {
 	New() mcp.Tool
 }
 
+// McpResource 定义了固定URI的MCP资源必须实现的接口
+type McpResource interface {
+	// Handle 返回资源内容
+	Handle(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
+	// New 返回资源注册信息
+	New() mcp.Resource
+}
+
+// McpResourceTemplate 定义了URI模板资源必须实现的接口 如 gva://dictionaries/{type}
+type McpResourceTemplate interface {
+	// Handle 返回资源内容 模板变量在request.Params.Arguments中
+	Handle(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
+	// New 返回资源模板注册信息
+	New() mcp.ResourceTemplate
+}
+
+// McpPrompt 定义了MCP提示词必须实现的接口
+type McpPrompt interface {
+	// Handle 返回填充参数后的提示词
+	Handle(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+	// New 返回提示词注册信息
+	New() mcp.Prompt
+}
+
 // 工具注册表
 var toolRegister = make(map[string]McpTool)
 
+// 资源注册表 键为资源名称
+var (
+	resourceRegister         = make(map[string]McpResource)
+	resourceTemplateRegister = make(map[string]McpResourceTemplate)
+)
+
+// 提示词注册表
+var promptRegister = make(map[string]McpPrompt)
+
 // RegisterTool 供工具在init时调用，将自己注册到工具注册表中
 func RegisterTool(tool McpTool) {
 	mcpTool := tool.New()
 	toolRegister[mcpTool.Name] = tool
 }
 
+// RegisterResource 供资源在init时调用，将自己注册到资源注册表中
+func RegisterResource(resource McpResource) {
+	resourceRegister[resource.New().Name] = resource
+}
+
+// RegisterResourceTemplate 供资源模板在init时调用，将自己注册到资源注册表中
+func RegisterResourceTemplate(template McpResourceTemplate) {
+	resourceTemplateRegister[template.New().Name] = template
+}
+
+// RegisterPrompt 供提示词在init时调用，将自己注册到提示词注册表中
+func RegisterPrompt(prompt McpPrompt) {
+	promptRegister[prompt.New().Name] = prompt
+}
+
 // RegisterAllTools 将所有注册的工具注册到MCP服务中
 func RegisterAllTools(mcpServer *server.MCPServer) {
 	for _, tool := range toolRegister {
 		mcpServer.AddTool(tool.New(), tool.Handle)
 	}
 }
+
+// RegisterAllResources 将所有注册的资源与资源模板注册到MCP服务中
+func RegisterAllResources(mcpServer *server.MCPServer) {
+	for _, resource := range resourceRegister {
+		mcpServer.AddResource(resource.New(), resource.Handle)
+	}
+	for _, template := range resourceTemplateRegister {
+		mcpServer.AddResourceTemplate(template.New(), template.Handle)
+	}
+}
+
+// RegisterAllPrompts 将所有注册的提示词注册到MCP服务中
+func RegisterAllPrompts(mcpServer *server.MCPServer) {
+	for _, prompt := range promptRegister {
+		mcpServer.AddPrompt(prompt.New(), prompt.Handle)
+	}
+}
@@ -0,0 +1,123 @@
+package mcpTool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	common "github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// 操作记录中请求参数与响应的最大长度 与OperationRecord中间件一致
+const recordBodySize = 1024
+
+// Route 工具对应的HTTP路由 调用工具需要具备该路由的casbin权限
+type Route struct {
+	Path   string // 不含路由前缀的路径 与casbin策略一致
+	Method string
+}
+
+// invoke 以调用方身份执行工具 校验登录与路由对应的casbin权限 执行结果以JSON文本返回 每次调用都写入操作记录
+func invoke(ctx context.Context, request mcp.CallToolRequest, route Route, fn func(claims *systemReq.CustomClaims) (any, error)) (*mcp.CallToolResult, error) {
+	text, err := guard(ctx, request.Params.Name, request.GetArguments(), route, []Route{route}, fn)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(text), nil
+}
+
+// guard 以调用方身份执行 校验登录与routes中每个路由的casbin权限 执行结果以JSON文本返回 每次执行都以record路由写入操作记录
+func guard(ctx context.Context, name string, args any, record Route, routes []Route, fn func(claims *systemReq.CustomClaims) (any, error)) (text string, err error) {
+	caller := CallerFromContext(ctx)
+	start := time.Now()
+	status := http.StatusOK
+	defer func() {
+		audit(caller, name, args, record, status, text, err, time.Since(start))
+	}()
+
+	if caller.Claims == nil {
+		status = http.StatusUnauthorized
+		if caller.AuthErr != "" {
+			return "", errors.New(caller.AuthErr)
+		}
+		return "", errors.New("未登录或非法访问")
+	}
+	sub := strconv.Itoa(int(caller.Claims.AuthorityId))
+	for _, route := range routes {
+		ok, err := utils.GetCasbin().Enforce(sub, route.Path, route.Method)
+		if err != nil {
+			status = http.StatusInternalServerError
+			return "", err
+		}
+		if !ok {
+			status = http.StatusForbidden
+			return "", errors.New("权限不足")
+		}
+	}
+	data, err := fn(caller.Claims)
+	if err != nil {
+		status = http.StatusInternalServerError
+		return "", err
+	}
+	content, err := json.Marshal(data)
+	if err != nil {
+		status = http.StatusInternalServerError
+		return "", err
+	}
+	return string(content), nil
+}
+
+// audit 将MCP调用写入操作记录 请求路径与方法为对应的路由 User-Agent中标记工具名或资源URI
+func audit(caller Caller, name string, args any, route Route, status int, resp string, err error, latency time.Duration) {
+	record := system.SysOperationRecord{
+		Ip:      caller.IP,
+		Method:  route.Method,
+		Path:    global.GVA_CONFIG.System.RouterPrefix + route.Path,
+		Status:  status,
+		Latency: latency,
+		Agent:   strings.TrimSpace("MCP " + name + " " + caller.Agent),
+	}
+	if len(resp) > recordBodySize {
+		record.Resp = "[超出记录长度]"
+	} else {
+		record.Resp = resp
+	}
+	if caller.Claims != nil {
+		record.UserID = int(caller.Claims.BaseClaims.ID)
+	}
+	if err != nil {
+		record.ErrorMessage = err.Error()
+	}
+	if body, _ := json.Marshal(args); len(body) > recordBodySize {
+		record.Body = "[超出记录长度]"
+	} else {
+		record.Body = string(body)
+	}
+	if err := systemService.OperationRecordServiceApp.CreateSysOperationRecord(record); err != nil {
+		global.GVA_LOG.Error("create mcp operation record error:", zap.Error(err))
+	}
+}
+
+// pageDefault 未传入分页参数时使用第一页 每页10条 每页最多100条
+func pageDefault(info *common.PageInfo) {
+	if info.Page <= 0 {
+		info.Page = 1
+	}
+	switch {
+	case info.PageSize > 100:
+		info.PageSize = 100
+	case info.PageSize <= 0:
+		info.PageSize = 10
+	}
+}
@@ -0,0 +1,145 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// openMcpTestDB 打开内存sqlite并迁移表结构
+func openMcpTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取数据库连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 内存库只在同一连接内可见
+	if err = db.AutoMigrate(models...); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+	return db
+}
+
+// setupMcpTestDB 使用内存sqlite作为MCP测试的数据库 默认迁移操作记录表
+func setupMcpTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db := openMcpTestDB(t, append([]any{&system.SysOperationRecord{}}, models...)...)
+	oldDB, oldLog := global.GVA_DB, global.GVA_LOG
+	global.GVA_DB, global.GVA_LOG = db, zap.NewNop()
+	t.Cleanup(func() {
+		global.GVA_DB, global.GVA_LOG = oldDB, oldLog
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	return db
+}
+
+var casbinOnce sync.Once
+
+// grantMcp 授权角色访问路由 测试结束后撤销
+// casbin执行器全局只初始化一次并绑定当时的数据库 因此使用单独且不关闭的数据库
+func grantMcp(t *testing.T, authorityId string, path string, method string) {
+	t.Helper()
+	casbinOnce.Do(func() {
+		oldDB := global.GVA_DB
+		global.GVA_DB = openMcpTestDB(t, &gormadapter.CasbinRule{})
+		utils.GetCasbin()
+		global.GVA_DB = oldDB
+	})
+	e := utils.GetCasbin()
+	if _, err := e.AddPolicy(authorityId, path, method); err != nil {
+		t.Fatalf("授权失败: %v", err)
+	}
+	_ = e.InvalidateCache()
+	t.Cleanup(func() {
+		_, _ = e.RemovePolicy(authorityId, path, method)
+		_ = e.InvalidateCache()
+	})
+}
+
+func TestGuard(t *testing.T) {
+	db := setupMcpTestDB(t)
+	grantMcp(t, "888", "/user/getUserList", "POST")
+	claims := &systemReq.CustomClaims{BaseClaims: systemReq.BaseClaims{ID: 1, Username: "admin", AuthorityId: 888}}
+	route := Route{Path: "/user/getUserList", Method: "POST"}
+	tests := []struct {
+		name       string
+		caller     Caller
+		routes     []Route
+		args       any
+		result     any
+		wantErr    string
+		wantCalled bool
+		wantStatus int
+		wantBody   string
+		wantResp   string
+	}{
+		{name: "未登录", caller: Caller{IP: "127.0.0.1"}, routes: []Route{route}, wantErr: "未登录或非法访问", wantStatus: http.StatusUnauthorized},
+		{name: "令牌无效时返回原因", caller: Caller{AuthErr: "授权已过期"}, routes: []Route{route}, wantErr: "授权已过期", wantStatus: http.StatusUnauthorized},
+		{name: "没有路由权限", caller: Caller{Claims: &systemReq.CustomClaims{BaseClaims: systemReq.BaseClaims{ID: 2, AuthorityId: 9528}}}, routes: []Route{route}, wantErr: "权限不足", wantStatus: http.StatusForbidden},
+		{name: "需要全部路由的权限", caller: Caller{Claims: claims}, routes: []Route{route, {Path: "/user/deleteUser", Method: "DELETE"}}, wantErr: "权限不足", wantStatus: http.StatusForbidden},
+		{name: "执行成功", caller: Caller{Claims: claims}, routes: []Route{route}, args: map[string]any{"page": 1}, result: map[string]any{"total": 1},
+			wantCalled: true, wantStatus: http.StatusOK, wantBody: `{"page":1}`, wantResp: `{"total":1}`},
+		{name: "执行失败", caller: Caller{Claims: claims}, routes: []Route{route}, result: errors.New("查询失败"), wantErr: "查询失败", wantCalled: true, wantStatus: http.StatusInternalServerError},
+		{name: "超长的参数与响应不写入记录", caller: Caller{Claims: claims}, routes: []Route{route}, args: strings.Repeat("a", recordBodySize), result: strings.Repeat("b", recordBodySize),
+			wantCalled: true, wantStatus: http.StatusOK, wantBody: "[超出记录长度]", wantResp: "[超出记录长度]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.Where("1 = 1").Delete(&system.SysOperationRecord{})
+			called := false
+			_, err := guard(WithCaller(context.Background(), tt.caller), "get_user_list", tt.args, route, tt.routes, func(claims *systemReq.CustomClaims) (any, error) {
+				called = true
+				if err, ok := tt.result.(error); ok {
+					return nil, err
+				}
+				return tt.result, nil
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("guard() error = %v, want %s", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("guard() error = %v", err)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("是否执行 = %v, 期望 %v", called, tt.wantCalled)
+			}
+			var records []system.SysOperationRecord
+			db.Find(&records)
+			if len(records) != 1 {
+				t.Fatalf("操作记录数 = %d, 期望 1", len(records))
+			}
+			record := records[0]
+			if record.Status != tt.wantStatus || record.Path != route.Path || record.Method != route.Method || !strings.HasPrefix(record.Agent, "MCP get_user_list") {
+				t.Errorf("操作记录 status=%d path=%s method=%s agent=%s", record.Status, record.Path, record.Method, record.Agent)
+			}
+			if record.ErrorMessage != tt.wantErr {
+				t.Errorf("操作记录错误信息 = %s, 期望 %s", record.ErrorMessage, tt.wantErr)
+			}
+			if tt.wantBody != "" && record.Body != tt.wantBody {
+				t.Errorf("操作记录请求 = %s, 期望 %s", record.Body, tt.wantBody)
+			}
+			if record.Resp != tt.wantResp {
+				t.Errorf("操作记录响应 = %s, 期望 %s", record.Resp, tt.wantResp)
+			}
+		})
+	}
+}
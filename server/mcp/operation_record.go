@@ -0,0 +1,43 @@
+package mcpTool
+
+import (
+	"context"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&GetOperationRecordList{})
+}
+
+// GetOperationRecordList 分页查询操作记录 对应 GET /sysOperationRecord/getSysOperationRecordList
+type GetOperationRecordList struct{}
+
+func (t *GetOperationRecordList) New() mcp.Tool {
+	return mcp.NewTool("getOperationRecordList",
+		mcp.WithDescription("分页查询操作记录 可按请求方法/路径/状态码筛选 按时间倒序"),
+		mcp.WithNumber("page", mcp.Description("页码 默认1")),
+		mcp.WithNumber("pageSize", mcp.Description("每页条数 默认10 最大100")),
+		mcp.WithString("method", mcp.Description("请求方法")),
+		mcp.WithString("path", mcp.Description("请求路径 模糊匹配")),
+		mcp.WithNumber("status", mcp.Description("响应状态码")),
+	)
+}
+
+func (t *GetOperationRecordList) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/sysOperationRecord/getSysOperationRecordList", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		var info systemReq.SysOperationRecordSearch
+		if err := request.BindArguments(&info); err != nil {
+			return nil, err
+		}
+		pageDefault(&info.PageInfo)
+		list, total, err := systemService.OperationRecordServiceApp.GetSysOperationRecordInfoList(info)
+		if err != nil {
+			return nil, err
+		}
+		return response.PageResult{List: list, Total: total, Page: info.Page, PageSize: info.PageSize}, nil
+	})
+}
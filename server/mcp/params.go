@@ -0,0 +1,67 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&GetParamsList{})
+	RegisterTool(&GetParam{})
+}
+
+// GetParamsList 分页查询系统参数 对应 GET /sysParams/getSysParamsList
+type GetParamsList struct{}
+
+func (t *GetParamsList) New() mcp.Tool {
+	return mcp.NewTool("getParamsList",
+		mcp.WithDescription("分页查询系统参数 可按参数名称/键模糊筛选"),
+		mcp.WithNumber("page", mcp.Description("页码 默认1")),
+		mcp.WithNumber("pageSize", mcp.Description("每页条数 默认10 最大100")),
+		mcp.WithString("name", mcp.Description("参数名称")),
+		mcp.WithString("key", mcp.Description("参数键")),
+	)
+}
+
+func (t *GetParamsList) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/sysParams/getSysParamsList", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		var info systemReq.SysParamsSearch
+		if err := request.BindArguments(&info); err != nil {
+			return nil, err
+		}
+		pageDefault(&info.PageInfo)
+		list, total, err := systemService.SysParamsServiceApp.GetSysParamsInfoList(info)
+		if err != nil {
+			return nil, err
+		}
+		return response.PageResult{List: list, Total: total, Page: info.Page, PageSize: info.PageSize}, nil
+	})
+}
+
+// GetParam 按键读取系统参数 对应 GET /sysParams/getSysParam
+type GetParam struct{}
+
+func (t *GetParam) New() mcp.Tool {
+	return mcp.NewTool("getParam",
+		mcp.WithDescription("按参数键读取系统参数的值与类型"),
+		mcp.WithString("key", mcp.Required(), mcp.Description("参数键")),
+	)
+}
+
+func (t *GetParam) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/sysParams/getSysParam", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		key, err := request.RequireString("key")
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			return nil, errors.New("参数错误：key 不能为空")
+		}
+		return systemService.SysParamsServiceApp.GetSysParam(key)
+	})
+}
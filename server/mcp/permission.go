@@ -0,0 +1,136 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// ToolMethod MCP工具、资源与提示词权限在casbin与API中使用的请求方法
+const ToolMethod = "MCP"
+
+// ToolPath MCP工具权限在casbin与API中使用的路径 按角色授权该API即可使用对应工具
+func ToolPath(name string) string {
+	return "/mcp/tools/" + name
+}
+
+// ResourcePath MCP资源权限在casbin与API中使用的路径
+func ResourcePath(name string) string {
+	return "/mcp/resources/" + name
+}
+
+// PromptPath MCP提示词权限在casbin与API中使用的路径
+func PromptPath(name string) string {
+	return "/mcp/prompts/" + name
+}
+
+// Routes 已注册工具、资源与提示词对应的权限路由 加入GVA_ROUTERS后可通过API同步录入 再在角色的API权限中授权
+func Routes() gin.RoutesInfo {
+	paths := make([]string, 0, len(toolRegister)+len(resourceRegister)+len(resourceTemplateRegister)+len(promptRegister))
+	for name := range toolRegister {
+		paths = append(paths, ToolPath(name))
+	}
+	for name := range resourceRegister {
+		paths = append(paths, ResourcePath(name))
+	}
+	for name := range resourceTemplateRegister {
+		paths = append(paths, ResourcePath(name))
+	}
+	for name := range promptRegister {
+		paths = append(paths, PromptPath(name))
+	}
+	sort.Strings(paths)
+	routes := make(gin.RoutesInfo, 0, len(paths))
+	for _, path := range paths {
+		routes = append(routes, gin.RouteInfo{Method: ToolMethod, Path: global.GVA_CONFIG.System.RouterPrefix + path})
+	}
+	return routes
+}
+
+// allowed 角色是否被授权使用path对应的工具、资源或提示词
+func allowed(claims *systemReq.CustomClaims, path string) bool {
+	if claims == nil {
+		return false
+	}
+	ok, err := utils.GetCasbin().Enforce(strconv.Itoa(int(claims.AuthorityId)), path, ToolMethod)
+	if err != nil {
+		global.GVA_LOG.Error("mcp permission check error:", zap.String("path", path), zap.Error(err))
+	}
+	return ok
+}
+
+// ToolFilter 工具列表中只返回调用方被授权的工具
+func ToolFilter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	claims := CallerFromContext(ctx).Claims
+	result := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if allowed(claims, ToolPath(tool.Name)) {
+			result = append(result, tool)
+		}
+	}
+	return result
+}
+
+// ListHooks 资源、资源模板与提示词列表中只返回调用方被授权的条目
+// mcp-go没有资源与提示词的列表过滤选项 在返回列表前的钩子中过滤结果
+func ListHooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddAfterListResources(func(ctx context.Context, id any, message *mcp.ListResourcesRequest, result *mcp.ListResourcesResult) {
+		claims := CallerFromContext(ctx).Claims
+		resources := make([]mcp.Resource, 0, len(result.Resources))
+		for _, item := range result.Resources {
+			if allowed(claims, ResourcePath(item.Name)) {
+				resources = append(resources, item)
+			}
+		}
+		result.Resources = resources
+	})
+	hooks.AddAfterListResourceTemplates(func(ctx context.Context, id any, message *mcp.ListResourceTemplatesRequest, result *mcp.ListResourceTemplatesResult) {
+		claims := CallerFromContext(ctx).Claims
+		templates := make([]mcp.ResourceTemplate, 0, len(result.ResourceTemplates))
+		for _, item := range result.ResourceTemplates {
+			if allowed(claims, ResourcePath(item.Name)) {
+				templates = append(templates, item)
+			}
+		}
+		result.ResourceTemplates = templates
+	})
+	hooks.AddAfterListPrompts(func(ctx context.Context, id any, message *mcp.ListPromptsRequest, result *mcp.ListPromptsResult) {
+		claims := CallerFromContext(ctx).Claims
+		prompts := make([]mcp.Prompt, 0, len(result.Prompts))
+		for _, item := range result.Prompts {
+			if allowed(claims, PromptPath(item.Name)) {
+				prompts = append(prompts, item)
+			}
+		}
+		result.Prompts = prompts
+	})
+	return hooks
+}
+
+// ToolMiddleware 调用工具前校验工具权限 拒绝的调用写入操作记录
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		caller := CallerFromContext(ctx)
+		if !allowed(caller.Claims, ToolPath(request.Params.Name)) {
+			err := errors.New("没有使用该工具的权限")
+			status := http.StatusForbidden
+			if caller.Claims == nil {
+				err, status = errors.New("未登录或非法访问"), http.StatusUnauthorized
+			}
+			audit(caller, request.Params.Name, request.GetArguments(), Route{Path: ToolPath(request.Params.Name), Method: ToolMethod}, status, "", err, 0)
+			return nil, err
+		}
+		return next(ctx, request)
+	}
+}
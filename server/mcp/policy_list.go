@@ -0,0 +1,33 @@
+package mcpTool
+
+import (
+	"context"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&GetPolicyPathByAuthorityId{})
+}
+
+// GetPolicyPathByAuthorityId 查询角色的API权限 对应 POST /casbin/getPolicyPathByAuthorityId
+type GetPolicyPathByAuthorityId struct{}
+
+func (t *GetPolicyPathByAuthorityId) New() mcp.Tool {
+	return mcp.NewTool("getPolicyPathByAuthorityId",
+		mcp.WithDescription("查询角色已授权的API路径与请求方法"),
+		mcp.WithNumber("authorityId", mcp.Required(), mcp.Description("角色ID")),
+	)
+}
+
+func (t *GetPolicyPathByAuthorityId) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/casbin/getPolicyPathByAuthorityId", Method: "POST"}, func(*systemReq.CustomClaims) (any, error) {
+		authorityId, err := request.RequireInt("authorityId")
+		if err != nil {
+			return nil, err
+		}
+		return systemService.CasbinServiceApp.GetPolicyPathByAuthorityId(uint(authorityId)), nil
+	})
+}
@@ -0,0 +1,29 @@
+package mcpTool
+
+import (
+	"context"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// prompt 校验调用方具备提示词的casbin权限后生成提示词 每次获取都写入操作记录
+func prompt(ctx context.Context, request mcp.GetPromptRequest, build func(args map[string]string) *mcp.GetPromptResult) (*mcp.GetPromptResult, error) {
+	route := Route{Path: PromptPath(request.Params.Name), Method: ToolMethod}
+	var result *mcp.GetPromptResult
+	_, err := guard(ctx, request.Params.Name, request.Params.Arguments, route, []Route{route}, func(*systemReq.CustomClaims) (any, error) {
+		result = build(request.Params.Arguments)
+		return result.Description, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// userPrompt 单条用户消息的提示词
+func userPrompt(description, text string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	})
+}
@@ -0,0 +1,113 @@
+package mcpTool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterPrompt(&DesignModule{})
+	RegisterPrompt(&ReviewAuthority{})
+	RegisterPrompt(&ExplainRoute{})
+}
+
+// DesignModule 按业务需求设计代码生成器的模块配置
+type DesignModule struct{}
+
+func (p *DesignModule) New() mcp.Prompt {
+	return mcp.NewPrompt("designModule",
+		mcp.WithPromptDescription("按业务需求设计一个可直接用于代码生成器的模块 复用已有的包、表结构与字典"),
+		mcp.WithArgument("name", mcp.RequiredArgument(), mcp.ArgumentDescription("模块中文名称 如 客户管理")),
+		mcp.WithArgument("requirement", mcp.RequiredArgument(), mcp.ArgumentDescription("业务需求描述 需要记录哪些信息、如何查询")),
+		mcp.WithArgument("package", mcp.ArgumentDescription("放入的包名 为空时由你建议")),
+		mcp.WithArgument("businessDB", mcp.ArgumentDescription("业务库别名 为空时使用主库")),
+	)
+}
+
+func (p *DesignModule) Handle(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return prompt(ctx, request, func(args map[string]string) *mcp.GetPromptResult {
+		var b strings.Builder
+		fmt.Fprintf(&b, "请为 gin-vue-admin 设计「%s」模块。\n\n业务需求：\n%s\n\n", args["name"], args["requirement"])
+		b.WriteString("设计前请先读取以下资源：\n")
+		b.WriteString("1. gva://packages 了解已有的包与插件\n")
+		if args["package"] != "" {
+			fmt.Fprintf(&b, "2. gva://packages/%s/schema 了解包内已有的表 避免重复建表 并沿用其字段命名风格\n", args["package"])
+		} else {
+			b.WriteString("2. 从已有的包中选择最合适的一个 读取 gva://packages/{package}/schema 了解其表结构\n")
+		}
+		b.WriteString("3. gva://dictionaries 对状态、类型等枚举字段优先复用已有字典 没有合适的字典时说明需要新建的字典及字典项\n")
+		if args["businessDB"] != "" {
+			fmt.Fprintf(&b, "\n数据表建在业务库 %s 中。\n", args["businessDB"])
+		}
+		b.WriteString(`
+请输出：
+- 一段代码生成器的 JSON 配置 字段与代码生成器的请求一致：package、tableName、businessDB、structName、packageName、description、abbreviation、gvaModel、autoMigrate、autoCreateApiToSql、autoCreateMenuToSql、generateWeb、fields
+- fields 中每个字段给出 fieldName、fieldDesc、fieldType、fieldJson、columnName、dataTypeLong、comment、fieldSearchType、dictType、form、table、desc、require
+- 需要关联其他表时说明关联表与关联字段
+- 需要新建的字典及字典项
+- 设计取舍的简要说明
+`)
+		return userPrompt("设计"+args["name"]+"模块", b.String())
+	})
+}
+
+// ReviewAuthority 审查角色的API权限
+type ReviewAuthority struct{}
+
+func (p *ReviewAuthority) New() mcp.Prompt {
+	return mcp.NewPrompt("reviewAuthority",
+		mcp.WithPromptDescription("审查角色的API权限是否符合最小权限原则"),
+		mcp.WithArgument("authorityId", mcp.RequiredArgument(), mcp.ArgumentDescription("角色ID 如 888")),
+		mcp.WithArgument("duty", mcp.ArgumentDescription("该角色的职责 用于判断权限是否多余")),
+	)
+}
+
+func (p *ReviewAuthority) Handle(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return prompt(ctx, request, func(args map[string]string) *mcp.GetPromptResult {
+		var b strings.Builder
+		fmt.Fprintf(&b, "请审查角色 %s 的API权限。\n", args["authorityId"])
+		if args["duty"] != "" {
+			fmt.Fprintf(&b, "该角色的职责：%s\n", args["duty"])
+		}
+		fmt.Fprintf(&b, `
+步骤：
+1. 调用 getAuthorityList 确认角色名称与父角色
+2. 调用 getPolicyPathByAuthorityId 获取角色 %s 的全部API权限
+3. 读取 gva://routes 对照每条权限的分组与描述 找出已不存在的路由
+4. 对删除、授权、用户管理、插件安装等高危接口逐条说明是否必要
+
+请按「保留 / 建议移除 / 路由已失效」分组输出表格 每行给出请求方法、路径、描述与理由。
+`, args["authorityId"])
+		return userPrompt("审查角色"+args["authorityId"]+"的API权限", b.String())
+	})
+}
+
+// ExplainRoute 解释一个路由的处理函数、权限与近期调用
+type ExplainRoute struct{}
+
+func (p *ExplainRoute) New() mcp.Prompt {
+	return mcp.NewPrompt("explainRoute",
+		mcp.WithPromptDescription("解释一个路由的处理函数、哪些角色可以访问以及近期的调用情况"),
+		mcp.WithArgument("path", mcp.RequiredArgument(), mcp.ArgumentDescription("路由路径 如 /user/getUserList")),
+		mcp.WithArgument("method", mcp.ArgumentDescription("请求方法 为空时包含该路径的全部方法")),
+	)
+}
+
+func (p *ExplainRoute) Handle(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return prompt(ctx, request, func(args map[string]string) *mcp.GetPromptResult {
+		route := strings.TrimSpace(strings.ToUpper(args["method"]) + " " + args["path"])
+		text := fmt.Sprintf(`请解释路由 %s。
+
+步骤：
+1. 读取 gva://routes 找到该路由的处理函数、API分组与描述
+2. 调用 getAuthorityList 与 getPolicyPathByAuthorityId 列出可以访问该路由的角色
+3. 调用 getOperationRecordList 按路径查询近期调用 统计状态码与出错信息
+
+请输出：处理函数与用途、可访问的角色、近期调用概况 以及发现的异常。
+`, route)
+		return userPrompt("解释路由"+route, text)
+	})
+}
@@ -0,0 +1,35 @@
+package mcpTool
+
+import (
+	"context"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// read 以调用方身份读取资源 需要资源本身与数据对应路由的casbin权限 内容以JSON返回 每次读取都写入操作记录
+func read(ctx context.Context, request mcp.ReadResourceRequest, name string, route Route, fn func(claims *systemReq.CustomClaims) (any, error)) ([]mcp.ResourceContents, error) {
+	permission := Route{Path: ResourcePath(name), Method: ToolMethod}
+	text, err := guard(ctx, request.Params.URI, request.Params.Arguments, permission, []Route{permission, route}, fn)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      request.Params.URI,
+		MIMEType: "application/json",
+		Text:     text,
+	}}, nil
+}
+
+// templateArg 读取URI模板变量 模板匹配结果为字符串切片
+func templateArg(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case string:
+		return value
+	case []string:
+		if len(value) > 0 {
+			return value[0]
+		}
+	}
+	return ""
+}
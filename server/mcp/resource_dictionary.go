@@ -0,0 +1,51 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterResource(&DictionaryListing{})
+	RegisterResourceTemplate(&DictionaryContent{})
+}
+
+// DictionaryListing 全部字典 数据对应 GET /sysDictionary/getSysDictionaryList
+type DictionaryListing struct{}
+
+func (r *DictionaryListing) New() mcp.Resource {
+	return mcp.NewResource("gva://dictionaries", "dictionaries",
+		mcp.WithResourceDescription("全部字典的类型、名称与状态 字典详情通过 gva://dictionaries/{type} 读取"),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+func (r *DictionaryListing) Handle(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return read(ctx, request, "dictionaries", Route{Path: "/sysDictionary/getSysDictionaryList", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		return systemService.DictionaryServiceApp.GetSysDictionaryInfoList()
+	})
+}
+
+// DictionaryContent 按字典类型读取字典及字典详情 数据对应 GET /sysDictionary/findSysDictionary
+type DictionaryContent struct{}
+
+func (r *DictionaryContent) New() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate("gva://dictionaries/{type}", "dictionary",
+		mcp.WithTemplateDescription("已启用字典的全部字典详情 type为字典类型 如 gender"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+}
+
+func (r *DictionaryContent) Handle(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return read(ctx, request, "dictionary", Route{Path: "/sysDictionary/findSysDictionary", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		dictionaryType := templateArg(request, "type")
+		if dictionaryType == "" {
+			return nil, errors.New("参数错误：type 不能为空")
+		}
+		return systemService.DictionaryServiceApp.GetSysDictionary(dictionaryType, 0, nil)
+	})
+}
@@ -0,0 +1,51 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterResource(&PackageListing{})
+	RegisterResourceTemplate(&PackageSchema{})
+}
+
+// PackageListing 代码生成器的包与插件 数据对应 POST /autoCode/getPackage
+type PackageListing struct{}
+
+func (r *PackageListing) New() mcp.Resource {
+	return mcp.NewResource("gva://packages", "packages",
+		mcp.WithResourceDescription("代码生成器中的包与插件 包内的表结构通过 gva://packages/{package}/schema 读取"),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+func (r *PackageListing) Handle(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return read(ctx, request, "packages", Route{Path: "/autoCode/getPackage", Method: "POST"}, func(*systemReq.CustomClaims) (any, error) {
+		return systemService.AutoCodePackage.All(ctx)
+	})
+}
+
+// PackageSchema 包内由代码生成器创建的表结构 数据对应 GET /autoCode/getColumn
+type PackageSchema struct{}
+
+func (r *PackageSchema) New() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate("gva://packages/{package}/schema", "packageSchema",
+		mcp.WithTemplateDescription("包内由代码生成器创建的数据表 含结构体名称、业务库与字段的类型、长度、注释和主键"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+}
+
+func (r *PackageSchema) Handle(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return read(ctx, request, "packageSchema", Route{Path: "/autoCode/getColumn", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		packageName := templateArg(request, "package")
+		if packageName == "" {
+			return nil, errors.New("参数错误：package 不能为空")
+		}
+		return systemService.AutoCodePackage.Schema(ctx, packageName)
+	})
+}
@@ -0,0 +1,29 @@
+package mcpTool
+
+import (
+	"context"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterResource(&PluginListing{})
+}
+
+// PluginListing 已安装插件及版本、依赖与启用状态 数据对应 GET /autoCode/getPluginList
+type PluginListing struct{}
+
+func (r *PluginListing) New() mcp.Resource {
+	return mcp.NewResource("gva://plugins", "plugins",
+		mcp.WithResourceDescription("已安装的插件 含版本、依赖、启用状态与安装状态"),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+func (r *PluginListing) Handle(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return read(ctx, request, "plugins", Route{Path: "/autoCode/getPluginList", Method: "GET"}, func(*systemReq.CustomClaims) (any, error) {
+		return systemService.PluginServiceApp.GetPluginList(ctx)
+	})
+}
@@ -0,0 +1,56 @@
+package mcpTool
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterResource(&RouteCatalogue{})
+}
+
+// RouteCatalogue 路由目录 来自GVA_ROUTERS 附带API管理中登记的分组与描述 数据对应 POST /api/getAllApis
+type RouteCatalogue struct{}
+
+// RouteCatalogueItem 路由目录中的一条路由
+type RouteCatalogueItem struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Handler     string `json:"handler"`
+	ApiGroup    string `json:"apiGroup,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (r *RouteCatalogue) New() mcp.Resource {
+	return mcp.NewResource("gva://routes", "routes",
+		mcp.WithResourceDescription("系统注册的全部路由 含请求方法、路径、处理函数以及API管理中登记的分组与描述"),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+func (r *RouteCatalogue) Handle(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return read(ctx, request, "routes", Route{Path: "/api/getAllApis", Method: "POST"}, func(claims *systemReq.CustomClaims) (any, error) {
+		apis, err := systemService.ApiServiceApp.GetAllApis(claims.AuthorityId)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]RouteCatalogueItem, 0, len(global.GVA_ROUTERS))
+		for _, route := range global.GVA_ROUTERS {
+			item := RouteCatalogueItem{Method: route.Method, Path: route.Path, Handler: route.Handler}
+			path := strings.TrimPrefix(route.Path, global.GVA_CONFIG.System.RouterPrefix)
+			for _, api := range apis {
+				if api.Method == route.Method && (api.Path == route.Path || api.Path == path) {
+					item.ApiGroup, item.Description = api.ApiGroup, api.Description
+					break
+				}
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	})
+}
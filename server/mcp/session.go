@@ -0,0 +1,43 @@
+package mcpTool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sessionOwner SSE会话的所有者 消息端点只按sessionId路由 需要由传输层校验归属
+type sessionOwner struct {
+	UserID   uint
+	TokenKey string
+}
+
+var sessionOwners sync.Map // sessionId -> sessionOwner
+
+// Hooks 服务端钩子 过滤资源与提示词列表 并在SSE连接建立时登记会话所有者
+func Hooks() *server.Hooks {
+	hooks := ListHooks()
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		caller := CallerFromContext(ctx)
+		if caller.Claims == nil {
+			return
+		}
+		sessionOwners.Store(session.SessionID(), sessionOwner{UserID: caller.Claims.BaseClaims.ID, TokenKey: caller.TokenKey})
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		sessionOwners.Delete(session.SessionID())
+	})
+	return hooks
+}
+
+// SessionOwnedBy 会话是否由调用方建立 必须是同一用户的同一令牌
+// 未登记的会话返回true 由mcp-go按无效会话处理
+func SessionOwnedBy(sessionID string, caller Caller) bool {
+	value, ok := sessionOwners.Load(sessionID)
+	if !ok {
+		return true
+	}
+	owner := value.(sessionOwner)
+	return caller.Claims != nil && owner.UserID == caller.Claims.BaseClaims.ID && owner.TokenKey == caller.TokenKey
+}
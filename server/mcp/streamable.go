@@ -0,0 +1,73 @@
+package mcpTool
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// 单次请求体的最大长度
+const streamableMaxBody = 4 << 20
+
+// StreamableHTTPServer 无状态的Streamable HTTP传输
+// 每个POST请求携带一条或一批JSON-RPC消息 响应直接以JSON返回 不建立会话 也不提供GET推送流
+type StreamableHTTPServer struct {
+	server *server.MCPServer
+}
+
+func NewStreamableHTTPServer(mcpServer *server.MCPServer) *StreamableHTTPServer {
+	return &StreamableHTTPServer{server: mcpServer}
+}
+
+func (s *StreamableHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		// 规范允许服务端不提供GET推送流 此时返回405
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, streamableMaxBody))
+	if err != nil {
+		writeJSONRPC(w, http.StatusBadRequest, mcp.NewJSONRPCError(mcp.RequestId{}, mcp.PARSE_ERROR, "读取请求失败: "+err.Error(), nil))
+		return
+	}
+	ctx := ContextFunc(r.Context(), r)
+
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		if response := s.server.HandleMessage(ctx, body); response != nil {
+			writeJSONRPC(w, http.StatusOK, response)
+			return
+		}
+		// 通知与响应没有返回值
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var messages []json.RawMessage
+	if err = json.Unmarshal(body, &messages); err != nil {
+		writeJSONRPC(w, http.StatusBadRequest, mcp.NewJSONRPCError(mcp.RequestId{}, mcp.PARSE_ERROR, "Parse error", nil))
+		return
+	}
+	responses := make([]mcp.JSONRPCMessage, 0, len(messages))
+	for _, message := range messages {
+		if response := s.server.HandleMessage(ctx, message); response != nil {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeJSONRPC(w, http.StatusOK, responses)
+}
+
+func writeJSONRPC(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,109 @@
+package mcpTool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newTestMCPServer 注册两个资源、两个资源模板与两个提示词 角色888只被授权其中的allowed
+func newTestMCPServer(t *testing.T) *StreamableHTTPServer {
+	t.Helper()
+	s := server.NewMCPServer("gva-test", "1.0.0",
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithHooks(Hooks()),
+	)
+	readResource := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	}
+	getPrompt := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return userPrompt("test", "test"), nil
+	}
+	for _, name := range []string{"allowed", "denied"} {
+		s.AddResource(mcp.NewResource("gva://"+name, name), readResource)
+		s.AddResourceTemplate(mcp.NewResourceTemplate("gva://"+name+"/{id}", name+"Template"), readResource)
+		s.AddPrompt(mcp.NewPrompt(name), getPrompt)
+	}
+	grantMcp(t, "888", ResourcePath("allowed"), ToolMethod)
+	grantMcp(t, "888", ResourcePath("allowedTemplate"), ToolMethod)
+	grantMcp(t, "888", PromptPath("allowed"), ToolMethod)
+	return NewStreamableHTTPServer(s)
+}
+
+func TestStreamableHTTPServer(t *testing.T) {
+	setupMcpTestDB(t)
+	h := newTestMCPServer(t)
+	caller := Caller{Claims: &systemReq.CustomClaims{BaseClaims: systemReq.BaseClaims{ID: 1, AuthorityId: 888}}}
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		caller   Caller
+		wantCode int
+		wantIds  []float64 // 批量响应中的请求id 按顺序
+		want     string    // 响应中应包含的内容
+		notWant  string    // 响应中不应包含的内容
+	}{
+		{name: "不支持GET推送流", method: http.MethodGet, wantCode: http.StatusMethodNotAllowed},
+		{name: "单条请求", method: http.MethodPost, body: `{"jsonrpc":"2.0","id":1,"method":"ping"}`, wantCode: http.StatusOK, want: `"id":1`},
+		{name: "单条通知没有响应", method: http.MethodPost, body: `{"jsonrpc":"2.0","method":"notifications/initialized"}`, wantCode: http.StatusAccepted},
+		{name: "批量请求跳过通知的响应", method: http.MethodPost, body: `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"ping"}]`,
+			wantCode: http.StatusOK, wantIds: []float64{1, 2}},
+		{name: "批量请求全部为通知", method: http.MethodPost, body: `[{"jsonrpc":"2.0","method":"notifications/initialized"}]`, wantCode: http.StatusAccepted},
+		{name: "批量请求中的错误单独返回", method: http.MethodPost, body: `[{"jsonrpc":"2.0","id":1,"method":"unknown"},{"jsonrpc":"2.0","id":2,"method":"ping"}]`,
+			wantCode: http.StatusOK, wantIds: []float64{1, 2}, want: `"error"`},
+		{name: "批量请求格式错误", method: http.MethodPost, body: `[{"jsonrpc":"2.0"`, wantCode: http.StatusBadRequest, want: "Parse error"},
+		{name: "资源列表只返回授权的资源", method: http.MethodPost, caller: caller, body: `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+			wantCode: http.StatusOK, want: `"gva://allowed"`, notWant: `"gva://denied"`},
+		{name: "资源模板列表只返回授权的模板", method: http.MethodPost, caller: caller, body: `{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`,
+			wantCode: http.StatusOK, want: `"allowedTemplate"`, notWant: `"deniedTemplate"`},
+		{name: "提示词列表只返回授权的提示词", method: http.MethodPost, caller: caller, body: `{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`,
+			wantCode: http.StatusOK, want: `"allowed"`, notWant: `"denied"`},
+		{name: "未登录时列表为空", method: http.MethodPost, body: `{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`,
+			wantCode: http.StatusOK, want: `"prompts":[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/mcp", strings.NewReader(tt.body))
+			r = r.WithContext(WithCaller(r.Context(), tt.caller)) // 与McpAuth一致 由传输层写入调用方
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.wantCode {
+				t.Fatalf("状态码 = %d, 期望 %d, body: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			body := w.Body.String()
+			if tt.wantCode == http.StatusAccepted && body != "" {
+				t.Errorf("通知不应返回内容: %s", body)
+			}
+			if tt.want != "" && !strings.Contains(body, tt.want) {
+				t.Errorf("响应 %s 中没有 %s", body, tt.want)
+			}
+			if tt.notWant != "" && strings.Contains(body, tt.notWant) {
+				t.Errorf("响应 %s 中不应有 %s", body, tt.notWant)
+			}
+			if tt.wantIds == nil {
+				return
+			}
+			var responses []map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+				t.Fatalf("批量响应不是数组: %v, body: %s", err, body)
+			}
+			if len(responses) != len(tt.wantIds) {
+				t.Fatalf("批量响应数 = %d, 期望 %d", len(responses), len(tt.wantIds))
+			}
+			for i, response := range responses {
+				if response["id"] != tt.wantIds[i] {
+					t.Errorf("第%d条响应id = %v, 期望 %v", i, response["id"], tt.wantIds[i])
+				}
+			}
+		})
+	}
+}
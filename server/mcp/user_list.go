@@ -0,0 +1,44 @@
+package mcpTool
+
+import (
+	"context"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&GetUserList{})
+}
+
+// GetUserList 分页查询用户 对应 POST /user/getUserList
+type GetUserList struct{}
+
+func (t *GetUserList) New() mcp.Tool {
+	return mcp.NewTool("getUserList",
+		mcp.WithDescription("分页查询系统用户 可按用户名/昵称/手机号/邮箱模糊筛选"),
+		mcp.WithNumber("page", mcp.Description("页码 默认1")),
+		mcp.WithNumber("pageSize", mcp.Description("每页条数 默认10 最大100")),
+		mcp.WithString("username", mcp.Description("用户名")),
+		mcp.WithString("nickName", mcp.Description("昵称")),
+		mcp.WithString("phone", mcp.Description("手机号")),
+		mcp.WithString("email", mcp.Description("邮箱")),
+	)
+}
+
+func (t *GetUserList) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return invoke(ctx, request, Route{Path: "/user/getUserList", Method: "POST"}, func(*systemReq.CustomClaims) (any, error) {
+		var info systemReq.GetUserList
+		if err := request.BindArguments(&info); err != nil {
+			return nil, err
+		}
+		pageDefault(&info.PageInfo)
+		list, total, err := systemService.UserServiceApp.GetUserInfoList(info)
+		if err != nil {
+			return nil, err
+		}
+		return response.PageResult{List: list, Total: total, Page: info.Page, PageSize: info.PageSize}, nil
+	})
+}
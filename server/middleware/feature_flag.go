@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureFlag 按功能开关控制路由组 对当前用户未开启时拒绝访问
+// 需放在 JWTAuth 之后才能按用户和角色定向
+func FeatureFlag(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !systemService.FeatureFlagServiceApp.IsEnabled(key, utils.FeatureSubject(c)) {
+			response.FailWithDetailed(gin.H{"flag": key}, "功能未开启", c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
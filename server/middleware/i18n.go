@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// I18n 确定请求语言 已登录用户优先使用个人配置中的 locale 其次使用 Accept-Language
+// 需放在 JWTAuth 之后才能读取到用户
+func I18n() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var userID uint
+		if _, ok := c.Get("claims"); ok {
+			userID = utils.GetUserID(c)
+		}
+		locale := systemService.I18nServiceApp.ResolveLocale(userID, c.GetHeader("Accept-Language"))
+		c.Set(i18n.ContextKey, locale)
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
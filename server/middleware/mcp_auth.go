@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	mcpTool "github.com/flipped-aurora/gin-vue-admin/server/mcp"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/gin-gonic/gin"
+)
+
+// McpAuth MCP传输层鉴权 SSE与消息端点都要求携带JWT或个人访问令牌 消息请求按令牌每分钟限流
+// 消息请求只能发往调用方自己建立的SSE会话 客户端IP使用gin按受信任代理解析的结果
+func McpAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller := mcpTool.Authenticate(c.Request, c.ClientIP())
+		if caller.Claims == nil {
+			response.NoAuth(caller.AuthErr, c)
+			c.Abort()
+			return
+		}
+		if sessionID := c.Query("sessionId"); sessionID != "" && !mcpTool.SessionOwnedBy(sessionID, caller) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"code": response.ERROR, "msg": "无权访问该会话"})
+			return
+		}
+		if c.Request.Method == http.MethodPost && caller.RateLimit > 0 {
+			if err := mcpRateLimit(caller.TokenKey, caller.RateLimit); err != nil {
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"code": response.ERROR, "msg": err.Error()})
+				return
+			}
+		}
+		c.Request = c.Request.WithContext(mcpTool.WithCaller(c.Request.Context(), caller))
+		c.Next()
+	}
+}
+
+// mcpRateLimit 开启redis时多实例共享计数 否则在本实例内计数
+func mcpRateLimit(key string, limit int) error {
+	if global.GVA_REDIS != nil {
+		return SetLimitWithTime("GVA_MCP_Limit:"+key, limit, time.Minute)
+	}
+	return mcpLimiter.allow(key, limit)
+}
+
+var mcpLimiter = &windowLimiter{counts: make(map[string]int)}
+
+// windowLimiter 按分钟的固定窗口计数
+type windowLimiter struct {
+	mu     sync.Mutex
+	window int64
+	counts map[string]int
+}
+
+func (l *windowLimiter) allow(key string, limit int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if window := now.Unix() / 60; window != l.window {
+		l.window = window
+		l.counts = make(map[string]int)
+	}
+	if l.counts[key] >= limit {
+		return errors.New("请求太过频繁, 请 " + (time.Duration(60-now.Second()) * time.Second).String() + " 后尝试")
+	}
+	l.counts[key]++
+	return nil
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	mcpTool "github.com/flipped-aurora/gin-vue-admin/server/mcp"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/songzhibin97/gkit/cache/local_cache"
+)
+
+func TestWindowLimiter(t *testing.T) {
+	l := &windowLimiter{counts: make(map[string]int)}
+	// 各步骤按顺序执行 共用同一个计数器
+	tests := []struct {
+		name      string
+		key       string
+		limit     int
+		newWindow bool // 本步骤之前进入下一分钟
+		wantErr   bool
+	}{
+		{name: "首次请求", key: "pat:1", limit: 2},
+		{name: "未超过上限", key: "pat:1", limit: 2},
+		{name: "超过上限", key: "pat:1", limit: 2, wantErr: true},
+		{name: "不同令牌分别计数", key: "jwt:a", limit: 2},
+		{name: "下一分钟重新计数", key: "pat:1", limit: 2, newWindow: true},
+		{name: "上限为1", key: "pat:2", limit: 1},
+		{name: "上限为1时第二次拒绝", key: "pat:2", limit: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.newWindow {
+				l.window-- // 模拟窗口过期
+			}
+			err := l.allow(tt.key, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("allow(%s, %d) error = %v, wantErr %v", tt.key, tt.limit, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// setupMcpAuth 使用测试签名密钥、黑名单与限流计数器 测试结束后恢复
+func setupMcpAuth(t *testing.T, rateLimit int) {
+	t.Helper()
+	setupMiddlewareTestDB(t)
+	oldJWT, oldRateLimit, oldCache := global.GVA_CONFIG.JWT, global.GVA_CONFIG.MCP.RateLimit, global.BlackCache
+	global.GVA_CONFIG.JWT.SigningKey, global.GVA_CONFIG.JWT.ExpiresTime, global.GVA_CONFIG.JWT.BufferTime = "mcp-test", "1d", "1d"
+	global.GVA_CONFIG.MCP.RateLimit = rateLimit
+	global.BlackCache = local_cache.NewCache()
+	oldLimiter := mcpLimiter
+	mcpLimiter = &windowLimiter{counts: make(map[string]int)}
+	t.Cleanup(func() {
+		global.GVA_CONFIG.JWT, global.GVA_CONFIG.MCP.RateLimit, global.BlackCache = oldJWT, oldRateLimit, oldCache
+		mcpLimiter = oldLimiter
+	})
+}
+
+// mcpToken 签发测试用JWT 过期时间不同的令牌互不相同
+func mcpToken(t *testing.T, userID uint, expiresAt time.Time) string {
+	t.Helper()
+	j := utils.NewJWT()
+	claims := j.CreateClaims(systemReq.BaseClaims{ID: userID, Username: "admin", AuthorityId: 888})
+	claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	token, err := j.CreateToken(claims)
+	if err != nil {
+		t.Fatalf("签发JWT失败: %v", err)
+	}
+	return token
+}
+
+func TestMcpAuth(t *testing.T) {
+	setupMcpAuth(t, 2)
+	token := mcpToken(t, 1, time.Now().Add(time.Hour))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	_ = r.SetTrustedProxies(nil) // 不信任代理 X-Forwarded-For 不生效
+	var ip string
+	r.Any("/mcp", McpAuth(), func(c *gin.Context) {
+		ip = mcpTool.CallerFromContext(c.Request.Context()).IP
+		c.String(http.StatusOK, "ok")
+	})
+	tests := []struct {
+		name     string
+		method   string
+		token    string
+		wantCode int
+	}{
+		{name: "未携带令牌", method: http.MethodPost, wantCode: http.StatusUnauthorized},
+		{name: "SSE连接不计入限流", method: http.MethodGet, token: token, wantCode: http.StatusOK},
+		{name: "第一条消息", method: http.MethodPost, token: token, wantCode: http.StatusOK},
+		{name: "第二条消息", method: http.MethodPost, token: token, wantCode: http.StatusOK},
+		{name: "超过每分钟上限", method: http.MethodPost, token: token, wantCode: http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip = ""
+			req := httptest.NewRequest(tt.method, "/mcp", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.Header.Set("X-Forwarded-For", "1.2.3.4")
+			if tt.token != "" {
+				req.Header.Set("x-token", tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Errorf("%s /mcp 状态码 = %d, 期望 %d, body: %s", tt.method, w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode == http.StatusOK && ip != "10.0.0.1" {
+				t.Errorf("调用方IP = %s, 期望gin解析的 10.0.0.1", ip)
+			}
+		})
+	}
+}
+
+func TestMcpAuth_SessionOwner(t *testing.T) {
+	setupMcpAuth(t, 0)
+	owner := mcpToken(t, 1, time.Now().Add(time.Hour))
+	s := server.NewMCPServer("gva-test", "1.0.0", server.WithHooks(mcpTool.Hooks()))
+	sse := server.NewSSEServer(s, server.WithHTTPContextFunc(mcpTool.ContextFunc))
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/sse", McpAuth(), func(c *gin.Context) { sse.SSEHandler().ServeHTTP(c.Writer, c.Request) })
+	r.POST("/message", McpAuth(), func(c *gin.Context) { sse.MessageHandler().ServeHTTP(c.Writer, c.Request) })
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	// 建立SSE连接 从endpoint事件中取得消息端点
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/sse", nil)
+	req.Header.Set("x-token", owner)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("建立SSE连接失败: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	var endpoint string
+	scanner := bufio.NewScanner(resp.Body)
+	for endpoint == "" && scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			endpoint = data
+		}
+	}
+	if !strings.Contains(endpoint, "sessionId=") {
+		t.Fatalf("未收到消息端点: %s", endpoint)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{name: "其他用户", token: mcpToken(t, 2, time.Now().Add(time.Hour)), wantCode: http.StatusForbidden},
+		{name: "同一用户的其他令牌", token: mcpToken(t, 1, time.Now().Add(2*time.Hour)), wantCode: http.StatusForbidden},
+		{name: "会话所有者", token: owner, wantCode: http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, ts.URL+endpoint, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			req.Header.Set("x-token", tt.token)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("发送消息失败: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("POST %s 状态码 = %d, 期望 %d", endpoint, resp.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/gin-gonic/gin"
+)
+
+// PluginGate 插件路由组网关 插件停用后返回503 启停无需重启服务
+// 需放在插件路由组的第一个中间件 停用的插件不再进行鉴权等后续处理
+func PluginGate(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !systemService.PluginServiceApp.IsEnabled(name) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, response.Response{
+				Code: response.ERROR,
+				Data: gin.H{"plugin": name},
+				Msg:  "插件已停用",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemService "github.com/flipped-aurora/gin-vue-admin/server/service/system"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// setupMiddlewareTestDB 使用内存sqlite作为中间件测试的数据库
+func setupMiddlewareTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取数据库连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 内存库只在同一连接内可见
+	if err = db.AutoMigrate(models...); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+	oldDB, oldLog := global.GVA_DB, global.GVA_LOG
+	global.GVA_DB, global.GVA_LOG = db, zap.NewNop()
+	t.Cleanup(func() {
+		global.GVA_DB, global.GVA_LOG = oldDB, oldLog
+		_ = sqlDB.Close()
+	})
+	return db
+}
+
+func TestPluginGate(t *testing.T) {
+	db := setupMiddlewareTestDB(t, &system.SysPlugin{})
+	plugins := []system.SysPlugin{
+		{Name: "base", State: system.SysPluginStateInstalled},
+		{Name: "child", State: system.SysPluginStateInstalled, Dependencies: map[string]string{"base": "^1.0.0"}},
+	}
+	if err := db.Create(&plugins).Error; err != nil {
+		t.Fatalf("准备数据失败: %v", err)
+	}
+	// 状态缓存是进程级的 从测试数据库重新加载 结束后丢弃测试数据
+	systemService.PluginServiceApp.ReloadPluginStates()
+	t.Cleanup(systemService.PluginServiceApp.ReloadPluginStates)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	for _, name := range []string{"base", "child", "legacy"} {
+		r.GET("/"+name, PluginGate(name), func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	}
+	// 启停通过服务进行 网关读取的是服务维护的状态缓存
+	tests := []struct {
+		name     string
+		disable  []string // 本步骤之前依次停用的插件
+		plugin   string
+		wantCode int
+	}{
+		{name: "启用中的插件放行", plugin: "base", wantCode: http.StatusOK},
+		{name: "未登记的旧版插件放行", plugin: "legacy", wantCode: http.StatusOK},
+		{name: "停用的插件返回503", disable: []string{"child"}, plugin: "child", wantCode: http.StatusServiceUnavailable},
+		{name: "依赖方停用后停用依赖", disable: []string{"base"}, plugin: "base", wantCode: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range tt.disable {
+				if err := systemService.PluginServiceApp.SetPluginEnabled(context.Background(), name, false); err != nil {
+					t.Fatalf("停用插件[%s]失败: %v", name, err)
+				}
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+tt.plugin, nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("GET /%s 状态码 = %d, 期望 %d, body: %s", tt.plugin, w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
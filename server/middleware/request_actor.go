@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/versioning"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+// RequestActor 为请求分配请求ID 并将当前用户写入请求上下文
+// service 通过 global.GVA_DB.WithContext(c.Request.Context()) 写入时 变更历史会记录操作人和请求ID
+func RequestActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = uuid.NewString()
+		}
+		c.Header(requestIDHeader, requestID)
+		actor := versioning.Actor{RequestID: requestID}
+		if claims := utils.GetUserInfo(c); claims != nil {
+			actor.UserID = claims.BaseClaims.ID
+			actor.Username = claims.Username
+		}
+		c.Request = c.Request.WithContext(versioning.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
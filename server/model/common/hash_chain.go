@@ -0,0 +1,12 @@
+package common
+
+// HashChain 防篡改哈希链字段 嵌入到需要审计的模型中
+type HashChain struct {
+	PrevHash string `json:"prev_hash" gorm:"size:64;comment:上一行哈希"`     // 上一行哈希
+	RowHash  string `json:"row_hash" gorm:"size:64;index;comment:本行哈希"` // 本行哈希 sha256(prev_hash|内容)
+}
+
+func (h *HashChain) SetChain(prevHash, rowHash string) {
+	h.PrevHash = prevHash
+	h.RowHash = rowHash
+}
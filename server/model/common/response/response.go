@@ -3,6 +3,7 @@ package response
 import (
 	"net/http"
 
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/i18n"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,7 +23,7 @@ func Result(code int, data interface{}, msg string, c *gin.Context) {
 	c.JSON(http.StatusOK, Response{
 		code,
 		data,
-		msg,
+		i18n.Translate(c, msg),
 	})
 }
 
@@ -54,7 +55,7 @@ func NoAuth(message string, c *gin.Context) {
 	c.JSON(http.StatusUnauthorized, Response{
 		7,
 		nil,
-		message,
+		i18n.Translate(c, message),
 	})
 }
 
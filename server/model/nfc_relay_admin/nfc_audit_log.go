@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
 )
 
 // NfcAuditLog NFC中继系统审计日志表
@@ -27,6 +28,33 @@ type NfcAuditLog struct {
 	ServerID          string    `json:"server_id" gorm:"size:64;comment:服务器ID"`                      // 服务器ID
 	RequestID         string    `json:"request_id" gorm:"index;size:64;comment:请求ID"`                // 请求ID
 	EventTime         time.Time `json:"event_time" gorm:"index;not null;comment:事件发生时间"`             // 事件发生时间
+	common.HashChain
+}
+
+// ChainPayload 参与防篡改哈希链计算的字段
+// EventTime 截断到秒 避免不同数据库时间精度导致校验失败
+func (l *NfcAuditLog) ChainPayload() any {
+	l.EventTime = l.EventTime.Truncate(time.Second)
+	return map[string]any{
+		"event_type":          l.EventType,
+		"session_id":          l.SessionID,
+		"client_id_initiator": l.ClientIDInitiator,
+		"client_id_responder": l.ClientIDResponder,
+		"user_id":             l.UserID,
+		"source_ip":           l.SourceIP,
+		"user_agent":          l.UserAgent,
+		"details":             l.Details,
+		"result":              l.Result,
+		"error_message":       l.ErrorMessage,
+		"duration":            l.Duration,
+		"resource":            l.Resource,
+		"action":              l.Action,
+		"level":               l.Level,
+		"category":            l.Category,
+		"server_id":           l.ServerID,
+		"request_id":          l.RequestID,
+		"event_time":          l.EventTime.Unix(),
+	}
 }
 
 // TableName 指定表名
@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+// CreateAccessToken 创建个人访问令牌
+type CreateAccessToken struct {
+	Name      string     `json:"name" binding:"required"` // 令牌名称
+	RateLimit int        `json:"rateLimit"`               // 每分钟请求上限 0使用默认值
+	ExpiresAt *time.Time `json:"expiresAt"`               // 过期时间 为空时不过期
+}
@@ -0,0 +1,5 @@
+package request
+
+type AuditChainSearch struct {
+	Table string `json:"table" form:"table" binding:"required"` // 哈希链所属表
+}
@@ -7,6 +7,7 @@ import (
 	model "github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"github.com/pkg/errors"
 	"go/token"
+	"slices"
 	"strings"
 )
 
@@ -30,9 +31,14 @@ type AutoCode struct {
 	IsTree              bool                   `json:"isTree" example:"false"`              // 是否树形结构
 	TreeJson            string                 `json:"treeJson" example:"展示的树json字段"`       // 展示的树json字段
 	IsAdd               bool                   `json:"isAdd" example:"false"`               // 是否新增
+	Merge               bool                   `json:"merge" example:"false"`               // 是否重新生成并与本地修改三方合并
+	Resolutions         ConflictResolutions    `json:"resolutions"`                         // 合并冲突的处理方式 文件路径=>冲突序号=>ours/theirs/both/base
 	Fields              []*AutoCodeField       `json:"fields"`
+	Relations           []*AutoCodeRelation    `json:"relations"`
+	Variables           map[string]any         `json:"variables"`                     // 模板包声明的额外变量 模板中通过 .Variables.xxx 使用
 	GenerateWeb         bool                   `json:"generateWeb" example:"true"`    // 是否生成web
 	GenerateServer      bool                   `json:"generateServer" example:"true"` // 是否生成server
+	Mongo               bool                   `json:"mongo" example:"false"`         // 是否生成MongoDB(qmgo)代码 表名即集合名
 	Module              string                 `json:"-"`
 	DictTypes           []string               `json:"-"`
 	PrimaryField        *AutoCodeField         `json:"primaryField"`
@@ -47,6 +53,12 @@ type AutoCode struct {
 	HasSearchTimer      bool                   `json:"-"`
 	HasArray            bool                   `json:"-"`
 	HasExcel            bool                   `json:"-"`
+	HasRelation         bool                   `json:"-"`
+	HasAssociationWrite bool                   `json:"-"` // 存在需要随主记录一起写入的 hasMany/many2many 关联
+	RelationOmits       []string               `json:"-"` // 创建时不写入的 belongsTo 关联
+	RelationPackages    []string               `json:"-"` // 关联模型所在的其他包
+	HasMongoRegex       bool                   `json:"-"` // MongoDB代码存在模糊搜索
+	HasMongoUnique      bool                   `json:"-"` // MongoDB代码存在唯一索引
 }
 
 type DataSource struct {
@@ -191,6 +203,12 @@ func (r *AutoCode) Pretreatment() error {
 			}
 		}
 	} // GvaModel
+	if err := r.relationPretreatment(); err != nil {
+		return err
+	} // 关联关系
+	if err := r.mongoPretreatment(); err != nil {
+		return err
+	} // MongoDB
 	{
 		if r.IsAdd && r.PrimaryField == nil {
 			r.PrimaryField = new(AutoCodeField)
@@ -209,6 +227,153 @@ func (r *AutoCode) Pretreatment() error {
 	return nil
 }
 
+// mongoPretreatment 校验MongoDB代码不支持的选项 主键固定为_id 字段名即数据库字段名
+func (r *AutoCode) mongoPretreatment() error {
+	if !r.Mongo {
+		return nil
+	}
+	switch {
+	case r.BusinessDB != "":
+		return errors.New("MongoDB代码不支持选择业务库!")
+	case !r.GvaModel && !r.IsAdd:
+		return errors.New("MongoDB代码需要使用GVA默认结构!")
+	case r.IsTree:
+		return errors.New("MongoDB代码不支持树形结构!")
+	case r.AutoCreateResource:
+		return errors.New("MongoDB代码不支持自动创建资源标识!")
+	case len(r.Relations) > 0:
+		return errors.New("MongoDB代码不支持模型关联!")
+	case r.HasDataSource:
+		return errors.New("MongoDB代码不支持数据源!")
+	case r.HasExcel:
+		return errors.New("MongoDB代码不支持导入导出!")
+	}
+	if r.TableName == "" {
+		r.TableName = r.HumpPackageName
+	}
+	for _, field := range r.Fields {
+		if field.PrimaryKey {
+			return errors.Errorf("MongoDB代码的主键固定为_id 字段[%s]不能设为主键!", field.FieldName)
+		}
+		if field.ColumnName == "" {
+			field.ColumnName = field.FieldJson
+		}
+		if field.FieldSearchType == "LIKE" && !slices.Contains([]string{"pictures", "picture", "video", "json", "richtext", "array", "file"}, field.FieldType) {
+			r.HasMongoRegex = true
+		} // 复杂类型的搜索需自行实现
+		if field.FieldIndexType == "uniqueIndex" {
+			r.HasMongoUnique = true
+		}
+	}
+	if r.PrimaryField != nil && r.GvaModel {
+		r.PrimaryField.FieldType = "string"
+		r.PrimaryField.ColumnName = "_id"
+	}
+	return nil
+}
+
+// structFieldNames 生成的结构体中已有的字段名(含方法名)和json名 与 model.go.tpl 保持一致
+func (r *AutoCode) structFieldNames() (names map[string]bool, jsons map[string]bool) {
+	names, jsons = map[string]bool{"TableName": true}, map[string]bool{}
+	if r.GvaModel {
+		for _, name := range []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"} {
+			names[name] = true
+		}
+		jsons["ID"], jsons["CreatedAt"], jsons["UpdatedAt"] = true, true, true
+	}
+	if r.AutoCreateResource {
+		names["CreatedBy"], names["UpdatedBy"], names["DeletedBy"] = true, true, true
+		jsons["CreatedBy"], jsons["UpdatedBy"], jsons["DeletedBy"] = true, true, true
+	}
+	if r.IsTree {
+		for _, name := range []string{"Children", "ParentID", "GetChildren", "SetChildren", "GetID", "GetParentID"} {
+			names[name] = true
+		}
+		jsons["children"], jsons["parentID"] = true, true
+	}
+	for _, field := range r.Fields {
+		names[field.FieldName], jsons[field.FieldJson] = true, true
+	}
+	return names, jsons
+}
+
+// relationPretreatment 校验关联关系 belongsTo 的外键字段和 hasMany/many2many 关联字段加入数据源 用于前端选择关联数据
+func (r *AutoCode) relationPretreatment() error {
+	packages := make(map[string]bool)
+	names, jsons := r.structFieldNames()
+	for _, relation := range r.Relations {
+		if relation.FieldName == "" || !token.IsExported(relation.FieldName) {
+			return errors.Errorf("关联字段名[%s]必须以大写字母开头!", relation.FieldName)
+		}
+		if relation.StructName == "" {
+			return errors.Errorf("关联字段[%s]未指定关联模型!", relation.FieldName)
+		}
+		if relation.ForeignKey == "" {
+			return errors.Errorf("关联字段[%s]未指定外键!", relation.FieldName)
+		}
+		if relation.FieldJson == "" {
+			relation.FieldJson = strings.ToLower(relation.FieldName[:1]) + relation.FieldName[1:]
+		}
+		if names[relation.FieldName] {
+			return errors.Errorf("关联字段名[%s]与结构体中已有的字段重名!", relation.FieldName)
+		}
+		if jsons[relation.FieldJson] {
+			return errors.Errorf("关联字段[%s]的json名[%s]与已有字段重复!", relation.FieldName, relation.FieldJson)
+		}
+		names[relation.FieldName], jsons[relation.FieldJson] = true, true
+		value := relation.References
+		if value == "" {
+			value = "id"
+		}
+		label := relation.Label
+		if label == "" {
+			label = value
+		}
+		switch relation.Type {
+		case RelationBelongsTo:
+			var field *AutoCodeField
+			for i := range r.Fields {
+				if r.Fields[i].ColumnName == relation.ForeignKey {
+					field = r.Fields[i]
+				}
+			}
+			if field == nil {
+				return errors.Errorf("关联字段[%s]的外键[%s]不在字段列表中!", relation.FieldName, relation.ForeignKey)
+			}
+			if field.DataSource == nil && relation.Table != "" {
+				field.DataSource = &DataSource{DBName: r.BusinessDB, Table: relation.Table, Label: label, Value: value, Association: 1, HasDeletedAt: relation.HasDeletedAt}
+				field.CheckDataSource = true
+				r.HasDataSource = true
+				r.DataSourceMap[field.FieldJson] = field.DataSource
+			}
+			r.RelationOmits = append(r.RelationOmits, relation.FieldName)
+		case RelationHasMany, RelationMany2Many:
+			if relation.Type == RelationMany2Many && (relation.JoinTable == "" || relation.JoinForeignKey == "" || relation.JoinReferences == "") {
+				return errors.Errorf("多对多关联字段[%s]未指定中间表!", relation.FieldName)
+			}
+			if relation.Type == RelationHasMany {
+				value = "id"
+				if relation.Label == "" {
+					label = value
+				}
+			} // hasMany 选择的是关联表的记录
+			if relation.Table != "" {
+				r.HasDataSource = true
+				r.DataSourceMap[relation.FieldJson] = &DataSource{DBName: r.BusinessDB, Table: relation.Table, Label: label, Value: value, Association: 2, HasDeletedAt: relation.HasDeletedAt}
+			}
+			r.HasAssociationWrite = true
+		default:
+			return errors.Errorf("关联字段[%s]的关联类型[%s]不支持!", relation.FieldName, relation.Type)
+		}
+		if relation.Package != "" && relation.Package != r.Package && !packages[relation.Package] {
+			packages[relation.Package] = true
+			r.RelationPackages = append(r.RelationPackages, relation.Package)
+		}
+		r.HasRelation = true
+	}
+	return nil
+}
+
 func (r *AutoCode) History() SysAutoHistoryCreate {
 	bytes, _ := json.Marshal(r)
 	return SysAutoHistoryCreate{
@@ -248,6 +413,34 @@ type AutoCodeField struct {
 	FieldIndexType  string      `json:"fieldIndexType"`  // 索引类型
 }
 
+// ConflictResolutions 文件路径=>冲突序号=>处理方式
+type ConflictResolutions map[string]map[int]string
+
+const (
+	RelationBelongsTo = "belongsTo"
+	RelationHasMany   = "hasMany"
+	RelationMany2Many = "many2many"
+)
+
+// AutoCodeRelation 模型关联 外键和引用均填写数据库字段名
+type AutoCodeRelation struct {
+	Type           string `json:"type"`           // 关联类型 belongsTo hasMany many2many
+	FieldName      string `json:"fieldName"`      // 关联字段名
+	FieldJson      string `json:"fieldJson"`      // 关联字段json名
+	FieldDesc      string `json:"fieldDesc"`      // 中文名
+	Package        string `json:"package"`        // 关联模型所在包 为空时与本模块相同
+	StructName     string `json:"structName"`     // 关联模型结构体名
+	Table          string `json:"table"`          // 关联表 用于生成数据源
+	ForeignKey     string `json:"foreignKey"`     // 外键 belongsTo在本表 hasMany在关联表 many2many为本表被引用的字段
+	References     string `json:"references"`     // 被引用字段 belongsTo/many2many在关联表 hasMany在本表
+	JoinTable      string `json:"joinTable"`      // 多对多中间表
+	JoinForeignKey string `json:"joinForeignKey"` // 中间表中引用本表的字段
+	JoinReferences string `json:"joinReferences"` // 中间表中引用关联表的字段
+	Label          string `json:"label"`          // 数据源展示字段
+	Preload        bool   `json:"preload"`        // 查询时预加载
+	HasDeletedAt   bool   `json:"hasDeletedAt"`   // 关联表是否软删除
+}
+
 type AutoFunc struct {
 	Package         string `json:"package"`
 	FuncName        string `json:"funcName"`        // 方法名称
@@ -280,6 +473,25 @@ type InitApi struct {
 	APIs     []uint `json:"apis"`
 }
 
+// PubPlug 打包插件 选择为空时按插件initialize与已有数据清单中声明的菜单/API/字典/参数导出
+type PubPlug struct {
+	PlugName     string `json:"plugName" form:"plugName"` // 插件名称
+	Menus        []uint `json:"menus"`                    // 打包的菜单ID
+	Apis         []uint `json:"apis"`                     // 打包的API ID
+	Dictionaries []uint `json:"dictionaries"`             // 打包的字典ID
+	Params       []uint `json:"params"`                   // 打包的参数ID
+}
+
+type UninstallPlugin struct {
+	PlugName    string `json:"plugName"`    // 插件名称
+	DeleteTable bool   `json:"deleteTable"` // 是否删除插件的表
+}
+
+type SetPluginState struct {
+	Name    string `json:"name"`    // 插件名称
+	Enabled bool   `json:"enabled"` // 是否启用
+}
+
 type LLMAutoCode struct {
 	Prompt string `json:"prompt" form:"prompt" gorm:"column:prompt;comment:提示语;type:text;"` //提示语
 	Mode   string `json:"mode" form:"mode" gorm:"column:mode;comment:模式;type:text;"`        //模式
@@ -6,26 +6,31 @@ import (
 )
 
 type SysAutoCodePackageCreate struct {
-	Desc        string `json:"desc" example:"描述"`
-	Label       string `json:"label" example:"展示名"`
-	Template    string `json:"template"  example:"模版"`
-	PackageName string `json:"packageName" example:"包名"`
-	Module      string `json:"-" example:"模块"`
+	Desc            string `json:"desc" example:"描述"`
+	Label           string `json:"label" example:"展示名"`
+	Template        string `json:"template"  example:"模版"`
+	PackageName     string `json:"packageName" example:"包名"`
+	TemplatePack    string `json:"templatePack" example:"模板包"`      // 外部模板包 为空时使用内置模板
+	TemplateVersion string `json:"templateVersion" example:"模板包版本"` // 外部模板包版本
+	Module          string `json:"-" example:"模块"`
 }
 
 func (r *SysAutoCodePackageCreate) AutoCode() AutoCode {
 	return AutoCode{
-		Package: r.PackageName,
-		Module:  global.GVA_CONFIG.AutoCode.Module,
+		Package:     r.PackageName,
+		Module:      global.GVA_CONFIG.AutoCode.Module,
+		Description: r.Desc,
 	}
 }
 
 func (r *SysAutoCodePackageCreate) Create() model.SysAutoCodePackage {
 	return model.SysAutoCodePackage{
-		Desc:        r.Desc,
-		Label:       r.Label,
-		Template:    r.Template,
-		PackageName: r.PackageName,
-		Module:      global.GVA_CONFIG.AutoCode.Module,
+		Desc:            r.Desc,
+		Label:           r.Label,
+		Template:        r.Template,
+		PackageName:     r.PackageName,
+		TemplatePack:    r.TemplatePack,
+		TemplateVersion: r.TemplateVersion,
+		Module:          global.GVA_CONFIG.AutoCode.Module,
 	}
 }
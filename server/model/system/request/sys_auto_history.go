@@ -6,17 +6,18 @@ import (
 )
 
 type SysAutoHistoryCreate struct {
-	Table            string            // 表名
-	Package          string            // 模块名/插件名
-	Request          string            // 前端传入的结构化信息
-	StructName       string            // 结构体名称
-	BusinessDB       string            // 业务库
-	Description      string            // Struct中文名称
-	Injections       map[string]string // 注入路径
-	Templates        map[string]string // 模板信息
-	ApiIDs           []uint            // api表注册内容
-	MenuID           uint              // 菜单ID
-	ExportTemplateID uint              // 导出模板ID
+	Table            string              // 表名
+	Package          string              // 模块名/插件名
+	Request          string              // 前端传入的结构化信息
+	StructName       string              // 结构体名称
+	BusinessDB       string              // 业务库
+	Description      string              // Struct中文名称
+	Injections       map[string]string   // 注入路径
+	Templates        map[string]string   // 模板信息
+	ApiIDs           []uint              // api表注册内容
+	MenuID           uint                // 菜单ID
+	ExportTemplateID uint                // 导出模板ID
+	Snapshots        model.FileSnapshots // 生成前被修改文件的快照
 }
 
 func (r *SysAutoHistoryCreate) Create() model.SysAutoCodeHistory {
@@ -33,6 +34,7 @@ func (r *SysAutoHistoryCreate) Create() model.SysAutoCodeHistory {
 		ApiIDs:           r.ApiIDs,
 		MenuID:           r.MenuID,
 		ExportTemplateID: r.ExportTemplateID,
+		Snapshots:        r.Snapshots,
 	}
 	if entity.Table == "" {
 		entity.Table = r.StructName
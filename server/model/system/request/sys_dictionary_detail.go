@@ -9,3 +9,29 @@ type SysDictionaryDetailSearch struct {
 	system.SysDictionaryDetail
 	request.PageInfo
 }
+
+// SysDictionaryDetailMove 移动字典详情到新的父级
+type SysDictionaryDetailMove struct {
+	ID       uint  `json:"ID" binding:"required"` // 字典详情ID
+	ParentID *uint `json:"parentID"`              // 新的父级ID 为空表示移动到顶层
+	Position *int  `json:"position"`              // 在同级中的位置 从0开始 为空表示放到最后
+}
+
+// SysDictionaryDetailSort 同级字典详情排序
+type SysDictionaryDetailSort struct {
+	SysDictionaryID int    `json:"sysDictionaryID" binding:"required"` // 字典ID
+	ParentID        *uint  `json:"parentID"`                           // 父级ID 为空表示顶层
+	IDs             []uint `json:"ids" binding:"required"`             // 按新顺序排列的字典详情ID 未列出的同级排在其后
+}
+
+// SysDictionaryDetailChildren 按父级获取字典详情
+type SysDictionaryDetailChildren struct {
+	SysDictionaryID int   `json:"sysDictionaryID" form:"sysDictionaryID" binding:"required"` // 字典ID
+	ParentID        *uint `json:"parentID" form:"parentID"`                                  // 父级ID 为空表示顶层
+}
+
+// SysDictionaryDetailPath 按字典值查找路径
+type SysDictionaryDetailPath struct {
+	Type  string `json:"type" form:"type" binding:"required"`   // 字典type
+	Value string `json:"value" form:"value" binding:"required"` // 字典值
+}
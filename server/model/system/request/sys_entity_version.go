@@ -0,0 +1,18 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+type EntityVersionSearch struct {
+	EntityTable string `json:"entityTable" form:"entityTable" binding:"required"` // 实体表名
+	EntityID    string `json:"entityId" form:"entityId" binding:"required"`       // 实体主键
+	request.PageInfo
+}
+
+type EntityVersionDiff struct {
+	EntityTable string `json:"entityTable" form:"entityTable" binding:"required"` // 实体表名
+	EntityID    string `json:"entityId" form:"entityId" binding:"required"`       // 实体主键
+	From        int    `json:"from" form:"from"`                                  // 起始版本 0 表示空状态
+	To          int    `json:"to" form:"to" binding:"required"`                   // 目标版本
+}
@@ -0,0 +1,16 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+type SysFeatureFlagSearch struct {
+	Key     string `json:"key" form:"key"`         // 开关键 模糊匹配
+	Enabled *bool  `json:"enabled" form:"enabled"` // 总开关
+	request.PageInfo
+}
+
+type SysFeatureFlagAuditSearch struct {
+	FlagKey string `json:"flagKey" form:"flagKey"` // 开关键
+	request.PageInfo
+}
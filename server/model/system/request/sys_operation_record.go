@@ -9,3 +9,12 @@ type SysOperationRecordSearch struct {
 	system.SysOperationRecord
 	request.PageInfo
 }
+
+type OperationRecordArchiveQuery struct {
+	ID     uint   `json:"id" form:"id" binding:"required"` // 归档ID
+	Method string `json:"method" form:"method"`            // 请求方法
+	Path   string `json:"path" form:"path"`                // 请求路径 包含匹配
+	Status int    `json:"status" form:"status"`            // 请求状态
+	UserID int    `json:"user_id" form:"user_id"`          // 用户id
+	request.PageInfo
+}
@@ -2,6 +2,7 @@ package request
 
 import (
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"time"
 )
 
@@ -12,3 +13,15 @@ type SysParamsSearch struct {
 	Key            string     `json:"key" form:"key" `
 	request.PageInfo
 }
+
+type SysParamsHistorySearch struct {
+	Key string `json:"key" form:"key" binding:"required"` // 参数键
+	request.PageInfo
+}
+
+// SysParamsUpdate 更新参数 type/schema 未传入或为 null 时沿用原值 传入空字符串表示清空
+type SysParamsUpdate struct {
+	system.SysParams
+	Type   *string `json:"type"`   // 参数类型
+	Schema *string `json:"schema"` // JSON Schema
+}
@@ -0,0 +1,18 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+type SysTranslationSearch struct {
+	Locale    string `json:"locale" form:"locale"`       // 语言
+	Namespace string `json:"namespace" form:"namespace"` // 命名空间
+	Key       string `json:"key" form:"key"`             // 翻译键 模糊匹配
+	request.PageInfo
+}
+
+// TranslationFile 翻译文件 导入导出使用同一格式
+type TranslationFile struct {
+	Locale       string                       `json:"locale"`       // 语言
+	Translations map[string]map[string]string `json:"translations"` // 命名空间 -> 翻译键 -> 译文
+}
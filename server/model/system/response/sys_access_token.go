@@ -0,0 +1,9 @@
+package response
+
+import "github.com/flipped-aurora/gin-vue-admin/server/model/system"
+
+// CreateAccessToken 新建的个人访问令牌 明文令牌只在创建时返回一次
+type CreateAccessToken struct {
+	Token       string                `json:"token"`
+	AccessToken system.SysAccessToken `json:"accessToken"`
+}
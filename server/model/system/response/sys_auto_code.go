@@ -1,5 +1,7 @@
 package response
 
+import "encoding/json"
+
 type Db struct {
 	Database string `json:"database" gorm:"column:database"`
 }
@@ -15,3 +17,81 @@ type Column struct {
 	ColumnComment string `json:"columnComment" gorm:"column:column_comment"`
 	PrimaryKey    bool   `json:"primaryKey" gorm:"column:primary_key"`
 }
+
+// AutoCodeTableSchema 代码生成器创建的表及其字段
+type AutoCodeTableSchema struct {
+	TableName   string   `json:"tableName"`       // 表名
+	StructName  string   `json:"structName"`      // 结构体名称
+	Description string   `json:"description"`     // 结构体中文名称
+	BusinessDB  string   `json:"businessDb"`      // 业务库 为空时为主库
+	Columns     []Column `json:"columns"`         // 字段
+	Error       string   `json:"error,omitempty"` // 读取字段失败的原因
+}
+
+type ForeignKey struct {
+	ConstraintName string `json:"constraintName" gorm:"column:constraint_name"` // 外键约束名 复合外键的各字段相同
+	TableName      string `json:"tableName" gorm:"column:table_name"`           // 外键所在表
+	ColumnName     string `json:"columnName" gorm:"column:column_name"`         // 外键字段
+	RefTable       string `json:"refTable" gorm:"column:ref_table"`             // 引用表
+	RefColumn      string `json:"refColumn" gorm:"column:ref_column"`           // 引用字段
+}
+
+// Relation 根据外键和中间表推断的关联关系 字段含义与 request.AutoCodeRelation 一致
+type Relation struct {
+	Type           string `json:"type"`                     // belongsTo hasMany many2many
+	FieldName      string `json:"fieldName"`                // 建议的关联字段名
+	FieldJson      string `json:"fieldJson"`                // 建议的json名
+	Table          string `json:"table"`                    // 关联表
+	ForeignKey     string `json:"foreignKey"`               // 外键字段 belongsTo在本表 hasMany在关联表
+	References     string `json:"references"`               // 被引用字段
+	JoinTable      string `json:"joinTable,omitempty"`      // many2many中间表
+	JoinForeignKey string `json:"joinForeignKey,omitempty"` // 中间表中引用本表的字段
+	JoinReferences string `json:"joinReferences,omitempty"` // 中间表中引用关联表的字段
+	HasDeletedAt   bool   `json:"hasDeletedAt"`             // 关联表是否软删除
+}
+
+// AutoCodeTemplate 可选的代码模板 内置模板没有版本 外部模板包按版本从新到旧排列
+type AutoCodeTemplate struct {
+	Name     string                    `json:"name"`
+	Builtin  bool                      `json:"builtin"`
+	Versions []AutoCodeTemplateVersion `json:"versions"`
+}
+
+type AutoCodeTemplateVersion struct {
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	Extends     string          `json:"extends"`   // 继承的内置模板
+	Variables   json.RawMessage `json:"variables"` // 额外变量的JSON Schema
+}
+
+// PluginInstall 插件安装计划 预览时只返回计划不写入文件
+type PluginInstall struct {
+	Plugin    string              `json:"plugin"`    // 插件名称
+	Version   string              `json:"version"`   // 插件清单中的版本 旧版插件为空
+	Signed    bool                `json:"signed"`    // 安装包是否带签名
+	Verified  bool                `json:"verified"`  // 签名是否已通过受信任公钥校验
+	Signer    string              `json:"signer"`    // 签名公钥(base64)
+	Upgrade   bool                `json:"upgrade"`   // 是否为已安装插件的升级
+	Files     []PluginInstallFile `json:"files"`     // 将写入的文件
+	Removed   []string            `json:"removed"`   // 升级时将删除的旧文件
+	Data      PluginInstallData   `json:"data"`      // 数据清单中将新增的记录
+	Installed bool                `json:"installed"` // 是否已写入
+}
+
+// PluginInstallData 安装数据清单时新增的记录 已存在的记录保持不变不会列出
+type PluginInstallData struct {
+	AuthorityId  uint     `json:"authorityId"`  // 授权的默认角色
+	Menus        []string `json:"menus"`        // 菜单name
+	Buttons      []string `json:"buttons"`      // 菜单name:按钮name
+	Apis         []string `json:"apis"`         // METHOD path
+	Grants       []string `json:"grants"`       // 授权给默认角色的菜单与API
+	Dictionaries []string `json:"dictionaries"` // 字典type
+	Params       []string `json:"params"`       // 参数key
+	Warnings     []string `json:"warnings"`     // 父菜单不存在等提示
+}
+
+type PluginInstallFile struct {
+	Path      string `json:"path"`      // 相对项目根目录的路径
+	Size      int64  `json:"size"`      // 文件大小
+	Overwrite bool   `json:"overwrite"` // 是否覆盖已存在的文件
+}
@@ -0,0 +1,26 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// AccessTokenPrefix 个人访问令牌前缀 用于与JWT区分
+const AccessTokenPrefix = "gva_pat_"
+
+// SysAccessToken 个人访问令牌 供MCP客户端等无法登录的程序以用户身份调用 只保存令牌的哈希
+type SysAccessToken struct {
+	global.GVA_MODEL
+	UserID     uint       `json:"userId" gorm:"column:user_id;index;comment:所属用户"`                 // 所属用户
+	Name       string     `json:"name" gorm:"column:name;size:64;comment:令牌名称"`                    // 令牌名称
+	Hint       string     `json:"hint" gorm:"column:hint;size:32;comment:令牌前几位 用于辨认"`              // 令牌前几位 用于辨认
+	TokenHash  string     `json:"-" gorm:"column:token_hash;size:64;uniqueIndex;comment:令牌SHA256"` // 令牌SHA256
+	RateLimit  int        `json:"rateLimit" gorm:"column:rate_limit;comment:每分钟请求上限"`              // 每分钟请求上限 0使用mcp.rate_limit
+	ExpiresAt  *time.Time `json:"expiresAt" gorm:"column:expires_at;comment:过期时间"`                 // 过期时间 为空时不过期
+	LastUsedAt *time.Time `json:"lastUsedAt" gorm:"column:last_used_at;comment:最后使用时间"`            // 最后使用时间
+}
+
+func (SysAccessToken) TableName() string {
+	return "sys_access_tokens"
+}
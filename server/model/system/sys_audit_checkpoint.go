@@ -0,0 +1,24 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/hashchain"
+)
+
+// SysAuditCheckpoint 审计哈希链签名检查点
+type SysAuditCheckpoint struct {
+	global.GVA_MODEL
+	ChainTable string `json:"chainTable" gorm:"column:chain_table;size:100;index;comment:哈希链所属表"` // 哈希链所属表
+	LastID     uint   `json:"lastId" gorm:"column:last_id;comment:检查点对应的最后一行ID"`                  // 检查点对应的最后一行ID
+	RowHash    string `json:"rowHash" gorm:"column:row_hash;size:64;comment:最后一行哈希"`              // 最后一行哈希
+	RowCount   int64  `json:"rowCount" gorm:"column:row_count;comment:检查点时已链接的行数"`                // 检查点时已链接的行数
+	Signature  string `json:"signature" gorm:"column:signature;size:128;comment:ed25519签名"`       // ed25519签名
+}
+
+func (SysAuditCheckpoint) TableName() string {
+	return "sys_audit_checkpoints"
+}
+
+func (c SysAuditCheckpoint) Checkpoint() hashchain.Checkpoint {
+	return hashchain.Checkpoint{Table: c.ChainTable, LastID: c.LastID, RowHash: c.RowHash, Signature: c.Signature}
+}
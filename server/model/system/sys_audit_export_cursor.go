@@ -0,0 +1,22 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// SysAuditExportCursor SIEM 投递游标 记录每个投递目标在每个事件来源上已成功投递的最后ID
+type SysAuditExportCursor struct {
+	global.GVA_MODEL
+	Sink        string     `json:"sink" gorm:"size:100;uniqueIndex:idx_sink_source;comment:投递目标名称"` // 投递目标名称
+	Source      string     `json:"source" gorm:"size:50;uniqueIndex:idx_sink_source;comment:事件来源"`  // 事件来源
+	LastID      uint       `json:"lastId" gorm:"comment:已投递的最后ID"`                                  // 已投递的最后ID
+	Delivered   int64      `json:"delivered" gorm:"comment:累计投递条数"`                                 // 累计投递条数
+	LastSuccess *time.Time `json:"lastSuccess" gorm:"comment:最后一次成功投递时间"`                           // 最后一次成功投递时间
+	LastError   string     `json:"lastError" gorm:"type:text;comment:最后一次错误"`                       // 最后一次错误
+}
+
+func (SysAuditExportCursor) TableName() string {
+	return "sys_audit_export_cursors"
+}
@@ -27,6 +27,16 @@ type SysAutoCodeHistory struct {
 	ExportTemplateID uint               `json:"exportTemplateID" gorm:"column:export_template_id;comment:导出模板ID"`
 	AutoCodePackage  SysAutoCodePackage `json:"autoCodePackage" gorm:"foreignKey:ID;references:PackageID"`
 	PackageID        uint               `json:"packageID" gorm:"column:package_id;comment:包ID"`
+	Snapshots        FileSnapshots      `json:"-" gorm:"serializer:json;type:text;column:snapshots;comment:生成前被修改文件的快照"`
+}
+
+// FileSnapshots 相对于项目根目录的文件路径=>快照
+type FileSnapshots map[string]SysAutoCodeFileSnapshot
+
+// SysAutoCodeFileSnapshot 生成代码前被注入修改的文件快照 回滚时文件未被再次修改则直接恢复
+type SysAutoCodeFileSnapshot struct {
+	Content string `json:"content"` // 生成前的内容
+	Hash    string `json:"hash"`    // 生成后内容的md5 用于判断文件是否被再次修改
 }
 
 func (s *SysAutoCodeHistory) BeforeCreate(db *gorm.DB) error {
@@ -0,0 +1,18 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// SysAutoCodeSnapshot 代码生成器最近一次生成的原始文件 重新生成时作为三方合并的基准
+type SysAutoCodeSnapshot struct {
+	global.GVA_MODEL
+	Package    string `json:"package" gorm:"column:package;comment:模块名/插件名"`
+	StructName string `json:"structName" gorm:"column:struct_name;comment:结构体名称"`
+	Path       string `json:"path" gorm:"column:path;size:255;uniqueIndex;comment:相对于项目根目录的文件路径"`
+	Content    string `json:"content" gorm:"type:text;column:content;comment:生成时的文件内容"`
+}
+
+func (s *SysAutoCodeSnapshot) TableName() string {
+	return "sys_auto_code_snapshots"
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
 )
 
 // ConfigChangeHistory 配置变更历史
@@ -25,6 +26,31 @@ type ConfigChangeHistory struct {
 	UserAgent        string    `json:"user_agent" gorm:"type:text;comment:用户代理"`
 	SessionID        string    `json:"session_id" gorm:"type:varchar(100);comment:会话ID"`
 	Metadata         string    `json:"metadata" gorm:"type:json;comment:元数据"`
+	common.HashChain
+}
+
+// ChainPayload 参与防篡改哈希链计算的字段
+// ChangeTime 截断到秒 避免不同数据库时间精度导致校验失败
+func (h *ConfigChangeHistory) ChainPayload() any {
+	h.ChangeTime = h.ChangeTime.Truncate(time.Second)
+	return map[string]any{
+		"config_type":       h.ConfigType,
+		"config_path":       h.ConfigPath,
+		"change_type":       h.ChangeType,
+		"operator_id":       h.OperatorID,
+		"operator_name":     h.OperatorName,
+		"change_reason":     h.ChangeReason,
+		"old_value":         h.OldValue,
+		"new_value":         h.NewValue,
+		"validation_passed": h.ValidationPassed,
+		"validation_errors": h.ValidationErrors,
+		"backup_path":       h.BackupPath,
+		"change_time":       h.ChangeTime.Unix(),
+		"source_ip":         h.SourceIP,
+		"user_agent":        h.UserAgent,
+		"session_id":        h.SessionID,
+		"metadata":          h.Metadata,
+	}
 }
 
 // TableName 表名
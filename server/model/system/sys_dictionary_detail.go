@@ -14,6 +14,9 @@ type SysDictionaryDetail struct {
 	Status          *bool  `json:"status" form:"status" gorm:"column:status;comment:启用状态"`                              // 启用状态
 	Sort            int    `json:"sort" form:"sort" gorm:"column:sort;comment:排序标记"`                                    // 排序标记
 	SysDictionaryID int    `json:"sysDictionaryID" form:"sysDictionaryID" gorm:"column:sys_dictionary_id;comment:关联标记"` // 关联标记
+	ParentID        *uint  `json:"parentID" form:"parentID" gorm:"column:parent_id;index;comment:父级字典详情ID"`             // 父级字典详情ID 为空表示顶层
+
+	Children []SysDictionaryDetail `json:"children,omitempty" gorm:"-"` // 子级 仅树形查询时填充
 }
 
 func (SysDictionaryDetail) TableName() string {
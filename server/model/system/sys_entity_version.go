@@ -0,0 +1,24 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// SysEntityVersion 实体变更历史 保存每次创建、更新、删除前后的JSON快照
+type SysEntityVersion struct {
+	global.GVA_MODEL
+	EntityTable   string `json:"entityTable" gorm:"column:entity_table;size:100;uniqueIndex:idx_entity_version_unique;comment:实体表名"` // 实体表名
+	EntityID      string `json:"entityId" gorm:"column:entity_id;size:64;uniqueIndex:idx_entity_version_unique;comment:实体主键"`        // 实体主键
+	Version       int    `json:"version" gorm:"column:version;uniqueIndex:idx_entity_version_unique;comment:版本号"`                    // 版本号 同一实体内递增
+	Action        string `json:"action" gorm:"column:action;size:16;comment:变更类型"`                                                   // 变更类型 create/update/delete/restore
+	Before        string `json:"before" gorm:"column:before;type:text;comment:变更前快照"`                                                // 变更前快照
+	After         string `json:"after" gorm:"column:after;type:text;comment:变更后快照"`                                                  // 变更后快照
+	ChangedFields string `json:"changedFields" gorm:"column:changed_fields;type:text;comment:变化字段"`                                  // 变化字段 逗号分隔
+	UserID        uint   `json:"userId" gorm:"column:user_id;comment:操作人ID"`                                                         // 操作人ID
+	Username      string `json:"username" gorm:"column:username;size:64;comment:操作人"`                                                // 操作人
+	RequestID     string `json:"requestId" gorm:"column:request_id;size:64;index;comment:请求ID"`                                      // 请求ID 可关联操作记录
+}
+
+func (SysEntityVersion) TableName() string {
+	return "sys_entity_versions"
+}
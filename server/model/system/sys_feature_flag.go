@@ -0,0 +1,35 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/featureflag"
+)
+
+// SysFeatureFlag 功能开关
+type SysFeatureFlag struct {
+	global.GVA_MODEL
+	Key            string                `json:"key" form:"key" gorm:"column:flag_key;size:100;uniqueIndex;comment:开关键" binding:"required"` // 开关键
+	Name           string                `json:"name" form:"name" gorm:"column:name;size:100;comment:开关名称"`                                 // 开关名称
+	Description    string                `json:"description" form:"description" gorm:"column:description;size:500;comment:说明"`              // 说明
+	Type           string                `json:"type" form:"type" gorm:"column:type;size:16;comment:开关类型" binding:"required"`               // 开关类型 boolean/variant
+	Enabled        bool                  `json:"enabled" form:"enabled" gorm:"column:enabled;comment:总开关"`                                  // 总开关
+	DefaultVariant string                `json:"defaultVariant" form:"defaultVariant" gorm:"column:default_variant;size:64;comment:默认取值"`   // 默认取值 仅多值开关使用
+	Variants       []featureflag.Variant `json:"variants" gorm:"column:variants;type:text;serializer:json;comment:取值及权重"`                   // 取值及权重
+	Rules          []featureflag.Rule    `json:"rules" gorm:"column:rules;type:text;serializer:json;comment:定向规则"`                          // 定向规则 按顺序匹配
+}
+
+func (SysFeatureFlag) TableName() string {
+	return "sys_feature_flags"
+}
+
+// Flag 转换为求值用的开关定义
+func (f SysFeatureFlag) Flag() featureflag.Flag {
+	return featureflag.Flag{
+		Key:            f.Key,
+		Type:           f.Type,
+		Enabled:        f.Enabled,
+		DefaultVariant: f.DefaultVariant,
+		Variants:       f.Variants,
+		Rules:          f.Rules,
+	}
+}
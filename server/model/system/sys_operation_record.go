@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
 )
 
 // 如果含有time.Time 请自行import time包
@@ -21,4 +22,28 @@ type SysOperationRecord struct {
 	Resp         string        `json:"resp" form:"resp" gorm:"type:text;column:resp;comment:响应Body"`                 // 响应Body
 	UserID       int           `json:"user_id" form:"user_id" gorm:"column:user_id;comment:用户id"`                    // 用户id
 	User         SysUser       `json:"user"`
+	common.HashChain
+}
+
+func (SysOperationRecord) TableName() string {
+	return "sys_operation_records"
+}
+
+// ChainPayload 参与防篡改哈希链计算的字段
+// CreatedAt 由哈希链插件在写入前填充 截断到秒 避免不同数据库时间精度导致校验失败
+func (r *SysOperationRecord) ChainPayload() any {
+	r.CreatedAt = r.CreatedAt.Truncate(time.Second)
+	return map[string]any{
+		"created_at":    r.CreatedAt.Unix(),
+		"ip":            r.Ip,
+		"method":        r.Method,
+		"path":          r.Path,
+		"status":        r.Status,
+		"latency":       r.Latency,
+		"agent":         r.Agent,
+		"error_message": r.ErrorMessage,
+		"body":          r.Body,
+		"resp":          r.Resp,
+		"user_id":       r.UserID,
+	}
 }
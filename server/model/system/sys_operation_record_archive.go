@@ -0,0 +1,29 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// SysOperationRecordArchive 操作记录归档索引 每个归档为一个 gzip 压缩的 JSONL 文件
+type SysOperationRecordArchive struct {
+	global.GVA_MODEL
+	FileName     string     `json:"fileName" gorm:"column:file_name;size:255;comment:归档文件名"`      // 归档文件名
+	Storage      string     `json:"storage" gorm:"column:storage;size:32;comment:存储方式"`           // 存储方式 local|oss
+	Key          string     `json:"key" gorm:"column:key;size:255;comment:存储key"`                 // 本地路径或对象存储key
+	Url          string     `json:"url" gorm:"column:url;size:512;comment:访问地址"`                  // 对象存储访问地址
+	FirstID      uint       `json:"firstId" gorm:"column:first_id;index;comment:首条记录ID"`          // 首条记录ID
+	LastID       uint       `json:"lastId" gorm:"column:last_id;index;comment:末条记录ID"`            // 末条记录ID
+	RowCount     int        `json:"rowCount" gorm:"column:row_count;comment:记录条数"`                // 记录条数
+	StartTime    time.Time  `json:"startTime" gorm:"column:start_time;index;comment:最早记录时间"`      // 最早记录时间
+	EndTime      time.Time  `json:"endTime" gorm:"column:end_time;index;comment:最晚记录时间"`          // 最晚记录时间
+	Size         int64      `json:"size" gorm:"column:size;comment:文件大小"`                         // 文件大小(字节)
+	Sha256       string     `json:"sha256" gorm:"column:sha256;size:64;comment:文件sha256"`         // 文件sha256
+	RestoredAt   *time.Time `json:"restoredAt" gorm:"column:restored_at;comment:最近一次恢复到数据库的时间"`   // 最近一次恢复到数据库的时间
+	RestoredRows int        `json:"restoredRows" gorm:"column:restored_rows;comment:最近一次恢复写入的条数"` // 最近一次恢复写入的条数
+}
+
+func (SysOperationRecordArchive) TableName() string {
+	return "sys_operation_record_archives"
+}
@@ -8,12 +8,22 @@ import (
 // 参数 结构体  SysParams
 type SysParams struct {
 	global.GVA_MODEL
-	Name  string `json:"name" form:"name" gorm:"column:name;comment:参数名称;" binding:"required"`   //参数名称
-	Key   string `json:"key" form:"key" gorm:"column:key;comment:参数键;" binding:"required"`       //参数键
-	Value string `json:"value" form:"value" gorm:"column:value;comment:参数值;" binding:"required"` //参数值
-	Desc  string `json:"desc" form:"desc" gorm:"column:desc;comment:参数说明;"`                      //参数说明
+	Name   string `json:"name" form:"name" gorm:"column:name;comment:参数名称;" binding:"required"`     //参数名称
+	Key    string `json:"key" form:"key" gorm:"column:key;comment:参数键;" binding:"required"`         //参数键
+	Value  string `json:"value" form:"value" gorm:"column:value;comment:参数值;" binding:"required"`   //参数值
+	Desc   string `json:"desc" form:"desc" gorm:"column:desc;comment:参数说明;"`                        //参数说明
+	Type   string `json:"type" form:"type" gorm:"column:type;size:16;comment:参数类型;"`                //参数类型 string/int/bool/duration/json 为空按 string 处理
+	Schema string `json:"schema" form:"schema" gorm:"column:schema;type:text;comment:JSON Schema;"` //JSON Schema 仅 json 类型使用
 }
 
+const (
+	SysParamsTypeString   = "string"
+	SysParamsTypeInt      = "int"
+	SysParamsTypeBool     = "bool"
+	SysParamsTypeDuration = "duration"
+	SysParamsTypeJSON     = "json"
+)
+
 // TableName 参数 SysParams自定义表名 sys_params
 func (SysParams) TableName() string {
 	return "sys_params"
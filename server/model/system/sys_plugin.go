@@ -0,0 +1,32 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+const (
+	SysPluginStateInstalled = "installed" // 已安装并注册
+	SysPluginStateFailed    = "failed"    // 版本不兼容/依赖缺失/升级失败 未注册
+)
+
+// SysPlugin 已安装插件登记 记录插件版本与状态
+type SysPlugin struct {
+	global.GVA_MODEL
+	Name         string            `json:"name" gorm:"column:name;size:64;uniqueIndex;comment:插件名称"`                        // 插件名称
+	Version      string            `json:"version" gorm:"column:version;size:32;comment:已安装版本"`                             // 已安装版本 升级成功后更新
+	Core         string            `json:"core" gorm:"column:core;size:64;comment:兼容的核心版本范围"`                               // 兼容的核心版本范围
+	Dependencies map[string]string `json:"dependencies" gorm:"column:dependencies;type:text;serializer:json;comment:依赖的插件"` // 依赖的插件及版本范围
+	Description  string            `json:"description" gorm:"column:description;size:500;comment:插件描述"`                     // 插件描述
+	State        string            `json:"state" gorm:"column:state;size:16;comment:状态"`                                    // 状态 installed/failed
+	Message      string            `json:"message" gorm:"column:message;type:text;comment:失败原因"`                            // 失败原因
+	Disabled     bool              `json:"disabled" gorm:"column:disabled;default:false;comment:是否停用"`                      // 停用后路由返回503 菜单隐藏 定时任务暂停
+}
+
+// PluginMenuPrefix 插件前端页面的组件路径前缀 用于识别插件注册的菜单
+func PluginMenuPrefix(name string) string {
+	return "plugin/" + name + "/"
+}
+
+func (SysPlugin) TableName() string {
+	return "sys_plugins"
+}
@@ -0,0 +1,33 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+const (
+	SysPluginResourceMenu             = "menu"              // 菜单 RecordID为菜单id
+	SysPluginResourceMenuParameter    = "menu_parameter"    // 菜单参数 RecordID为参数id
+	SysPluginResourceButton           = "button"            // 菜单按钮 RecordID为按钮id
+	SysPluginResourceAuthorityMenu    = "authority_menu"    // 角色菜单授权 RecordID为菜单id
+	SysPluginResourceAuthorityButton  = "authority_btn"     // 角色按钮授权 RecordID为按钮id
+	SysPluginResourceApi              = "api"               // API RecordID为api id
+	SysPluginResourceCasbin           = "casbin"            // API授权 AuthorityId/Path/Method为casbin规则
+	SysPluginResourceDictionary       = "dictionary"        // 字典 RecordID为字典id
+	SysPluginResourceDictionaryDetail = "dictionary_detail" // 字典详情 RecordID为详情id
+	SysPluginResourceParam            = "param"             // 参数 RecordID为参数id
+)
+
+// SysPluginResource 插件安装时新增的数据记录 卸载时只删除这些记录 安装前已存在的同名记录不会登记
+type SysPluginResource struct {
+	global.GVA_MODEL
+	Plugin      string `json:"plugin" gorm:"column:plugin;size:64;index;comment:插件名称"`       // 插件名称
+	Kind        string `json:"kind" gorm:"column:kind;size:32;comment:记录类型"`                 // 记录类型
+	RecordID    uint   `json:"recordId" gorm:"column:record_id;comment:记录id"`                // 新增记录的id
+	AuthorityId string `json:"authorityId" gorm:"column:authority_id;size:64;comment:授权的角色"` // 授权类记录的角色id
+	Path        string `json:"path" gorm:"column:path;comment:授权的API路径"`                     // casbin规则的路径
+	Method      string `json:"method" gorm:"column:method;size:16;comment:授权的API方法"`         // casbin规则的请求方法
+}
+
+func (SysPluginResource) TableName() string {
+	return "sys_plugin_resources"
+}
@@ -0,0 +1,18 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// SysTranslation 翻译条目 覆盖内置翻译
+type SysTranslation struct {
+	global.GVA_MODEL
+	Locale    string `json:"locale" form:"locale" gorm:"column:locale;size:16;uniqueIndex:idx_translation;comment:语言" binding:"required"`            // 语言 如 en-US
+	Namespace string `json:"namespace" form:"namespace" gorm:"column:namespace;size:32;uniqueIndex:idx_translation;comment:命名空间" binding:"required"` // 命名空间 menu/dict/param/message
+	Key       string `json:"key" form:"key" gorm:"column:translation_key;size:191;uniqueIndex:idx_translation;comment:翻译键" binding:"required"`       // 翻译键
+	Value     string `json:"value" form:"value" gorm:"column:value;type:text;comment:译文"`                                                            // 译文
+}
+
+func (SysTranslation) TableName() string {
+	return "sys_translations"
+}
@@ -12,6 +12,7 @@ type Login interface {
 	GetUUID() uuid.UUID
 	GetUserId() uint
 	GetAuthorityId() uint
+	GetTenant() string
 	GetUserInfo() any
 }
 
@@ -30,6 +31,7 @@ type SysUser struct {
 	Phone         string         `json:"phone"  gorm:"comment:用户手机号"`                                                                        // 用户手机号
 	Email         string         `json:"email"  gorm:"comment:用户邮箱"`                                                                         // 用户邮箱
 	Enable        int            `json:"enable" gorm:"default:1;comment:用户是否被冻结 1正常 2冻结"`                                                    //用户是否被冻结 1正常 2冻结
+	Tenant        string         `json:"tenant" gorm:"size:64;comment:所属租户"`                                                                 // 所属租户 用于功能开关的租户定向
 	OriginSetting common.JSONMap `json:"originSetting" form:"originSetting" gorm:"type:text;default:null;column:origin_setting;comment:配置;"` //配置
 }
 
@@ -57,6 +59,10 @@ func (s *SysUser) GetAuthorityId() uint {
 	return s.AuthorityId
 }
 
+func (s *SysUser) GetTenant() string {
+	return s.Tenant
+}
+
 func (s *SysUser) GetUserInfo() any {
 	return *s
 }
@@ -9,7 +9,8 @@ import (
 
 func Router(engine *gin.Engine) {
 	public := engine.Group(global.GVA_CONFIG.System.RouterPrefix).Group("")
+	public.Use(middleware.PluginGate("announcement"))
 	private := engine.Group(global.GVA_CONFIG.System.RouterPrefix).Group("")
-	private.Use(middleware.JWTAuth()).Use(middleware.CasbinHandler())
+	private.Use(middleware.PluginGate("announcement")).Use(middleware.JWTAuth()).Use(middleware.CasbinHandler())
 	router.Router.Info.Init(public, private)
 }
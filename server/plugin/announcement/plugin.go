@@ -2,17 +2,27 @@ package announcement
 
 import (
 	"context"
+	_ "embed"
 	"github.com/flipped-aurora/gin-vue-admin/server/plugin/announcement/initialize"
 	interfaces "github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/v2"
 	"github.com/gin-gonic/gin"
 )
 
 var _ interfaces.Plugin = (*plugin)(nil)
+var _ interfaces.Describer = (*plugin)(nil)
 
 var Plugin = new(plugin)
 
+//go:embed plugin.json
+var manifest []byte
+
 type plugin struct{}
 
+// Manifest 插件清单 升级插件时修改plugin.json中的版本 需要调整表结构或数据时实现 Migrations() []interfaces.Migration
+func (p *plugin) Manifest() interfaces.Manifest {
+	return interfaces.MustManifest(manifest)
+}
+
 func (p *plugin) Register(group *gin.Engine) {
 	ctx := context.Background()
 	// 如果需要配置文件，请到config.Config中填充配置结构，且到下方发放中填入其在config.yaml中的key
@@ -1,8 +1,8 @@
 {{- $db := "" }}
 {{- if eq .BusinessDB "" }}
- {{- $db = "global.GVA_DB" }}
+ {{- $db = "global.GVA_DB.WithContext(ctx)" }}
 {{- else}}
- {{- $db =  printf "global.MustGetGlobalDBByDBName(\"%s\")" .BusinessDB   }}
+ {{- $db =  printf "global.MustGetGlobalDBByDBName(\"%s\").WithContext(ctx)" .BusinessDB   }}
 {{- end}}
 {{if .IsPlugin}}
 
@@ -0,0 +1,38 @@
+{{- if .IsAdd}}
+{{- template "testAddFields" . }}
+{{- else }}
+package {{.Package}}
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	{{- if .HasTimer }}
+	"time"
+	{{- end }}
+
+	"{{.Module}}/global"
+	"{{.Module}}/model/common/response"
+	"{{.Module}}/model/{{.Package}}"
+	{{- range .RelationPackages }}
+	"{{$.Module}}/model/{{.}}"
+	{{- end }}
+	{{- if .AutoCreateResource }}
+	systemReq "{{.Module}}/model/system/request"
+	{{- end }}
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	{{- if .NeedJSON }}
+	"gorm.io/datatypes"
+	{{- end }}
+	"gorm.io/gorm"
+)
+{{- template "apiTests" (dict "Info" . "Model" .Package "Api" (printf "new(%sApi)" .StructName)) }}
+{{- end }}
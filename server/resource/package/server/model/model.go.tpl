@@ -19,6 +19,9 @@ import (
 	{{- if .NeedJSON }}
 	"gorm.io/datatypes"
 	{{- end }}
+	{{- range .RelationPackages }}
+	"{{$.Module}}/model/{{.}}"
+	{{- end }}
 )
 {{- end }}
 
@@ -30,6 +33,9 @@ type {{.StructName}} struct {
 {{- end }}
 {{- range .Fields}}
   {{ GenerateField . }}
+{{- end }}
+{{- range .Relations}}
+  {{ GenerateRelationField . $.Package }}
 {{- end }}
     {{- if .AutoCreateResource }}
     CreatedBy  uint   `gorm:"column:created_by;comment:创建者"`
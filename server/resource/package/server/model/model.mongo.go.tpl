@@ -0,0 +1,60 @@
+{{- if .IsAdd}}
+// 在结构体中新增如下字段
+{{- range .Fields}}
+  {{ GenerateMongoField . }}
+{{- end }}
+
+// 在Indexes中新增如下索引
+{{- range .Fields}}
+{{- if .FieldIndexType }}
+		{{ GenerateMongoIndex . }},
+{{- end }}
+{{- end }}
+
+{{ else }}
+// 自动生成模板{{.StructName}}
+package {{.Package}}
+
+{{- if not .OnlyTemplate}}
+import (
+	"{{.Module}}/global"
+	{{- if .HasTimer }}
+	"time"
+	{{- end }}
+	"github.com/qiniu/qmgo/options"
+	{{- if .HasMongoUnique }}
+	option "go.mongodb.org/mongo-driver/mongo/options"
+	{{- end }}
+)
+{{- end }}
+
+// {{.Description}} 结构体  {{.StructName}}
+type {{.StructName}} struct {
+{{- if not .OnlyTemplate}}
+    global.GVA_MONGO_MODEL `bson:",inline"`
+{{- range .Fields}}
+  {{ GenerateMongoField . }}
+{{- end }}
+{{- end }}
+}
+
+{{- if not .OnlyTemplate}}
+
+// CollectionName {{.Description}} {{.StructName}}的集合名 {{.TableName}}
+func ({{.StructName}}) CollectionName() string {
+    return "{{.TableName}}"
+}
+
+// Indexes {{.Description}} {{.StructName}}的索引 启动时自动创建
+func ({{.StructName}}) Indexes() []options.IndexModel {
+    return []options.IndexModel{
+        {Key: []string{"-createdAt"}},
+{{- range .Fields}}
+{{- if .FieldIndexType }}
+        {{ GenerateMongoIndex . }},
+{{- end }}
+{{- end }}
+    }
+}
+{{- end }}
+{{ end }}
@@ -1,8 +1,8 @@
 {{- $db := "" }}
 {{- if eq .BusinessDB "" }}
- {{- $db = "global.GVA_DB" }}
+ {{- $db = "global.GVA_DB.WithContext(ctx)" }}
 {{- else}}
- {{- $db =  printf "global.MustGetGlobalDBByDBName(\"%s\")" .BusinessDB   }}
+ {{- $db =  printf "global.MustGetGlobalDBByDBName(\"%s\").WithContext(ctx)" .BusinessDB   }}
 {{- end}}
 
 {{- if .IsAdd}}
@@ -25,7 +25,7 @@ orderMap["{{.ColumnName}}"] = true
 {{- if eq $value.DBName "" }}
 {{ $dataDB = $db }}
 {{- else}}
-{{ $dataDB = printf "global.MustGetGlobalDBByDBName(\"%s\")" $value.DBName }}
+{{ $dataDB = printf "global.MustGetGlobalDBByDBName(\"%s\").WithContext(ctx)" $value.DBName }}
 {{- end}}
 {{$dataDB}}.Table("{{$value.Table}}"){{- if $value.HasDeletedAt}}.Where("deleted_at IS NULL"){{ end }}.Select("{{$value.Label}} as label,{{$value.Value}} as value").Scan(&{{$key}})
 res["{{$key}}"] = {{$key}}
@@ -45,9 +45,12 @@ import (
     "{{.Module}}/utils"
     "errors"
     {{- end }}
-    {{- if .AutoCreateResource }}
+    {{- if or .AutoCreateResource .HasAssociationWrite }}
     "gorm.io/gorm"
     {{- end}}
+    {{- if .HasRelation }}
+    "gorm.io/gorm/clause"
+    {{- end}}
 {{- end }}
 )
 
@@ -57,7 +60,7 @@ type {{.StructName}}Service struct {}
 // Create{{.StructName}} 创建{{.Description}}记录
 // Author [yourname](https://github.com/yourname)
 func ({{.Abbreviation}}Service *{{.StructName}}Service) Create{{.StructName}}(ctx context.Context, {{.Abbreviation}} *{{.Package}}.{{.StructName}}) (err error) {
-	err = {{$db}}.Create({{.Abbreviation}}).Error
+	err = {{$db}}{{- if .RelationOmits }}.Omit({{- range $i, $name := .RelationOmits }}{{- if $i }}, {{ end }}"{{$name}}"{{- end }}){{- end }}.Create({{.Abbreviation}}).Error
 	return err
 }
 
@@ -113,14 +116,32 @@ func ({{.Abbreviation}}Service *{{.StructName}}Service)Delete{{.StructName}}ById
 // Update{{.StructName}} 更新{{.Description}}记录
 // Author [yourname](https://github.com/yourname)
 func ({{.Abbreviation}}Service *{{.StructName}}Service)Update{{.StructName}}(ctx context.Context, {{.Abbreviation}} {{.Package}}.{{.StructName}}) (err error) {
-	err = {{$db}}.Model(&{{.Package}}.{{.StructName}}{}).Where("{{.PrimaryField.ColumnName}} = ?",{{.Abbreviation}}.{{.PrimaryField.FieldName}}).Updates(&{{.Abbreviation}}).Error
+	{{- if .HasAssociationWrite }}
+	err = {{$db}}.Transaction(func(tx *gorm.DB) error {
+	    if err := tx.Model(&{{.Package}}.{{.StructName}}{}).Where("{{.PrimaryField.ColumnName}} = ?",{{.Abbreviation}}.{{.PrimaryField.FieldName}}).Omit(clause.Associations).Updates(&{{.Abbreviation}}).Error; err != nil {
+            return err
+        }
+        {{- range .Relations }}
+        {{- if ne .Type "belongsTo" }}
+        if {{$.Abbreviation}}.{{.FieldName}} != nil {
+            if err := tx.Model(&{{$.Abbreviation}}).Association("{{.FieldName}}").Replace({{$.Abbreviation}}.{{.FieldName}}); err != nil {
+                return err
+            }
+        }
+        {{- end }}
+        {{- end }}
+        return nil
+    })
+	{{- else }}
+	err = {{$db}}.Model(&{{.Package}}.{{.StructName}}{}).Where("{{.PrimaryField.ColumnName}} = ?",{{.Abbreviation}}.{{.PrimaryField.FieldName}}){{- if .HasRelation }}.Omit(clause.Associations){{- end }}.Updates(&{{.Abbreviation}}).Error
+	{{- end }}
 	return err
 }
 
 // Get{{.StructName}} 根据{{.PrimaryField.FieldJson}}获取{{.Description}}记录
 // Author [yourname](https://github.com/yourname)
 func ({{.Abbreviation}}Service *{{.StructName}}Service)Get{{.StructName}}(ctx context.Context, {{.PrimaryField.FieldJson}} string) ({{.Abbreviation}} {{.Package}}.{{.StructName}}, err error) {
-	err = {{$db}}.Where("{{.PrimaryField.ColumnName}} = ?", {{.PrimaryField.FieldJson}}).First(&{{.Abbreviation}}).Error
+	err = {{$db}}{{- range .Relations }}{{- if .Preload }}.Preload("{{.FieldName}}"){{- end }}{{- end }}.Where("{{.PrimaryField.ColumnName}} = ?", {{.PrimaryField.FieldJson}}).First(&{{.Abbreviation}}).Error
 	return
 }
 
@@ -133,7 +154,7 @@ func ({{.Abbreviation}}Service *{{.StructName}}Service)Get{{.StructName}}InfoLis
 	db := {{$db}}.Model(&{{.Package}}.{{.StructName}}{})
     var {{.Abbreviation}}s []*{{.Package}}.{{.StructName}}
 
-	err = db.Find(&{{.Abbreviation}}s).Error
+	err = db{{- range .Relations }}{{- if .Preload }}.Preload("{{.FieldName}}"){{- end }}{{- end }}.Find(&{{.Abbreviation}}s).Error
 
 	return utils.BuildTree({{.Abbreviation}}s), err
 }
@@ -182,7 +203,7 @@ func ({{.Abbreviation}}Service *{{.StructName}}Service)Get{{.StructName}}InfoLis
        db = db.Limit(limit).Offset(offset)
     }
 
-	err = db.Find(&{{.Abbreviation}}s).Error
+	err = db{{- range .Relations }}{{- if .Preload }}.Preload("{{.FieldName}}"){{- end }}{{- end }}.Find(&{{.Abbreviation}}s).Error
 	return  {{.Abbreviation}}s, total, err
 }
 
@@ -197,7 +218,7 @@ func ({{.Abbreviation}}Service *{{.StructName}}Service)Get{{.StructName}}DataSou
 	   {{- if eq $value.DBName "" }}
        {{ $dataDB = $db }}
        {{- else}}
-       {{ $dataDB = printf "global.MustGetGlobalDBByDBName(\"%s\")" $value.DBName }}
+       {{ $dataDB = printf "global.MustGetGlobalDBByDBName(\"%s\").WithContext(ctx)" $value.DBName }}
        {{- end}}
        {{$dataDB}}.Table("{{$value.Table}}"){{- if $value.HasDeletedAt}}.Where("deleted_at IS NULL"){{ end }}.Select("{{$value.Label}} as label,{{$value.Value}} as value").Scan(&{{$key}})
 	   res["{{$key}}"] = {{$key}}
@@ -0,0 +1,136 @@
+{{- if .IsAdd}}
+
+// Get{{.StructName}}InfoList 新增搜索语句
+       {{ GenerateMongoSearchConditions .Fields }}
+// Get{{.StructName}}InfoList 新增排序语句 请自行在搜索语句中添加sortMap内容
+       {{- range .Fields}}
+            {{- if .Sort}}
+sortMap["{{.ColumnName}}"] = "{{.ColumnName}}"
+         	{{- end}}
+       {{- end}}
+{{- else}}
+package {{.Package}}
+
+import (
+{{- if not .OnlyTemplate }}
+	"context"
+	{{- if .HasMongoRegex }}
+	"regexp"
+	{{- end }}
+	"{{.Module}}/global"
+	"{{.Module}}/model/{{.Package}}"
+    {{.Package}}Req "{{.Module}}/model/{{.Package}}/request"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+{{- end }}
+)
+
+type {{.StructName}}Service struct {}
+
+{{- if not .OnlyTemplate }}
+// collection {{.Description}}所在的MongoDB集合
+func ({{.Abbreviation}}Service *{{.StructName}}Service) collection() *qmgo.Collection {
+	return global.GVA_MONGO.Database.Collection({{.Package}}.{{.StructName}}{}.CollectionName())
+}
+
+// Create{{.StructName}} 创建{{.Description}}记录
+// Author [yourname](https://github.com/yourname)
+func ({{.Abbreviation}}Service *{{.StructName}}Service) Create{{.StructName}}(ctx context.Context, {{.Abbreviation}} *{{.Package}}.{{.StructName}}) (err error) {
+	_, err = {{.Abbreviation}}Service.collection().InsertOne(ctx, {{.Abbreviation}})
+	return err
+}
+
+// Delete{{.StructName}} 删除{{.Description}}记录
+// Author [yourname](https://github.com/yourname)
+func ({{.Abbreviation}}Service *{{.StructName}}Service)Delete{{.StructName}}(ctx context.Context, {{.PrimaryField.FieldJson}} string) (err error) {
+	id, err := primitive.ObjectIDFromHex({{.PrimaryField.FieldJson}})
+	if err != nil {
+		return err
+	}
+	return {{.Abbreviation}}Service.collection().RemoveId(ctx, id)
+}
+
+// Delete{{.StructName}}ByIds 批量删除{{.Description}}记录
+// Author [yourname](https://github.com/yourname)
+func ({{.Abbreviation}}Service *{{.StructName}}Service)Delete{{.StructName}}ByIds(ctx context.Context, {{.PrimaryField.FieldJson}}s []string) (err error) {
+	ids := make([]primitive.ObjectID, 0, len({{.PrimaryField.FieldJson}}s))
+	for _, hex := range {{.PrimaryField.FieldJson}}s {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	_, err = {{.Abbreviation}}Service.collection().RemoveAll(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// Update{{.StructName}} 更新{{.Description}}记录 创建时间为空时不覆盖
+// Author [yourname](https://github.com/yourname)
+func ({{.Abbreviation}}Service *{{.StructName}}Service)Update{{.StructName}}(ctx context.Context, {{.Abbreviation}} {{.Package}}.{{.StructName}}) (err error) {
+	{{.Abbreviation}}.DefaultUpdateAt()
+	return {{.Abbreviation}}Service.collection().UpdateId(ctx, {{.Abbreviation}}.ID, bson.M{"$set": {{.Abbreviation}}})
+}
+
+// Get{{.StructName}} 根据{{.PrimaryField.FieldJson}}获取{{.Description}}记录
+// Author [yourname](https://github.com/yourname)
+func ({{.Abbreviation}}Service *{{.StructName}}Service)Get{{.StructName}}(ctx context.Context, {{.PrimaryField.FieldJson}} string) ({{.Abbreviation}} {{.Package}}.{{.StructName}}, err error) {
+	id, err := primitive.ObjectIDFromHex({{.PrimaryField.FieldJson}})
+	if err != nil {
+		return
+	}
+	err = {{.Abbreviation}}Service.collection().Find(ctx, bson.M{"_id": id}).One(&{{.Abbreviation}})
+	return
+}
+
+// Get{{.StructName}}InfoList 分页获取{{.Description}}记录
+// Author [yourname](https://github.com/yourname)
+func ({{.Abbreviation}}Service *{{.StructName}}Service)Get{{.StructName}}InfoList(ctx context.Context, info {{.Package}}Req.{{.StructName}}Search) (list []{{.Package}}.{{.StructName}}, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	filter := bson.M{}
+    // 如果有条件搜索 下方会自动创建搜索语句
+    if len(info.CreatedAtRange) == 2 {
+        filter["createdAt"] = bson.M{"$gte": info.CreatedAtRange[0], "$lte": info.CreatedAtRange[1]}
+    }
+    {{ GenerateMongoSearchConditions .Fields }}
+	query := {{.Abbreviation}}Service.collection().Find(ctx, filter)
+	total, err = query.Count()
+	if err != nil {
+		return
+	}
+	{{- if .NeedSort}}
+	sortMap := map[string]string{
+		"ID":        "_id",
+		"CreatedAt": "createdAt",
+       {{- range .Fields}}
+            {{- if .Sort}}
+		"{{.ColumnName}}": "{{.ColumnName}}",
+         	{{- end}}
+       {{- end}}
+	}
+	if field, ok := sortMap[info.Sort]; ok {
+		if info.Order == "descending" {
+			field = "-" + field
+		}
+		query = query.Sort(field)
+	} else {
+		query = query.Sort("-createdAt")
+	}
+	{{- else }}
+	query = query.Sort("-createdAt")
+	{{- end}}
+	if limit != 0 {
+		query = query.Skip(int64(offset)).Limit(int64(limit))
+	}
+	list = make([]{{.Package}}.{{.StructName}}, 0)
+	err = query.All(&list)
+	return list, total, err
+}
+{{- end }}
+func ({{.Abbreviation}}Service *{{.StructName}}Service)Get{{.StructName}}Public(ctx context.Context) {
+    // 此方法为获取数据源定义的数据
+    // 请自行实现
+}
+{{- end }}
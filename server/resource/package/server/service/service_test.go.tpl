@@ -0,0 +1,30 @@
+{{- if .IsAdd}}
+{{- template "testAddFields" . }}
+{{- else }}
+package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	{{- if .HasTimer }}
+	"time"
+	{{- end }}
+
+	"{{.Module}}/global"
+	"{{.Module}}/model/{{.Package}}"
+	{{- range .RelationPackages }}
+	"{{$.Module}}/model/{{.}}"
+	{{- end }}
+	{{- if not .IsTree }}
+	{{.Package}}Req "{{.Module}}/model/{{.Package}}/request"
+	{{- end }}
+	"github.com/glebarez/sqlite"
+	{{- if .NeedJSON }}
+	"gorm.io/datatypes"
+	{{- end }}
+	"gorm.io/gorm"
+)
+{{- template "serviceTests" (dict "Info" . "Model" .Package "Req" (printf "%sReq" .Package) "Service" (printf "new(%sService)" .StructName)) }}
+{{- end }}
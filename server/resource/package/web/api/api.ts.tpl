@@ -0,0 +1,161 @@
+{{- if .IsAdd}}
+// 在 {{.StructName}} 接口中新增如下字段
+{{- range .Fields}}
+  {{ GenerateTsField . }}
+{{- end }}
+
+// 在 {{.StructName}}Search 接口中新增如下字段
+{{- range .Fields}}
+  {{- if ne .FieldSearchType ""}}
+  {{ GenerateTsSearchField . }}
+  {{- end}}
+{{- end }}
+{{- if .NeedSort}}
+  sort?: string
+  order?: string
+{{- end}}
+{{- else -}}
+import service from '@/utils/request'
+import type { ApiResponse, PageInfo, PageResult } from '@/api/types'
+
+// {{.Description}}
+export interface {{.StructName}} {
+{{- if not .OnlyTemplate}}
+{{- if .GvaModel }}
+  ID?: {{ if .Mongo }}string{{ else }}number{{ end }} // 主键ID
+  CreatedAt?: string // 创建时间
+  UpdatedAt?: string // 更新时间
+{{- end }}
+{{- range .Fields}}
+  {{ GenerateTsField . }}
+{{- end }}
+{{- range .Relations}}
+  {{ GenerateTsRelationField . }}
+{{- end }}
+{{- if .AutoCreateResource }}
+  CreatedBy?: number // 创建者
+  UpdatedBy?: number // 更新者
+  DeletedBy?: number // 删除者
+{{- end }}
+{{- if .IsTree }}
+  children?: {{.StructName}}[] // 子节点
+  parentID?: number // 父节点
+{{- end }}
+{{- end }}
+}
+
+// {{.Description}}搜索条件
+export interface {{.StructName}}Search extends PageInfo {
+{{- if not .OnlyTemplate}}
+{{- if .GvaModel }}
+  createdAtRange?: string[]
+{{- end }}
+{{- range .Fields}}
+  {{- if ne .FieldSearchType ""}}
+  {{ GenerateTsSearchField . }}
+  {{- end}}
+{{- end }}
+{{- if .NeedSort}}
+  sort?: string
+  order?: string
+{{- end}}
+{{- end}}
+}
+
+{{- if not .OnlyTemplate}}
+
+// 创建{{.Description}}
+// @Router /{{.Abbreviation}}/create{{.StructName}} [post]
+export const create{{.StructName}} = (data: {{.StructName}}): Promise<ApiResponse<null>> => {
+  return service({
+    url: '/{{.Abbreviation}}/create{{.StructName}}',
+    method: 'post',
+    data
+  })
+}
+
+// 删除{{.Description}}
+// @Router /{{.Abbreviation}}/delete{{.StructName}} [delete]
+export const delete{{.StructName}} = (params: { {{.PrimaryField.FieldJson}}: {{ GenerateTsType .PrimaryField }} }): Promise<ApiResponse<null>> => {
+  return service({
+    url: '/{{.Abbreviation}}/delete{{.StructName}}',
+    method: 'delete',
+    params
+  })
+}
+
+// 批量删除{{.Description}}
+// @Router /{{.Abbreviation}}/delete{{.StructName}}ByIds [delete]
+export const delete{{.StructName}}ByIds = (params: { {{.PrimaryField.FieldJson}}s: {{ GenerateTsType .PrimaryField }}[] }): Promise<ApiResponse<null>> => {
+  return service({
+    url: '/{{.Abbreviation}}/delete{{.StructName}}ByIds',
+    method: 'delete',
+    params
+  })
+}
+
+// 更新{{.Description}}
+// @Router /{{.Abbreviation}}/update{{.StructName}} [put]
+export const update{{.StructName}} = (data: {{.StructName}}): Promise<ApiResponse<null>> => {
+  return service({
+    url: '/{{.Abbreviation}}/update{{.StructName}}',
+    method: 'put',
+    data
+  })
+}
+
+// 用{{.PrimaryField.FieldJson}}查询{{.Description}}
+// @Router /{{.Abbreviation}}/find{{.StructName}} [get]
+export const find{{.StructName}} = (params: { {{.PrimaryField.FieldJson}}: {{ GenerateTsType .PrimaryField }} }): Promise<ApiResponse<{{.StructName}}>> => {
+  return service({
+    url: '/{{.Abbreviation}}/find{{.StructName}}',
+    method: 'get',
+    params
+  })
+}
+
+{{- if .IsTree }}
+
+// 获取{{.Description}}树
+// @Router /{{.Abbreviation}}/get{{.StructName}}List [get]
+export const get{{.StructName}}List = (): Promise<ApiResponse<{{.StructName}}[]>> => {
+  return service({
+    url: '/{{.Abbreviation}}/get{{.StructName}}List',
+    method: 'get'
+  })
+}
+{{- else }}
+
+// 分页获取{{.Description}}列表
+// @Router /{{.Abbreviation}}/get{{.StructName}}List [get]
+export const get{{.StructName}}List = (params: {{.StructName}}Search): Promise<ApiResponse<PageResult<{{.StructName}}>>> => {
+  return service({
+    url: '/{{.Abbreviation}}/get{{.StructName}}List',
+    method: 'get',
+    params
+  })
+}
+{{- end }}
+
+{{- if .HasDataSource}}
+
+// 获取{{.Description}}的数据源 字段json名=>可选项
+// @Router /{{.Abbreviation}}/get{{.StructName}}DataSource [get]
+export const get{{.StructName}}DataSource = (): Promise<ApiResponse<Record<string, Array<{ label: any, value: any }>>>> => {
+  return service({
+    url: '/{{.Abbreviation}}/get{{.StructName}}DataSource',
+    method: 'get'
+  })
+}
+{{- end}}
+{{- end}}
+
+// 不需要鉴权的{{.Description}}接口
+// @Router /{{.Abbreviation}}/get{{.StructName}}Public [get]
+export const get{{.StructName}}Public = (): Promise<ApiResponse<any>> => {
+  return service({
+    url: '/{{.Abbreviation}}/get{{.StructName}}Public',
+    method: 'get'
+  })
+}
+{{- end }}
@@ -0,0 +1,511 @@
+{{- /* 自动化代码生成的测试公共部分 package 与 plugin 模板通过 dict 传入模型包名等差异 */ -}}
+
+{{- define "testAddFields" }}
+{{- $ptr := printf "ptr%s" .StructName }}
+// 在 new{{.StructName}}TestData 中补充新增字段的测试数据
+{{- range .Fields}}
+		{{.FieldName}}: {{ GenerateTestValue . $ptr }},
+{{- end }}
+{{- end }}
+
+{{- define "testFixtures" }}
+{{- $m := .Model }}{{ $log := .Log }}{{ $info := .Info }}
+{{- with .Info }}
+{{- $ptr := printf "ptr%s" .StructName }}
+{{- $id := "v.ID" }}
+{{- if not .GvaModel }}{{ $id = GenerateTestPrimaryKey .PrimaryField "v" }}{{ end }}
+
+// setup{{.StructName}}TestDB 使用内存sqlite作为{{.Description}}的测试数据库
+func setup{{.StructName}}TestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取数据库连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 内存库只在同一连接内可见
+	if err = migrate{{.StructName}}TestTables(db, &{{$m}}.{{.StructName}}{}{{- range .Relations }}, &{{ if and .Package (ne .Package $info.Package) }}{{.Package}}{{ else }}{{$m}}{{ end }}.{{.StructName}}{}{{- end }}); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+	{{- if $log }}
+	oldLog := global.GVA_LOG
+	global.GVA_LOG = zap.NewNop()
+	{{- end }}
+	{{- if eq .BusinessDB "" }}
+	old := global.GVA_DB
+	global.GVA_DB = db
+	t.Cleanup(func() {
+		global.GVA_DB = old
+		{{- if $log }}
+		global.GVA_LOG = oldLog
+		{{- end }}
+		_ = sqlDB.Close()
+	})
+	{{- else }}
+	old := global.GVA_DBList
+	global.GVA_DBList = map[string]*gorm.DB{"{{.BusinessDB}}": db}
+	t.Cleanup(func() {
+		global.GVA_DBList = old
+		{{- if $log }}
+		global.GVA_LOG = oldLog
+		{{- end }}
+		_ = sqlDB.Close()
+	})
+	{{- end }}
+	return db
+}
+
+// migrate{{.StructName}}TestTables 迁移测试表 sqlite 不支持 enum 类型 改为文本列建表
+func migrate{{.StructName}}TestTables(db *gorm.DB, models ...any) error {
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return err
+		}
+		for _, field := range stmt.Schema.Fields {
+			if strings.HasPrefix(strings.ToLower(string(field.DataType)), "enum") {
+				field.DataType = "text"
+			}
+		}
+	}
+	return db.AutoMigrate(models...)
+}
+
+func {{$ptr}}[T any](v T) *T {
+	return &v
+}
+
+// new{{.StructName}}TestData 生成第 n 条{{.Description}}测试数据
+func new{{.StructName}}TestData(n int) {{$m}}.{{.StructName}} {
+	return {{$m}}.{{.StructName}}{
+		{{- range .Fields }}
+		{{.FieldName}}: {{ GenerateTestValue . $ptr }},
+		{{- end }}
+	}
+}
+
+// {{.Abbreviation}}TestID 读取{{.Description}}的主键
+func {{.Abbreviation}}TestID(v {{$m}}.{{.StructName}}) string {
+	return fmt.Sprint({{$id}})
+}
+{{- end }}
+{{- end }}
+
+{{- define "serviceTests" }}
+{{- $m := .Model }}{{ $req := .Req }}{{ $svc := .Service }}
+{{- template "testFixtures" (dict "Info" .Info "Model" .Model "Log" false) }}
+{{- with .Info }}
+
+func Test{{.StructName}}Service_Create(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	ctx := context.Background()
+	svc := {{$svc}}
+	tests := []struct {
+		name string
+		data {{$m}}.{{.StructName}}
+	}{
+		{name: "创建第一条", data: new{{.StructName}}TestData(1)},
+		{name: "创建第二条", data: new{{.StructName}}TestData(2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			if err := svc.Create{{.StructName}}(ctx, &data); err != nil {
+				t.Fatalf("创建失败: %v", err)
+			}
+			got, err := svc.Get{{.StructName}}(ctx, {{.Abbreviation}}TestID(data))
+			if err != nil {
+				t.Fatalf("查询失败: %v", err)
+			}
+			if {{.Abbreviation}}TestID(got) != {{.Abbreviation}}TestID(data) {
+				t.Fatalf("查询结果主键 = %s, 期望 %s", {{.Abbreviation}}TestID(got), {{.Abbreviation}}TestID(data))
+			}
+		})
+	}
+}
+
+func Test{{.StructName}}Service_Update(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	ctx := context.Background()
+	svc := {{$svc}}
+	created := new{{.StructName}}TestData(1)
+	if err := svc.Create{{.StructName}}(ctx, &created); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	updated := new{{.StructName}}TestData(2)
+	updated.{{.PrimaryField.FieldName}} = created.{{.PrimaryField.FieldName}}
+	if err := svc.Update{{.StructName}}(ctx, updated); err != nil {
+		t.Fatalf("更新失败: %v", err)
+	}
+	if _, err := svc.Get{{.StructName}}(ctx, {{.Abbreviation}}TestID(created)); err != nil {
+		t.Fatalf("更新后查询失败: %v", err)
+	}
+}
+
+func Test{{.StructName}}Service_Delete(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	ctx := context.Background()
+	svc := {{$svc}}
+	tests := []struct {
+		name  string
+		count int
+		batch bool
+	}{
+		{name: "删除单条", count: 1},
+		{name: "批量删除", count: 3, batch: true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := make([]string, 0, tt.count)
+			for j := 0; j < tt.count; j++ {
+				data := new{{.StructName}}TestData(i*10 + j + 1)
+				if err := svc.Create{{.StructName}}(ctx, &data); err != nil {
+					t.Fatalf("创建失败: %v", err)
+				}
+				ids = append(ids, {{.Abbreviation}}TestID(data))
+			}
+			var err error
+			if tt.batch {
+				err = svc.Delete{{.StructName}}ByIds(ctx, ids{{- if .AutoCreateResource }}, 1{{- end }})
+			} else {
+				err = svc.Delete{{.StructName}}(ctx, ids[0]{{- if .AutoCreateResource }}, 1{{- end }})
+			}
+			if err != nil {
+				t.Fatalf("删除失败: %v", err)
+			}
+			for _, id := range ids {
+				if _, err = svc.Get{{.StructName}}(ctx, id); err == nil {
+					t.Fatalf("删除后仍能查询到 %s", id)
+				}
+			}
+		})
+	}
+}
+{{- if .IsTree }}
+
+func Test{{.StructName}}Service_GetInfoList(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	ctx := context.Background()
+	svc := {{$svc}}
+	for n := 1; n <= 3; n++ {
+		data := new{{.StructName}}TestData(n)
+		if err := svc.Create{{.StructName}}(ctx, &data); err != nil {
+			t.Fatalf("创建失败: %v", err)
+		}
+	}
+	list, err := svc.Get{{.StructName}}InfoList(ctx)
+	if err != nil {
+		t.Fatalf("获取列表失败: %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("根节点数量 = %d, 期望 3", len(list))
+	}
+}
+{{- else }}
+
+func Test{{.StructName}}Service_GetInfoList(t *testing.T) {
+	setup{{.StructName}}TestDB(t)
+	ctx := context.Background()
+	svc := {{$svc}}
+	for n := 1; n <= 5; n++ {
+		data := new{{.StructName}}TestData(n)
+		if err := svc.Create{{.StructName}}(ctx, &data); err != nil {
+			t.Fatalf("创建失败: %v", err)
+		}
+	}
+	tests := []struct {
+		name     string
+		page     int
+		pageSize int
+		wantLen  int
+	}{
+		{name: "第一页", page: 1, pageSize: 2, wantLen: 2},
+		{name: "最后一页", page: 3, pageSize: 2, wantLen: 1},
+		{name: "超出范围", page: 4, pageSize: 2, wantLen: 0},
+		{name: "不分页", page: 0, pageSize: 0, wantLen: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var search {{$req}}.{{.StructName}}Search
+			search.Page = tt.page
+			search.PageSize = tt.pageSize
+			list, total, err := svc.Get{{.StructName}}InfoList(ctx, search)
+			if err != nil {
+				t.Fatalf("获取列表失败: %v", err)
+			}
+			if total != 5 {
+				t.Fatalf("总数 = %d, 期望 5", total)
+			}
+			if len(list) != tt.wantLen {
+				t.Fatalf("本页数量 = %d, 期望 %d", len(list), tt.wantLen)
+			}
+		})
+	}
+}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{- define "apiTests" }}
+{{- $m := .Model }}{{ $api := .Api }}
+{{- template "testFixtures" (dict "Info" .Info "Model" .Model "Log" true) }}
+{{- with .Info }}
+{{- $hasRequire := false }}
+{{- range .Fields }}
+{{- if .Require }}{{ $hasRequire = true }}{{ end }}
+{{- end }}
+
+// seed{{.StructName}} 直接写库准备 n 条{{.Description}} 返回主键
+func seed{{.StructName}}(t *testing.T, db *gorm.DB, count int) []string {
+	t.Helper()
+	ids := make([]string, 0, count)
+	for n := 1; n <= count; n++ {
+		data := new{{.StructName}}TestData(n)
+		if err := db.Create(&data).Error; err != nil {
+			t.Fatalf("准备数据失败: %v", err)
+		}
+		ids = append(ids, {{.Abbreviation}}TestID(data))
+	}
+	return ids
+}
+
+// new{{.StructName}}TestRouter 注册{{.Description}}接口 不经过鉴权和操作记录中间件
+func new{{.StructName}}TestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	{{- if .AutoCreateResource }}
+	r.Use(func(c *gin.Context) {
+		c.Set("claims", &systemReq.CustomClaims{BaseClaims: systemReq.BaseClaims{ID: 1}})
+		c.Next()
+	})
+	{{- end }}
+	api := {{$api}}
+	group := r.Group("{{.Abbreviation}}")
+	group.POST("create{{.StructName}}", api.Create{{.StructName}})
+	group.DELETE("delete{{.StructName}}", api.Delete{{.StructName}})
+	group.DELETE("delete{{.StructName}}ByIds", api.Delete{{.StructName}}ByIds)
+	group.PUT("update{{.StructName}}", api.Update{{.StructName}})
+	group.GET("find{{.StructName}}", api.Find{{.StructName}})
+	group.GET("get{{.StructName}}List", api.Get{{.StructName}}List)
+	return r
+}
+
+type {{.Abbreviation}}TestResponse struct {
+	Code int             `json:"code"`
+	Data json.RawMessage `json:"data"`
+	Msg  string          `json:"msg"`
+}
+
+// do{{.StructName}}Request 发起请求 body 为字符串时原样发送 否则编码为JSON
+func do{{.StructName}}Request(t *testing.T, r *gin.Engine, method, path string, body any) {{.Abbreviation}}TestResponse {
+	t.Helper()
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(b)
+	default:
+		raw, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("编码请求失败: %v", err)
+		}
+		reader = bytes.NewBuffer(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%s %s 状态码 = %d", method, path, w.Code)
+	}
+	var resp {{.Abbreviation}}TestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	return resp
+}
+
+func count{{.StructName}}(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var total int64
+	if err := db.Model(&{{$m}}.{{.StructName}}{}).Count(&total).Error; err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	return total
+}
+
+func Test{{.StructName}}Api_Create(t *testing.T) {
+	db := setup{{.StructName}}TestDB(t)
+	r := new{{.StructName}}TestRouter()
+	tests := []struct {
+		name      string
+		body      any
+		wantCode  int
+		wantTotal int64
+	}{
+		{name: "创建成功", body: new{{.StructName}}TestData(1), wantCode: response.SUCCESS, wantTotal: 1},
+		{name: "请求体格式错误", body: "{", wantCode: response.ERROR, wantTotal: 1},
+		{{- if $hasRequire }}
+		{name: "缺少必填字段", body: "{}", wantCode: response.ERROR, wantTotal: 1},
+		{{- end }}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := do{{.StructName}}Request(t, r, http.MethodPost, "/{{.Abbreviation}}/create{{.StructName}}", tt.body)
+			if resp.Code != tt.wantCode {
+				t.Fatalf("code = %d, 期望 %d, msg: %s", resp.Code, tt.wantCode, resp.Msg)
+			}
+			if total := count{{.StructName}}(t, db); total != tt.wantTotal {
+				t.Fatalf("记录数 = %d, 期望 %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func Test{{.StructName}}Api_Find(t *testing.T) {
+	db := setup{{.StructName}}TestDB(t)
+	r := new{{.StructName}}TestRouter()
+	ids := seed{{.StructName}}(t, db, 1)
+	tests := []struct {
+		name     string
+		id       string
+		wantCode int
+	}{
+		{name: "存在", id: ids[0], wantCode: response.SUCCESS},
+		{name: "不存在", id: "-1", wantCode: response.ERROR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{"{{.PrimaryField.FieldJson}}": {tt.id}}
+			resp := do{{.StructName}}Request(t, r, http.MethodGet, "/{{.Abbreviation}}/find{{.StructName}}?"+query.Encode(), nil)
+			if resp.Code != tt.wantCode {
+				t.Fatalf("code = %d, 期望 %d, msg: %s", resp.Code, tt.wantCode, resp.Msg)
+			}
+			if tt.wantCode != response.SUCCESS {
+				return
+			}
+			var got {{$m}}.{{.StructName}}
+			if err := json.Unmarshal(resp.Data, &got); err != nil {
+				t.Fatalf("解析数据失败: %v", err)
+			}
+			if {{.Abbreviation}}TestID(got) != tt.id {
+				t.Fatalf("主键 = %s, 期望 %s", {{.Abbreviation}}TestID(got), tt.id)
+			}
+		})
+	}
+}
+
+func Test{{.StructName}}Api_Update(t *testing.T) {
+	db := setup{{.StructName}}TestDB(t)
+	r := new{{.StructName}}TestRouter()
+	seed{{.StructName}}(t, db, 1)
+	var created {{$m}}.{{.StructName}}
+	if err := db.First(&created).Error; err != nil {
+		t.Fatalf("读取数据失败: %v", err)
+	}
+	updated := new{{.StructName}}TestData(2)
+	updated.{{.PrimaryField.FieldName}} = created.{{.PrimaryField.FieldName}}
+	tests := []struct {
+		name     string
+		body     any
+		wantCode int
+	}{
+		{name: "更新成功", body: updated, wantCode: response.SUCCESS},
+		{name: "请求体格式错误", body: "{", wantCode: response.ERROR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := do{{.StructName}}Request(t, r, http.MethodPut, "/{{.Abbreviation}}/update{{.StructName}}", tt.body)
+			if resp.Code != tt.wantCode {
+				t.Fatalf("code = %d, 期望 %d, msg: %s", resp.Code, tt.wantCode, resp.Msg)
+			}
+		})
+	}
+}
+
+func Test{{.StructName}}Api_Delete(t *testing.T) {
+	db := setup{{.StructName}}TestDB(t)
+	r := new{{.StructName}}TestRouter()
+	ids := seed{{.StructName}}(t, db, 3)
+	tests := []struct {
+		name      string
+		path      string
+		wantTotal int64
+	}{
+		{name: "删除单条", path: "/{{.Abbreviation}}/delete{{.StructName}}?" + url.Values{"{{.PrimaryField.FieldJson}}": {ids[0]}}.Encode(), wantTotal: 2},
+		{name: "批量删除", path: "/{{.Abbreviation}}/delete{{.StructName}}ByIds?" + url.Values{"{{.PrimaryField.FieldJson}}s[]": ids[1:]}.Encode(), wantTotal: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := do{{.StructName}}Request(t, r, http.MethodDelete, tt.path, nil)
+			if resp.Code != response.SUCCESS {
+				t.Fatalf("code = %d, msg: %s", resp.Code, resp.Msg)
+			}
+			if total := count{{.StructName}}(t, db); total != tt.wantTotal {
+				t.Fatalf("记录数 = %d, 期望 %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+{{- if .IsTree }}
+
+func Test{{.StructName}}Api_GetList(t *testing.T) {
+	db := setup{{.StructName}}TestDB(t)
+	r := new{{.StructName}}TestRouter()
+	seed{{.StructName}}(t, db, 3)
+	resp := do{{.StructName}}Request(t, r, http.MethodGet, "/{{.Abbreviation}}/get{{.StructName}}List", nil)
+	if resp.Code != response.SUCCESS {
+		t.Fatalf("code = %d, msg: %s", resp.Code, resp.Msg)
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(resp.Data, &list); err != nil {
+		t.Fatalf("解析数据失败: %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("根节点数量 = %d, 期望 3", len(list))
+	}
+}
+{{- else }}
+
+func Test{{.StructName}}Api_GetList(t *testing.T) {
+	db := setup{{.StructName}}TestDB(t)
+	r := new{{.StructName}}TestRouter()
+	seed{{.StructName}}(t, db, 5)
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantLen  int
+	}{
+		{name: "第一页", query: "page=1&pageSize=2", wantCode: response.SUCCESS, wantLen: 2},
+		{name: "最后一页", query: "page=3&pageSize=2", wantCode: response.SUCCESS, wantLen: 1},
+		{name: "分页参数错误", query: "page=abc", wantCode: response.ERROR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := do{{.StructName}}Request(t, r, http.MethodGet, "/{{.Abbreviation}}/get{{.StructName}}List?"+tt.query, nil)
+			if resp.Code != tt.wantCode {
+				t.Fatalf("code = %d, 期望 %d, msg: %s", resp.Code, tt.wantCode, resp.Msg)
+			}
+			if tt.wantCode != response.SUCCESS {
+				return
+			}
+			var page struct {
+				List  []json.RawMessage `json:"list"`
+				Total int64             `json:"total"`
+			}
+			if err := json.Unmarshal(resp.Data, &page); err != nil {
+				t.Fatalf("解析数据失败: %v", err)
+			}
+			if page.Total != 5 || len(page.List) != tt.wantLen {
+				t.Fatalf("总数 = %d, 本页数量 = %d, 期望 5, %d", page.Total, len(page.List), tt.wantLen)
+			}
+		})
+	}
+}
+{{- end }}
+{{- end }}
+{{- end }}
@@ -8,7 +8,7 @@ import (
 
 func Router(engine *gin.Engine) {
 	public := engine.Group(global.GVA_CONFIG.System.RouterPrefix).Group("")
-	public.Use()
+	public.Use(middleware.PluginGate("{{ .Package }}"))
 	private := engine.Group(global.GVA_CONFIG.System.RouterPrefix).Group("")
-	private.Use(middleware.JWTAuth()).Use(middleware.CasbinHandler())
+	private.Use(middleware.PluginGate("{{ .Package }}")).Use(middleware.JWTAuth()).Use(middleware.CasbinHandler())
 }
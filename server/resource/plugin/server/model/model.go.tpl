@@ -18,6 +18,9 @@ import (
 	{{- if .NeedJSON }}
 	"gorm.io/datatypes"
 	{{- end }}
+	{{- range .RelationPackages }}
+	"{{$.Module}}/model/{{.}}"
+	{{- end }}
 )
 {{- end }}
 
@@ -29,6 +32,9 @@ type {{.StructName}} struct {
 {{- end }}
 {{- range .Fields}}
   {{ GenerateField . }}
+{{- end }}
+{{- range .Relations}}
+  {{ GenerateRelationField . $.Package }}
 {{- end }}
     {{- if .AutoCreateResource }}
     CreatedBy  uint   `gorm:"column:created_by;comment:创建者"`
@@ -2,23 +2,34 @@ package {{ .Package }}
 
 import (
 	"context"
+	_ "embed"
 	"{{.Module}}/plugin/{{ .Package }}/initialize"
 	interfaces "{{.Module}}/utils/plugin/v2"
 	"github.com/gin-gonic/gin"
 )
 
 var _ interfaces.Plugin = (*plugin)(nil)
+var _ interfaces.Describer = (*plugin)(nil)
 
 var Plugin = new(plugin)
 
+//go:embed plugin.json
+var manifest []byte
+
 type plugin struct{}
 
+// Manifest 插件清单 升级插件时修改plugin.json中的版本 需要调整表结构或数据时实现 Migrations() []interfaces.Migration
+func (p *plugin) Manifest() interfaces.Manifest {
+	return interfaces.MustManifest(manifest)
+}
+
 // 如果需要配置文件，请到config.Config中填充配置结构，且到下方发放中填入其在config.yaml中的key并添加如下方法
 // initialize.Viper()
 // 安装插件时候自动注册的api数据请到下方法.Api方法中实现并添加如下方法
 // initialize.Api(ctx)
 // 安装插件时候自动注册的api数据请到下方法.Menu方法中实现并添加如下方法
 // initialize.Menu(ctx)
+// 定时任务请使用 global.GVA_Timer.AddTaskByFunc("{{ .Package }}", ...) 注册在与插件同名的cron中 停用插件时随之暂停
 func (p *plugin) Register(group *gin.Engine) {
 	ctx := context.Background() 
 	initialize.Gorm(ctx)
@@ -0,0 +1,7 @@
+{
+  "name": "{{ .Package }}",
+  "version": "1.0.0",
+  "core": ">={{ coreVersion }}",
+  "dependencies": {},
+  "description": {{ printf "%q" .Description }}
+}
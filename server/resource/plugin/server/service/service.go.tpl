@@ -1,8 +1,8 @@
 {{- $db := "" }}
 {{- if eq .BusinessDB "" }}
- {{- $db = "global.GVA_DB" }}
+ {{- $db = "global.GVA_DB.WithContext(ctx)" }}
 {{- else}}
- {{- $db =  printf "global.MustGetGlobalDBByDBName(\"%s\")" .BusinessDB   }}
+ {{- $db =  printf "global.MustGetGlobalDBByDBName(\"%s\").WithContext(ctx)" .BusinessDB   }}
 {{- end}}
 
 {{- if .IsAdd}}
@@ -40,9 +40,12 @@ import (
     {{- else }}
     "errors"
     {{- end }}
-    {{- if .AutoCreateResource }}
+    {{- if or .AutoCreateResource .HasAssociationWrite }}
     "gorm.io/gorm"
     {{- end}}
+    {{- if .HasRelation }}
+    "gorm.io/gorm/clause"
+    {{- end}}
 {{- if .IsTree }}
     "{{.Module}}/utils"
 {{- end }}
@@ -55,15 +58,15 @@ type {{.Abbreviation}} struct {}
 
 {{- $db := "" }}
 {{- if eq .BusinessDB "" }}
- {{- $db = "global.GVA_DB" }}
+ {{- $db = "global.GVA_DB.WithContext(ctx)" }}
 {{- else}}
- {{- $db =  printf "global.MustGetGlobalDBByDBName(\"%s\")" .BusinessDB   }}
+ {{- $db =  printf "global.MustGetGlobalDBByDBName(\"%s\").WithContext(ctx)" .BusinessDB   }}
 {{- end}}
 {{- if not .OnlyTemplate }}
 // Create{{.StructName}} 创建{{.Description}}记录
 // Author [yourname](https://github.com/yourname)
 func (s *{{.Abbreviation}}) Create{{.StructName}}(ctx context.Context, {{.Abbreviation}} *model.{{.StructName}}) (err error) {
-	err = {{$db}}.Create({{.Abbreviation}}).Error
+	err = {{$db}}{{- if .RelationOmits }}.Omit({{- range $i, $name := .RelationOmits }}{{- if $i }}, {{ end }}"{{$name}}"{{- end }}){{- end }}.Create({{.Abbreviation}}).Error
 	return err
 }
 
@@ -120,14 +123,32 @@ func (s *{{.Abbreviation}}) Delete{{.StructName}}ByIds(ctx context.Context, {{.P
 // Update{{.StructName}} 更新{{.Description}}记录
 // Author [yourname](https://github.com/yourname)
 func (s *{{.Abbreviation}}) Update{{.StructName}}(ctx context.Context, {{.Abbreviation}} model.{{.StructName}}) (err error) {
-	err = {{$db}}.Model(&model.{{.StructName}}{}).Where("{{.PrimaryField.ColumnName}} = ?",{{.Abbreviation}}.{{.PrimaryField.FieldName}}).Updates(&{{.Abbreviation}}).Error
+	{{- if .HasAssociationWrite }}
+	err = {{$db}}.Transaction(func(tx *gorm.DB) error {
+	    if err := tx.Model(&model.{{.StructName}}{}).Where("{{.PrimaryField.ColumnName}} = ?",{{.Abbreviation}}.{{.PrimaryField.FieldName}}).Omit(clause.Associations).Updates(&{{.Abbreviation}}).Error; err != nil {
+            return err
+        }
+        {{- range .Relations }}
+        {{- if ne .Type "belongsTo" }}
+        if {{$.Abbreviation}}.{{.FieldName}} != nil {
+            if err := tx.Model(&{{$.Abbreviation}}).Association("{{.FieldName}}").Replace({{$.Abbreviation}}.{{.FieldName}}); err != nil {
+                return err
+            }
+        }
+        {{- end }}
+        {{- end }}
+        return nil
+    })
+	{{- else }}
+	err = {{$db}}.Model(&model.{{.StructName}}{}).Where("{{.PrimaryField.ColumnName}} = ?",{{.Abbreviation}}.{{.PrimaryField.FieldName}}){{- if .HasRelation }}.Omit(clause.Associations){{- end }}.Updates(&{{.Abbreviation}}).Error
+	{{- end }}
 	return err
 }
 
 // Get{{.StructName}} 根据{{.PrimaryField.FieldJson}}获取{{.Description}}记录
 // Author [yourname](https://github.com/yourname)
 func (s *{{.Abbreviation}}) Get{{.StructName}}(ctx context.Context, {{.PrimaryField.FieldJson}} string) ({{.Abbreviation}} model.{{.StructName}}, err error) {
-	err = {{$db}}.Where("{{.PrimaryField.ColumnName}} = ?", {{.PrimaryField.FieldJson}}).First(&{{.Abbreviation}}).Error
+	err = {{$db}}{{- range .Relations }}{{- if .Preload }}.Preload("{{.FieldName}}"){{- end }}{{- end }}.Where("{{.PrimaryField.ColumnName}} = ?", {{.PrimaryField.FieldJson}}).First(&{{.Abbreviation}}).Error
 	return
 }
 
@@ -140,7 +161,7 @@ func (s *{{.Abbreviation}}) Get{{.StructName}}InfoList(ctx context.Context) (lis
 	db := {{$db}}.Model(&model.{{.StructName}}{})
     var {{.Abbreviation}}s []*model.{{.StructName}}
 
-	err = db.Find(&{{.Abbreviation}}s).Error
+	err = db{{- range .Relations }}{{- if .Preload }}.Preload("{{.FieldName}}"){{- end }}{{- end }}.Find(&{{.Abbreviation}}s).Error
 
 	return utils.BuildTree({{.Abbreviation}}s), err
 }
@@ -188,7 +209,7 @@ func (s *{{.Abbreviation}}) Get{{.StructName}}InfoList(ctx context.Context, info
 	if limit != 0 {
        db = db.Limit(limit).Offset(offset)
     }
-	err = db.Find(&{{.Abbreviation}}s).Error
+	err = db{{- range .Relations }}{{- if .Preload }}.Preload("{{.FieldName}}"){{- end }}{{- end }}.Find(&{{.Abbreviation}}s).Error
 	return  {{.Abbreviation}}s, total, err
 }
 {{- end }}
@@ -0,0 +1,30 @@
+{{- if .IsAdd}}
+{{- template "testAddFields" . }}
+{{- else }}
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	{{- if .HasTimer }}
+	"time"
+	{{- end }}
+
+	"{{.Module}}/global"
+	"{{.Module}}/plugin/{{.Package}}/model"
+	{{- range .RelationPackages }}
+	"{{$.Module}}/model/{{.}}"
+	{{- end }}
+	{{- if not .IsTree }}
+	"{{.Module}}/plugin/{{.Package}}/model/request"
+	{{- end }}
+	"github.com/glebarez/sqlite"
+	{{- if .NeedJSON }}
+	"gorm.io/datatypes"
+	{{- end }}
+	"gorm.io/gorm"
+)
+{{- template "serviceTests" (dict "Info" . "Model" "model" "Req" "request" "Service" (printf "new(%s)" .Abbreviation)) }}
+{{- end }}
@@ -20,6 +20,13 @@ type RouterGroup struct {
 	SysExportTemplateRouter
 	SysParamsRouter
 	ConfigManagerRouter
+	AuditChainRouter
+	AuditExportRouter
+	EntityVersionRouter
+	OperationRecordArchiveRouter
+	I18nRouter
+	FeatureFlagRouter
+	AccessTokenRouter
 }
 
 var (
@@ -42,5 +49,12 @@ var (
 	dictionaryDetailApi = api.ApiGroupApp.SystemApiGroup.DictionaryDetailApi
 	autoCodeTemplateApi = api.ApiGroupApp.SystemApiGroup.AutoCodeTemplateApi
 	exportTemplateApi   = api.ApiGroupApp.SystemApiGroup.SysExportTemplateApi
+	auditChainApi       = api.ApiGroupApp.SystemApiGroup.AuditChainApi
+	auditExportApi      = api.ApiGroupApp.SystemApiGroup.AuditExportApi
+	entityVersionApi    = api.ApiGroupApp.SystemApiGroup.EntityVersionApi
+	recordArchiveApi    = api.ApiGroupApp.SystemApiGroup.OperationRecordArchiveApi
+	i18nApi             = api.ApiGroupApp.SystemApiGroup.I18nApi
+	featureFlagApi      = api.ApiGroupApp.SystemApiGroup.FeatureFlagApi
+	accessTokenApi      = api.ApiGroupApp.SystemApiGroup.AccessTokenApi
 	// configManagerApi 在路由初始化时获取
 )
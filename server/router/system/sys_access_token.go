@@ -0,0 +1,21 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type AccessTokenRouter struct{}
+
+// InitAccessTokenRouter 初始化 个人访问令牌 路由信息
+func (s *AccessTokenRouter) InitAccessTokenRouter(Router *gin.RouterGroup) {
+	accessTokenRouter := Router.Group("accessToken").Use(middleware.OperationRecord())
+	accessTokenRouterWithoutRecord := Router.Group("accessToken")
+	{
+		accessTokenRouter.POST("createAccessToken", accessTokenApi.CreateAccessToken)   // 创建个人访问令牌
+		accessTokenRouter.DELETE("deleteAccessToken", accessTokenApi.DeleteAccessToken) // 吊销个人访问令牌
+	}
+	{
+		accessTokenRouterWithoutRecord.GET("getAccessTokenList", accessTokenApi.GetAccessTokenList) // 获取当前用户的个人访问令牌
+	}
+}
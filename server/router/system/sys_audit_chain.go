@@ -0,0 +1,22 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditChainRouter struct{}
+
+// InitAuditChainRouter 初始化 审计哈希链 路由信息
+func (s *AuditChainRouter) InitAuditChainRouter(Router *gin.RouterGroup) {
+	auditChainRouter := Router.Group("auditChain").Use(middleware.OperationRecord())
+	auditChainRouterWithoutRecord := Router.Group("auditChain")
+	{
+		auditChainRouter.POST("createCheckpoint", auditChainApi.CreateAuditCheckpoint) // 生成签名检查点
+	}
+	{
+		auditChainRouterWithoutRecord.GET("getAuditChainInfo", auditChainApi.GetAuditChainInfo) // 获取哈希链信息
+		auditChainRouterWithoutRecord.GET("verify", auditChainApi.VerifyAuditChain)             // 校验哈希链
+		auditChainRouterWithoutRecord.GET("export", auditChainApi.ExportAuditChain)             // 导出哈希链归档
+	}
+}
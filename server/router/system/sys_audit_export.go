@@ -0,0 +1,20 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditExportRouter struct{}
+
+// InitAuditExportRouter 初始化 审计事件SIEM投递 路由信息
+func (s *AuditExportRouter) InitAuditExportRouter(Router *gin.RouterGroup) {
+	auditExportRouter := Router.Group("auditExport").Use(middleware.OperationRecord())
+	auditExportRouterWithoutRecord := Router.Group("auditExport")
+	{
+		auditExportRouter.POST("export", auditExportApi.TriggerAuditExport) // 立即投递
+	}
+	{
+		auditExportRouterWithoutRecord.GET("getAuditExportStatus", auditExportApi.GetAuditExportStatus) // 获取投递状态
+	}
+}
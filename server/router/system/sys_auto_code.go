@@ -10,9 +10,10 @@ func (s *AutoCodeRouter) InitAutoCodeRouter(Router *gin.RouterGroup, RouterPubli
 	autoCodeRouter := Router.Group("autoCode")
 	publicAutoCodeRouter := RouterPublic.Group("autoCode")
 	{
-		autoCodeRouter.GET("getDB", autoCodeApi.GetDB)         // 获取数据库
-		autoCodeRouter.GET("getTables", autoCodeApi.GetTables) // 获取对应数据库的表
-		autoCodeRouter.GET("getColumn", autoCodeApi.GetColumn) // 获取指定表所有字段信息
+		autoCodeRouter.GET("getDB", autoCodeApi.GetDB)               // 获取数据库
+		autoCodeRouter.GET("getTables", autoCodeApi.GetTables)       // 获取对应数据库的表
+		autoCodeRouter.GET("getColumn", autoCodeApi.GetColumn)       // 获取指定表所有字段信息
+		autoCodeRouter.GET("getRelations", autoCodeApi.GetRelations) // 根据外键推断指定表的关联关系
 	}
 	{
 		autoCodeRouter.POST("preview", autoCodeTemplateApi.Preview)   // 获取自动创建代码预览
@@ -30,11 +31,18 @@ func (s *AutoCodeRouter) InitAutoCodeRouter(Router *gin.RouterGroup, RouterPubli
 		autoCodeRouter.POST("createPackage", autoCodePackageApi.Create) // 创建package包
 	}
 	{
-		autoCodeRouter.GET("getTemplates", autoCodePackageApi.Templates) // 创建package包
+		autoCodeRouter.GET("getTemplates", autoCodePackageApi.Templates)                   // 创建package包
+		autoCodeRouter.POST("installTemplatePack", autoCodePackageApi.InstallTemplatePack) // 安装外部模板包
 	}
 	{
-		autoCodeRouter.POST("pubPlug", autoCodePluginApi.Packaged)      // 打包插件
-		autoCodeRouter.POST("installPlugin", autoCodePluginApi.Install) // 自动安装插件
+		autoCodeRouter.POST("generateTypeScript", autoCodeApi.GenerateTypeScript) // 为已注册的api生成TypeScript客户端
+	}
+	{
+		autoCodeRouter.POST("pubPlug", autoCodePluginApi.Packaged)              // 打包插件
+		autoCodeRouter.POST("installPlugin", autoCodePluginApi.Install)         // 自动安装插件
+		autoCodeRouter.POST("uninstallPlugin", autoCodePluginApi.Uninstall)     // 卸载插件
+		autoCodeRouter.GET("getPluginList", autoCodePluginApi.GetPluginList)    // 已安装的插件及状态
+		autoCodeRouter.POST("setPluginState", autoCodePluginApi.SetPluginState) // 启用或停用插件
 
 	}
 	{
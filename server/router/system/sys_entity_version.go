@@ -0,0 +1,22 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type EntityVersionRouter struct{}
+
+// InitEntityVersionRouter 初始化 实体变更历史 路由信息
+func (s *EntityVersionRouter) InitEntityVersionRouter(Router *gin.RouterGroup) {
+	entityVersionRouter := Router.Group("entityVersion").Use(middleware.OperationRecord())
+	entityVersionRouterWithoutRecord := Router.Group("entityVersion")
+	{
+		entityVersionRouter.POST("restore", entityVersionApi.RestoreEntityVersion) // 恢复到指定版本
+	}
+	{
+		entityVersionRouterWithoutRecord.GET("getVersionedTables", entityVersionApi.GetVersionedTables)     // 获取已开启变更历史的表
+		entityVersionRouterWithoutRecord.GET("getEntityVersionList", entityVersionApi.GetEntityVersionList) // 获取实体变更历史
+		entityVersionRouterWithoutRecord.GET("diff", entityVersionApi.DiffEntityVersion)                    // 比较版本差异
+	}
+}
@@ -0,0 +1,25 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type FeatureFlagRouter struct{}
+
+// InitFeatureFlagRouter 初始化 功能开关 路由信息
+func (s *FeatureFlagRouter) InitFeatureFlagRouter(Router *gin.RouterGroup) {
+	featureFlagRouter := Router.Group("featureFlag").Use(middleware.OperationRecord())
+	featureFlagRouterWithoutRecord := Router.Group("featureFlag")
+	{
+		featureFlagRouter.POST("createFeatureFlag", featureFlagApi.CreateFeatureFlag)   // 创建功能开关
+		featureFlagRouter.PUT("updateFeatureFlag", featureFlagApi.UpdateFeatureFlag)    // 更新功能开关
+		featureFlagRouter.DELETE("deleteFeatureFlag", featureFlagApi.DeleteFeatureFlag) // 删除功能开关
+	}
+	{
+		featureFlagRouterWithoutRecord.GET("findFeatureFlag", featureFlagApi.FindFeatureFlag)                 // 用id查询功能开关
+		featureFlagRouterWithoutRecord.GET("getFeatureFlagList", featureFlagApi.GetFeatureFlagList)           // 分页获取功能开关
+		featureFlagRouterWithoutRecord.GET("getFeatureFlagAuditList", featureFlagApi.GetFeatureFlagAuditList) // 分页获取功能开关变更审计
+		featureFlagRouterWithoutRecord.GET("getEvaluatedFlags", featureFlagApi.GetEvaluatedFlags)             // 获取当前用户的功能开关
+	}
+}
@@ -0,0 +1,28 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type I18nRouter struct{}
+
+// InitI18nRouter 初始化 多语言翻译 路由信息
+func (s *I18nRouter) InitI18nRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup) {
+	i18nRouter := Router.Group("sysTranslation").Use(middleware.OperationRecord())
+	i18nRouterWithoutRecord := Router.Group("sysTranslation")
+	i18nRouterWithoutAuth := PublicRouter.Group("sysTranslation")
+	{
+		i18nRouter.POST("createTranslation", i18nApi.CreateTranslation)   // 创建翻译
+		i18nRouter.PUT("updateTranslation", i18nApi.UpdateTranslation)    // 更新翻译
+		i18nRouter.DELETE("deleteTranslation", i18nApi.DeleteTranslation) // 删除翻译
+		i18nRouter.POST("importTranslations", i18nApi.ImportTranslations) // 导入翻译文件
+	}
+	{
+		i18nRouterWithoutRecord.GET("getTranslationList", i18nApi.GetTranslationList) // 分页获取翻译
+		i18nRouterWithoutRecord.GET("exportTranslations", i18nApi.ExportTranslations) // 导出翻译文件
+	}
+	{
+		i18nRouterWithoutAuth.GET("getLocales", i18nApi.GetLocales) // 获取可用语言
+	}
+}
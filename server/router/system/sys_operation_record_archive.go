@@ -0,0 +1,22 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type OperationRecordArchiveRouter struct{}
+
+// InitOperationRecordArchiveRouter 初始化 操作记录归档 路由信息
+func (s *OperationRecordArchiveRouter) InitOperationRecordArchiveRouter(Router *gin.RouterGroup) {
+	archiveRouter := Router.Group("sysOperationRecordArchive").Use(middleware.OperationRecord())
+	archiveRouterWithoutRecord := Router.Group("sysOperationRecordArchive")
+	{
+		archiveRouter.POST("restoreArchive", recordArchiveApi.RestoreArchive) // 恢复归档到数据库
+		archiveRouter.POST("applyRetention", recordArchiveApi.ApplyRetention) // 立即执行保留策略
+	}
+	{
+		archiveRouterWithoutRecord.GET("getArchiveList", recordArchiveApi.GetArchiveList) // 获取归档列表
+		archiveRouterWithoutRecord.GET("queryArchive", recordArchiveApi.QueryArchive)     // 查询归档中的记录
+	}
+}
@@ -18,8 +18,9 @@ func (s *SysParamsRouter) InitSysParamsRouter(Router *gin.RouterGroup, PublicRou
 		sysParamsRouter.PUT("updateSysParams", sysParamsApi.UpdateSysParams)              // 更新参数
 	}
 	{
-		sysParamsRouterWithoutRecord.GET("findSysParams", sysParamsApi.FindSysParams)       // 根据ID获取参数
-		sysParamsRouterWithoutRecord.GET("getSysParamsList", sysParamsApi.GetSysParamsList) // 获取参数列表
-		sysParamsRouterWithoutRecord.GET("getSysParam", sysParamsApi.GetSysParam)           // 根据Key获取参数
+		sysParamsRouterWithoutRecord.GET("findSysParams", sysParamsApi.FindSysParams)             // 根据ID获取参数
+		sysParamsRouterWithoutRecord.GET("getSysParamsList", sysParamsApi.GetSysParamsList)       // 获取参数列表
+		sysParamsRouterWithoutRecord.GET("getSysParam", sysParamsApi.GetSysParam)                 // 根据Key获取参数
+		sysParamsRouterWithoutRecord.GET("getSysParamsHistory", sysParamsApi.GetSysParamsHistory) // 获取参数变更历史
 	}
 }
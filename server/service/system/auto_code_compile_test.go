@@ -0,0 +1,138 @@
+package system
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+)
+
+// copyServerTree 复制服务端代码到临时目录 生成的代码及注入只作用于副本
+func copyServerTree(t *testing.T, dst string) {
+	t.Helper()
+	src, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatalf("获取服务端目录失败: %v", err)
+	}
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		if d.IsDir() {
+			if name := d.Name(); rel != "." && (name[0] == '.' || name == "log" || name == "uploads") {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dst, rel), os.ModePerm)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), content, 0644)
+	})
+	if err != nil {
+		t.Fatalf("复制服务端代码失败: %v", err)
+	}
+}
+
+// sampleAutoCodeFields 覆盖各类字段的示例模型 用于检查生成的测试代码能否编译运行
+func sampleAutoCodeFields() []*request.AutoCodeField {
+	field := func(name, json, column, fieldType, dataTypeLong, search string) *request.AutoCodeField {
+		return &request.AutoCodeField{
+			FieldName: name, FieldDesc: name, FieldJson: json, ColumnName: column, FieldType: fieldType,
+			DataTypeLong: dataTypeLong, FieldSearchType: search, Form: true, Table: true, Desc: true, Clearable: true,
+		}
+	}
+	return []*request.AutoCodeField{
+		field("Title", "title", "title", "string", "191", "LIKE"),
+		field("Count", "count", "count", "int", "", "="),
+		field("Price", "price", "price", "float64", "", ""),
+		field("Enabled", "enabled", "enabled", "bool", "", "="),
+		field("Status", "status", "status", "enum", "'draft','published'", "="),
+		field("PublishedAt", "publishedAt", "published_at", "time.Time", "", "BETWEEN"),
+		field("Meta", "meta", "meta", "json", "", ""),
+		field("Tags", "tags", "tags", "array", "", ""),
+		field("Cover", "cover", "cover", "picture", "", ""),
+		field("AuthorId", "authorId", "author_id", "int", "", "="),
+	}
+}
+
+func TestAutoCodeTemplate_GeneratedTestsCompile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要编译生成的代码")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("未找到go命令")
+	}
+	tests := []struct {
+		template string
+		pkg      string
+		dirs     []string // 需要编译并运行测试的目录 插件的 gen 目录是 gorm gen 的入口 与生成的测试无关 不在此编译
+	}{
+		{template: "package", pkg: "gentest", dirs: []string{"./model/gentest/...", "./service/gentest/...", "./api/v1/gentest/...", "./router/gentest/..."}},
+		{template: "plugin", pkg: "gentestplugin", dirs: []string{"./plugin/gentestplugin/model/...", "./plugin/gentestplugin/service/...", "./plugin/gentestplugin/api/...", "./plugin/gentestplugin/router/..."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			setupPluginTestDB(t, &system.SysAutoCodePackage{}, &system.SysAutoCodeHistory{}, &system.SysAutoCodeSnapshot{})
+			root := t.TempDir()
+			copyServerTree(t, filepath.Join(root, "server"))
+			oldAutoCode := global.GVA_CONFIG.AutoCode
+			global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, global.GVA_CONFIG.AutoCode.Web = root, "server", "web/src"
+			global.GVA_CONFIG.AutoCode.Module = "github.com/flipped-aurora/gin-vue-admin/server"
+			t.Cleanup(func() { global.GVA_CONFIG.AutoCode = oldAutoCode })
+
+			ctx := context.Background()
+			if err := AutoCodePackage.Create(ctx, &request.SysAutoCodePackageCreate{Template: tt.template, PackageName: tt.pkg, Desc: "生成测试"}); err != nil {
+				t.Fatalf("创建包失败: %v", err)
+			}
+			author := request.AutoCode{
+				Package: tt.pkg, StructName: "Author", TableName: "authors", PackageName: "author", HumpPackageName: "author",
+				Abbreviation: "author", Description: "作者", GvaModel: true, AutoMigrate: true, GenerateServer: true, GenerateWeb: true,
+				Fields: []*request.AutoCodeField{{FieldName: "Name", FieldDesc: "名称", FieldJson: "name", ColumnName: "name", FieldType: "string", FieldSearchType: "LIKE", Form: true, Table: true, Desc: true}},
+			}
+			book := request.AutoCode{
+				Package: tt.pkg, StructName: "Book", TableName: "books", PackageName: "book", HumpPackageName: "book",
+				Abbreviation: "book", Description: "图书", GvaModel: true, AutoMigrate: true, GenerateServer: true, GenerateWeb: true,
+				Fields: sampleAutoCodeFields(),
+				Relations: []*request.AutoCodeRelation{{
+					Type: request.RelationBelongsTo, FieldName: "Author", StructName: "Author", Table: "authors",
+					ForeignKey: "author_id", References: "id", Label: "name", Preload: true,
+				}},
+			}
+			// 自定义主键
+			sku := request.AutoCode{
+				Package: tt.pkg, StructName: "Sku", TableName: "skus", PackageName: "sku", HumpPackageName: "sku",
+				Abbreviation: "sku", Description: "商品编码", AutoMigrate: true, GenerateServer: true, GenerateWeb: true,
+				Fields: []*request.AutoCodeField{
+					{FieldName: "Code", FieldDesc: "编码", FieldJson: "code", ColumnName: "code", FieldType: "int", PrimaryKey: true, Form: true, Table: true, Desc: true},
+					{FieldName: "Label", FieldDesc: "名称", FieldJson: "label", ColumnName: "label", FieldType: "string", Form: true, Table: true, Desc: true},
+				},
+			}
+			for _, info := range []request.AutoCode{author, book, sku} {
+				if err := info.Pretreatment(); err != nil {
+					t.Fatalf("[%s]预处理失败: %v", info.StructName, err)
+				}
+				if err := AutoCodeTemplate.Create(ctx, info); err != nil {
+					t.Fatalf("[%s]生成代码失败: %v", info.StructName, err)
+				}
+			}
+
+			cmd := exec.Command("go", append([]string{"test", "-count=1"}, tt.dirs...)...)
+			cmd.Dir = filepath.Join(root, "server")
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("生成的代码测试未通过: %v\n%s", err, output)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils/ast"
 	"github.com/pkg/errors"
+	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -17,8 +19,10 @@ import (
 	model "github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	request "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/filetx"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 var AutocodeHistory = new(autoCodeHistory)
@@ -61,37 +65,12 @@ func (s *autoCodeHistory) Repeat(businessDB, structName, abbreviation, Package s
 // RollBack 回滚
 // Author [SliverHorn](https://github.com/SliverHorn)
 // Author [songzhibin97](https://github.com/songzhibin97)
-func (s *autoCodeHistory) RollBack(ctx context.Context, info request.SysAutoHistoryRollBack) error {
+func (s *autoCodeHistory) RollBack(ctx context.Context, info request.SysAutoHistoryRollBack) (err error) {
 	var history model.SysAutoCodeHistory
-	err := global.GVA_DB.Where("id = ?", info.ID).First(&history).Error
+	err = global.GVA_DB.WithContext(ctx).Where("id = ?", info.ID).First(&history).Error
 	if err != nil {
 		return err
 	}
-	if history.ExportTemplateID != 0 {
-		err = global.GVA_DB.Delete(&model.SysExportTemplate{}, "id = ?", history.ExportTemplateID).Error
-		if err != nil {
-			return err
-		}
-	}
-	if info.DeleteApi {
-		ids := info.ApiIds(history)
-		err = ApiServiceApp.DeleteApisByIds(ids)
-		if err != nil {
-			global.GVA_LOG.Error("ClearTag DeleteApiByIds:", zap.Error(err))
-		}
-	} // 清除API表
-	if info.DeleteMenu {
-		err = BaseMenuServiceApp.DeleteBaseMenu(int(history.MenuID))
-		if err != nil {
-			return errors.Wrap(err, "删除菜单失败!")
-		}
-	} // 清除菜单表
-	if info.DeleteTable {
-		err = s.DropTable(history.BusinessDB, history.Table)
-		if err != nil {
-			return errors.Wrap(err, "删除表失败!")
-		}
-	} // 删除表
 	templates := make(map[string]string, len(history.Templates))
 	for key, template := range history.Templates {
 		{
@@ -116,6 +95,84 @@ func (s *autoCodeHistory) RollBack(ctx context.Context, info request.SysAutoHist
 		templates[key] = template
 	}
 	history.Templates = templates
+	restores := make(map[string]string, len(history.Snapshots))
+	for key, snapshot := range history.Snapshots {
+		file := filepath.Join(global.GVA_CONFIG.AutoCode.Root, filepath.FromSlash(key))
+		current, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if utils.MD5V(current) == snapshot.Hash {
+			restores[file] = snapshot.Content
+		}
+	} // 生成后未被再次修改的文件直接恢复快照 其余文件通过ast回滚注入的代码
+
+	files := filetx.Begin()
+	err = s.rollbackFiles(files, history, restores)
+	if err != nil {
+		if rollbackErr := files.Rollback(); rollbackErr != nil {
+			global.GVA_LOG.Error("恢复回滚前的文件失败!", zap.Error(rollbackErr))
+		}
+		return err
+	}
+	err = global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if history.ExportTemplateID != 0 {
+			err := tx.Delete(&model.SysExportTemplate{}, "id = ?", history.ExportTemplateID).Error
+			if err != nil {
+				return errors.Wrap(err, "删除导出模板失败!")
+			}
+		}
+		if info.DeleteApi {
+			err := ApiServiceApp.deleteApis(tx, info.ApiIds(history))
+			if err != nil {
+				return errors.Wrap(err, "删除API失败!")
+			}
+		} // 清除API表
+		if info.DeleteMenu {
+			err := BaseMenuServiceApp.deleteBaseMenu(tx, int(history.MenuID))
+			if err != nil {
+				return errors.Wrap(err, "删除菜单失败!")
+			}
+		} // 清除菜单表
+		err := tx.Unscoped().Where("package = ? AND struct_name = ?", history.Package, history.StructName).Delete(&model.SysAutoCodeSnapshot{}).Error
+		if err != nil {
+			return errors.Wrap(err, "删除生成快照失败!")
+		} // 清除三方合并的基准
+		err = tx.Model(&model.SysAutoCodeHistory{}).Where("id = ?", info.ID).Update("flag", 1).Error
+		if err != nil {
+			return errors.Wrap(err, "更新失败!")
+		}
+		return nil
+	})
+	if err != nil {
+		if rollbackErr := files.Rollback(); rollbackErr != nil {
+			global.GVA_LOG.Error("恢复回滚前的文件失败!", zap.Error(rollbackErr))
+		}
+		return err
+	}
+	files.Commit()
+	if info.DeleteApi {
+		if err = CasbinServiceApp.FreshCasbin(); err != nil {
+			global.GVA_LOG.Error("回滚后刷新casbin失败!", zap.Error(err))
+		}
+	}
+	if info.DeleteTable {
+		err = s.DropTable(history.BusinessDB, history.Table)
+		if err != nil {
+			return errors.Wrap(err, "删除表失败!")
+		}
+	} // 删除表无法随事务回滚 在文件与数据提交后执行
+	return nil
+}
+
+// rollbackFiles 在文件事务中恢复快照、回滚注入的代码并将生成的文件移入 rm_file
+func (s *autoCodeHistory) rollbackFiles(files *filetx.Tx, history model.SysAutoCodeHistory, restores map[string]string) (err error) {
+	for file, content := range restores {
+		if err = files.WriteFile(file, []byte(content), 0644); err != nil {
+			return errors.Wrapf(err, "[filepath:%s]恢复快照失败!", file)
+		}
+		fmt.Printf("[filepath:%s]恢复生成前的快照成功!\n", file)
+	} // 恢复快照
 	for key, value := range history.Injections {
 		var injection ast.Ast
 		switch key {
@@ -129,6 +186,10 @@ func (s *autoCodeHistory) RollBack(ctx context.Context, info request.SysAutoHist
 			var entity ast.PackageInitializeGorm
 			_ = json.Unmarshal([]byte(value), &entity)
 			injection = &entity
+		case ast.TypePackageInitializeMongo:
+			var entity ast.PackageInitializeMongo
+			_ = json.Unmarshal([]byte(value), &entity)
+			injection = &entity
 		case ast.TypePackageInitializeRouter:
 			var entity ast.PackageInitializeRouter
 			_ = json.Unmarshal([]byte(value), &entity)
@@ -154,33 +215,61 @@ func (s *autoCodeHistory) RollBack(ctx context.Context, info request.SysAutoHist
 			continue
 		}
 		file, _ := injection.Parse("", nil)
-		if file != nil {
-			_ = injection.Rollback(file)
-			err = injection.Format("", nil, file)
-			if err != nil {
-				return err
-			}
-			fmt.Printf("[filepath:%s]回滚注入代码成功!\n", key)
+		if file == nil {
+			continue
 		}
+		path := injectionPath(injection)
+		if _, ok := restores[path]; ok {
+			continue
+		} // 已恢复快照
+		_ = injection.Rollback(file)
+		var builder strings.Builder
+		err = injection.Format("", &builder, file)
+		if err != nil {
+			return err
+		}
+		if err = files.WriteFile(path, []byte(builder.String()), 0644); err != nil {
+			return errors.Wrapf(err, "[filepath:%s]回滚注入代码失败!", path)
+		}
+		fmt.Printf("[filepath:%s]回滚注入代码成功!\n", key)
 	} // 清除注入代码
+	err = files.Validate()
+	if err != nil {
+		return err
+	}
 	removeBasePath := filepath.Join(global.GVA_CONFIG.AutoCode.Root, "rm_file", strconv.FormatInt(int64(time.Now().Nanosecond()), 10))
 	for _, value := range history.Templates {
 		if !filepath.IsAbs(value) {
 			continue
 		}
 		removePath := filepath.Join(removeBasePath, strings.TrimPrefix(value, global.GVA_CONFIG.AutoCode.Root))
-		err = utils.FileMove(value, removePath)
+		info, err := os.Stat(value)
+		if err != nil {
+			return errors.Wrapf(err, "[src:%s][dst:%s]文件移动失败!", value, removePath)
+		}
+		content, err := os.ReadFile(value)
 		if err != nil {
 			return errors.Wrapf(err, "[src:%s][dst:%s]文件移动失败!", value, removePath)
 		}
+		if err = files.WriteFile(removePath, content, info.Mode().Perm()); err != nil {
+			return errors.Wrapf(err, "[src:%s][dst:%s]文件移动失败!", value, removePath)
+		}
+		if err = files.RemoveAll(value); err != nil {
+			return errors.Wrapf(err, "[src:%s][dst:%s]文件移动失败!", value, removePath)
+		}
 	} // 移动文件
-	err = global.GVA_DB.WithContext(ctx).Model(&model.SysAutoCodeHistory{}).Where("id = ?", info.ID).Update("flag", 1).Error
-	if err != nil {
-		return errors.Wrap(err, "更新失败!")
-	}
 	return nil
 }
 
+// injectionPath 注入代码所在的文件 Parse之后各类注入的Path均为绝对路径
+func injectionPath(injection ast.Ast) string {
+	path := reflect.ValueOf(injection).Elem().FieldByName("Path")
+	if !path.IsValid() {
+		return ""
+	}
+	return path.String()
+}
+
 // Delete 删除历史数据
 // Author [SliverHorn](https://github.com/SliverHorn)
 // Author [songzhibin97](https://github.com/songzhibin97)
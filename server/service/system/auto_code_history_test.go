@@ -0,0 +1,143 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	common "github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/ast"
+)
+
+const rollbackGormBiz = `package initialize
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/demo"
+)
+
+func bizModel() error {
+	db := global.GVA_DB
+	err := db.AutoMigrate(demo.Author{}, demo.Book{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+`
+
+func TestAutoCodeHistory_RollBack(t *testing.T) {
+	tests := []struct {
+		name     string
+		children bool // 菜单存在子菜单 删除菜单失败
+		wantErr  string
+	}{
+		{name: "回滚成功"},
+		{name: "删除菜单失败时恢复文件与数据", children: true, wantErr: "此菜单存在子菜单不可删除"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupPluginTestDB(t, &system.SysAutoCodeHistory{}, &system.SysAutoCodeSnapshot{}, &system.SysExportTemplate{},
+				&system.SysBaseMenu{}, &system.SysBaseMenuParameter{}, &system.SysBaseMenuBtn{}, &system.SysAuthorityBtn{}, &system.SysAuthorityMenu{}, &system.SysAuthority{})
+			root := t.TempDir()
+			oldAutoCode := global.GVA_CONFIG.AutoCode
+			global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, global.GVA_CONFIG.AutoCode.Web = root, "server", "web/src"
+			t.Cleanup(func() { global.GVA_CONFIG.AutoCode = oldAutoCode })
+
+			generated := filepath.Join(root, "server", "model", "demo", "book.go")
+			snapshot := filepath.Join(root, "server", "router", "demo", "enter.go")
+			gormBiz := filepath.Join(root, "server", "initialize", "gorm_biz.go")
+			contents := map[string]string{generated: "package demo\n", snapshot: "package demo\n\n// book\n", gormBiz: rollbackGormBiz}
+			for file, content := range contents {
+				if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+					t.Fatalf("创建文件夹失败: %v", err)
+				}
+				if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+					t.Fatalf("写入文件失败: %v", err)
+				}
+			}
+			menu := system.SysBaseMenu{Name: "book", Path: "book"}
+			template := system.SysExportTemplate{Name: "book", TemplateID: "book"}
+			if err := db.Create(&menu).Error; err != nil {
+				t.Fatalf("准备菜单失败: %v", err)
+			}
+			if tt.children {
+				if err := db.Create(&system.SysBaseMenu{Name: "child", Path: "child", ParentId: menu.ID}).Error; err != nil {
+					t.Fatalf("准备子菜单失败: %v", err)
+				}
+			}
+			if err := db.Create(&template).Error; err != nil {
+				t.Fatalf("准备导出模板失败: %v", err)
+			}
+			injection, _ := json.Marshal(&ast.PackageInitializeGorm{Type: ast.TypePackageInitializeGorm, Path: gormBiz, PackageName: "demo", StructName: "Book"})
+			history := system.SysAutoCodeHistory{
+				Package:          "demo",
+				StructName:       "Book",
+				Templates:        map[string]string{"resource/package/server/model/model.go.tpl": generated},
+				Injections:       map[string]string{ast.TypePackageInitializeGorm: string(injection)},
+				MenuID:           menu.ID,
+				ExportTemplateID: template.ID,
+				Snapshots: system.FileSnapshots{"server/router/demo/enter.go": {
+					Content: "package demo\n",
+					Hash:    utils.MD5V([]byte(contents[snapshot])),
+				}},
+			}
+			if err := db.Create(&history).Error; err != nil {
+				t.Fatalf("准备生成记录失败: %v", err)
+			}
+
+			err := AutocodeHistory.RollBack(context.Background(), request.SysAutoHistoryRollBack{GetById: common.GetById{ID: int(history.ID)}, DeleteMenu: true})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RollBack() error = %v, 期望包含 %s", err, tt.wantErr)
+				}
+				for file, content := range contents {
+					if current, _ := os.ReadFile(file); string(current) != content {
+						t.Errorf("[%s]未恢复回滚前的内容: %s", file, current)
+					}
+				}
+				if entries, _ := os.ReadDir(filepath.Join(root, "rm_file")); len(entries) != 0 {
+					t.Errorf("失败时不应保留移动后的文件: %v", entries)
+				}
+				var count int64
+				db.Model(&system.SysExportTemplate{}).Count(&count)
+				if count != 1 {
+					t.Errorf("导出模板数 = %d, 期望删除被撤销", count)
+				}
+				db.Model(&system.SysAutoCodeHistory{}).Where("id = ? AND flag = ?", history.ID, 0).Count(&count)
+				if count != 1 {
+					t.Errorf("生成记录不应被标记为已回滚")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RollBack() error = %v", err)
+			}
+			if _, err = os.Stat(generated); !os.IsNotExist(err) {
+				t.Errorf("生成的文件未被移除: %v", err)
+			}
+			if current, _ := os.ReadFile(snapshot); string(current) != "package demo\n" {
+				t.Errorf("未恢复快照: %s", current)
+			}
+			if current, _ := os.ReadFile(gormBiz); strings.Contains(string(current), "demo.Book{}") || !strings.Contains(string(current), "demo.Author{}") {
+				t.Errorf("未回滚注入的代码: %s", current)
+			}
+			var count int64
+			db.Model(&system.SysBaseMenu{}).Count(&count)
+			if count != 0 {
+				t.Errorf("菜单数 = %d, 期望 0", count)
+			}
+			db.Model(&system.SysAutoCodeHistory{}).Where("id = ? AND flag = ?", history.ID, 1).Count(&count)
+			if count != 1 {
+				t.Errorf("生成记录未标记为已回滚")
+			}
+		})
+	}
+}
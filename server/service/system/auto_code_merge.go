@@ -0,0 +1,57 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	model "github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/merge"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// relativePath 生成文件相对于项目根目录的路径 用作预览、快照和冲突处理的键
+func (s *autoCodeTemplate) relativePath(file string) string {
+	if len(file) > len(global.GVA_CONFIG.AutoCode.Root) {
+		if rel, err := filepath.Rel(global.GVA_CONFIG.AutoCode.Root, file); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// merge 将重新生成的内容与本地文件进行三方合并 以上次生成的快照为基准 没有快照时整个文件的差异视为冲突
+// 本地文件不存在时返回nil 直接写入生成的内容即可
+func (s *autoCodeTemplate) merge(ctx context.Context, file string, generated string) (*merge.Result, error) {
+	current, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "[filepath:%s]读取文件失败!", file)
+	}
+	var snapshot model.SysAutoCodeSnapshot
+	err = global.GVA_DB.WithContext(ctx).Where("path = ?", s.relativePath(file)).First(&snapshot).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.Wrap(err, "查询生成快照失败!")
+	}
+	return merge.Merge3(snapshot.Content, string(current), generated), nil
+}
+
+// saveSnapshots 保存本次生成的原始内容 作为下次重新生成时的合并基准
+func (s *autoCodeTemplate) saveSnapshots(tx *gorm.DB, info request.AutoCode, files map[string]string) error {
+	for file, content := range files {
+		path := s.relativePath(file)
+		if err := tx.Unscoped().Where("path = ?", path).Delete(&model.SysAutoCodeSnapshot{}).Error; err != nil {
+			return err
+		}
+		snapshot := model.SysAutoCodeSnapshot{Package: info.Package, StructName: info.StructName, Path: path, Content: content}
+		if err := tx.Create(&snapshot).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
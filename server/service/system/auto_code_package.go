@@ -7,9 +7,12 @@ import (
 	common "github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
 	model "github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils/ast"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils/autocode"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/v2"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/templatepack"
 	"github.com/pkg/errors"
 	"go/token"
 	"gorm.io/gorm"
@@ -27,6 +30,14 @@ type autoCodePackage struct{}
 // @author: [piexlmax](https://github.com/piexlmax)
 // @author: [SliverHorn](https://github.com/SliverHorn)
 func (s *autoCodePackage) Create(ctx context.Context, info *request.SysAutoCodePackageCreate) error {
+	if info.TemplatePack != "" {
+		pack, err := s.templatePack(info.TemplatePack, info.TemplateVersion)
+		if err != nil {
+			return err
+		}
+		info.Template = pack.Extends
+		info.TemplateVersion = pack.Version
+	} // 外部模板包 基于其继承的内置模板创建包
 	switch {
 	case info.Template == "":
 		return errors.New("模板不能为空!")
@@ -59,7 +70,7 @@ func (s *autoCodePackage) Create(ctx context.Context, info *request.SysAutoCodeP
 		}
 		for key, value := range creates { // key 为 模版绝对路径
 			var files *template.Template
-			files, err = template.New(filepath.Base(key)).Funcs(autocode.GetTemplateFuncMap()).ParseFiles(key)
+			files, err = parseAutoCodeTemplate(key)
 			if err != nil {
 				return errors.Wrapf(err, "[filepath:%s]读取模版文件失败!", key)
 			}
@@ -214,10 +225,55 @@ func (s *autoCodePackage) All(ctx context.Context) (entities []model.SysAutoCode
 	return entities, nil
 }
 
-// Templates 获取所有模版文件夹
+// Schema 获取包内由代码生成器创建的表及字段 同一张表多次生成时取最近一次
+func (s *autoCodePackage) Schema(ctx context.Context, packageName string) (schema []response.AutoCodeTableSchema, err error) {
+	var histories []model.SysAutoCodeHistory
+	err = global.GVA_DB.WithContext(ctx).Where("package = ? AND flag = ?", packageName, 0).Order("id desc").Find(&histories).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "获取生成记录失败!")
+	}
+	seen := make(map[string]bool, len(histories))
+	schema = make([]response.AutoCodeTableSchema, 0, len(histories))
+	for _, history := range histories {
+		key := history.BusinessDB + "." + history.Table
+		if history.Table == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		table := response.AutoCodeTableSchema{
+			TableName:   history.Table,
+			StructName:  history.StructName,
+			Description: history.Description,
+			BusinessDB:  history.BusinessDB,
+		}
+		var autoCodeService AutoCodeService
+		table.Columns, err = autoCodeService.Database(history.BusinessDB).GetColumn(history.BusinessDB, history.Table, s.dbName(history.BusinessDB))
+		if err != nil {
+			// 表可能已被手动删除 不影响其余表
+			table.Error = err.Error()
+		}
+		schema = append(schema, table)
+	}
+	return schema, nil
+}
+
+// dbName 业务库对应的数据库名 为空时使用当前主库
+func (s *autoCodePackage) dbName(businessDB string) string {
+	for _, db := range global.GVA_CONFIG.DBList {
+		if businessDB != "" && db.AliasName == businessDB {
+			return db.Dbname
+		}
+	}
+	if global.GVA_ACTIVE_DBNAME != nil {
+		return *global.GVA_ACTIVE_DBNAME
+	}
+	return ""
+}
+
+// Templates 获取所有模版文件夹以及已安装的外部模板包
 // @author: [SliverHorn](https://github.com/SliverHorn)
-func (s *autoCodePackage) Templates(ctx context.Context) ([]string, error) {
-	templates := make([]string, 0)
+func (s *autoCodePackage) Templates(ctx context.Context) ([]response.AutoCodeTemplate, error) {
+	templates := make([]response.AutoCodeTemplate, 0)
 	entries, err := os.ReadDir("resource")
 	if err != nil {
 		return nil, errors.Wrap(err, "读取模版文件夹失败!")
@@ -236,16 +292,26 @@ func (s *autoCodePackage) Templates(ctx context.Context) ([]string, error) {
 			if entries[i].Name() == "mcp" {
 				continue
 			} // preview 为mcp生成器的代码
-			templates = append(templates, entries[i].Name())
+			if entries[i].Name() == "packs" {
+				continue
+			} // packs 为外部模板包
+			templates = append(templates, response.AutoCodeTemplate{Name: entries[i].Name(), Builtin: true})
 		}
 	}
-	return templates, nil
+	packs, err := s.templatePacks()
+	if err != nil {
+		return nil, err
+	}
+	return append(templates, packs...), nil
 }
 
 func (s *autoCodePackage) templates(ctx context.Context, entity model.SysAutoCodePackage, info request.AutoCode, isPackage bool) (code map[string]string, asts map[string]ast.Ast, creates map[string]string, err error) {
 	code = make(map[string]string)
 	asts = make(map[string]ast.Ast)
 	creates = make(map[string]string)
+	if info.Mongo && entity.Template != "package" {
+		return nil, nil, nil, errors.New("MongoDB代码仅支持package模板!")
+	}
 	templateDir := filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "resource", entity.Template)
 	templateDirs, err := os.ReadDir(templateDir)
 	if err != nil {
@@ -286,6 +352,10 @@ func (s *autoCodePackage) templates(ctx context.Context, entity model.SysAutoCod
 						creates[three] = pluginInitialize.Path
 						continue
 					}
+					if name == plugin.ManifestFile {
+						creates[three] = filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "plugin", entity.PackageName, name)
+						continue
+					} // 插件清单
 					return nil, nil, nil, errors.Errorf("[filpath:%s]非法模版文件!", three)
 				}
 				switch secondDirs[j].Name() {
@@ -307,6 +377,9 @@ func (s *autoCodePackage) templates(ctx context.Context, entity model.SysAutoCod
 						if ext != ".tpl" {
 							return nil, nil, nil, errors.Errorf("[filpath:%s]非法模版后缀!", four)
 						}
+						if skipMongoTemplate(four, info.Mongo) {
+							continue
+						}
 						api := strings.Index(threeDirs[k].Name(), "api")
 						hasEnter := strings.Index(threeDirs[k].Name(), "enter")
 						router := strings.Index(threeDirs[k].Name(), "router")
@@ -319,6 +392,12 @@ func (s *autoCodePackage) templates(ctx context.Context, entity model.SysAutoCod
 							if api != -1 {
 								create = filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, secondDirs[j].Name(), "v1", entity.PackageName, info.HumpPackageName+".go")
 							}
+							if strings.HasSuffix(strings.TrimSuffix(threeDirs[k].Name(), ext), "_test.go") {
+								if info.OnlyTemplate || info.Mongo {
+									continue
+								} // 仅生成模板时没有可测试的方法 MongoDB代码的测试依赖MongoDB服务 不生成
+								create = strings.TrimSuffix(create, ".go") + "_test.go"
+							} // 测试文件与被测代码放在同一目录
 							if hasEnter != -1 {
 								isApi := strings.Index(secondDirs[j].Name(), "api")
 								isRouter := strings.Index(secondDirs[j].Name(), "router")
@@ -456,6 +535,12 @@ func (s *autoCodePackage) templates(ctx context.Context, entity model.SysAutoCod
 							continue
 						} // enter.go
 						create := filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "plugin", entity.PackageName, secondDirs[j].Name(), info.HumpPackageName+".go")
+						if strings.HasSuffix(strings.TrimSuffix(threeDirs[k].Name(), ext), "_test.go") {
+							if info.OnlyTemplate {
+								continue
+							} // 仅生成模板时没有可测试的方法
+							create = strings.TrimSuffix(create, ".go") + "_test.go"
+						} // 测试文件与被测代码放在同一目录
 						code[four] = create
 					}
 				case "gen", "config", "initialize", "plugin", "response":
@@ -563,6 +648,9 @@ func (s *autoCodePackage) templates(ctx context.Context, entity model.SysAutoCod
 								if ext != ".tpl" {
 									return nil, nil, nil, errors.Errorf("[filpath:%s]非法模版后缀!", five)
 								}
+								if skipMongoTemplate(five, info.Mongo) {
+									continue
+								}
 								hasRequest := strings.Index(fourDirs[l].Name(), "request")
 								if hasRequest == -1 {
 									return nil, nil, nil, errors.Errorf("[filpath:%s]非法模版文件!", five)
@@ -579,11 +667,26 @@ func (s *autoCodePackage) templates(ctx context.Context, entity model.SysAutoCod
 						if ext != ".tpl" {
 							return nil, nil, nil, errors.Errorf("[filpath:%s]非法模版后缀!", four)
 						}
+						if skipMongoTemplate(four, info.Mongo) {
+							continue
+						}
 						hasModel := strings.Index(threeDirs[k].Name(), "model")
 						if hasModel == -1 {
 							return nil, nil, nil, errors.Errorf("[filpath:%s]非法模版文件!", four)
 						}
 						create := filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "plugin", entity.PackageName, secondDirs[j].Name(), info.HumpPackageName+".go")
+						if entity.Template == "package" && info.Mongo {
+							packageInitializeMongo := &ast.PackageInitializeMongo{
+								Type:        ast.TypePackageInitializeMongo,
+								Path:        filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "initialize", "mongo_biz.go"),
+								ImportPath:  fmt.Sprintf(`"%s/model/%s"`, global.GVA_CONFIG.AutoCode.Module, entity.PackageName),
+								StructName:  info.StructName,
+								PackageName: entity.PackageName,
+							}
+							asts[packageInitializeMongo.Path+"=>"+packageInitializeMongo.Type.String()] = packageInitializeMongo
+							code[four] = filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, secondDirs[j].Name(), entity.PackageName, info.HumpPackageName+".go")
+							continue
+						} // MongoDB模型注册到mongo_biz.go 启动时创建索引
 						if entity.Template == "package" {
 							packageInitializeGorm := &ast.PackageInitializeGorm{
 								Type:        ast.TypePackageInitializeGorm,
@@ -679,5 +782,45 @@ func (s *autoCodePackage) templates(ctx context.Context, entity model.SysAutoCod
 			return nil, nil, nil, errors.Errorf("[filpath:%s]非法模版文件!", second)
 		}
 	}
+	if !isPackage {
+		var pack *templatepack.Pack
+		pack, err = s.entityTemplatePack(entity)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if pack != nil {
+			err = s.packTemplates(ctx, pack, entity, info, code)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	} // 外部模板包
 	return code, asts, creates, nil
 }
+
+// parseAutoCodeTemplate 解析模版文件 同时载入 resource/partial 下的公共模版定义
+func parseAutoCodeTemplate(path string) (*template.Template, error) {
+	files, err := template.New(filepath.Base(path)).Funcs(autocode.GetTemplateFuncMap()).ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	partials, err := filepath.Glob(filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "resource", "partial", "*.tpl"))
+	if err != nil || len(partials) == 0 {
+		return files, err
+	}
+	return files.ParseFiles(partials...)
+}
+
+// skipMongoTemplate 按是否生成MongoDB代码选择模板 xxx.mongo.go.tpl 仅用于MongoDB代码
+// 生成MongoDB代码时 存在对应MongoDB模板的普通模板被跳过
+func skipMongoTemplate(path string, mongo bool) bool {
+	name := strings.TrimSuffix(filepath.Base(path), ".tpl")
+	ext := filepath.Ext(name)
+	if strings.HasSuffix(strings.TrimSuffix(name, ext), ".mongo") {
+		return !mongo
+	}
+	if !mongo {
+		return false
+	}
+	return utils.FileExist(filepath.Join(filepath.Dir(path), strings.TrimSuffix(name, ext)+".mongo"+ext+".tpl"))
+}
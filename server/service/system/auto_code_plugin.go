@@ -3,23 +3,32 @@ package system
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
-	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils/ast"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/filetx"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/archive"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/v2"
 	"github.com/mholt/archives"
-	cp "github.com/otiai10/copy"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	goast "go/ast"
+	"go/format"
 	"go/parser"
 	"go/printer"
 	"go/token"
+	"gorm.io/gorm"
 	"io"
+	"io/fs"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -27,116 +36,221 @@ var AutoCodePlugin = new(autoCodePlugin)
 
 type autoCodePlugin struct{}
 
-// Install 插件安装
-func (s *autoCodePlugin) Install(file *multipart.FileHeader) (web, server int, err error) {
-	const GVAPLUGPINATH = "./gva-plug-temp/"
-	defer os.RemoveAll(GVAPLUGPINATH)
-	_, err = os.Stat(GVAPLUGPINATH)
-	if os.IsNotExist(err) {
-		os.Mkdir(GVAPLUGPINATH, os.ModePerm)
+// Install 插件安装 校验安装包的结构、大小与签名后写入插件自身目录 并写入数据清单中的菜单/API/字典/参数且授权给authorityId
+// preview为true时只返回将写入的文件与将新增的数据
+func (s *autoCodePlugin) Install(ctx context.Context, file *multipart.FileHeader, preview bool, authorityId uint) (result response.PluginInstall, err error) {
+	cfg := global.GVA_CONFIG.PluginPackage
+	limits := archive.Limits{MaxSize: cfg.MaxSize << 20, MaxFiles: cfg.MaxFiles}
+	maxSize := limits.MaxSize
+	if maxSize <= 0 {
+		maxSize = archive.DefaultMaxSize
+	}
+	if file.Size > maxSize {
+		return result, errors.Errorf("插件安装包大小超过上限%dMB!", maxSize>>20)
 	}
-
 	src, err := file.Open()
 	if err != nil {
-		return -1, -1, err
+		return result, err
 	}
 	defer src.Close()
-
-	out, err := os.Create(GVAPLUGPINATH + file.Filename)
+	content, err := io.ReadAll(io.LimitReader(src, maxSize+1))
 	if err != nil {
-		return -1, -1, err
+		return result, err
+	}
+	if int64(len(content)) > maxSize {
+		return result, errors.Errorf("插件安装包大小超过上限%dMB!", maxSize>>20)
+	}
+	pkg, err := archive.Read(bytes.NewReader(content), int64(len(content)), limits)
+	if err != nil {
+		return result, err
+	}
+	result.Plugin = pkg.Plugin
+	result.Signed = pkg.Signature != nil
+	err = s.verify(pkg, &result)
+	if err != nil {
+		return result, err
+	}
+	err = s.checkManifest(ctx, pkg, &result)
+	if err != nil {
+		return result, err
+	}
+	var data plugin.Data
+	if f, ok := pkg.Lookup(archive.SideServer, plugin.DataFile); ok {
+		data, err = plugin.ParseData(f.Content)
+		if err != nil {
+			return result, err
+		}
 	}
-	defer out.Close()
-
-	_, err = io.Copy(out, src)
-
-	paths, err := utils.Unzip(GVAPLUGPINATH+file.Filename, GVAPLUGPINATH)
-	paths = filterFile(paths)
-	var webIndex = -1
-	var serverIndex = -1
-	webPlugin := ""
-	serverPlugin := ""
-
-	for i := range paths {
-		paths[i] = filepath.ToSlash(paths[i])
-		pathArr := strings.Split(paths[i], "/")
-		ln := len(pathArr)
 
-		if ln < 4 {
+	root := global.GVA_CONFIG.AutoCode.Root
+	dirs := map[string]string{
+		archive.SideServer: filepath.Join(root, global.GVA_CONFIG.AutoCode.Server, "plugin"),
+		archive.SideWeb:    filepath.Join(root, global.GVA_CONFIG.AutoCode.Web, "plugin"),
+	}
+	var replaced []string
+	for _, side := range []string{archive.SideServer, archive.SideWeb} {
+		if len(pkg.Side(side)) == 0 {
 			continue
 		}
-		if pathArr[2]+"/"+pathArr[3] == `server/plugin` && len(serverPlugin) == 0 {
-			serverPlugin = filepath.Join(pathArr[0], pathArr[1], pathArr[2], pathArr[3])
+		dir := filepath.Join(dirs[side], pkg.Plugin)
+		if _, err = os.Lstat(dir); errors.Is(err, os.ErrNotExist) {
+			continue
 		}
-		if pathArr[2]+"/"+pathArr[3] == `web/plugin` && len(webPlugin) == 0 {
-			webPlugin = filepath.Join(pathArr[0], pathArr[1], pathArr[2], pathArr[3])
+		if !result.Upgrade {
+			return result, errors.Errorf("[%s]已存在同名插件, 升级请使用带有更高版本清单的安装包!", relativePath(dir))
 		}
+		removed, err := s.removedFiles(pkg, side, dir)
+		if err != nil {
+			return result, err
+		}
+		result.Removed = append(result.Removed, removed...)
+		replaced = append(replaced, dir)
+	}
+	targets, err := pkg.Targets(dirs)
+	if err != nil {
+		return result, err
+	}
+	result.Files = make([]response.PluginInstallFile, 0, len(targets))
+	for _, target := range targets {
+		result.Files = append(result.Files, response.PluginInstallFile{
+			Path:      relativePath(target.Dest),
+			Size:      target.Size,
+			Overwrite: target.Exists,
+		})
 	}
-	if len(serverPlugin) == 0 && len(webPlugin) == 0 {
-		zap.L().Error("非标准插件，请按照文档自动迁移使用")
-		return webIndex, serverIndex, errors.New("非标准插件，请按照文档自动迁移使用")
+	if preview {
+		result.Data, err = s.previewData(ctx, pkg.Plugin, data, authorityId)
+		return result, err
 	}
 
-	if len(serverPlugin) != 0 {
-		err = installation(serverPlugin, global.GVA_CONFIG.AutoCode.Server, global.GVA_CONFIG.AutoCode.Server)
+	files := filetx.Begin()
+	err = func() error {
+		for _, dir := range replaced {
+			if err := files.RemoveAll(dir); err != nil {
+				return err
+			}
+		}
+		for _, target := range targets {
+			if err := files.WriteFile(target.Dest, target.Content, 0644); err != nil {
+				return err
+			}
+		}
+		err := files.Validate()
 		if err != nil {
-			return webIndex, serverIndex, err
+			return err
 		}
+		return global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			result.Data, err = s.applyData(tx, pkg.Plugin, data, authorityId)
+			return err
+		})
+	}()
+	if err != nil {
+		if rollback := files.Rollback(); rollback != nil {
+			global.GVA_LOG.Error("插件安装回滚失败!", zap.Error(rollback))
+		}
+		return result, errors.Wrapf(err, "插件[%s]安装失败!", pkg.Plugin)
 	}
-
-	if len(webPlugin) != 0 {
-		err = installation(webPlugin, global.GVA_CONFIG.AutoCode.Server, global.GVA_CONFIG.AutoCode.Web)
+	files.Commit()
+	result.Installed = true
+	if len(result.Data.Params) > 0 {
+		sysParamsCache.publish()
+	}
+	if len(data.Apis) > 0 {
+		err = CasbinServiceApp.FreshCasbin()
 		if err != nil {
-			return webIndex, serverIndex, err
+			global.GVA_LOG.Error("安装插件后刷新casbin失败!", zap.String("plugin", pkg.Plugin), zap.Error(err))
 		}
 	}
-
-	return 1, 1, err
+	return result, nil
 }
 
-func installation(path string, formPath string, toPath string) error {
-	arr := strings.Split(filepath.ToSlash(path), "/")
-	ln := len(arr)
-	if ln < 3 {
-		return errors.New("arr")
+// verify 配置了受信任公钥或要求签名时校验安装包签名
+func (s *autoCodePlugin) verify(pkg *archive.Package, result *response.PluginInstall) error {
+	cfg := global.GVA_CONFIG.PluginPackage
+	keys, err := archive.ParsePublicKeys(cfg.TrustedKeys)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		if cfg.RequireSignature {
+			return errors.New("已开启插件签名校验但未配置受信任公钥(plugin-package.trusted-keys)!")
+		}
+		return nil
+	}
+	key, err := pkg.Verify(keys)
+	if err != nil {
+		return err
 	}
-	name := arr[ln-3]
+	result.Verified = true
+	result.Signer = base64.StdEncoding.EncodeToString(key)
+	return nil
+}
 
-	var form = filepath.Join(global.GVA_CONFIG.AutoCode.Root, formPath, path)
-	var to = filepath.Join(global.GVA_CONFIG.AutoCode.Root, toPath, "plugin")
-	_, err := os.Stat(to + name)
-	if err == nil {
-		zap.L().Error("autoPath 已存在同名插件，请自行手动安装", zap.String("to", to))
-		return errors.New(toPath + "已存在同名插件，请自行手动安装")
+// checkManifest 读取安装包中插件的清单 检查核心版本兼容与依赖 没有清单的旧版插件跳过检查
+func (s *autoCodePlugin) checkManifest(ctx context.Context, pkg *archive.Package, result *response.PluginInstall) error {
+	f, ok := pkg.Lookup(archive.SideServer, plugin.ManifestFile)
+	if !ok {
+		return nil
+	}
+	manifest, err := plugin.ParseManifest(f.Content)
+	if err != nil {
+		return err
 	}
-	return cp.Copy(form, to, cp.Options{Skip: skipMacSpecialDocument})
+	if manifest.Name != pkg.Plugin {
+		return errors.Errorf("插件清单名称[%s]与插件目录[%s]不一致", manifest.Name, pkg.Plugin)
+	}
+	err = PluginServiceApp.CheckInstall(ctx, manifest)
+	if err != nil {
+		return err
+	}
+	installed, err := PluginServiceApp.GetPlugin(ctx, manifest.Name)
+	if err != nil {
+		return err
+	}
+	result.Version = manifest.Version
+	result.Upgrade = installed.ID != 0 && installed.Version != ""
+	return nil
 }
 
-func filterFile(paths []string) []string {
-	np := make([]string, 0, len(paths))
-	for _, path := range paths {
-		if ok, _ := skipMacSpecialDocument(nil, path, ""); ok {
-			continue
+// removedFiles 升级时插件目录中已存在但新安装包中没有的文件
+func (s *autoCodePlugin) removedFiles(pkg *archive.Package, side string, dir string) (removed []string, err error) {
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
 		}
-		np = append(np, path)
-	}
-	return np
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := pkg.Lookup(side, filepath.ToSlash(rel)); !ok {
+			removed = append(removed, relativePath(path))
+		}
+		return nil
+	})
+	return removed, err
 }
 
-func skipMacSpecialDocument(_ os.FileInfo, src, _ string) (bool, error) {
-	if strings.Contains(src, ".DS_Store") || strings.Contains(src, "__MACOSX") {
-		return true, nil
+// relativePath 相对项目根目录的路径
+func relativePath(path string) string {
+	rel, err := filepath.Rel(global.GVA_CONFIG.AutoCode.Root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
 	}
-	return false, nil
+	return filepath.ToSlash(rel)
 }
 
-func (s *autoCodePlugin) PubPlug(plugName string) (zipPath string, err error) {
-	if plugName == "" {
+// PubPlug 打包插件 打包前导出插件的菜单/API/字典/参数到插件数据清单
+func (s *autoCodePlugin) PubPlug(ctx context.Context, info request.PubPlug) (zipPath string, err error) {
+	if info.PlugName == "" {
 		return "", errors.New("插件名称不能为空")
 	}
 
 	// 防止路径穿越
-	plugName = filepath.Clean(plugName)
+	plugName := filepath.Clean(info.PlugName)
+	if plugName != filepath.Base(plugName) || plugName == "." || plugName == ".." {
+		return "", errors.New("插件名称不合法")
+	}
+	info.PlugName = plugName
 
 	webPath := filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Web, "plugin", plugName)
 	serverPath := filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "plugin", plugName)
@@ -151,6 +265,10 @@ func (s *autoCodePlugin) PubPlug(plugName string) (zipPath string, err error) {
 	if err != nil {
 		return "", errors.New("server路径不存在")
 	}
+	err = s.writeData(ctx, info, serverPath)
+	if err != nil {
+		return "", err
+	}
 
 	fileName := plugName + ".zip"
 	// 创建一个新的zip文件
@@ -170,7 +288,7 @@ func (s *autoCodePlugin) PubPlug(plugName string) (zipPath string, err error) {
 	// (compression is not required; you could use Tar directly)
 	format := archives.CompressedArchive{
 		//Compression: archives.Gz{},
-		Archival:    archives.Zip{},
+		Archival: archives.Zip{},
 	}
 
 	// create the archive
@@ -178,10 +296,34 @@ func (s *autoCodePlugin) PubPlug(plugName string) (zipPath string, err error) {
 	if err != nil {
 		return
 	}
+	if key := global.GVA_CONFIG.PluginPackage.SigningKey; key != "" {
+		err = signPackage(fileName, key)
+		if err != nil {
+			return
+		}
+	}
 
 	return filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, fileName), nil
 }
 
+// signPackage 使用配置的私钥签名打包好的插件 签名写入安装包顶层目录的plugin.sig
+func signPackage(fileName string, encoded string) error {
+	key, err := archive.ParsePrivateKey(encoded)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	cfg := global.GVA_CONFIG.PluginPackage
+	content, err = archive.Sign(content, key, archive.Limits{MaxSize: cfg.MaxSize << 20, MaxFiles: cfg.MaxFiles})
+	if err != nil {
+		return errors.Wrap(err, "插件签名失败!")
+	}
+	return os.WriteFile(fileName, content, 0644)
+}
+
 func (s *autoCodePlugin) InitMenu(menuInfo request.InitMenu) (err error) {
 	menuPath := filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "plugin", menuInfo.PlugName, "initialize", "menu.go")
 	src, err := os.ReadFile(menuPath)
@@ -248,3 +390,375 @@ func (s *autoCodePlugin) InitAPI(apiInfo request.InitApi) (err error) {
 	os.WriteFile(apiPath, bf.Bytes(), 0666)
 	return nil
 }
+
+// pluginSeed 插件初始化时注册的数据 从插件initialize目录中的声明读取 导出时合并插件数据清单
+type pluginSeed struct {
+	menus        []string        // 菜单name
+	apis         []system.SysApi // api路径与请求方法
+	dictionaries []string        // 字典type
+	params       []string        // 参数key 只来自数据清单
+	tables       []string        // AutoMigrate 注册的表
+}
+
+// Uninstall 插件卸载
+// 回滚 initialize 中注入的插件注册代码 删除插件目录以及插件注册的菜单/API/权限/字典/参数 可选删除插件的表
+// 代码回滚后会校验语法以及是否还有文件引用该插件 数据库清理失败时恢复全部文件
+func (s *autoCodePlugin) Uninstall(ctx context.Context, info request.UninstallPlugin) error {
+	name := info.PlugName
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return errors.New("插件名称不合法")
+	}
+	pluginPath := filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "plugin", name)
+	if _, err := os.Stat(pluginPath); err != nil {
+		return errors.Wrapf(err, "插件[%s]不存在!", name)
+	}
+	dependents, err := PluginServiceApp.Dependents(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return errors.Errorf("插件%v依赖插件[%s], 请先卸载依赖它的插件!", dependents, name)
+	}
+	seed, err := s.seed(pluginPath)
+	if err != nil {
+		return err
+	}
+	files := filetx.Begin()
+	err = s.uninstallCode(files, name)
+	if err != nil {
+		_ = files.Rollback()
+		return err
+	}
+	err = global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.uninstallData(tx, name, seed)
+	})
+	if err != nil {
+		_ = files.Rollback()
+		return err
+	}
+	files.Commit()
+	pluginStateCache.publish()
+	sysParamsCache.publish()
+	err = CasbinServiceApp.FreshCasbin()
+	if err != nil {
+		global.GVA_LOG.Error("卸载插件后刷新casbin失败!", zap.String("plugin", name), zap.Error(err))
+	}
+	if !info.DeleteTable {
+		return nil
+	}
+	migrator := global.GVA_DB.WithContext(ctx).Migrator()
+	for _, table := range seed.tables {
+		if !migrator.HasTable(table) {
+			continue
+		}
+		err = migrator.DropTable(table)
+		if err != nil {
+			return errors.Wrapf(err, "插件已卸载 删除表[%s]失败!", table)
+		}
+	}
+	return nil
+}
+
+// seed 读取插件 initialize 目录中注册的菜单/API/字典/表 文件不存在时跳过
+// 数据清单写入的记录登记在 sys_plugin_resources 中 不从清单读取
+func (s *autoCodePlugin) seed(pluginPath string) (seed pluginSeed, err error) {
+	parse := func(name string) (*goast.File, error) {
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(pluginPath, "initialize", name), nil, 0)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "[filepath:%s]解析插件初始化文件失败!", name)
+		}
+		return file, nil
+	}
+	values := func(name string, selector string) ([]map[string]string, error) {
+		file, err := parse(name)
+		if err != nil || file == nil {
+			return nil, err
+		}
+		return ast.FindArrayValues(file, "model", selector), nil
+	}
+	menus, err := values("menu.go", "SysBaseMenu")
+	if err != nil {
+		return seed, err
+	}
+	for _, value := range menus {
+		if value["Name"] != "" {
+			seed.menus = append(seed.menus, value["Name"])
+		}
+	}
+	apis, err := values("api.go", "SysApi")
+	if err != nil {
+		return seed, err
+	}
+	for _, value := range apis {
+		if value["Path"] != "" {
+			seed.apis = append(seed.apis, system.SysApi{Path: value["Path"], Method: value["Method"]})
+		}
+	}
+	dictionaries, err := values("dictionary.go", "SysDictionary")
+	if err != nil {
+		return seed, err
+	}
+	for _, value := range dictionaries {
+		if value["Type"] != "" {
+			seed.dictionaries = append(seed.dictionaries, value["Type"])
+		}
+	}
+	file, err := parse("gorm.go")
+	if err != nil || file == nil {
+		return seed, err
+	}
+	names, err := ast.FindTableNames(filepath.Join(pluginPath, "model"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return seed, errors.Wrap(err, "解析插件model失败!")
+	}
+	for _, structName := range ast.FindAutoMigrateStructs(file) {
+		table, ok := names[structName]
+		if !ok {
+			table = global.GVA_DB.NamingStrategy.TableName(structName)
+		}
+		seed.tables = append(seed.tables, table)
+	}
+	return seed, nil
+}
+
+// uninstallCode 回滚 initialize 中插件的注册代码与引用并删除server/web端的插件目录
+func (s *autoCodePlugin) uninstallCode(files *filetx.Tx, name string) error {
+	server := filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server)
+	importPath := fmt.Sprintf("%s/plugin/%s", global.GVA_CONFIG.AutoCode.Module, name)
+	injections := []ast.Ast{
+		&ast.PluginInitializeV1{Type: ast.TypePluginInitializeV1, ImportPath: strconv.Quote(importPath), PackageName: name},
+		&ast.PluginInitializeV2{Type: ast.TypePluginInitializeV2, ImportPath: strconv.Quote(importPath), PackageName: name},
+	}
+	entries, err := os.ReadDir(filepath.Join(server, "initialize"))
+	if err != nil {
+		return errors.Wrap(err, "读取initialize文件夹失败!")
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".go" {
+			continue
+		}
+		path := filepath.Join(server, "initialize", entry.Name())
+		fileSet := token.NewFileSet()
+		file, err := parser.ParseFile(fileSet, path, nil, parser.ParseComments)
+		if err != nil {
+			return errors.Wrapf(err, "[filepath:%s]解析文件失败!", path)
+		}
+		if !importPlugin(file, importPath) {
+			continue
+		} // 未引用该插件 保持文件原样
+		for _, injection := range injections {
+			_ = injection.Rollback(file)
+		} // bizPluginV1/bizPluginV2 中的注册语句
+		_ = ast.RollbackPluginReference(file, importPath)
+		var buffer bytes.Buffer
+		err = format.Node(&buffer, fileSet, file) // 使用原文件的FileSet 保留注释位置
+		if err != nil {
+			return errors.Wrapf(err, "[filepath:%s]格式化失败!", path)
+		}
+		err = files.WriteFile(path, buffer.Bytes(), 0644)
+		if err != nil {
+			return errors.Wrapf(err, "[filepath:%s]回滚插件注册失败!", path)
+		}
+	}
+	err = files.RemoveAll(filepath.Join(server, "plugin", name))
+	if err != nil {
+		return errors.Wrap(err, "删除server端插件目录失败!")
+	}
+	err = files.RemoveAll(filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Web, "plugin", name))
+	if err != nil {
+		return errors.Wrap(err, "删除web端插件目录失败!")
+	}
+	err = files.Validate()
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(server, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != server && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if err != nil {
+			return errors.Wrapf(err, "[filepath:%s]语法校验失败!", path)
+		}
+		if importPlugin(file, importPath) {
+			return errors.Errorf("[filepath:%s]仍在引用插件[%s], 请手动移除后再卸载!", path, name)
+		}
+		return nil
+	}) // 确认没有遗留的引用 避免卸载后项目无法编译
+}
+
+// importPlugin 文件是否导入了插件包或其子包
+func importPlugin(file *goast.File, importPath string) bool {
+	for _, spec := range file.Imports {
+		value, _ := strconv.Unquote(spec.Path.Value)
+		if value == importPath || strings.HasPrefix(value, importPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// uninstallData 删除插件initialize中注册的菜单/API/权限/字典 以及安装时登记的菜单/API/授权/字典/参数和插件包记录
+// 数据清单中安装前已存在的菜单/API/字典/参数未登记 卸载时保留 授权只撤销安装时新增的
+func (s *autoCodePlugin) uninstallData(tx *gorm.DB, name string, seed pluginSeed) error {
+	var resources []system.SysPluginResource
+	err := tx.Where("plugin = ?", name).Find(&resources).Error
+	if err != nil {
+		return err
+	}
+	recorded := make(map[string][]uint)
+	for _, resource := range resources {
+		switch resource.Kind {
+		case system.SysPluginResourceAuthorityMenu:
+			err = tx.Delete(&system.SysAuthorityMenu{}, "sys_base_menu_id = ? AND sys_authority_authority_id = ?", resource.RecordID, resource.AuthorityId).Error
+		case system.SysPluginResourceAuthorityButton:
+			err = tx.Delete(&system.SysAuthorityBtn{}, "sys_base_menu_btn_id = ? AND authority_id = ?", resource.RecordID, resource.AuthorityId).Error
+		case system.SysPluginResourceCasbin:
+			err = tx.Delete(&gormadapter.CasbinRule{}, "ptype = ? AND v0 = ? AND v1 = ? AND v2 = ?", "p", resource.AuthorityId, resource.Path, resource.Method).Error
+		default:
+			recorded[resource.Kind] = append(recorded[resource.Kind], resource.RecordID)
+		}
+		if err != nil {
+			return errors.Wrap(err, "撤销插件授权失败!")
+		}
+	}
+
+	var menus []system.SysBaseMenu
+	err = tx.Where("id in ? OR name in ?", append(recorded[system.SysPluginResourceMenu], 0), append(seed.menus, "")).Find(&menus).Error
+	if err != nil {
+		return err
+	}
+	if len(menus) > 0 {
+		ids := make([]uint, 0, len(menus))
+		names := make([]string, 0, len(menus))
+		for i := range menus {
+			ids = append(ids, menus[i].ID)
+			names = append(names, menus[i].Name)
+		}
+		var authorities []system.SysAuthority
+		err = tx.Where("default_router in ?", names).Limit(1).Find(&authorities).Error
+		if err != nil {
+			return err
+		}
+		if len(authorities) > 0 {
+			return errors.Errorf("插件菜单正在被角色[%s]作为首页, 请修改后再卸载!", authorities[0].AuthorityName)
+		}
+		var count int64
+		err = tx.Model(&system.SysBaseMenu{}).Where("parent_id in ? AND id not in ?", ids, ids).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("插件菜单下存在其他子菜单, 请移除后再卸载!")
+		}
+		err = tx.Delete(&system.SysBaseMenu{}, "id in ?", ids).Error
+		if err != nil {
+			return errors.Wrap(err, "删除插件菜单失败!")
+		}
+		err = tx.Delete(&system.SysBaseMenuParameter{}, "sys_base_menu_id in ?", ids).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&system.SysBaseMenuBtn{}, "sys_base_menu_id in ?", ids).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&system.SysAuthorityBtn{}, "sys_menu_id in ?", ids).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&system.SysAuthorityMenu{}, "sys_base_menu_id in ?", ids).Error
+		if err != nil {
+			return err
+		}
+	}
+	if ids := recorded[system.SysPluginResourceMenuParameter]; len(ids) > 0 {
+		err = tx.Delete(&system.SysBaseMenuParameter{}, "id in ?", ids).Error
+		if err != nil {
+			return err
+		}
+	} // 已有菜单上新增的参数
+	if ids := recorded[system.SysPluginResourceButton]; len(ids) > 0 {
+		err = tx.Delete(&system.SysBaseMenuBtn{}, "id in ?", ids).Error
+		if err != nil {
+			return errors.Wrap(err, "删除插件按钮失败!")
+		}
+		err = tx.Delete(&system.SysAuthorityBtn{}, "sys_base_menu_btn_id in ?", ids).Error
+		if err != nil {
+			return err
+		}
+	} // 已有菜单上新增的按钮
+
+	apis := seed.apis
+	if ids := recorded[system.SysPluginResourceApi]; len(ids) > 0 {
+		var created []system.SysApi
+		err = tx.Where("id in ?", ids).Find(&created).Error
+		if err != nil {
+			return err
+		}
+		apis = append(apis, created...)
+	}
+	for _, api := range apis {
+		err = tx.Delete(&system.SysApi{}, "path = ? AND method = ?", api.Path, api.Method).Error
+		if err != nil {
+			return errors.Wrap(err, "删除插件API失败!")
+		}
+		err = tx.Delete(&gormadapter.CasbinRule{}, "ptype = ? AND v1 = ? AND v2 = ?", "p", api.Path, api.Method).Error
+		if err != nil {
+			return errors.Wrap(err, "删除插件API权限失败!")
+		}
+	} // 插件自己的API删除后 其全部授权随之失效
+
+	dictionaryIds := recorded[system.SysPluginResourceDictionary]
+	if len(seed.dictionaries) > 0 {
+		var ids []uint
+		err = tx.Model(&system.SysDictionary{}).Where("type in ?", seed.dictionaries).Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		dictionaryIds = append(dictionaryIds, ids...)
+	}
+	if len(dictionaryIds) > 0 {
+		err = tx.Delete(&system.SysDictionaryDetail{}, "sys_dictionary_id in ?", dictionaryIds).Error
+		if err != nil {
+			return errors.Wrap(err, "删除插件字典失败!")
+		}
+		err = tx.Delete(&system.SysDictionary{}, "id in ?", dictionaryIds).Error
+		if err != nil {
+			return errors.Wrap(err, "删除插件字典失败!")
+		}
+	}
+	if ids := recorded[system.SysPluginResourceDictionaryDetail]; len(ids) > 0 {
+		err = tx.Delete(&system.SysDictionaryDetail{}, "id in ?", ids).Error
+		if err != nil {
+			return errors.Wrap(err, "删除插件字典详情失败!")
+		}
+	} // 已有字典中新增的详情
+	if ids := recorded[system.SysPluginResourceParam]; len(ids) > 0 {
+		err = tx.Delete(&system.SysParams{}, "id in ?", ids).Error
+		if err != nil {
+			return errors.Wrap(err, "删除插件参数失败!")
+		}
+	}
+	err = tx.Unscoped().Delete(&system.SysPluginResource{}, "plugin = ?", name).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Unscoped().Delete(&system.SysPlugin{}, "name = ?", name).Error
+	if err != nil {
+		return errors.Wrap(err, "删除插件登记失败!")
+	}
+	return tx.Delete(&system.SysAutoCodePackage{}, "package_name = ? AND template = ?", name, "plugin").Error
+}
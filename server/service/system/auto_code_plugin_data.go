@@ -0,0 +1,553 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/v2"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// errPluginDataPreview 预览数据清单时回滚事务
+var errPluginDataPreview = errors.New("preview")
+
+// writeData 导出插件数据清单并写入插件目录 随插件源码一起打包
+func (s *autoCodePlugin) writeData(ctx context.Context, info request.PubPlug, serverPath string) error {
+	data, err := s.exportData(ctx, info, serverPath)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(serverPath, plugin.DataFile)
+	if data.Empty() {
+		err = os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// exportData 导出插件的菜单(含按钮与参数)、API及其授权、字典、参数 未选择的类别按插件initialize与已有数据清单中声明的记录导出
+func (s *autoCodePlugin) exportData(ctx context.Context, info request.PubPlug, serverPath string) (data plugin.Data, err error) {
+	seed, err := s.seed(serverPath)
+	if err != nil {
+		return data, err
+	}
+	err = seed.readData(serverPath)
+	if err != nil {
+		return data, err
+	}
+	db := global.GVA_DB.WithContext(ctx)
+
+	var menus []system.SysBaseMenu
+	query := db.Preload("MenuBtn").Preload("Parameters").Order("parent_id, sort, id")
+	if len(info.Menus) > 0 {
+		query = query.Where("id in ?", info.Menus)
+	} else {
+		query = query.Where("name in ? OR component LIKE ?", append(seed.menus, ""), system.PluginMenuPrefix(info.PlugName)+"%")
+	}
+	err = query.Find(&menus).Error
+	if err != nil {
+		return data, errors.Wrap(err, "获取插件菜单失败!")
+	}
+	parentIds := make([]uint, 0, len(menus))
+	for _, menu := range menus {
+		if menu.ParentId != 0 {
+			parentIds = append(parentIds, menu.ParentId)
+		}
+	}
+	var parents []system.SysBaseMenu
+	err = db.Select("id", "name").Where("id in ?", append(parentIds, 0)).Find(&parents).Error
+	if err != nil {
+		return data, err
+	}
+	parentNames := make(map[uint]string, len(parents))
+	for _, parent := range parents {
+		parentNames[parent.ID] = parent.Name
+	}
+	for _, menu := range menus {
+		item := plugin.DataMenu{
+			Parent:    parentNames[menu.ParentId],
+			Name:      menu.Name,
+			Path:      menu.Path,
+			Component: menu.Component,
+			Hidden:    menu.Hidden,
+			Sort:      menu.Sort,
+			Meta: plugin.DataMenuMeta{
+				ActiveName:     menu.ActiveName,
+				KeepAlive:      menu.KeepAlive,
+				DefaultMenu:    menu.DefaultMenu,
+				Title:          menu.Title,
+				Icon:           menu.Icon,
+				CloseTab:       menu.CloseTab,
+				TransitionType: menu.TransitionType,
+			},
+		}
+		for _, button := range menu.MenuBtn {
+			item.Buttons = append(item.Buttons, plugin.DataMenuButton{Name: button.Name, Desc: button.Desc})
+		}
+		for _, parameter := range menu.Parameters {
+			item.Parameters = append(item.Parameters, plugin.DataMenuParameter{Type: parameter.Type, Key: parameter.Key, Value: parameter.Value})
+		}
+		data.Menus = append(data.Menus, item)
+	}
+
+	var apis []system.SysApi
+	if len(info.Apis) > 0 {
+		err = db.Where("id in ?", info.Apis).Order("api_group, path").Find(&apis).Error
+	} else if len(seed.apis) > 0 {
+		paths := make([]string, 0, len(seed.apis))
+		for _, api := range seed.apis {
+			paths = append(paths, api.Path)
+		}
+		var candidates []system.SysApi
+		err = db.Where("path in ?", paths).Order("api_group, path").Find(&candidates).Error
+		for _, candidate := range candidates {
+			for _, api := range seed.apis {
+				if api.Path == candidate.Path && api.Method == candidate.Method {
+					apis = append(apis, candidate)
+					break
+				}
+			}
+		}
+	}
+	if err != nil {
+		return data, errors.Wrap(err, "获取插件API失败!")
+	}
+	if len(apis) > 0 {
+		paths := make([]string, 0, len(apis))
+		for _, api := range apis {
+			paths = append(paths, api.Path)
+		}
+		var rules []gormadapter.CasbinRule
+		err = db.Where("ptype = ? AND v1 in ?", "p", paths).Find(&rules).Error
+		if err != nil {
+			return data, errors.Wrap(err, "获取插件API权限失败!")
+		}
+		granted := make(map[string]bool, len(rules))
+		for _, rule := range rules {
+			granted[rule.V2+" "+rule.V1] = true
+		}
+		for _, api := range apis {
+			data.Apis = append(data.Apis, plugin.DataApi{
+				Path:        api.Path,
+				Method:      api.Method,
+				ApiGroup:    api.ApiGroup,
+				Description: api.Description,
+				Grant:       granted[api.Method+" "+api.Path],
+			})
+		}
+	}
+
+	var dictionaries []system.SysDictionary
+	query = db.Preload("SysDictionaryDetails", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort, id")
+	})
+	if len(info.Dictionaries) > 0 {
+		query = query.Where("id in ?", info.Dictionaries)
+	} else {
+		query = query.Where("type in ?", append(seed.dictionaries, ""))
+	}
+	err = query.Find(&dictionaries).Error
+	if err != nil {
+		return data, errors.Wrap(err, "获取插件字典失败!")
+	}
+	for _, dictionary := range dictionaries {
+		data.Dictionaries = append(data.Dictionaries, plugin.DataDictionary{
+			Type:    dictionary.Type,
+			Name:    dictionary.Name,
+			Desc:    dictionary.Desc,
+			Status:  dictionary.Status,
+			Details: exportDetails(dictionary.SysDictionaryDetails, nil),
+		})
+	}
+
+	var params []system.SysParams
+	if len(info.Params) > 0 {
+		err = db.Where("id in ?", info.Params).Find(&params).Error
+	} else {
+		err = db.Where(map[string]any{"key": append(seed.params, "")}).Find(&params).Error
+	}
+	if err != nil {
+		return data, errors.Wrap(err, "获取插件参数失败!")
+	}
+	for _, param := range params {
+		data.Params = append(data.Params, plugin.DataParam{
+			Key:    param.Key,
+			Name:   param.Name,
+			Value:  param.Value,
+			Desc:   param.Desc,
+			Type:   param.Type,
+			Schema: param.Schema,
+		})
+	}
+	return data, data.Validate()
+}
+
+// readData 合并插件目录中已有数据清单声明的菜单/API/字典/参数 文件不存在时跳过
+func (seed *pluginSeed) readData(pluginPath string) error {
+	content, err := os.ReadFile(filepath.Join(pluginPath, plugin.DataFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, err := plugin.ParseData(content)
+	if err != nil {
+		return err
+	}
+	for _, menu := range data.Menus {
+		seed.menus = append(seed.menus, menu.Name)
+	}
+	for _, api := range data.Apis {
+		seed.apis = append(seed.apis, system.SysApi{Path: api.Path, Method: api.Method})
+	}
+	for _, dictionary := range data.Dictionaries {
+		seed.dictionaries = append(seed.dictionaries, dictionary.Type)
+	}
+	for _, param := range data.Params {
+		seed.params = append(seed.params, param.Key)
+	}
+	return nil
+}
+
+// exportDetails 按父级组装字典详情树
+func exportDetails(details []system.SysDictionaryDetail, parentId *uint) []plugin.DataDictionaryDetail {
+	var result []plugin.DataDictionaryDetail
+	for _, detail := range details {
+		if (parentId == nil) != (detail.ParentID == nil) || (parentId != nil && *parentId != *detail.ParentID) {
+			continue
+		}
+		id := detail.ID
+		result = append(result, plugin.DataDictionaryDetail{
+			Label:    detail.Label,
+			Value:    detail.Value,
+			Extend:   detail.Extend,
+			Status:   detail.Status,
+			Sort:     detail.Sort,
+			Children: exportDetails(details, &id),
+		})
+	}
+	return result
+}
+
+// previewData 在回滚的事务中写入数据清单 获取安装时将新增的记录
+func (s *autoCodePlugin) previewData(ctx context.Context, name string, data plugin.Data, authorityId uint) (report response.PluginInstallData, err error) {
+	err = global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		report, err = s.applyData(tx, name, data, authorityId)
+		if err != nil {
+			return err
+		}
+		return errPluginDataPreview
+	})
+	if errors.Is(err, errPluginDataPreview) {
+		err = nil
+	}
+	return report, err
+}
+
+// applyData 幂等写入插件数据清单 已存在的记录保持不变 菜单、按钮与授权API授权给默认角色
+// 新增的记录与授权登记到插件名下 卸载时只删除登记的记录
+// 写入casbin规则与参数后需要由调用方在事务提交后刷新casbin与参数缓存
+func (s *autoCodePlugin) applyData(tx *gorm.DB, name string, data plugin.Data, authorityId uint) (report response.PluginInstallData, err error) {
+	report.AuthorityId = authorityId
+	if data.Empty() {
+		return report, nil
+	}
+	var authority system.SysAuthority
+	err = tx.Where("authority_id = ?", authorityId).Limit(1).Find(&authority).Error
+	if err != nil {
+		return report, err
+	}
+	if authority.AuthorityId == 0 {
+		return report, errors.Errorf("默认角色[%d]不存在!", authorityId)
+	}
+	authorityIdStr := strconv.Itoa(int(authorityId))
+	var resources []system.SysPluginResource
+	record := func(resource system.SysPluginResource) {
+		resource.Plugin = name
+		resources = append(resources, resource)
+	}
+
+	menus, err := data.SortedMenus()
+	if err != nil {
+		return report, err
+	}
+	ids := make(map[string]uint, len(menus))
+	for _, menu := range menus {
+		var entity system.SysBaseMenu
+		err = tx.Where("name = ?", menu.Name).Limit(1).Find(&entity).Error
+		if err != nil {
+			return report, err
+		}
+		if entity.ID == 0 {
+			parentId, ok := ids[menu.Parent]
+			if !ok && menu.Parent != "" {
+				var parent system.SysBaseMenu
+				err = tx.Where("name = ?", menu.Parent).Limit(1).Find(&parent).Error
+				if err != nil {
+					return report, err
+				}
+				if parent.ID == 0 {
+					report.Warnings = append(report.Warnings, "菜单["+menu.Name+"]的父菜单["+menu.Parent+"]不存在, 已作为顶级菜单")
+				}
+				parentId = parent.ID
+			}
+			entity = system.SysBaseMenu{
+				ParentId:  parentId,
+				Path:      menu.Path,
+				Name:      menu.Name,
+				Hidden:    menu.Hidden,
+				Component: menu.Component,
+				Sort:      menu.Sort,
+				Meta: system.Meta{
+					ActiveName:     menu.Meta.ActiveName,
+					KeepAlive:      menu.Meta.KeepAlive,
+					DefaultMenu:    menu.Meta.DefaultMenu,
+					Title:          menu.Meta.Title,
+					Icon:           menu.Meta.Icon,
+					CloseTab:       menu.Meta.CloseTab,
+					TransitionType: menu.Meta.TransitionType,
+				},
+			}
+			err = tx.Omit("SysAuthoritys", "Parameters", "MenuBtn").Create(&entity).Error
+			if err != nil {
+				return report, errors.Wrapf(err, "创建菜单[%s]失败!", menu.Name)
+			}
+			record(system.SysPluginResource{Kind: system.SysPluginResourceMenu, RecordID: entity.ID})
+			report.Menus = append(report.Menus, menu.Name)
+		}
+		ids[menu.Name] = entity.ID
+		for _, parameter := range menu.Parameters {
+			var count int64
+			err = tx.Model(&system.SysBaseMenuParameter{}).Where(map[string]any{"sys_base_menu_id": entity.ID, "type": parameter.Type, "key": parameter.Key}).Count(&count).Error
+			if err != nil {
+				return report, err
+			}
+			if count == 0 {
+				created := system.SysBaseMenuParameter{SysBaseMenuID: entity.ID, Type: parameter.Type, Key: parameter.Key, Value: parameter.Value}
+				err = tx.Create(&created).Error
+				if err != nil {
+					return report, err
+				}
+				record(system.SysPluginResource{Kind: system.SysPluginResourceMenuParameter, RecordID: created.ID})
+			}
+		}
+		granted, err := s.grantMenu(tx, entity.ID, authorityIdStr)
+		if err != nil {
+			return report, err
+		}
+		for _, item := range granted {
+			record(system.SysPluginResource{Kind: system.SysPluginResourceAuthorityMenu, RecordID: item.ID, AuthorityId: authorityIdStr})
+			report.Grants = append(report.Grants, "menu:"+item.Name)
+		}
+		for _, button := range menu.Buttons {
+			var btn system.SysBaseMenuBtn
+			err = tx.Where("sys_base_menu_id = ? AND name = ?", entity.ID, button.Name).Limit(1).Find(&btn).Error
+			if err != nil {
+				return report, err
+			}
+			if btn.ID == 0 {
+				btn = system.SysBaseMenuBtn{Name: button.Name, Desc: button.Desc, SysBaseMenuID: entity.ID}
+				err = tx.Create(&btn).Error
+				if err != nil {
+					return report, errors.Wrapf(err, "创建菜单[%s]的按钮[%s]失败!", menu.Name, button.Name)
+				}
+				record(system.SysPluginResource{Kind: system.SysPluginResourceButton, RecordID: btn.ID})
+				report.Buttons = append(report.Buttons, menu.Name+":"+button.Name)
+			}
+			var count int64
+			err = tx.Model(&system.SysAuthorityBtn{}).Where("authority_id = ? AND sys_menu_id = ? AND sys_base_menu_btn_id = ?", authorityId, entity.ID, btn.ID).Count(&count).Error
+			if err != nil {
+				return report, err
+			}
+			if count == 0 {
+				err = tx.Omit("SysBaseMenuBtn").Create(&system.SysAuthorityBtn{AuthorityId: authorityId, SysMenuID: entity.ID, SysBaseMenuBtnID: btn.ID}).Error
+				if err != nil {
+					return report, err
+				}
+				record(system.SysPluginResource{Kind: system.SysPluginResourceAuthorityButton, RecordID: btn.ID, AuthorityId: authorityIdStr})
+			}
+		}
+	}
+
+	for _, api := range data.Apis {
+		var entity system.SysApi
+		err = tx.Where("path = ? AND method = ?", api.Path, api.Method).Limit(1).Find(&entity).Error
+		if err != nil {
+			return report, err
+		}
+		key := api.Method + " " + api.Path
+		if entity.ID == 0 {
+			entity = system.SysApi{Path: api.Path, Method: api.Method, ApiGroup: api.ApiGroup, Description: api.Description}
+			err = tx.Create(&entity).Error
+			if err != nil {
+				return report, errors.Wrapf(err, "创建API[%s]失败!", key)
+			}
+			record(system.SysPluginResource{Kind: system.SysPluginResourceApi, RecordID: entity.ID})
+			report.Apis = append(report.Apis, key)
+		}
+		if !api.Grant {
+			continue
+		}
+		var count int64
+		err = tx.Model(&gormadapter.CasbinRule{}).Where("ptype = ? AND v0 = ? AND v1 = ? AND v2 = ?", "p", authorityIdStr, api.Path, api.Method).Count(&count).Error
+		if err != nil {
+			return report, err
+		}
+		if count == 0 {
+			err = tx.Create(&gormadapter.CasbinRule{Ptype: "p", V0: authorityIdStr, V1: api.Path, V2: api.Method}).Error
+			if err != nil {
+				return report, errors.Wrapf(err, "授权API[%s]失败!", key)
+			}
+			record(system.SysPluginResource{Kind: system.SysPluginResourceCasbin, AuthorityId: authorityIdStr, Path: api.Path, Method: api.Method})
+			report.Grants = append(report.Grants, key)
+		}
+	}
+
+	for _, dictionary := range data.Dictionaries {
+		var entity system.SysDictionary
+		err = tx.Where("type = ?", dictionary.Type).Limit(1).Find(&entity).Error
+		if err != nil {
+			return report, err
+		}
+		if entity.ID == 0 {
+			status := dictionary.Status
+			if status == nil {
+				enabled := true
+				status = &enabled
+			}
+			entity = system.SysDictionary{Type: dictionary.Type, Name: dictionary.Name, Desc: dictionary.Desc, Status: status}
+			err = tx.Omit("SysDictionaryDetails").Create(&entity).Error
+			if err != nil {
+				return report, errors.Wrapf(err, "创建字典[%s]失败!", dictionary.Type)
+			}
+			record(system.SysPluginResource{Kind: system.SysPluginResourceDictionary, RecordID: entity.ID})
+			report.Dictionaries = append(report.Dictionaries, dictionary.Type)
+		}
+		details, err := applyDetails(tx, int(entity.ID), nil, dictionary.Details)
+		if err != nil {
+			return report, errors.Wrapf(err, "创建字典[%s]详情失败!", dictionary.Type)
+		}
+		for _, id := range details {
+			record(system.SysPluginResource{Kind: system.SysPluginResourceDictionaryDetail, RecordID: id})
+		}
+	}
+
+	for _, param := range data.Params {
+		var entity system.SysParams
+		err = tx.Where(map[string]any{"key": param.Key}).Limit(1).Find(&entity).Error
+		if err != nil {
+			return report, err
+		}
+		if entity.ID != 0 {
+			continue
+		}
+		entity = system.SysParams{Key: param.Key, Name: param.Name, Value: param.Value, Desc: param.Desc, Type: param.Type, Schema: param.Schema}
+		err = validateSysParams(&entity)
+		if err != nil {
+			return report, errors.Wrapf(err, "参数[%s]校验失败!", param.Key)
+		}
+		err = tx.Create(&entity).Error
+		if err != nil {
+			return report, errors.Wrapf(err, "创建参数[%s]失败!", param.Key)
+		}
+		record(system.SysPluginResource{Kind: system.SysPluginResourceParam, RecordID: entity.ID})
+		report.Params = append(report.Params, param.Key)
+	}
+	if len(resources) > 0 {
+		err = tx.Create(&resources).Error
+		if err != nil {
+			return report, errors.Wrap(err, "登记插件数据失败!")
+		}
+	}
+	return report, nil
+}
+
+// grantMenu 将菜单及其上级菜单授权给角色 返回新授权的菜单
+func (s *autoCodePlugin) grantMenu(tx *gorm.DB, id uint, authorityId string) (granted []system.SysBaseMenu, err error) {
+	visited := make(map[uint]bool)
+	for id != 0 && !visited[id] {
+		visited[id] = true
+		var menu system.SysBaseMenu
+		err = tx.Select("id", "parent_id", "name").Where("id = ?", id).Limit(1).Find(&menu).Error
+		if err != nil || menu.ID == 0 {
+			return granted, err
+		}
+		menuId := strconv.Itoa(int(menu.ID))
+		var count int64
+		err = tx.Model(&system.SysAuthorityMenu{}).Where("sys_base_menu_id = ? AND sys_authority_authority_id = ?", menuId, authorityId).Count(&count).Error
+		if err != nil {
+			return granted, err
+		}
+		if count == 0 {
+			err = tx.Create(&system.SysAuthorityMenu{MenuId: menuId, AuthorityId: authorityId}).Error
+			if err != nil {
+				return granted, err
+			}
+			granted = append(granted, menu)
+		}
+		id = menu.ParentId
+	}
+	return granted, nil
+}
+
+// applyDetails 按字典值幂等写入字典详情树 返回新增的详情id
+func applyDetails(tx *gorm.DB, dictionaryId int, parentId *uint, details []plugin.DataDictionaryDetail) (created []uint, err error) {
+	for _, detail := range details {
+		var entity system.SysDictionaryDetail
+		query := tx.Where("sys_dictionary_id = ? AND value = ?", dictionaryId, detail.Value)
+		if parentId == nil {
+			query = query.Where("parent_id IS NULL")
+		} else {
+			query = query.Where("parent_id = ?", *parentId)
+		}
+		err = query.Limit(1).Find(&entity).Error
+		if err != nil {
+			return created, err
+		}
+		if entity.ID == 0 {
+			status := detail.Status
+			if status == nil {
+				enabled := true
+				status = &enabled
+			}
+			entity = system.SysDictionaryDetail{
+				Label:           detail.Label,
+				Value:           detail.Value,
+				Extend:          detail.Extend,
+				Status:          status,
+				Sort:            detail.Sort,
+				SysDictionaryID: dictionaryId,
+				ParentID:        parentId,
+			}
+			err = tx.Create(&entity).Error
+			if err != nil {
+				return created, err
+			}
+			created = append(created, entity.ID)
+		}
+		id := entity.ID
+		children, err := applyDetails(tx, dictionaryId, &id, detail.Children)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, children...)
+	}
+	return created, nil
+}
@@ -0,0 +1,177 @@
+package system
+
+import (
+	"testing"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/v2"
+	"gorm.io/gorm"
+)
+
+// setupPluginDataTestDB 准备插件数据清单测试用的表与核心数据 核心数据与插件清单中的部分记录同名
+func setupPluginDataTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := setupPluginTestDB(t,
+		&system.SysPluginResource{},
+		&system.SysAuthority{},
+		&system.SysBaseMenu{},
+		&system.SysBaseMenuBtn{},
+		&system.SysBaseMenuParameter{},
+		&system.SysAuthorityMenu{},
+		&system.SysAuthorityBtn{},
+		&system.SysApi{},
+		&gormadapter.CasbinRule{},
+		&system.SysDictionary{},
+		&system.SysDictionaryDetail{},
+		&system.SysParams{},
+		&system.SysAutoCodePackage{},
+	)
+	enabled := true
+	core := []any{
+		&system.SysAuthority{AuthorityId: 888, AuthorityName: "普通用户", DefaultRouter: "dashboard"},
+		&system.SysAuthority{AuthorityId: 9528, AuthorityName: "测试角色", DefaultRouter: "dashboard"},
+		&system.SysBaseMenu{Name: "dashboard", Path: "dashboard", Component: "view/dashboard/index.vue", Meta: system.Meta{Title: "仪表盘"}},
+		&system.SysAuthorityMenu{MenuId: "1", AuthorityId: "9528"},
+		&system.SysApi{Path: "/user/getUserInfo", Method: "GET", ApiGroup: "系统用户", Description: "获取自身信息"},
+		&gormadapter.CasbinRule{Ptype: "p", V0: "888", V1: "/user/getUserInfo", V2: "GET"},
+		&gormadapter.CasbinRule{Ptype: "p", V0: "9528", V1: "/user/getUserInfo", V2: "GET"},
+		&system.SysDictionary{Type: "gender", Name: "性别", Status: &enabled},
+		&system.SysDictionaryDetail{Label: "男", Value: "1", Status: &enabled, SysDictionaryID: 1},
+		&system.SysParams{Key: "site.name", Name: "站点名称", Value: "gva", Type: system.SysParamsTypeString},
+	}
+	for _, record := range core {
+		if err := db.Omit("SysAuthoritys", "DataAuthorityId", "SysBaseMenus", "Parameters", "MenuBtn", "SysDictionaryDetails").Create(record).Error; err != nil {
+			t.Fatalf("准备核心数据失败: %v", err)
+		}
+	}
+	return db
+}
+
+// testPluginData 插件数据清单 其中dashboard菜单、gender字典、getUserInfo接口与site.name参数是核心数据
+func testPluginData() plugin.Data {
+	return plugin.Data{
+		Menus: []plugin.DataMenu{
+			{Name: "dashboard", Path: "dashboard", Component: "view/dashboard/index.vue", Meta: plugin.DataMenuMeta{Title: "仪表盘"},
+				Buttons: []plugin.DataMenuButton{{Name: "export", Desc: "导出"}}},
+			{Name: "demo", Path: "demo", Component: "plugin/demo/view/index.vue", Meta: plugin.DataMenuMeta{Title: "示例"},
+				Buttons:    []plugin.DataMenuButton{{Name: "add", Desc: "新增"}},
+				Parameters: []plugin.DataMenuParameter{{Type: "query", Key: "tab", Value: "1"}}},
+		},
+		Apis: []plugin.DataApi{
+			{Path: "/user/getUserInfo", Method: "GET", ApiGroup: "系统用户", Description: "获取自身信息", Grant: true},
+			{Path: "/demo/list", Method: "GET", ApiGroup: "示例", Description: "示例列表", Grant: true},
+		},
+		Dictionaries: []plugin.DataDictionary{
+			{Type: "gender", Name: "性别", Details: []plugin.DataDictionaryDetail{{Label: "男", Value: "1"}, {Label: "未知", Value: "0"}}},
+			{Type: "demo_status", Name: "示例状态", Details: []plugin.DataDictionaryDetail{{Label: "启用", Value: "1"}}},
+		},
+		Params: []plugin.DataParam{
+			{Key: "site.name", Name: "站点名称", Value: "demo"},
+			{Key: "demo.size", Name: "示例数量", Value: "10", Type: system.SysParamsTypeInt},
+		},
+	}
+}
+
+func TestAutoCodePlugin_applyData(t *testing.T) {
+	db := setupPluginDataTestDB(t)
+	data := testPluginData()
+	tests := []struct {
+		name          string
+		wantMenus     int
+		wantButtons   int
+		wantApis      int
+		wantGrants    int
+		wantParams    int
+		wantResources int64
+	}{
+		// 新增: demo菜单/菜单参数/两个按钮/两个按钮授权/demo与dashboard菜单授权/demo接口/888对demo接口的授权/demo_status字典及详情/gender中新增的详情/demo.size参数
+		{name: "首次安装只登记新增的记录", wantMenus: 1, wantButtons: 2, wantApis: 1, wantGrants: 3, wantParams: 1, wantResources: 14},
+		{name: "重复安装不新增记录", wantResources: 14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var report struct {
+				menus, buttons, apis, grants, params int
+			}
+			err := db.Transaction(func(tx *gorm.DB) error {
+				result, err := AutoCodePlugin.applyData(tx, "demo", data, 888)
+				report.menus, report.buttons, report.apis = len(result.Menus), len(result.Buttons), len(result.Apis)
+				report.grants, report.params = len(result.Grants), len(result.Params)
+				return err
+			})
+			if err != nil {
+				t.Fatalf("applyData() error = %v", err)
+			}
+			if report.menus != tt.wantMenus || report.buttons != tt.wantButtons || report.apis != tt.wantApis || report.grants != tt.wantGrants || report.params != tt.wantParams {
+				t.Errorf("applyData() 新增 菜单%d 按钮%d API%d 授权%d 参数%d", report.menus, report.buttons, report.apis, report.grants, report.params)
+			}
+			var count int64
+			db.Model(&system.SysPluginResource{}).Where("plugin = ?", "demo").Count(&count)
+			if count != tt.wantResources {
+				t.Errorf("登记记录数 = %d, 期望 %d", count, tt.wantResources)
+			}
+		})
+	}
+	var param system.SysParams
+	db.Where(map[string]any{"key": "site.name"}).First(&param)
+	if param.Value != "gva" {
+		t.Errorf("已有参数被覆盖: %s", param.Value)
+	}
+}
+
+func TestAutoCodePlugin_uninstallData(t *testing.T) {
+	db := setupPluginDataTestDB(t)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		_, err := AutoCodePlugin.applyData(tx, "demo", testPluginData(), 888)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("applyData() error = %v", err)
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return AutoCodePlugin.uninstallData(tx, "demo", pluginSeed{})
+	})
+	if err != nil {
+		t.Fatalf("uninstallData() error = %v", err)
+	}
+	count := func(model any, query any, args ...any) int64 {
+		var n int64
+		if err := db.Model(model).Where(query, args...).Count(&n).Error; err != nil {
+			t.Fatalf("查询失败: %v", err)
+		}
+		return n
+	}
+	tests := []struct {
+		name  string
+		model any
+		query any
+		args  []any
+		want  int64
+	}{
+		{name: "核心菜单保留", model: &system.SysBaseMenu{}, query: "name = ?", args: []any{"dashboard"}, want: 1},
+		{name: "插件菜单删除", model: &system.SysBaseMenu{}, query: "name = ?", args: []any{"demo"}, want: 0},
+		{name: "插件菜单参数删除", model: &system.SysBaseMenuParameter{}, query: "1 = 1", want: 0},
+		{name: "核心菜单上新增的按钮删除", model: &system.SysBaseMenuBtn{}, query: "1 = 1", want: 0},
+		{name: "按钮授权撤销", model: &system.SysAuthorityBtn{}, query: "1 = 1", want: 0},
+		{name: "只撤销新增的菜单授权", model: &system.SysAuthorityMenu{}, query: "sys_authority_authority_id = ?", args: []any{"9528"}, want: 1},
+		{name: "新增的菜单授权撤销", model: &system.SysAuthorityMenu{}, query: "sys_authority_authority_id = ?", args: []any{"888"}, want: 0},
+		{name: "核心API保留", model: &system.SysApi{}, query: "path = ?", args: []any{"/user/getUserInfo"}, want: 1},
+		{name: "核心API的已有授权保留", model: &gormadapter.CasbinRule{}, query: "v1 = ?", args: []any{"/user/getUserInfo"}, want: 2},
+		{name: "插件API删除", model: &system.SysApi{}, query: "path = ?", args: []any{"/demo/list"}, want: 0},
+		{name: "插件API授权删除", model: &gormadapter.CasbinRule{}, query: "v1 = ?", args: []any{"/demo/list"}, want: 0},
+		{name: "核心字典保留", model: &system.SysDictionary{}, query: "type = ?", args: []any{"gender"}, want: 1},
+		{name: "核心字典的已有详情保留", model: &system.SysDictionaryDetail{}, query: "sys_dictionary_id = ?", args: []any{1}, want: 1},
+		{name: "插件字典删除", model: &system.SysDictionary{}, query: "type = ?", args: []any{"demo_status"}, want: 0},
+		{name: "核心参数保留", model: &system.SysParams{}, query: map[string]any{"key": "site.name"}, want: 1},
+		{name: "插件参数删除", model: &system.SysParams{}, query: map[string]any{"key": "demo.size"}, want: 0},
+		{name: "登记记录清除", model: &system.SysPluginResource{}, query: "plugin = ?", args: []any{"demo"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := count(tt.model, tt.query, tt.args...); got != tt.want {
+				t.Errorf("记录数 = %d, 期望 %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,14 +11,19 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	model "github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	utilsAst "github.com/flipped-aurora/gin-vue-admin/server/utils/ast"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/filetx"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/merge"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -54,11 +59,11 @@ func (s *autoCodeTemplate) checkPackage(Pkg string, template string) (err error)
 	return nil
 }
 
-// Create 创建生成自动化代码
-func (s *autoCodeTemplate) Create(ctx context.Context, info request.AutoCode) error {
+// Create 创建生成自动化代码 写入的文件和注入的代码在同一个文件事务中 任意步骤失败时恢复全部文件
+func (s *autoCodeTemplate) Create(ctx context.Context, info request.AutoCode) (err error) {
 	history := info.History()
 	var autoPkg model.SysAutoCodePackage
-	err := global.GVA_DB.WithContext(ctx).Where("package_name = ?", info.Package).First(&autoPkg).Error
+	err = global.GVA_DB.WithContext(ctx).Where("package_name = ?", info.Package).First(&autoPkg).Error
 	if err != nil {
 		return errors.Wrap(err, "查询包失败!")
 	}
@@ -66,8 +71,14 @@ func (s *autoCodeTemplate) Create(ctx context.Context, info request.AutoCode) er
 	if err != nil {
 		return err
 	}
-	// 增加判断: 重复创建struct 或者重复的简称
-	if AutocodeHistory.Repeat(info.BusinessDB, info.StructName, info.Abbreviation, info.Package) {
+	var previous model.SysAutoCodeHistory
+	if info.Merge {
+		err = global.GVA_DB.WithContext(ctx).Where("business_db = ? and struct_name = ? and package = ? and flag = ?", info.BusinessDB, info.StructName, info.Package, 0).First(&previous).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.Wrap(err, "查询历史记录失败!")
+		}
+	} else if AutocodeHistory.Repeat(info.BusinessDB, info.StructName, info.Abbreviation, info.Package) {
+		// 增加判断: 重复创建struct 或者重复的简称
 		return errors.New("已经创建过此数据结构,请勿重复创建!")
 	}
 
@@ -75,149 +86,259 @@ func (s *autoCodeTemplate) Create(ctx context.Context, info request.AutoCode) er
 	if err != nil {
 		return err
 	}
+	generated := make(map[string]string, len(templates))
+	for _, create := range templates {
+		if builder, ok := generate[create]; ok {
+			generated[create] = builder.String()
+		}
+	}
+	contents := make(map[string]string, len(generate))
 	for key, builder := range generate {
-		err = os.MkdirAll(filepath.Dir(key), os.ModePerm)
+		contents[key] = builder.String()
+		if _, ok := generated[key]; !ok || !info.Merge {
+			continue
+		}
+		result, err := s.merge(ctx, key, contents[key])
 		if err != nil {
-			return errors.Wrapf(err, "[filepath:%s]创建文件夹失败!", key)
+			return err
+		}
+		if result != nil {
+			contents[key], err = result.Resolve(info.Resolutions[s.relativePath(key)])
+			if err != nil {
+				return errors.Wrapf(err, "[filepath:%s]合并失败!", s.relativePath(key))
+			}
 		}
-		err = os.WriteFile(key, []byte(builder.String()), 0666)
+	} // 先完成全部合并 存在未处理的冲突时不写入任何文件
+
+	files := filetx.Begin()
+	defer func() {
+		if err == nil {
+			files.Commit()
+			return
+		}
+		if rollbackErr := files.Rollback(); rollbackErr != nil {
+			global.GVA_LOG.Error("恢复生成前的文件失败!", zap.Error(rollbackErr))
+		}
+	}()
+	for key, content := range contents {
+		err = files.WriteFile(key, []byte(content), 0644)
 		if err != nil {
 			return errors.Wrapf(err, "[filepath:%s]写入文件失败!", key)
 		}
 	}
+	err = files.Validate()
+	if err != nil {
+		return err
+	}
+	history.Snapshots = make(model.FileSnapshots)
+	for _, key := range files.Files() {
+		content, exists, _ := files.Original(key)
+		if _, ok := generated[key]; ok || !exists {
+			continue
+		} // 生成的文件回滚时整体移除 只记录被注入修改的已有文件
+		history.Snapshots[s.relativePath(key)] = model.SysAutoCodeFileSnapshot{
+			Content: string(content),
+			Hash:    utils.MD5V([]byte(contents[key])),
+		}
+	}
 
-	// 自动创建api
-	if info.AutoCreateApiToSql && !info.OnlyTemplate {
-		apis := info.Apis()
-		err := global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-			for _, v := range apis {
+	// 接口、菜单、导出模板、生成快照和历史记录在同一个数据库事务中写入 事务失败时文件同样恢复
+	return global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 自动创建api
+		if info.AutoCreateApiToSql && !info.OnlyTemplate {
+			for _, v := range info.Apis() {
 				var api model.SysApi
 				var id uint
 				err := tx.Where("path = ? AND method = ?", v.Path, v.Method).First(&api).Error
 				if errors.Is(err, gorm.ErrRecordNotFound) {
-					if err = tx.Create(&v).Error; err != nil { // 遇到错误时回滚事务
-						return err
+					if err = tx.Create(&v).Error; err != nil {
+						return errors.Wrap(err, "创建api失败!")
 					}
 					id = v.ID
+				} else if err != nil {
+					return errors.Wrap(err, "查询api失败!")
 				} else {
 					id = api.ID
 				}
 				history.ApiIDs = append(history.ApiIDs, id)
 			}
-			return nil
-		})
-		if err != nil {
-			return err
 		}
-	}
 
-	// 自动创建menu
-	if info.AutoCreateMenuToSql {
-		var entity model.SysBaseMenu
-		var id uint
-		err := global.GVA_DB.WithContext(ctx).First(&entity, "name = ?", info.Abbreviation).Error
-		if err == nil {
-			id = entity.ID
-		} else {
-			entity = info.Menu(autoPkg.Template)
-			if info.AutoCreateBtnAuth && !info.OnlyTemplate {
-				entity.MenuBtn = []model.SysBaseMenuBtn{
-					{SysBaseMenuID: entity.ID, Name: "add", Desc: "新增"},
-					{SysBaseMenuID: entity.ID, Name: "batchDelete", Desc: "批量删除"},
-					{SysBaseMenuID: entity.ID, Name: "delete", Desc: "删除"},
-					{SysBaseMenuID: entity.ID, Name: "edit", Desc: "编辑"},
-					{SysBaseMenuID: entity.ID, Name: "info", Desc: "详情"},
-				}
-				if info.HasExcel {
-					excelBtn := []model.SysBaseMenuBtn{
-						{SysBaseMenuID: entity.ID, Name: "exportTemplate", Desc: "导出模板"},
-						{SysBaseMenuID: entity.ID, Name: "exportExcel", Desc: "导出Excel"},
-						{SysBaseMenuID: entity.ID, Name: "importExcel", Desc: "导入Excel"},
+		// 自动创建menu
+		if info.AutoCreateMenuToSql {
+			var entity model.SysBaseMenu
+			var id uint
+			err := tx.First(&entity, "name = ?", info.Abbreviation).Error
+			if err == nil {
+				id = entity.ID
+			} else {
+				entity = info.Menu(autoPkg.Template)
+				if info.AutoCreateBtnAuth && !info.OnlyTemplate {
+					entity.MenuBtn = []model.SysBaseMenuBtn{
+						{SysBaseMenuID: entity.ID, Name: "add", Desc: "新增"},
+						{SysBaseMenuID: entity.ID, Name: "batchDelete", Desc: "批量删除"},
+						{SysBaseMenuID: entity.ID, Name: "delete", Desc: "删除"},
+						{SysBaseMenuID: entity.ID, Name: "edit", Desc: "编辑"},
+						{SysBaseMenuID: entity.ID, Name: "info", Desc: "详情"},
 					}
-					entity.MenuBtn = append(entity.MenuBtn, excelBtn...)
+					if info.HasExcel {
+						excelBtn := []model.SysBaseMenuBtn{
+							{SysBaseMenuID: entity.ID, Name: "exportTemplate", Desc: "导出模板"},
+							{SysBaseMenuID: entity.ID, Name: "exportExcel", Desc: "导出Excel"},
+							{SysBaseMenuID: entity.ID, Name: "importExcel", Desc: "导入Excel"},
+						}
+						entity.MenuBtn = append(entity.MenuBtn, excelBtn...)
+					}
+				}
+				err = tx.Create(&entity).Error
+				id = entity.ID
+				if err != nil {
+					return errors.Wrap(err, "创建菜单失败!")
+				}
+			}
+			history.MenuID = id
+		}
+
+		if info.HasExcel && previous.ExportTemplateID != 0 {
+			history.ExportTemplateID = previous.ExportTemplateID
+		} else if info.HasExcel {
+			dbName := info.BusinessDB
+			name := info.Package + "_" + info.StructName
+			tableName := info.TableName
+			fieldsMap := make(map[string]string, len(info.Fields))
+			for _, field := range info.Fields {
+				if field.Excel {
+					fieldsMap[field.ColumnName] = field.FieldDesc
 				}
 			}
-			err = global.GVA_DB.WithContext(ctx).Create(&entity).Error
-			id = entity.ID
+			templateInfo, _ := json.Marshal(fieldsMap)
+			sysExportTemplate := model.SysExportTemplate{
+				DBName:       dbName,
+				Name:         name,
+				TableName:    tableName,
+				TemplateID:   name,
+				TemplateInfo: string(templateInfo),
+			}
+			err := tx.Create(&sysExportTemplate).Error
 			if err != nil {
-				return errors.Wrap(err, "创建菜单失败!")
+				return errors.Wrap(err, "创建导出模板失败!")
 			}
+			history.ExportTemplateID = sysExportTemplate.ID
 		}
-		history.MenuID = id
-	}
 
-	if info.HasExcel {
-		dbName := info.BusinessDB
-		name := info.Package + "_" + info.StructName
-		tableName := info.TableName
-		fieldsMap := make(map[string]string, len(info.Fields))
-		for _, field := range info.Fields {
-			if field.Excel {
-				fieldsMap[field.ColumnName] = field.FieldDesc
-			}
+		err := s.saveSnapshots(tx, info, generated)
+		if err != nil {
+			return errors.Wrap(err, "保存生成快照失败!")
 		}
-		templateInfo, _ := json.Marshal(fieldsMap)
-		sysExportTemplate := model.SysExportTemplate{
-			DBName:       dbName,
-			Name:         name,
-			TableName:    tableName,
-			TemplateID:   name,
-			TemplateInfo: string(templateInfo),
+
+		// 创建历史记录
+		history.Templates = templates
+		history.Injections = make(map[string]string, len(injections))
+		for key, value := range injections {
+			bytes, _ := json.Marshal(value)
+			history.Injections[key] = string(bytes)
 		}
-		err = SysExportTemplateServiceApp.CreateSysExportTemplate(&sysExportTemplate)
+		if previous.ID != 0 {
+			for _, id := range previous.ApiIDs {
+				if !slices.Contains(history.ApiIDs, id) {
+					history.ApiIDs = append(history.ApiIDs, id)
+				}
+			}
+			if history.MenuID == 0 {
+				history.MenuID = previous.MenuID
+			}
+			for key, snapshot := range previous.Snapshots {
+				if current, ok := history.Snapshots[key]; ok {
+					snapshot.Hash = current.Hash
+				}
+				history.Snapshots[key] = snapshot
+			} // 回滚时恢复到第一次生成之前的内容
+			err = tx.Delete(&model.SysAutoCodeHistory{}, "id = ?", previous.ID).Error
+			if err != nil {
+				return errors.Wrap(err, "更新历史记录失败!")
+			}
+		} // 重新生成时以新记录替换旧记录 保留之前创建的api和菜单 回滚时一并清除
+		create := history.Create()
+		err = tx.Create(&create).Error
 		if err != nil {
-			return err
+			return errors.Wrap(err, "创建历史记录失败!")
 		}
-		history.ExportTemplateID = sysExportTemplate.ID
-	}
+		return nil
+	})
+}
 
-	// 创建历史记录
-	history.Templates = templates
-	history.Injections = make(map[string]string, len(injections))
-	for key, value := range injections {
-		bytes, _ := json.Marshal(value)
-		history.Injections[key] = string(bytes)
-	}
-	err = AutocodeHistory.Create(ctx, history)
+// Preview 预览自动化代码 重新生成时返回与本地修改合并后的代码及各文件的冲突
+func (s *autoCodeTemplate) Preview(ctx context.Context, info request.AutoCode) (map[string]string, map[string][]merge.Conflict, error) {
+	files, conflicts, err := s.PreviewFiles(ctx, info)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	return nil
+	preview := make(map[string]string, len(files))
+	for key, content := range files {
+		key = s.relativePath(key)
+		// 获取key的后缀 取消.
+		suffix := filepath.Ext(key)[1:]
+		var builder strings.Builder
+		builder.WriteString("```" + suffix + "\n\n")
+		builder.WriteString(content)
+		builder.WriteString("\n\n```")
+		preview[key] = builder.String()
+	}
+	return preview, conflicts, nil
 }
 
-// Preview 预览自动化代码
-func (s *autoCodeTemplate) Preview(ctx context.Context, info request.AutoCode) (map[string]string, error) {
+// PreviewFiles 生成但不写入自动化代码 返回文件绝对路径=>内容 冲突以相对路径为键
+func (s *autoCodeTemplate) PreviewFiles(ctx context.Context, info request.AutoCode) (map[string]string, map[string][]merge.Conflict, error) {
 	var entity model.SysAutoCodePackage
 	err := global.GVA_DB.WithContext(ctx).Where("package_name = ?", info.Package).First(&entity).Error
 	if err != nil {
-		return nil, errors.Wrap(err, "查询包失败!")
+		return nil, nil, errors.Wrap(err, "查询包失败!")
 	}
 	// 增加判断: 重复创建struct 或者重复的简称
-	if AutocodeHistory.Repeat(info.BusinessDB, info.StructName, info.Abbreviation, info.Package) && !info.IsAdd {
-		return nil, errors.New("已经创建过此数据结构或重复简称,请勿重复创建!")
+	if AutocodeHistory.Repeat(info.BusinessDB, info.StructName, info.Abbreviation, info.Package) && !info.IsAdd && !info.Merge {
+		return nil, nil, errors.New("已经创建过此数据结构或重复简称,请勿重复创建!")
 	}
 
-	preview := make(map[string]string)
-	codes, _, _, err := s.generate(ctx, info, entity)
+	files := make(map[string]string)
+	conflicts := make(map[string][]merge.Conflict)
+	codes, templates, _, err := s.generate(ctx, info, entity)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	generated := make(map[string]bool, len(templates))
+	for _, create := range templates {
+		generated[create] = true
 	}
 	for key, writer := range codes {
-		if len(key) > len(global.GVA_CONFIG.AutoCode.Root) {
-			key, _ = filepath.Rel(global.GVA_CONFIG.AutoCode.Root, key)
+		content := writer.String()
+		if info.Merge && !info.IsAdd && generated[key] {
+			result, err := s.merge(ctx, key, content)
+			if err != nil {
+				return nil, nil, err
+			}
+			if result != nil {
+				content = result.String()
+				if list := result.Conflicts(); len(list) > 0 {
+					conflicts[s.relativePath(key)] = list
+				}
+			}
 		}
-		// 获取key的后缀 取消.
-		suffix := filepath.Ext(key)[1:]
-		var builder strings.Builder
-		builder.WriteString("```" + suffix + "\n\n")
-		builder.WriteString(writer.String())
-		builder.WriteString("\n\n```")
-		preview[key] = builder.String()
+		files[key] = content
 	}
-	return preview, nil
+	return files, conflicts, nil
 }
 
 func (s *autoCodeTemplate) generate(ctx context.Context, info request.AutoCode, entity model.SysAutoCodePackage) (map[string]strings.Builder, map[string]string, map[string]utilsAst.Ast, error) {
+	pack, err := AutoCodePackage.entityTemplatePack(entity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if pack != nil {
+		info.Variables, err = pack.Resolve(info.Variables)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	} // 外部模板包的额外变量
 	templates, asts, _, err := AutoCodePackage.templates(ctx, entity, info, false)
 	if err != nil {
 		return nil, nil, nil, err
@@ -225,7 +346,7 @@ func (s *autoCodeTemplate) generate(ctx context.Context, info request.AutoCode,
 	code := make(map[string]strings.Builder)
 	for key, create := range templates {
 		var files *template.Template
-		files, err = template.New(filepath.Base(key)).Funcs(autocode.GetTemplateFuncMap()).ParseFiles(key)
+		files, err = parseAutoCodeTemplate(key)
 		if err != nil {
 			return nil, nil, nil, errors.Wrapf(err, "[filpath:%s]读取模版文件失败!", key)
 		}
@@ -244,7 +365,7 @@ func (s *autoCodeTemplate) generate(ctx context.Context, info request.AutoCode,
 				continue
 			}
 			if info.OnlyTemplate {
-				if keys[1] == utilsAst.TypePackageInitializeGorm || keys[1] == utilsAst.TypePluginInitializeGorm {
+				if keys[1] == utilsAst.TypePackageInitializeGorm || keys[1] == utilsAst.TypePluginInitializeGorm || keys[1] == utilsAst.TypePackageInitializeMongo {
 					continue
 				}
 			}
@@ -0,0 +1,301 @@
+package system
+
+import (
+	"context"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	model "github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/autocode"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/archive"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/templatepack"
+	cp "github.com/otiai10/copy"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// templatePackLayout 模板包生成路径中可用的数据 包含 request.AutoCode 的全部字段
+type templatePackLayout struct {
+	request.AutoCode
+	Server string // server目录
+	Web    string // web源码目录
+}
+
+func newTemplatePackLayout(info request.AutoCode) templatePackLayout {
+	return templatePackLayout{
+		AutoCode: info,
+		Server:   filepath.ToSlash(global.GVA_CONFIG.AutoCode.Server),
+		Web:      filepath.ToSlash(global.GVA_CONFIG.AutoCode.WebRoot()),
+	}
+}
+
+// templatePackRoot 外部模板包的安装目录 resource/packs/名称/版本
+func templatePackRoot() string {
+	return filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, "resource", "packs")
+}
+
+// templatePackVersions 已安装的版本 从新到旧
+func templatePackVersions(name string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(templatePackRoot(), name))
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return templatepack.Compare(versions[i], versions[j]) > 0
+	})
+	return versions, nil
+}
+
+// templatePack 加载外部模板包 version为空时使用最新版本
+func (s *autoCodePackage) templatePack(name string, version string) (*templatepack.Pack, error) {
+	if version == "" {
+		versions, err := templatePackVersions(name)
+		if err != nil || len(versions) == 0 {
+			return nil, errors.Errorf("模板包[%s]未安装!", name)
+		}
+		version = versions[0]
+	}
+	if !filepath.IsLocal(name) || !filepath.IsLocal(version) {
+		return nil, errors.Errorf("模板包[%s@%s]不合法!", name, version)
+	}
+	pack, err := templatepack.Load(filepath.Join(templatePackRoot(), name, version))
+	if err != nil {
+		return nil, errors.Wrapf(err, "加载模板包[%s@%s]失败!", name, version)
+	}
+	return pack, nil
+}
+
+// entityTemplatePack 包选择的外部模板包 未选择时返回nil
+func (s *autoCodePackage) entityTemplatePack(entity model.SysAutoCodePackage) (*templatepack.Pack, error) {
+	if entity.TemplatePack == "" {
+		return nil, nil
+	}
+	return s.templatePack(entity.TemplatePack, entity.TemplateVersion)
+}
+
+// templatePackRoots 模板包生成路径允许的目录 即生成模块自身的目录 相对于项目根目录
+func templatePackRoots(template string, packageName string) []string {
+	server, web := global.GVA_CONFIG.AutoCode.Server, global.GVA_CONFIG.AutoCode.WebRoot()
+	if template != "package" {
+		return []string{filepath.Join(server, "plugin", packageName), filepath.Join(web, "plugin", packageName)}
+	}
+	return []string{
+		filepath.Join(server, "api", "v1", packageName),
+		filepath.Join(server, "model", packageName),
+		filepath.Join(server, "router", packageName),
+		filepath.Join(server, "service", packageName),
+		filepath.Join(web, "api", packageName),
+		filepath.Join(web, "view", packageName),
+	}
+}
+
+// packTemplates 将模板包中的文件加入生成列表 生成路径相同的内置模板被覆盖 未勾选生成web/server时跳过对应文件
+// 模板包新增的文件不能覆盖模块目录中已有且不是由代码生成器生成的文件
+func (s *autoCodePackage) packTemplates(ctx context.Context, pack *templatepack.Pack, entity model.SysAutoCodePackage, info request.AutoCode, code map[string]string) error {
+	targets, err := pack.Targets(autocode.GetTemplateFuncMap(), newTemplatePackLayout(info), templatePackRoots(entity.Template, entity.PackageName))
+	if err != nil {
+		return err
+	}
+	web := filepath.Clean(global.GVA_CONFIG.AutoCode.WebRoot()) + string(filepath.Separator)
+	server := filepath.Clean(global.GVA_CONFIG.AutoCode.Server) + string(filepath.Separator)
+	overrides := make(map[string]string, len(targets))
+	for key, target := range targets {
+		if !info.GenerateWeb && strings.HasPrefix(target, web) || !info.GenerateServer && strings.HasPrefix(target, server) {
+			continue
+		}
+		overrides[filepath.Join(global.GVA_CONFIG.AutoCode.Root, target)] = key
+	}
+	builtin := make(map[string]bool, len(code))
+	for _, create := range code {
+		builtin[create] = true
+	}
+	var generated map[string]bool
+	for create := range overrides {
+		if builtin[create] || !utils.FileExist(create) {
+			continue
+		}
+		if generated == nil {
+			generated, err = s.generatedFiles(ctx, entity.PackageName)
+			if err != nil {
+				return err
+			}
+		}
+		if !generated[historyTemplatePath(create)] {
+			return errors.Errorf("[target:%s]文件已存在且不是由代码生成器生成的!", AutoCodeTemplate.relativePath(create))
+		}
+	}
+	for key, create := range code {
+		if _, ok := overrides[create]; ok {
+			delete(code, key)
+		}
+	}
+	for create, key := range overrides {
+		code[key] = create
+	}
+	return nil
+}
+
+// generatedFiles 包下未回滚的生成记录中生成的文件 路径格式同 SysAutoCodeHistory.Templates
+func (s *autoCodePackage) generatedFiles(ctx context.Context, packageName string) (map[string]bool, error) {
+	var histories []model.SysAutoCodeHistory
+	err := global.GVA_DB.WithContext(ctx).Select("templates").Where("package = ? AND flag = ?", packageName, 0).Find(&histories).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "查询生成记录失败!")
+	}
+	generated := make(map[string]bool)
+	for _, history := range histories {
+		for _, create := range history.Templates {
+			generated[create] = true
+		}
+	}
+	return generated, nil
+}
+
+// historyTemplatePath 生成文件在 SysAutoCodeHistory.Templates 中记录的路径 相对于web或server目录
+func historyTemplatePath(create string) string {
+	for _, root := range []string{global.GVA_CONFIG.AutoCode.WebRoot(), global.GVA_CONFIG.AutoCode.Server} {
+		root = filepath.Join(global.GVA_CONFIG.AutoCode.Root, root)
+		if rel, err := filepath.Rel(root, create); err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(create)
+}
+
+// templatePacks 已安装的外部模板包 按名称排序
+func (s *autoCodePackage) templatePacks() ([]response.AutoCodeTemplate, error) {
+	entries, err := os.ReadDir(templatePackRoot())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "读取模板包文件夹失败!")
+	}
+	var packs []response.AutoCodeTemplate
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		versions, err := templatePackVersions(entry.Name())
+		if err != nil {
+			return nil, errors.Wrap(err, "读取模板包文件夹失败!")
+		}
+		item := response.AutoCodeTemplate{Name: entry.Name()}
+		for _, version := range versions {
+			pack, err := templatepack.Load(filepath.Join(templatePackRoot(), entry.Name(), version))
+			if err != nil {
+				global.GVA_LOG.Warn("模板包加载失败!", zap.Error(err))
+				continue
+			}
+			item.Versions = append(item.Versions, response.AutoCodeTemplateVersion{
+				Version:     pack.Version,
+				Description: pack.Description,
+				Extends:     pack.Extends,
+				Variables:   pack.Variables,
+			})
+		}
+		if len(item.Versions) > 0 {
+			packs = append(packs, item)
+		}
+	}
+	return packs, nil
+}
+
+// InstallTemplatePack 安装外部模板包 file为zip压缩包 dir为服务器上的模板包目录 二选一
+// 安装前使用示例数据渲染全部模板 同名同版本的模板包不允许重复安装
+func (s *autoCodePackage) InstallTemplatePack(ctx context.Context, file *multipart.FileHeader, dir string) (*templatepack.Manifest, error) {
+	if file != nil {
+		temp, err := os.MkdirTemp("", "gva-template-pack-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(temp)
+		dir, err = unzipTemplatePack(file, temp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dir == "" {
+		return nil, errors.New("请上传模板包或填写模板包目录!")
+	}
+	pack, err := templatepack.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	info := request.AutoCode{
+		Package:         "example",
+		StructName:      "Example",
+		PackageName:     "example",
+		HumpPackageName: "example",
+		Abbreviation:    "example",
+		Description:     "示例",
+		GvaModel:        true,
+		GenerateWeb:     true,
+		GenerateServer:  true,
+		Fields: []*request.AutoCodeField{
+			{FieldName: "Name", FieldDesc: "名称", FieldType: "string", FieldJson: "name", ColumnName: "name", FieldSearchType: "LIKE", Form: true, Table: true, Desc: true},
+		},
+		Variables: pack.Sample(),
+	}
+	if err = info.Pretreatment(); err != nil {
+		return nil, err
+	}
+	err = pack.Validate(parseAutoCodeTemplate, autocode.GetTemplateFuncMap(), info, newTemplatePackLayout(info), templatePackRoots(pack.Extends, info.Package))
+	if err != nil {
+		return nil, errors.Wrap(err, "模板包校验失败!")
+	}
+	dest := filepath.Join(templatePackRoot(), pack.Name, pack.Version)
+	if _, err = os.Stat(dest); err == nil {
+		return nil, errors.Errorf("模板包[%s@%s]已安装!", pack.Name, pack.Version)
+	}
+	if err = cp.Copy(dir, dest); err != nil {
+		_ = os.RemoveAll(dest)
+		return nil, errors.Wrap(err, "复制模板包失败!")
+	}
+	return &pack.Manifest, nil
+}
+
+// unzipTemplatePack 解压模板包 清单可以位于压缩包根目录或唯一的顶层文件夹中 解压限制与插件安装包相同
+func unzipTemplatePack(file *multipart.FileHeader, temp string) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	cfg := global.GVA_CONFIG.PluginPackage
+	dir := filepath.Join(temp, "pack")
+	err = archive.Unzip(src, file.Size, dir, archive.Limits{MaxSize: cfg.MaxSize << 20, MaxFiles: cfg.MaxFiles})
+	if err != nil {
+		return "", errors.Wrap(err, "解压模板包失败!")
+	}
+	if utils.FileExist(filepath.Join(dir, templatepack.ManifestName)) {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var found []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != "__MACOSX" && utils.FileExist(filepath.Join(dir, entry.Name(), templatepack.ManifestName)) {
+			found = append(found, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(found) != 1 {
+		return "", errors.Errorf("压缩包中没有找到%s!", templatepack.ManifestName)
+	}
+	return found[0], nil
+}
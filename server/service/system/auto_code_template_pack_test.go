@@ -0,0 +1,88 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/templatepack"
+)
+
+func TestAutoCodePackage_packTemplates(t *testing.T) {
+	db := setupPluginTestDB(t, &system.SysAutoCodeHistory{})
+	root := t.TempDir()
+	oldAutoCode := global.GVA_CONFIG.AutoCode
+	global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.Server, global.GVA_CONFIG.AutoCode.Web = root, "server", "web/src"
+	t.Cleanup(func() { global.GVA_CONFIG.AutoCode = oldAutoCode })
+	write := func(path string) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatalf("创建文件夹失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("package demo"), 0644); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+	}
+	write("server/plugin/demo/handwritten.go")
+	write("server/plugin/demo/generated.go")
+	history := system.SysAutoCodeHistory{Package: "demo", Templates: map[string]string{
+		"resource/pack/generated.go.tpl": filepath.Join(root, "server", "plugin", "demo", "generated.go"),
+	}}
+	if err := db.Create(&history).Error; err != nil {
+		t.Fatalf("准备生成记录失败: %v", err)
+	}
+
+	entity := system.SysAutoCodePackage{PackageName: "demo", Template: "plugin"}
+	info := request.AutoCode{Package: "demo", PackageName: "book", GenerateServer: true, GenerateWeb: true}
+	builtin := filepath.Join(root, "server", "plugin", "demo", "service", "book.go")
+	tests := []struct {
+		name    string
+		target  string
+		wantErr string
+	}{
+		{name: "覆盖内置模板", target: "{{.Server}}/plugin/{{.Package}}/service/{{.PackageName}}.go"},
+		{name: "模块目录中的新文件", target: "{{.Web}}/plugin/{{.Package}}/extra/{{.PackageName}}.vue"},
+		{name: "再次生成已生成的文件", target: "{{.Server}}/plugin/{{.Package}}/generated.go"},
+		{name: "模块目录之外", target: "{{.Server}}/initialize/router.go", wantErr: "生成路径必须位于模块目录内"},
+		{name: "其他插件的目录", target: "{{.Server}}/plugin/demo2/main.go", wantErr: "生成路径必须位于模块目录内"},
+		{name: "已有的非生成文件", target: "{{.Server}}/plugin/{{.Package}}/handwritten.go", wantErr: "不是由代码生成器生成的"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifest := `{"name": "audit", "version": "1.0.0", "extends": "plugin", "files": [{"template": "a.go.tpl", "target": "` + tt.target + `"}]}`
+			if err := os.WriteFile(filepath.Join(dir, templatepack.ManifestName), []byte(manifest), 0644); err != nil {
+				t.Fatalf("写入清单失败: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "a.go.tpl"), nil, 0644); err != nil {
+				t.Fatalf("写入模板失败: %v", err)
+			}
+			pack, err := templatepack.Load(dir)
+			if err != nil {
+				t.Fatalf("加载模板包失败: %v", err)
+			}
+			code := map[string]string{"resource/plugin/server/service/service.go.tpl": builtin}
+			err = AutoCodePackage.packTemplates(context.Background(), pack, entity, info, code)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("packTemplates() error = %v, 期望包含 %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("packTemplates() error = %v", err)
+			}
+			if create := code[filepath.Join(dir, "a.go.tpl")]; create == "" {
+				t.Errorf("模板包文件未加入生成列表: %v", code)
+			}
+			if _, ok := code["resource/plugin/server/service/service.go.tpl"]; ok == (tt.name == "覆盖内置模板") {
+				t.Errorf("内置模板是否保留 = %v, 生成列表: %v", ok, code)
+			}
+		})
+	}
+}
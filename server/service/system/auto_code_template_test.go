@@ -71,7 +71,7 @@ func Test_autoCodeTemplate_Preview(t *testing.T) {
 				t.Error(err)
 				return
 			}
-			got, err := AutoCodeTemplate.Preview(tt.args.ctx, tt.args.info)
+			got, _, err := AutoCodeTemplate.Preview(tt.args.ctx, tt.args.info)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Preview() error = %+v, wantErr %v", err, tt.wantErr)
 				return
@@ -19,6 +19,14 @@ type ServiceGroup struct {
 	AuthorityBtnService
 	SysExportTemplateService
 	SysParamsService
+	AuditChainService
+	AuditExportService
+	EntityVersionService
+	OperationRecordArchiveService
+	I18nService
+	FeatureFlagService
+	PluginService
+	AccessTokenService
 	AutoCodePlugin   autoCodePlugin
 	AutoCodePackage  autoCodePackage
 	AutoCodeHistory  autoCodeHistory
@@ -0,0 +1,105 @@
+package system
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// keyedCacheTTL 缓存有效期 redis 通知丢失或未配置 redis 时 其他实例的修改最迟在该时间后生效
+const keyedCacheTTL = time.Minute
+
+// cacheInstanceID 区分本实例发出的失效通知
+var cacheInstanceID = uuid.NewString()
+
+// keyedCache 整表加载的进程内缓存 适用于参数、功能开关等小表
+// 写入后调用 publish 使本实例立即失效 并通过 redis 频道通知其他实例
+type keyedCache[T any] struct {
+	channel string
+	load    func() (map[string]T, error)
+
+	mu         sync.RWMutex
+	items      map[string]T
+	loadedAt   time.Time
+	generation uint64
+	listenOnce sync.Once
+}
+
+func newKeyedCache[T any](channel string, load func() (map[string]T, error)) *keyedCache[T] {
+	return &keyedCache[T]{channel: channel, load: load}
+}
+
+// snapshot 返回当前缓存 返回的 map 只读
+func (c *keyedCache[T]) snapshot() (map[string]T, error) {
+	c.listenOnce.Do(c.listen)
+	c.mu.RLock()
+	if c.items != nil && time.Since(c.loadedAt) < keyedCacheTTL {
+		items := c.items
+		c.mu.RUnlock()
+		return items, nil
+	}
+	generation := c.generation
+	c.mu.RUnlock()
+
+	items, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	// 加载期间发生失效时不写入 避免缓存旧值
+	if generation == c.generation {
+		c.items = items
+		c.loadedAt = time.Now()
+	}
+	c.mu.Unlock()
+	return items, nil
+}
+
+func (c *keyedCache[T]) get(key string) (T, bool, error) {
+	items, err := c.snapshot()
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+	v, ok := items[key]
+	return v, ok, nil
+}
+
+func (c *keyedCache[T]) invalidate() {
+	c.mu.Lock()
+	c.items = nil
+	c.generation++
+	c.mu.Unlock()
+}
+
+// publish 失效本实例缓存并通知其他实例
+func (c *keyedCache[T]) publish() {
+	c.invalidate()
+	if global.GVA_REDIS == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := global.GVA_REDIS.Publish(ctx, c.channel, cacheInstanceID).Err(); err != nil {
+		global.GVA_LOG.Warn("发送缓存失效通知失败", zap.String("channel", c.channel), zap.Error(err))
+	}
+}
+
+// listen 订阅其他实例的失效通知
+func (c *keyedCache[T]) listen() {
+	if global.GVA_REDIS == nil {
+		return
+	}
+	sub := global.GVA_REDIS.Subscribe(context.Background(), c.channel)
+	go func() {
+		for msg := range sub.Channel() {
+			if msg.Payload != cacheInstanceID {
+				c.invalidate()
+			}
+		}
+	}()
+}
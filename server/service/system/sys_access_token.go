@@ -0,0 +1,128 @@
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/pkg/errors"
+)
+
+type AccessTokenService struct{}
+
+var AccessTokenServiceApp = new(AccessTokenService)
+
+// 最后使用时间的更新间隔 避免每次请求都写库
+const accessTokenTouchInterval = time.Minute
+
+//@function: CreateAccessToken
+//@description: 为用户创建个人访问令牌 返回只出现一次的明文令牌
+//@param: ctx context.Context, userID uint, info systemReq.CreateAccessToken
+//@return: result systemRes.CreateAccessToken, err error
+
+func (accessTokenService *AccessTokenService) CreateAccessToken(ctx context.Context, userID uint, info systemReq.CreateAccessToken) (result systemRes.CreateAccessToken, err error) {
+	if info.RateLimit < 0 {
+		return result, errors.New("每分钟请求上限不能小于0")
+	}
+	if info.ExpiresAt != nil && !info.ExpiresAt.After(time.Now()) {
+		return result, errors.New("过期时间必须晚于当前时间")
+	}
+	raw := make([]byte, 32)
+	if _, err = rand.Read(raw); err != nil {
+		return result, err
+	}
+	token := system.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	result.Token = token
+	result.AccessToken = system.SysAccessToken{
+		UserID:    userID,
+		Name:      info.Name,
+		Hint:      token[:len(system.AccessTokenPrefix)+4],
+		TokenHash: hashAccessToken(token),
+		RateLimit: info.RateLimit,
+		ExpiresAt: info.ExpiresAt,
+	}
+	err = global.GVA_DB.WithContext(ctx).Create(&result.AccessToken).Error
+	return result, err
+}
+
+//@function: GetAccessTokenList
+//@description: 获取用户的个人访问令牌
+//@param: userID uint
+//@return: list []system.SysAccessToken, err error
+
+func (accessTokenService *AccessTokenService) GetAccessTokenList(userID uint) (list []system.SysAccessToken, err error) {
+	err = global.GVA_DB.Where("user_id = ?", userID).Order("id desc").Find(&list).Error
+	return list, err
+}
+
+//@function: DeleteAccessToken
+//@description: 吊销用户的个人访问令牌 只能删除自己的令牌
+//@param: ctx context.Context, userID uint, id uint
+//@return: err error
+
+func (accessTokenService *AccessTokenService) DeleteAccessToken(ctx context.Context, userID uint, id uint) (err error) {
+	db := global.GVA_DB.WithContext(ctx).Unscoped().Delete(&system.SysAccessToken{}, "id = ? AND user_id = ?", id, userID)
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected == 0 {
+		return errors.New("令牌不存在")
+	}
+	return nil
+}
+
+//@function: AuthenticateAccessToken
+//@description: 校验个人访问令牌 以令牌所属用户当前的角色生成claims
+//@param: token string
+//@return: claims *systemReq.CustomClaims, accessToken system.SysAccessToken, err error
+
+func (accessTokenService *AccessTokenService) AuthenticateAccessToken(token string) (claims *systemReq.CustomClaims, accessToken system.SysAccessToken, err error) {
+	if !strings.HasPrefix(token, system.AccessTokenPrefix) {
+		return nil, accessToken, errors.New("令牌格式无效")
+	}
+	err = global.GVA_DB.Where("token_hash = ?", hashAccessToken(token)).Limit(1).Find(&accessToken).Error
+	if err != nil {
+		return nil, accessToken, err
+	}
+	if accessToken.ID == 0 {
+		return nil, accessToken, errors.New("令牌无效或已被吊销")
+	}
+	now := time.Now()
+	if accessToken.ExpiresAt != nil && now.After(*accessToken.ExpiresAt) {
+		return nil, accessToken, errors.New("令牌已过期")
+	}
+	var user system.SysUser
+	err = global.GVA_DB.Where("id = ?", accessToken.UserID).Limit(1).Find(&user).Error
+	if err != nil {
+		return nil, accessToken, err
+	}
+	if user.ID == 0 || user.Enable != 1 {
+		return nil, accessToken, errors.New("令牌所属用户不存在或已被冻结")
+	}
+	if accessToken.LastUsedAt == nil || now.Sub(*accessToken.LastUsedAt) > accessTokenTouchInterval {
+		accessToken.LastUsedAt = &now
+		global.GVA_DB.Model(&system.SysAccessToken{}).Where("id = ?", accessToken.ID).Update("last_used_at", now)
+	}
+	claims = &systemReq.CustomClaims{BaseClaims: systemReq.BaseClaims{
+		UUID:        user.UUID,
+		ID:          user.ID,
+		Username:    user.Username,
+		NickName:    user.NickName,
+		AuthorityId: user.AuthorityId,
+		Tenant:      user.Tenant,
+	}}
+	return claims, accessToken, nil
+}
+
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,10 +1,12 @@
 package system
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 
+	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
@@ -22,11 +24,11 @@ type ApiService struct{}
 
 var ApiServiceApp = new(ApiService)
 
-func (apiService *ApiService) CreateApi(api system.SysApi) (err error) {
+func (apiService *ApiService) CreateApi(ctx context.Context, api system.SysApi) (err error) {
 	if !errors.Is(global.GVA_DB.Where("path = ? AND method = ?", api.Path, api.Method).First(&system.SysApi{}).Error, gorm.ErrRecordNotFound) {
 		return errors.New("存在相同api")
 	}
-	return global.GVA_DB.Create(&api).Error
+	return global.GVA_DB.WithContext(ctx).Create(&api).Error
 }
 
 func (apiService *ApiService) GetApiGroups() (groups []string, groupApiMap map[string]string, err error) {
@@ -159,13 +161,13 @@ func (apiService *ApiService) EnterSyncApi(syncApis systemRes.SysSyncApis) (err
 //@param: api model.SysApi
 //@return: err error
 
-func (apiService *ApiService) DeleteApi(api system.SysApi) (err error) {
+func (apiService *ApiService) DeleteApi(ctx context.Context, api system.SysApi) (err error) {
 	var entity system.SysApi
 	err = global.GVA_DB.First(&entity, "id = ?", api.ID).Error // 根据id查询api记录
 	if errors.Is(err, gorm.ErrRecordNotFound) {                // api记录不存在
 		return err
 	}
-	err = global.GVA_DB.Delete(&entity).Error
+	err = global.GVA_DB.WithContext(ctx).Delete(&entity).Error
 	if err != nil {
 		return err
 	}
@@ -273,7 +275,7 @@ func (apiService *ApiService) GetApiById(id int) (api system.SysApi, err error)
 //@param: api model.SysApi
 //@return: err error
 
-func (apiService *ApiService) UpdateApi(api system.SysApi) (err error) {
+func (apiService *ApiService) UpdateApi(ctx context.Context, api system.SysApi) (err error) {
 	var oldA system.SysApi
 	err = global.GVA_DB.First(&oldA, "id = ?", api.ID).Error
 	if oldA.Path != api.Path || oldA.Method != api.Method {
@@ -298,7 +300,31 @@ func (apiService *ApiService) UpdateApi(api system.SysApi) (err error) {
 		return err
 	}
 
-	return global.GVA_DB.Save(&api).Error
+	return global.GVA_DB.WithContext(ctx).Save(&api).Error
+}
+
+//@function: deleteApis
+//@description: 在事务中删除API及其casbin规则 事务提交后需要由调用方刷新casbin
+//@param: tx *gorm.DB, ids request.IdsReq
+//@return: err error
+
+func (apiService *ApiService) deleteApis(tx *gorm.DB, ids request.IdsReq) (err error) {
+	var apis []system.SysApi
+	err = tx.Find(&apis, "id in ?", ids.Ids).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Delete(&[]system.SysApi{}, "id in ?", ids.Ids).Error
+	if err != nil {
+		return err
+	}
+	for _, sysApi := range apis {
+		err = tx.Delete(&gormadapter.CasbinRule{}, "ptype = ? AND v1 = ? AND v2 = ?", "p", sysApi.Path, sysApi.Method).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 //@author: [piexlmax](https://github.com/piexlmax)
@@ -307,8 +333,8 @@ func (apiService *ApiService) UpdateApi(api system.SysApi) (err error) {
 //@param: apis []model.SysApi
 //@return: err error
 
-func (apiService *ApiService) DeleteApisByIds(ids request.IdsReq) (err error) {
-	return global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+func (apiService *ApiService) DeleteApisByIds(ctx context.Context, ids request.IdsReq) (err error) {
+	return global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var apis []system.SysApi
 		err = tx.Find(&apis, "id in ?", ids.Ids).Error
 		if err != nil {
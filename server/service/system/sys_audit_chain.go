@@ -0,0 +1,277 @@
+package system
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/nfc_relay_admin"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/hashchain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const auditChainBatchSize = 500
+
+type AuditChainService struct{}
+
+var AuditChainServiceApp = new(AuditChainService)
+
+// auditChainLoaders 参与哈希链的表及其按ID分批读取方法
+var auditChainLoaders = map[string]func(db *gorm.DB, afterID uint, limit int) ([]hashchain.Record, error){
+	system.SysOperationRecord{}.TableName():   loadChainRecords[system.SysOperationRecord],
+	system.ConfigChangeHistory{}.TableName():  loadChainRecords[system.ConfigChangeHistory],
+	nfc_relay_admin.NfcAuditLog{}.TableName(): loadChainRecords[nfc_relay_admin.NfcAuditLog],
+}
+
+// auditChainExcluded 已移出哈希链的ID区间 区间内的记录不参与校验与导出
+// 恢复的操作记录归档按原ID写回 无法与当前链衔接 其完整性由归档文件校验
+var auditChainExcluded = map[string]func() (func(id uint) bool, error){
+	system.SysOperationRecord{}.TableName(): OperationRecordArchiveServiceApp.restoredRanges,
+}
+
+// loadChainRecords 读取 afterID 之后的全部记录 没有哈希的记录也一并返回 由校验器判断是否断链
+func loadChainRecords[T any](db *gorm.DB, afterID uint, limit int) (records []hashchain.Record, err error) {
+	var rows []T
+	err = db.Unscoped().Where("id > ?", afterID).Order("id").Limit(limit).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		item, ok := any(&rows[i]).(hashchain.Chained)
+		if !ok {
+			return nil, fmt.Errorf("%T 未实现 hashchain.Chained", rows[i])
+		}
+		payload, err := hashchain.Canonical(item.ChainPayload())
+		if err != nil {
+			return nil, err
+		}
+		rv := reflect.ValueOf(&rows[i]).Elem()
+		records = append(records, hashchain.Record{
+			ID:       uint(rv.FieldByName("ID").Uint()),
+			Payload:  payload,
+			PrevHash: rv.FieldByName("PrevHash").String(),
+			RowHash:  rv.FieldByName("RowHash").String(),
+		})
+	}
+	return records, nil
+}
+
+//@function: RegisterPlugin
+//@description: 开启哈希链时为数据库连接注册gorm回调
+//@param: db *gorm.DB
+//@return: error
+
+func (auditChainService *AuditChainService) RegisterPlugin(db *gorm.DB) error {
+	if db == nil || !global.GVA_CONFIG.AuditChain.Enable {
+		return nil
+	}
+	return db.Use(&hashchain.Plugin{})
+}
+
+//@function: Tables
+//@description: 参与哈希链的表
+//@return: []string
+
+func (auditChainService *AuditChainService) Tables() []string {
+	tables := make([]string, 0, len(auditChainLoaders))
+	for table := range auditChainLoaders {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// signingKey 检查点签名私钥 必须显式配置 不从其他密钥派生
+func (auditChainService *AuditChainService) signingKey() (ed25519.PrivateKey, error) {
+	return hashchain.ParseSigningKey(global.GVA_CONFIG.AuditChain.SigningKey)
+}
+
+//@function: PublicKey
+//@description: 检查点签名公钥(base64) 用于离线校验 未配置签名私钥时为空
+//@return: string
+
+func (auditChainService *AuditChainService) PublicKey() string {
+	key, err := auditChainService.signingKey()
+	if err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+}
+
+//@function: CreateCheckpoint
+//@description: 为指定表的当前链尾生成签名检查点
+//@param: table string
+//@return: checkpoint system.SysAuditCheckpoint, err error
+
+func (auditChainService *AuditChainService) CreateCheckpoint(table string) (checkpoint system.SysAuditCheckpoint, err error) {
+	if _, ok := auditChainLoaders[table]; !ok {
+		return checkpoint, fmt.Errorf("表 %s 未开启哈希链", table)
+	}
+	key, err := auditChainService.signingKey()
+	if err != nil {
+		return checkpoint, err
+	}
+	var last struct {
+		ID      uint
+		RowHash string
+	}
+	err = global.GVA_DB.Unscoped().Table(table).Select("id, row_hash").Order("id desc").Limit(1).Scan(&last).Error
+	if err != nil {
+		return checkpoint, err
+	}
+	if last.ID == 0 {
+		return checkpoint, errors.New("该表暂无可签名的记录")
+	}
+	if last.RowHash == "" {
+		return checkpoint, fmt.Errorf("记录 %d 没有哈希, 拒绝为其签名", last.ID)
+	}
+	var latest system.SysAuditCheckpoint
+	err = global.GVA_DB.Where("chain_table = ?", table).Order("id desc").Limit(1).Find(&latest).Error
+	if err != nil {
+		return checkpoint, err
+	}
+	if latest.LastID == last.ID {
+		return latest, nil
+	}
+	checkpoint = system.SysAuditCheckpoint{ChainTable: table, LastID: last.ID, RowHash: last.RowHash}
+	err = global.GVA_DB.Unscoped().Table(table).Where("id <= ?", last.ID).Count(&checkpoint.RowCount).Error
+	if err != nil {
+		return checkpoint, err
+	}
+	checkpoint.Signature = hashchain.Sign(key, checkpoint.Checkpoint()).Signature
+	err = global.GVA_DB.Create(&checkpoint).Error
+	return checkpoint, err
+}
+
+//@function: CreateCheckpoints
+//@description: 为所有哈希链表生成检查点 供定时任务调用
+//@return: error
+
+func (auditChainService *AuditChainService) CreateCheckpoints() error {
+	if _, err := auditChainService.signingKey(); err != nil {
+		return err
+	}
+	var errs []error
+	for _, table := range auditChainService.Tables() {
+		if _, err := auditChainService.CreateCheckpoint(table); err != nil {
+			global.GVA_LOG.Warn("生成审计检查点失败", zap.String("table", table), zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (auditChainService *AuditChainService) checkpoints(table string) (checkpoints []hashchain.Checkpoint, err error) {
+	var rows []system.SysAuditCheckpoint
+	if err = global.GVA_DB.Where("chain_table = ?", table).Order("last_id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		checkpoints = append(checkpoints, row.Checkpoint())
+	}
+	return checkpoints, nil
+}
+
+func (auditChainService *AuditChainService) each(table string, fn func(record hashchain.Record) error) error {
+	load, ok := auditChainLoaders[table]
+	if !ok {
+		return fmt.Errorf("表 %s 未开启哈希链", table)
+	}
+	excluded := func(id uint) bool { return false }
+	if ranges, ok := auditChainExcluded[table]; ok {
+		var err error
+		if excluded, err = ranges(); err != nil {
+			return err
+		}
+	}
+	var afterID uint
+	for {
+		records, err := load(global.GVA_DB, afterID, auditChainBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if excluded(record.ID) {
+				continue
+			}
+			if err = fn(record); err != nil {
+				return err
+			}
+		}
+		if len(records) < auditChainBatchSize {
+			return nil
+		}
+		afterID = records[len(records)-1].ID
+	}
+}
+
+var errAuditChainBroken = errors.New("audit chain broken")
+
+//@function: Verify
+//@description: 校验指定表的哈希链和检查点 返回第一处断链
+//@param: table string
+//@return: result hashchain.VerifyResult, err error
+
+func (auditChainService *AuditChainService) Verify(table string) (result hashchain.VerifyResult, err error) {
+	checkpoints, err := auditChainService.checkpoints(table)
+	if err != nil {
+		return result, err
+	}
+	var pub ed25519.PublicKey
+	if key, keyErr := auditChainService.signingKey(); keyErr == nil {
+		pub = key.Public().(ed25519.PublicKey)
+	} else if len(checkpoints) > 0 {
+		return result, keyErr // 无法校验检查点签名时不返回结果 避免被误认为有效
+	}
+	verifier := hashchain.NewVerifier(pub, checkpoints)
+	err = auditChainService.each(table, func(record hashchain.Record) error {
+		if verifier.Next(record) != nil {
+			return errAuditChainBroken
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAuditChainBroken) {
+		return result, err
+	}
+	result = hashchain.VerifyResult{Table: table, Checkpoints: len(checkpoints)}
+	result.Broken = verifier.Finish()
+	result.Valid = result.Broken == nil
+	result.Checked = verifier.Checked()
+	result.FirstID, result.LastID = verifier.Range()
+	return result, nil
+}
+
+//@function: Export
+//@description: 导出指定表的哈希链归档(JSON Lines) 可使用 `server audit-verify` 离线校验
+//@param: table string, w io.Writer
+//@return: err error
+
+func (auditChainService *AuditChainService) Export(table string, w io.Writer) (err error) {
+	checkpoints, err := auditChainService.checkpoints(table)
+	if err != nil {
+		return err
+	}
+	archive := hashchain.NewArchiveWriter(w)
+	err = archive.WriteHeader(hashchain.ArchiveHeader{
+		Table:      table,
+		PublicKey:  auditChainService.PublicKey(),
+		ExportedAt: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	for _, checkpoint := range checkpoints {
+		if err = archive.WriteCheckpoint(checkpoint); err != nil {
+			return err
+		}
+	}
+	return auditChainService.each(table, archive.WriteRow)
+}
@@ -0,0 +1,271 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/nfc_relay_admin"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/siem"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	auditExportMaxBatches = 20 // 单次任务每个来源最多追赶的批次
+	// 默认只投递创建于该时长之前的行 给ID较小但提交较晚的事务留出提交时间
+	defaultAuditExportSettle = 5 * time.Second
+)
+
+type AuditExportService struct{}
+
+var AuditExportServiceApp = new(AuditExportService)
+
+type auditExportSink struct {
+	conf      config.SiemSink
+	sink      siem.Sink
+	formatter siem.Formatter
+	filter    siem.Filter
+}
+
+var (
+	auditExportMu    sync.Mutex
+	auditExportSinks = map[string]*auditExportSink{}
+)
+
+// auditEventSources 可导出的事件来源 按ID增量读取 遇到创建时间不早于before的行即停止 游标不会越过尚未稳定的行
+var auditEventSources = []struct {
+	name string
+	load func(db *gorm.DB, afterID uint, before time.Time, limit int) ([]siem.Event, error)
+}{
+	{name: "operation", load: loadOperationEvents},
+	{name: "nfc_audit", load: loadNfcAuditEvents},
+}
+
+func loadOperationEvents(db *gorm.DB, afterID uint, before time.Time, limit int) (events []siem.Event, err error) {
+	var records []system.SysOperationRecord
+	err = db.Where("id > ?", afterID).Order("id").Limit(limit).Find(&records).Error
+	for _, r := range records {
+		if !r.CreatedAt.Before(before) {
+			break
+		}
+		severity := 3
+		switch {
+		case r.Status >= 500:
+			severity = 7
+		case r.Status >= 400:
+			severity = 5
+		}
+		events = append(events, siem.Event{
+			ID:       r.ID,
+			Source:   "operation",
+			Type:     "operation." + r.Method,
+			Time:     r.CreatedAt,
+			Severity: severity,
+			Name:     r.Method + " " + r.Path,
+			UserID:   strconv.Itoa(r.UserID),
+			SourceIP: r.Ip,
+			Action:   r.Path,
+			Outcome:  strconv.Itoa(r.Status),
+			Message:  r.ErrorMessage,
+			Extensions: map[string]string{
+				"requestMethod":            r.Method,
+				"request":                  r.Path,
+				"requestClientApplication": r.Agent,
+				"latencyMs":                strconv.FormatInt(r.Latency.Milliseconds(), 10),
+			},
+		})
+	}
+	return events, err
+}
+
+func loadNfcAuditEvents(db *gorm.DB, afterID uint, before time.Time, limit int) (events []siem.Event, err error) {
+	var logs []nfc_relay_admin.NfcAuditLog
+	err = db.Where("id > ?", afterID).Order("id").Limit(limit).Find(&logs).Error
+	for _, l := range logs {
+		if !l.CreatedAt.Before(before) {
+			break
+		}
+		severity := 3
+		switch l.Level {
+		case "warn":
+			severity = 5
+		case "error":
+			severity = 7
+		case "critical":
+			severity = 10
+		}
+		events = append(events, siem.Event{
+			ID:       l.ID,
+			Source:   "nfc_audit",
+			Type:     "nfc." + l.EventType,
+			Time:     l.EventTime,
+			Severity: severity,
+			Name:     l.EventType,
+			UserID:   l.UserID,
+			SourceIP: l.SourceIP,
+			Action:   l.Action,
+			Outcome:  l.Result,
+			Message:  l.ErrorMessage,
+			Extensions: map[string]string{
+				"sessionId":                l.SessionID,
+				"clientInitiator":          l.ClientIDInitiator,
+				"clientResponder":          l.ClientIDResponder,
+				"category":                 l.Category,
+				"resource":                 l.Resource,
+				"requestId":                l.RequestID,
+				"durationMs":               strconv.FormatInt(l.Duration, 10),
+				"requestClientApplication": l.UserAgent,
+			},
+		})
+	}
+	return events, err
+}
+
+func (auditExportService *AuditExportService) getSink(conf config.SiemSink) (*auditExportSink, error) {
+	if s, ok := auditExportSinks[conf.Name]; ok {
+		return s, nil
+	}
+	timeout, _ := utils.ParseDuration(conf.Timeout)
+	sink, err := siem.NewSink(siem.SinkOptions{
+		Type:       conf.Type,
+		Format:     conf.Format,
+		Address:    conf.Address,
+		URL:        conf.URL,
+		Headers:    conf.Headers,
+		Path:       conf.Path,
+		MaxSize:    int64(conf.MaxSize) * 1024 * 1024,
+		MaxBackups: conf.MaxBackups,
+		Timeout:    timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	formatter, err := siem.NewFormatter(conf.Format, global.GVA_VERSION)
+	if err != nil {
+		return nil, err
+	}
+	s := &auditExportSink{conf: conf, sink: sink, formatter: formatter, filter: conf.EventTypes}
+	auditExportSinks[conf.Name] = s
+	return s, nil
+}
+
+//@function: Export
+//@description: 将新增的操作记录和NFC审计事件投递到所有SIEM目标 成功后推进游标(至少一次投递)
+//@return: err error
+
+func (auditExportService *AuditExportService) Export() (err error) {
+	if !auditExportMu.TryLock() {
+		return nil // 上一次投递尚未结束
+	}
+	defer auditExportMu.Unlock()
+	cfg := global.GVA_CONFIG.Siem
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	var errs []error
+	for _, conf := range cfg.Sinks {
+		if conf.Name == "" {
+			errs = append(errs, errors.New("siem sink 缺少 name"))
+			continue
+		}
+		s, err := auditExportService.getSink(conf)
+		if err != nil {
+			global.GVA_LOG.Error("初始化SIEM投递目标失败", zap.String("sink", conf.Name), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		for _, source := range auditEventSources {
+			if err = auditExportService.deliver(s, source.name, source.load, batchSize); err != nil {
+				global.GVA_LOG.Warn("SIEM投递失败", zap.String("sink", conf.Name), zap.String("source", source.name), zap.Error(err))
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (auditExportService *AuditExportService) deliver(s *auditExportSink, source string, load func(*gorm.DB, uint, time.Time, int) ([]siem.Event, error), batchSize int) error {
+	cursor := system.SysAuditExportCursor{Sink: s.conf.Name, Source: source}
+	if err := global.GVA_DB.Where("sink = ? AND source = ?", cursor.Sink, cursor.Source).FirstOrCreate(&cursor).Error; err != nil {
+		return err
+	}
+	settle, err := utils.ParseDuration(global.GVA_CONFIG.Siem.Settle)
+	if err != nil || settle <= 0 {
+		settle = defaultAuditExportSettle
+	}
+	before := time.Now().Add(-settle)
+	for i := 0; i < auditExportMaxBatches; i++ {
+		events, err := load(global.GVA_DB, cursor.LastID, before, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		var messages [][]byte
+		for _, e := range events {
+			if !s.filter.Match(e) {
+				continue
+			}
+			msg, err := s.formatter.Format(e)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, msg)
+		}
+		if len(messages) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			err = s.sink.Send(ctx, messages)
+			cancel()
+			if err != nil {
+				global.GVA_DB.Model(&cursor).Update("last_error", err.Error())
+				return err
+			}
+		}
+		now := time.Now()
+		cursor.LastID = events[len(events)-1].ID
+		cursor.Delivered += int64(len(messages))
+		cursor.LastSuccess = &now
+		cursor.LastError = ""
+		if err = global.GVA_DB.Select("last_id", "delivered", "last_success", "last_error").Save(&cursor).Error; err != nil {
+			return err
+		}
+		if len(events) < batchSize {
+			// 读到了尚未稳定的行或已追平
+			return nil
+		}
+	}
+	return nil
+}
+
+//@function: GetExportStatus
+//@description: 获取各投递目标在各事件来源上的游标
+//@return: cursors []system.SysAuditExportCursor, err error
+
+func (auditExportService *AuditExportService) GetExportStatus() (cursors []system.SysAuditExportCursor, err error) {
+	err = global.GVA_DB.Order("sink, source").Find(&cursors).Error
+	return cursors, err
+}
+
+//@function: Close
+//@description: 关闭所有投递目标连接
+//@return: err error
+
+func (auditExportService *AuditExportService) Close() error {
+	auditExportMu.Lock()
+	defer auditExportMu.Unlock()
+	var errs []error
+	for name, s := range auditExportSinks {
+		errs = append(errs, s.sink.Close())
+		delete(auditExportSinks, name)
+	}
+	return errors.Join(errs...)
+}
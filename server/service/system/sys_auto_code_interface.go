@@ -11,6 +11,7 @@ type Database interface {
 	GetDB(businessDB string) (data []response.Db, err error)
 	GetTables(businessDB string, dbName string) (data []response.Table, err error)
 	GetColumn(businessDB string, tableName string, dbName string) (data []response.Column, err error)
+	GetForeignKeys(businessDB string, dbName string) (data []response.ForeignKey, err error)
 }
 
 func (autoCodeService *AutoCodeService) Database(businessDB string) Database {
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
@@ -81,3 +82,8 @@ ORDER BY
 
 	return entities, err
 }
+
+// GetForeignKeys 暂不支持从mssql推断关联关系
+func (s *autoCodeMssql) GetForeignKeys(businessDB string, dbName string) (data []response.ForeignKey, err error) {
+	return nil, errors.New("暂不支持从mssql推断关联关系, 请手动添加关联")
+}
@@ -81,3 +81,28 @@ ORDER BY
 
 	return entities, err
 }
+
+// GetForeignKeys 获取指定数据库的所有外键
+func (s *autoCodeMysql) GetForeignKeys(businessDB string, dbName string) (data []response.ForeignKey, err error) {
+	var entities []response.ForeignKey
+	sql := `
+	SELECT
+    kcu.CONSTRAINT_NAME constraint_name,
+    kcu.TABLE_NAME table_name,
+    kcu.COLUMN_NAME column_name,
+    kcu.REFERENCED_TABLE_NAME ref_table,
+    kcu.REFERENCED_COLUMN_NAME ref_column
+FROM
+    INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+WHERE
+    kcu.TABLE_SCHEMA = ?
+    AND kcu.REFERENCED_TABLE_NAME IS NOT NULL
+ORDER BY
+    kcu.TABLE_NAME, kcu.ORDINAL_POSITION;`
+	if businessDB == "" {
+		err = global.GVA_DB.Raw(sql, dbName).Scan(&entities).Error
+	} else {
+		err = global.GVA_DBList[businessDB].Raw(sql, dbName).Scan(&entities).Error
+	}
+	return entities, err
+}
@@ -1,6 +1,8 @@
 package system
 
 import (
+	"errors"
+
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
 )
@@ -70,3 +72,8 @@ ORDER BY
 	err = global.GVA_DBList[businessDB].Raw(sql, tableName, dbName).Scan(&entities).Error
 	return entities, err
 }
+
+// GetForeignKeys 暂不支持从oracle推断关联关系
+func (s *autoCodeOracle) GetForeignKeys(businessDB string, dbName string) (data []response.ForeignKey, err error) {
+	return nil, errors.New("暂不支持从oracle推断关联关系, 请手动添加关联")
+}
@@ -133,3 +133,34 @@ ORDER BY
 	err = db.Raw(sql, dbName, tableName).Scan(&entities).Error
 	return entities, err
 }
+
+// GetForeignKeys 获取指定数据库public模式下的所有外键
+func (a *autoCodePgsql) GetForeignKeys(businessDB string, dbName string) (data []response.ForeignKey, err error) {
+	sql := `
+SELECT
+    tc.constraint_name AS constraint_name,
+    tc.table_name AS table_name,
+    kcu.column_name AS column_name,
+    ccu.table_name AS ref_table,
+    ccu.column_name AS ref_column
+FROM
+    information_schema.table_constraints tc
+    JOIN information_schema.key_column_usage kcu
+        ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+    JOIN information_schema.constraint_column_usage ccu
+        ON ccu.constraint_name = tc.constraint_name AND ccu.table_schema = tc.table_schema
+WHERE
+    tc.constraint_type = 'FOREIGN KEY'
+    AND tc.table_catalog = ?
+    AND tc.table_schema = 'public'
+ORDER BY
+    tc.table_name, kcu.ordinal_position;
+`
+	var entities []response.ForeignKey
+	db := global.GVA_DB
+	if businessDB != "" {
+		db = global.GVA_DBList[businessDB]
+	}
+	err = db.Raw(sql, dbName).Scan(&entities).Error
+	return entities, err
+}
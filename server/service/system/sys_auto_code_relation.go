@@ -0,0 +1,191 @@
+package system
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+)
+
+// joinTableColumns 中间表中除两个外键之外允许出现的字段
+var joinTableColumns = map[string]bool{"id": true, "created_at": true, "updated_at": true, "deleted_at": true}
+
+//@function: GetRelations
+//@description: 根据外键推断表的关联关系 本表外键为belongsTo 其他表引用本表为hasMany 只有两个外键的中间表为many2many
+//@description: 生成的模型不支持复合外键 这类外键会被跳过
+//@param: businessDB string, dbName string, tableName string
+//@return: relations []response.Relation, err error
+
+func (autoCodeService *AutoCodeService) GetRelations(businessDB string, dbName string, tableName string) (relations []response.Relation, err error) {
+	database := autoCodeService.Database(businessDB)
+	keys, err := database.GetForeignKeys(businessDB, dbName)
+	if err != nil {
+		return nil, err
+	}
+	keysByTable := make(map[string][]response.ForeignKey)
+	for _, key := range singleColumnKeys(keys) {
+		keysByTable[key.TableName] = append(keysByTable[key.TableName], key)
+	}
+	columnsByTable := make(map[string][]response.Column)
+	columns := func(table string) ([]response.Column, error) {
+		if cached, ok := columnsByTable[table]; ok {
+			return cached, nil
+		}
+		loaded, err := database.GetColumn(businessDB, table, dbName)
+		if err != nil {
+			return nil, err
+		}
+		columnsByTable[table] = loaded
+		return loaded, nil
+	}
+	hasDeletedAt := func(table string) (bool, error) {
+		loaded, err := columns(table)
+		if err != nil {
+			return false, err
+		}
+		for _, column := range loaded {
+			if column.ColumnName == "deleted_at" {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	isJoinTable := func(table string) (bool, error) {
+		tableKeys := keysByTable[table]
+		if len(tableKeys) != 2 || tableKeys[0].ColumnName == tableKeys[1].ColumnName {
+			return false, nil
+		}
+		loaded, err := columns(table)
+		if err != nil {
+			return false, err
+		}
+		for _, column := range loaded {
+			if column.ColumnName == tableKeys[0].ColumnName || column.ColumnName == tableKeys[1].ColumnName || joinTableColumns[column.ColumnName] {
+				continue
+			}
+			return false, nil
+		}
+		return true, nil
+	}
+
+	for _, key := range keysByTable[tableName] {
+		relation := response.Relation{
+			Type:       "belongsTo",
+			FieldName:  relationFieldName(strings.TrimSuffix(key.ColumnName, "_id")),
+			Table:      key.RefTable,
+			ForeignKey: key.ColumnName,
+			References: key.RefColumn,
+		}
+		if relation.HasDeletedAt, err = hasDeletedAt(key.RefTable); err != nil {
+			return nil, err
+		}
+		relations = append(relations, relation)
+	}
+
+	tables := make([]string, 0, len(keysByTable))
+	for table := range keysByTable {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		join, err := isJoinTable(table)
+		if err != nil {
+			return nil, err
+		}
+		tableKeys := keysByTable[table]
+		if join {
+			for i, key := range tableKeys {
+				if key.RefTable != tableName {
+					continue
+				}
+				other := tableKeys[1-i]
+				relation := response.Relation{
+					Type:           "many2many",
+					FieldName:      relationFieldName(other.RefTable),
+					Table:          other.RefTable,
+					ForeignKey:     key.RefColumn,
+					References:     other.RefColumn,
+					JoinTable:      table,
+					JoinForeignKey: key.ColumnName,
+					JoinReferences: other.ColumnName,
+				}
+				if relation.HasDeletedAt, err = hasDeletedAt(other.RefTable); err != nil {
+					return nil, err
+				}
+				relations = append(relations, relation)
+				break
+			}
+			continue
+		}
+		for _, key := range tableKeys {
+			if key.RefTable != tableName {
+				continue
+			}
+			relation := response.Relation{
+				Type:       "hasMany",
+				FieldName:  relationFieldName(table),
+				Table:      table,
+				ForeignKey: key.ColumnName,
+				References: key.RefColumn,
+			}
+			if relation.HasDeletedAt, err = hasDeletedAt(table); err != nil {
+				return nil, err
+			}
+			relations = append(relations, relation)
+		}
+	}
+	// 建议的字段名避开本表字段和其他关联 多个外键引用同一张表时也不重名
+	loaded, err := columns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	taken := map[string]bool{"ID": true, "CreatedAt": true, "UpdatedAt": true, "DeletedAt": true, "TableName": true}
+	for _, column := range loaded {
+		taken[relationFieldName(column.ColumnName)] = true
+	}
+	for i := range relations {
+		name := relations[i].FieldName
+		for n := 1; taken[name]; n++ {
+			name = relations[i].FieldName + "Rel"
+			if n > 1 {
+				name += strconv.Itoa(n)
+			}
+		}
+		taken[name] = true
+		relations[i].FieldName = name
+		relations[i].FieldJson = utils.FirstLower(name)
+	}
+	return relations, nil
+}
+
+// singleColumnKeys 去掉复合外键 同一约束包含多个字段时无法用单个外键字段表达
+func singleColumnKeys(keys []response.ForeignKey) []response.ForeignKey {
+	count := make(map[string]int)
+	constraint := func(key response.ForeignKey) string {
+		if key.ConstraintName == "" {
+			return key.TableName + "." + key.ColumnName
+		}
+		return key.TableName + "." + key.ConstraintName
+	}
+	for _, key := range keys {
+		count[constraint(key)]++
+	}
+	single := make([]response.ForeignKey, 0, len(keys))
+	for _, key := range keys {
+		if count[constraint(key)] == 1 {
+			single = append(single, key)
+		}
+	}
+	return single
+}
+
+// relationFieldName 表名或字段名转为关联字段名 如 sys_users => SysUsers
+func relationFieldName(name string) string {
+	words := strings.Split(name, "_")
+	for i := range words {
+		words[i] = utils.FirstUpper(words[i])
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,84 @@
+package system
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+)
+
+func TestAutoCodeService_GetRelations(t *testing.T) {
+	db := setupPluginTestDB(t)
+	oldType := global.GVA_CONFIG.System.DbType
+	global.GVA_CONFIG.System.DbType = "sqlite"
+	t.Cleanup(func() { global.GVA_CONFIG.System.DbType = oldType })
+	for _, sql := range []string{
+		`CREATE TABLE authors (id integer PRIMARY KEY, code text, UNIQUE (id, code))`,
+		// author 字段与 author_id 推断的关联字段重名; 复合外键无法用单个字段表达
+		`CREATE TABLE books (id integer PRIMARY KEY, author text, author_id integer REFERENCES authors(id), editor_id integer REFERENCES authors(id),
+			ref_id integer, ref_code text, FOREIGN KEY (ref_id, ref_code) REFERENCES authors(id, code))`,
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatalf("建表失败: %v", err)
+		}
+	}
+	relations, err := new(AutoCodeService).GetRelations("", "", "books")
+	if err != nil {
+		t.Fatalf("GetRelations() error = %v", err)
+	}
+	var got []string
+	for _, relation := range relations {
+		got = append(got, relation.Type+":"+relation.FieldName+":"+relation.ForeignKey)
+	}
+	sort.Strings(got)
+	want := []string{"belongsTo:AuthorRel:author_id", "belongsTo:Editor:editor_id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRelations() = %v, 期望 %v", got, want)
+	}
+
+	for _, dbType := range []string{"mssql", "oracle"} {
+		global.GVA_CONFIG.System.DbType = dbType
+		if _, err = new(AutoCodeService).GetRelations("", "", "books"); err == nil || !strings.Contains(err.Error(), "暂不支持") {
+			t.Errorf("[%s] GetRelations() error = %v, 期望提示暂不支持", dbType, err)
+		}
+	}
+}
+
+func TestAutoCode_relationPretreatment(t *testing.T) {
+	tests := []struct {
+		name     string
+		gvaModel bool
+		relation request.AutoCodeRelation
+		wantErr  string
+	}{
+		{name: "正常", relation: request.AutoCodeRelation{FieldName: "Author"}},
+		{name: "与字段重名", relation: request.AutoCodeRelation{FieldName: "AuthorId"}, wantErr: "已有的字段重名"},
+		{name: "与默认结构重名", gvaModel: true, relation: request.AutoCodeRelation{FieldName: "CreatedAt"}, wantErr: "已有的字段重名"},
+		{name: "与TableName方法重名", relation: request.AutoCodeRelation{FieldName: "TableName"}, wantErr: "已有的字段重名"},
+		{name: "json名重复", relation: request.AutoCodeRelation{FieldName: "Writer", FieldJson: "authorId"}, wantErr: "json名[authorId]与已有字段重复"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relation := tt.relation
+			relation.Type, relation.StructName, relation.ForeignKey = request.RelationBelongsTo, "Author", "author_id"
+			info := request.AutoCode{
+				Package: "demo", StructName: "Book", PackageName: "book", GvaModel: tt.gvaModel,
+				Fields:    []*request.AutoCodeField{{FieldName: "AuthorId", FieldJson: "authorId", FieldType: "int", ColumnName: "author_id"}},
+				Relations: []*request.AutoCodeRelation{&relation},
+			}
+			err := info.Pretreatment()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Pretreatment() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Pretreatment() error = %v, 期望包含 %s", err, tt.wantErr)
+			}
+		})
+	}
+}
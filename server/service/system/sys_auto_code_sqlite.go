@@ -5,6 +5,7 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -82,3 +83,44 @@ func (a *autoCodeSqlite) GetColumn(businessDB string, tableName string, dbName s
 	}
 	return entities, err
 }
+
+// GetForeignKeys 获取所有表的外键 未写明引用字段时引用的是主键
+func (a *autoCodeSqlite) GetForeignKeys(businessDB string, dbName string) (data []response.ForeignKey, err error) {
+	db := global.GVA_DB
+	if businessDB != "" {
+		db = global.GVA_DBList[businessDB]
+	}
+	tables, err := a.GetTables(businessDB, dbName)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		var keys []struct {
+			ID    int     `gorm:"column:id"`
+			Table string  `gorm:"column:table"`
+			From  string  `gorm:"column:from"`
+			To    *string `gorm:"column:to"`
+		}
+		if err = db.Raw(fmt.Sprintf("PRAGMA foreign_key_list(%s);", table.TableName)).Scan(&keys).Error; err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			entity := response.ForeignKey{ConstraintName: strconv.Itoa(key.ID), TableName: table.TableName, ColumnName: key.From, RefTable: key.Table}
+			if key.To != nil && *key.To != "" {
+				entity.RefColumn = *key.To
+			} else {
+				var columns []response.Column
+				if columns, err = a.GetColumn(businessDB, key.Table, dbName); err != nil {
+					return nil, err
+				}
+				for _, column := range columns {
+					if column.PrimaryKey {
+						entity.RefColumn = column.ColumnName
+					}
+				}
+			}
+			data = append(data, entity)
+		}
+	}
+	return data, nil
+}
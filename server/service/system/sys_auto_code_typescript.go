@@ -0,0 +1,41 @@
+package system
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/docs"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/filetx"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/tsclient"
+	"github.com/pkg/errors"
+)
+
+//@function: GenerateTypeScript
+//@description: 根据swagger文档为全部已注册的api生成带类型的TypeScript客户端 写入web端的 api/client.ts 文档中没有定义的api会被跳过
+//@param: ctx context.Context
+//@return: path string, code string, skipped []tsclient.Route, err error
+
+func (autoCodeService *AutoCodeService) GenerateTypeScript(ctx context.Context) (path string, code string, skipped []tsclient.Route, err error) {
+	var apis []system.SysApi
+	if err = global.GVA_DB.WithContext(ctx).Order("path, method").Find(&apis).Error; err != nil {
+		return "", "", nil, errors.Wrap(err, "查询api失败!")
+	}
+	routes := make([]tsclient.Route, 0, len(apis))
+	for _, api := range apis {
+		routes = append(routes, tsclient.Route{Path: api.Path, Method: api.Method, Description: api.Description, ApiGroup: api.ApiGroup})
+	}
+	code, skipped, err = tsclient.Generate([]byte(docs.SwaggerInfo.ReadDoc()), routes)
+	if err != nil {
+		return "", "", nil, err
+	}
+	path = filepath.Join(global.GVA_CONFIG.AutoCode.Root, global.GVA_CONFIG.AutoCode.WebRoot(), "api", "client.ts")
+	files := filetx.Begin()
+	if err = files.WriteFile(path, []byte(code), 0644); err != nil {
+		_ = files.Rollback()
+		return "", "", nil, errors.Wrapf(err, "[filepath:%s]写入文件失败!", path)
+	}
+	files.Commit()
+	return path, code, skipped, nil
+}
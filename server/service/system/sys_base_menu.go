@@ -19,47 +19,51 @@ type BaseMenuService struct{}
 var BaseMenuServiceApp = new(BaseMenuService)
 
 func (baseMenuService *BaseMenuService) DeleteBaseMenu(id int) (err error) {
-	err = global.GVA_DB.First(&system.SysBaseMenu{}, "parent_id = ?", id).Error
+	return global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		return baseMenuService.deleteBaseMenu(tx, id)
+	})
+}
+
+//@function: deleteBaseMenu
+//@description: 在事务中删除基础路由及其参数、按钮与角色授权
+//@param: tx *gorm.DB, id int
+//@return: err error
+
+func (baseMenuService *BaseMenuService) deleteBaseMenu(tx *gorm.DB, id int) (err error) {
+	err = tx.First(&system.SysBaseMenu{}, "parent_id = ?", id).Error
 	if err == nil {
 		return errors.New("此菜单存在子菜单不可删除")
 	}
 	var menu system.SysBaseMenu
-	err = global.GVA_DB.First(&menu, id).Error
+	err = tx.First(&menu, id).Error
 	if err != nil {
 		return errors.New("记录不存在")
 	}
-	err = global.GVA_DB.First(&system.SysAuthority{}, "default_router = ?", menu.Name).Error
+	err = tx.First(&system.SysAuthority{}, "default_router = ?", menu.Name).Error
 	if err == nil {
 		return errors.New("此菜单有角色正在作为首页，不可删除")
 	}
-	return global.GVA_DB.Transaction(func(tx *gorm.DB) error {
-
-		err = tx.Delete(&system.SysBaseMenu{}, "id = ?", id).Error
-		if err != nil {
-			return err
-		}
 
-		err = tx.Delete(&system.SysBaseMenuParameter{}, "sys_base_menu_id = ?", id).Error
-		if err != nil {
-			return err
-		}
+	err = tx.Delete(&system.SysBaseMenu{}, "id = ?", id).Error
+	if err != nil {
+		return err
+	}
 
-		err = tx.Delete(&system.SysBaseMenuBtn{}, "sys_base_menu_id = ?", id).Error
-		if err != nil {
-			return err
-		}
-		err = tx.Delete(&system.SysAuthorityBtn{}, "sys_menu_id = ?", id).Error
-		if err != nil {
-			return err
-		}
+	err = tx.Delete(&system.SysBaseMenuParameter{}, "sys_base_menu_id = ?", id).Error
+	if err != nil {
+		return err
+	}
 
-		err = tx.Delete(&system.SysAuthorityMenu{}, "sys_base_menu_id = ?", id).Error
-		if err != nil {
-			return err
-		}
-		return nil
-	})
+	err = tx.Delete(&system.SysBaseMenuBtn{}, "sys_base_menu_id = ?", id).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Delete(&system.SysAuthorityBtn{}, "sys_menu_id = ?", id).Error
+	if err != nil {
+		return err
+	}
 
+	return tx.Delete(&system.SysAuthorityMenu{}, "sys_base_menu_id = ?", id).Error
 }
 
 //@author: [piexlmax](https://github.com/piexlmax)
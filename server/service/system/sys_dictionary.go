@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"errors"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
@@ -18,11 +19,11 @@ type DictionaryService struct{}
 
 var DictionaryServiceApp = new(DictionaryService)
 
-func (dictionaryService *DictionaryService) CreateSysDictionary(sysDictionary system.SysDictionary) (err error) {
+func (dictionaryService *DictionaryService) CreateSysDictionary(ctx context.Context, sysDictionary system.SysDictionary) (err error) {
 	if (!errors.Is(global.GVA_DB.First(&system.SysDictionary{}, "type = ?", sysDictionary.Type).Error, gorm.ErrRecordNotFound)) {
 		return errors.New("存在相同的type，不允许创建")
 	}
-	err = global.GVA_DB.Create(&sysDictionary).Error
+	err = global.GVA_DB.WithContext(ctx).Create(&sysDictionary).Error
 	return err
 }
 
@@ -32,7 +33,7 @@ func (dictionaryService *DictionaryService) CreateSysDictionary(sysDictionary sy
 //@param: sysDictionary model.SysDictionary
 //@return: err error
 
-func (dictionaryService *DictionaryService) DeleteSysDictionary(sysDictionary system.SysDictionary) (err error) {
+func (dictionaryService *DictionaryService) DeleteSysDictionary(ctx context.Context, sysDictionary system.SysDictionary) (err error) {
 	err = global.GVA_DB.Where("id = ?", sysDictionary.ID).Preload("SysDictionaryDetails").First(&sysDictionary).Error
 	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
 		return errors.New("请不要搞事")
@@ -40,13 +41,13 @@ func (dictionaryService *DictionaryService) DeleteSysDictionary(sysDictionary sy
 	if err != nil {
 		return err
 	}
-	err = global.GVA_DB.Delete(&sysDictionary).Error
+	err = global.GVA_DB.WithContext(ctx).Delete(&sysDictionary).Error
 	if err != nil {
 		return err
 	}
 
 	if sysDictionary.SysDictionaryDetails != nil {
-		return global.GVA_DB.Where("sys_dictionary_id=?", sysDictionary.ID).Delete(sysDictionary.SysDictionaryDetails).Error
+		return global.GVA_DB.WithContext(ctx).Where("sys_dictionary_id=?", sysDictionary.ID).Delete(sysDictionary.SysDictionaryDetails).Error
 	}
 	return
 }
@@ -57,7 +58,7 @@ func (dictionaryService *DictionaryService) DeleteSysDictionary(sysDictionary sy
 //@param: sysDictionary *model.SysDictionary
 //@return: err error
 
-func (dictionaryService *DictionaryService) UpdateSysDictionary(sysDictionary *system.SysDictionary) (err error) {
+func (dictionaryService *DictionaryService) UpdateSysDictionary(ctx context.Context, sysDictionary *system.SysDictionary) (err error) {
 	var dict system.SysDictionary
 	sysDictionaryMap := map[string]interface{}{
 		"Name":   sysDictionary.Name,
@@ -75,7 +76,7 @@ func (dictionaryService *DictionaryService) UpdateSysDictionary(sysDictionary *s
 			return errors.New("存在相同的type，不允许创建")
 		}
 	}
-	err = global.GVA_DB.Model(&dict).Updates(sysDictionaryMap).Error
+	err = global.GVA_DB.WithContext(ctx).Model(&dict).Updates(sysDictionaryMap).Error
 	return err
 }
 
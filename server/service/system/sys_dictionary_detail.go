@@ -1,9 +1,13 @@
 package system
 
 import (
+	"context"
+	"errors"
+
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"gorm.io/gorm"
 )
 
 //@author: [piexlmax](https://github.com/piexlmax)
@@ -16,31 +20,71 @@ type DictionaryDetailService struct{}
 
 var DictionaryDetailServiceApp = new(DictionaryDetailService)
 
-func (dictionaryDetailService *DictionaryDetailService) CreateSysDictionaryDetail(sysDictionaryDetail system.SysDictionaryDetail) (err error) {
-	err = global.GVA_DB.Create(&sysDictionaryDetail).Error
+func (dictionaryDetailService *DictionaryDetailService) CreateSysDictionaryDetail(ctx context.Context, sysDictionaryDetail system.SysDictionaryDetail) (err error) {
+	if sysDictionaryDetail.ParentID != nil {
+		var parent system.SysDictionaryDetail
+		if err = global.GVA_DB.First(&parent, *sysDictionaryDetail.ParentID).Error; err != nil {
+			return errors.New("父级字典详情不存在")
+		}
+		if sysDictionaryDetail.SysDictionaryID == 0 {
+			sysDictionaryDetail.SysDictionaryID = parent.SysDictionaryID
+		}
+		if parent.SysDictionaryID != sysDictionaryDetail.SysDictionaryID {
+			return errors.New("父级字典详情不属于同一字典")
+		}
+	}
+	err = global.GVA_DB.WithContext(ctx).Create(&sysDictionaryDetail).Error
 	return err
 }
 
 //@author: [piexlmax](https://github.com/piexlmax)
 //@function: DeleteSysDictionaryDetail
-//@description: 删除字典详情数据
+//@description: 删除字典详情数据 存在子级时不允许删除
 //@param: sysDictionaryDetail model.SysDictionaryDetail
 //@return: err error
 
-func (dictionaryDetailService *DictionaryDetailService) DeleteSysDictionaryDetail(sysDictionaryDetail system.SysDictionaryDetail) (err error) {
-	err = global.GVA_DB.Delete(&sysDictionaryDetail).Error
+func (dictionaryDetailService *DictionaryDetailService) DeleteSysDictionaryDetail(ctx context.Context, sysDictionaryDetail system.SysDictionaryDetail) (err error) {
+	var children int64
+	if err = global.GVA_DB.Model(&system.SysDictionaryDetail{}).Where("parent_id = ?", sysDictionaryDetail.ID).Count(&children).Error; err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("存在子级字典详情 请先删除或移走子级")
+	}
+	err = global.GVA_DB.WithContext(ctx).Delete(&sysDictionaryDetail).Error
 	return err
 }
 
 //@author: [piexlmax](https://github.com/piexlmax)
 //@function: UpdateSysDictionaryDetail
-//@description: 更新字典详情数据
+//@description: 更新字典详情数据 父级只能通过 MoveSysDictionaryDetail 修改 启用状态变化时同步到全部子级
 //@param: sysDictionaryDetail *model.SysDictionaryDetail
 //@return: err error
 
-func (dictionaryDetailService *DictionaryDetailService) UpdateSysDictionaryDetail(sysDictionaryDetail *system.SysDictionaryDetail) (err error) {
-	err = global.GVA_DB.Save(sysDictionaryDetail).Error
-	return err
+func (dictionaryDetailService *DictionaryDetailService) UpdateSysDictionaryDetail(ctx context.Context, sysDictionaryDetail *system.SysDictionaryDetail) (err error) {
+	return global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old system.SysDictionaryDetail
+		if err := tx.First(&old, sysDictionaryDetail.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("parent_id", "sys_dictionary_id").Save(sysDictionaryDetail).Error; err != nil {
+			return err
+		}
+		sysDictionaryDetail.ParentID = old.ParentID
+		sysDictionaryDetail.SysDictionaryID = old.SysDictionaryID
+		if sysDictionaryDetail.Status == nil || (old.Status != nil && *old.Status == *sysDictionaryDetail.Status) {
+			return nil
+		}
+		details, err := loadDictionaryDetails(tx, old.SysDictionaryID)
+		if err != nil {
+			return err
+		}
+		ids := dictionaryDetailDescendants(details, old.ID)
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&system.SysDictionaryDetail{}).Where("id in ?", ids).Update("status", *sysDictionaryDetail.Status).Error
+	})
 }
 
 //@author: [piexlmax](https://github.com/piexlmax)
@@ -79,6 +123,9 @@ func (dictionaryDetailService *DictionaryDetailService) GetSysDictionaryDetailIn
 	if info.SysDictionaryID != 0 {
 		db = db.Where("sys_dictionary_id = ?", info.SysDictionaryID)
 	}
+	if info.ParentID != nil {
+		db = db.Where("parent_id = ?", info.ParentID)
+	}
 	err = db.Count(&total).Error
 	if err != nil {
 		return
@@ -116,3 +163,261 @@ func (dictionaryDetailService *DictionaryDetailService) GetDictionaryInfoByTypeV
 	err = db.First(&sysDictionaryDetails, "sys_dictionaries.type = ? and sys_dictionary_details.value = ?", t, value).Error
 	return sysDictionaryDetails, err
 }
+
+//@function: GetDictionaryTreeList
+//@description: 按照字典id获取树形字典详情
+//@param: dictionaryID int
+//@return: list []system.SysDictionaryDetail, err error
+
+func (dictionaryDetailService *DictionaryDetailService) GetDictionaryTreeList(dictionaryID int) (list []system.SysDictionaryDetail, err error) {
+	details, err := loadDictionaryDetails(global.GVA_DB, dictionaryID)
+	if err != nil {
+		return nil, err
+	}
+	return buildDictionaryDetailTree(details), nil
+}
+
+//@function: GetDictionaryTreeListByType
+//@description: 按照字典type获取树形字典详情 GetDictionaryListByType 的树形版本
+//@param: t string
+//@return: list []system.SysDictionaryDetail, err error
+
+func (dictionaryDetailService *DictionaryDetailService) GetDictionaryTreeListByType(t string) (list []system.SysDictionaryDetail, err error) {
+	var dictionary system.SysDictionary
+	if err = global.GVA_DB.Where("type = ?", t).First(&dictionary).Error; err != nil {
+		return nil, err
+	}
+	return dictionaryDetailService.GetDictionaryTreeList(int(dictionary.ID))
+}
+
+//@function: GetDictionaryDetailsByParent
+//@description: 获取某个父级下的直接子级 用于树形懒加载
+//@param: info request.SysDictionaryDetailChildren
+//@return: list []system.SysDictionaryDetail, err error
+
+func (dictionaryDetailService *DictionaryDetailService) GetDictionaryDetailsByParent(info request.SysDictionaryDetailChildren) (list []system.SysDictionaryDetail, err error) {
+	db := global.GVA_DB.Where("sys_dictionary_id = ?", info.SysDictionaryID)
+	if info.ParentID == nil {
+		db = db.Where("parent_id IS NULL")
+	} else {
+		db = db.Where("parent_id = ?", *info.ParentID)
+	}
+	err = db.Order("sort, id").Find(&list).Error
+	return list, err
+}
+
+//@function: GetDictionaryPathByTypeValue
+//@description: 按照字典type+字典值获取从顶层到该详情的路径 如省/市/区
+//@param: t string, value string
+//@return: path []system.SysDictionaryDetail, err error
+
+func (dictionaryDetailService *DictionaryDetailService) GetDictionaryPathByTypeValue(t string, value string) (path []system.SysDictionaryDetail, err error) {
+	var dictionary system.SysDictionary
+	if err = global.GVA_DB.Where("type = ?", t).First(&dictionary).Error; err != nil {
+		return nil, err
+	}
+	details, err := loadDictionaryDetails(global.GVA_DB, int(dictionary.ID))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]system.SysDictionaryDetail, len(details))
+	var node *system.SysDictionaryDetail
+	for i := range details {
+		byID[details[i].ID] = details[i]
+		if node == nil && details[i].Value == value {
+			node = &details[i]
+		}
+	}
+	if node == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	for current := *node; ; {
+		path = append(path, current)
+		if current.ParentID == nil || len(path) > len(details) {
+			break
+		}
+		parent, ok := byID[*current.ParentID]
+		if !ok {
+			break
+		}
+		current = parent
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+//@function: MoveSysDictionaryDetail
+//@description: 将字典详情移动到新的父级和位置 不允许移动到自身或其子级下
+//@param: ctx context.Context, info request.SysDictionaryDetailMove
+//@return: err error
+
+func (dictionaryDetailService *DictionaryDetailService) MoveSysDictionaryDetail(ctx context.Context, info request.SysDictionaryDetailMove) (err error) {
+	return global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var node system.SysDictionaryDetail
+		if err := tx.First(&node, info.ID).Error; err != nil {
+			return err
+		}
+		details, err := loadDictionaryDetails(tx, node.SysDictionaryID)
+		if err != nil {
+			return err
+		}
+		if info.ParentID != nil {
+			if *info.ParentID == node.ID {
+				return errors.New("不能移动到自身下")
+			}
+			found := false
+			for _, d := range details {
+				found = found || d.ID == *info.ParentID
+			}
+			if !found {
+				return errors.New("父级字典详情不存在或不属于同一字典")
+			}
+			for _, id := range dictionaryDetailDescendants(details, node.ID) {
+				if id == *info.ParentID {
+					return errors.New("不能移动到自身的子级下")
+				}
+			}
+		}
+		var siblings []uint
+		for _, d := range dictionaryDetailChildren(details, info.ParentID) {
+			if d.ID != node.ID {
+				siblings = append(siblings, d.ID)
+			}
+		}
+		position := len(siblings)
+		if info.Position != nil && *info.Position >= 0 && *info.Position < position {
+			position = *info.Position
+		}
+		siblings = append(siblings[:position], append([]uint{node.ID}, siblings[position:]...)...)
+		if err = tx.Model(&node).Update("parent_id", info.ParentID).Error; err != nil {
+			return err
+		}
+		return resortDictionaryDetails(tx, details, siblings)
+	})
+}
+
+//@function: SortSysDictionaryDetail
+//@description: 调整同级字典详情的顺序
+//@param: ctx context.Context, info request.SysDictionaryDetailSort
+//@return: err error
+
+func (dictionaryDetailService *DictionaryDetailService) SortSysDictionaryDetail(ctx context.Context, info request.SysDictionaryDetailSort) (err error) {
+	return global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		details, err := loadDictionaryDetails(tx, info.SysDictionaryID)
+		if err != nil {
+			return err
+		}
+		siblings := dictionaryDetailChildren(details, info.ParentID)
+		isSibling := make(map[uint]bool, len(siblings))
+		for _, d := range siblings {
+			isSibling[d.ID] = true
+		}
+		ordered := make([]uint, 0, len(siblings))
+		listed := make(map[uint]bool, len(info.IDs))
+		for _, id := range info.IDs {
+			if !isSibling[id] {
+				return errors.New("排序的字典详情必须属于同一父级")
+			}
+			if !listed[id] {
+				listed[id] = true
+				ordered = append(ordered, id)
+			}
+		}
+		for _, d := range siblings {
+			if !listed[d.ID] {
+				ordered = append(ordered, d.ID)
+			}
+		}
+		return resortDictionaryDetails(tx, details, ordered)
+	})
+}
+
+// loadDictionaryDetails 加载字典下的全部详情 按排序标记排序
+func loadDictionaryDetails(db *gorm.DB, dictionaryID int) (details []system.SysDictionaryDetail, err error) {
+	err = db.Where("sys_dictionary_id = ?", dictionaryID).Order("sort, id").Find(&details).Error
+	return details, err
+}
+
+// dictionaryDetailChildren 返回某个父级下的直接子级 parentID 为空时返回顶层
+func dictionaryDetailChildren(details []system.SysDictionaryDetail, parentID *uint) (children []system.SysDictionaryDetail) {
+	for _, d := range details {
+		if (parentID == nil && d.ParentID == nil) || (parentID != nil && d.ParentID != nil && *d.ParentID == *parentID) {
+			children = append(children, d)
+		}
+	}
+	return children
+}
+
+// dictionaryDetailDescendants 返回某个详情的全部子孙ID
+func dictionaryDetailDescendants(details []system.SysDictionaryDetail, id uint) (ids []uint) {
+	children := map[uint][]uint{}
+	for _, d := range details {
+		if d.ParentID != nil {
+			children[*d.ParentID] = append(children[*d.ParentID], d.ID)
+		}
+	}
+	visited := map[uint]bool{id: true}
+	queue := []uint{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range children[current] {
+			if !visited[child] {
+				visited[child] = true
+				ids = append(ids, child)
+				queue = append(queue, child)
+			}
+		}
+	}
+	return ids
+}
+
+// buildDictionaryDetailTree 组装树形结构 details 需已按排序标记排序 父级不存在的详情作为顶层
+func buildDictionaryDetailTree(details []system.SysDictionaryDetail) []system.SysDictionaryDetail {
+	exists := make(map[uint]bool, len(details))
+	for _, d := range details {
+		exists[d.ID] = true
+	}
+	children := map[uint][]system.SysDictionaryDetail{}
+	var roots []system.SysDictionaryDetail
+	for _, d := range details {
+		if d.ParentID == nil || !exists[*d.ParentID] || *d.ParentID == d.ID {
+			roots = append(roots, d)
+			continue
+		}
+		children[*d.ParentID] = append(children[*d.ParentID], d)
+	}
+	visited := map[uint]bool{}
+	var fill func(nodes []system.SysDictionaryDetail)
+	fill = func(nodes []system.SysDictionaryDetail) {
+		for i := range nodes {
+			if visited[nodes[i].ID] {
+				continue
+			}
+			visited[nodes[i].ID] = true
+			nodes[i].Children = children[nodes[i].ID]
+			fill(nodes[i].Children)
+		}
+	}
+	fill(roots)
+	return roots
+}
+
+// resortDictionaryDetails 按给定顺序重写排序标记 只更新发生变化的行
+func resortDictionaryDetails(tx *gorm.DB, details []system.SysDictionaryDetail, ordered []uint) error {
+	current := make(map[uint]int, len(details))
+	for _, d := range details {
+		current[d.ID] = d.Sort
+	}
+	for i, id := range ordered {
+		if s, ok := current[id]; ok && s == i+1 {
+			continue
+		}
+		if err := tx.Model(&system.SysDictionaryDetail{GVA_MODEL: global.GVA_MODEL{ID: id}}).Update("sort", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
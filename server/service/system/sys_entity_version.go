@@ -0,0 +1,218 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/versioning"
+	"gorm.io/gorm"
+)
+
+type EntityVersionService struct{}
+
+var EntityVersionServiceApp = new(EntityVersionService)
+
+var entityVersionPlugin = &versioning.Plugin{Store: storeEntityVersions}
+
+// versionedModels 默认开启变更历史的模型 业务模型通过 RegisterModels 按需开启
+var versionedModels = []any{
+	&system.SysDictionary{},
+	&system.SysDictionaryDetail{},
+	&system.SysApi{},
+	&system.SysParams{},
+	&system.SysFeatureFlag{},
+}
+
+// entityVersionRetries 并发写入同一实体时版本号冲突的重试次数
+const entityVersionRetries = 5
+
+func storeEntityVersions(tx *gorm.DB, changes []versioning.Change) error {
+	for _, change := range changes {
+		var latest int
+		err := tx.Model(&system.SysEntityVersion{}).
+			Where("entity_table = ? AND entity_id = ?", change.Table, change.EntityID).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		version := system.SysEntityVersion{
+			EntityTable:   change.Table,
+			EntityID:      change.EntityID,
+			Version:       latest + 1,
+			Action:        change.Action,
+			Before:        string(change.Before),
+			After:         string(change.After),
+			ChangedFields: strings.Join(change.Fields, ","),
+			UserID:        change.Actor.UserID,
+			Username:      change.Actor.Username,
+			RequestID:     change.Actor.RequestID,
+		}
+		if err = storeEntityVersion(tx, &version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeEntityVersion 写入一条版本 唯一索引冲突时说明其他事务已占用该版本号 递增后重试
+// 写入放在嵌套事务(保存点)中 冲突不会使外层业务事务失效
+func storeEntityVersion(tx *gorm.DB, version *system.SysEntityVersion) (err error) {
+	for i := 0; i < entityVersionRetries; i++ {
+		err = tx.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(version).Error
+		})
+		if !isDuplicatedKey(tx, err) {
+			return err
+		}
+		version.ID = 0
+		version.Version++
+	}
+	return err
+}
+
+// isDuplicatedKey 是否为唯一索引冲突 未开启 TranslateError 时通过方言转换
+func isDuplicatedKey(db *gorm.DB, err error) bool {
+	if err == nil {
+		return false
+	}
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+//@function: RegisterPlugin
+//@description: 为数据库连接注册变更历史gorm回调 并开启默认模型
+//@param: db *gorm.DB
+//@return: error
+
+func (entityVersionService *EntityVersionService) RegisterPlugin(db *gorm.DB) error {
+	if db == nil {
+		return nil
+	}
+	if err := db.Use(entityVersionPlugin); err != nil && !errors.Is(err, gorm.ErrRegistered) {
+		return err
+	}
+	return entityVersionPlugin.Register(versionedModels...)
+}
+
+//@function: RegisterModels
+//@description: 为模型开启变更历史
+//@param: models ...any
+//@return: error
+
+func (entityVersionService *EntityVersionService) RegisterModels(models ...any) error {
+	return entityVersionPlugin.Register(models...)
+}
+
+//@function: Tables
+//@description: 已开启变更历史的表
+//@return: []string
+
+func (entityVersionService *EntityVersionService) Tables() []string {
+	return entityVersionPlugin.Tables()
+}
+
+//@function: GetEntityVersionList
+//@description: 分页获取实体的变更历史 按版本倒序
+//@param: info systemReq.EntityVersionSearch
+//@return: list []system.SysEntityVersion, total int64, err error
+
+func (entityVersionService *EntityVersionService) GetEntityVersionList(info systemReq.EntityVersionSearch) (list []system.SysEntityVersion, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.SysEntityVersion{}).Where("entity_table = ? AND entity_id = ?", info.EntityTable, info.EntityID)
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("version desc").Find(&list).Error
+	return list, total, err
+}
+
+//@function: GetTableVersionList
+//@description: 分页获取一张表的变更历史 按记录时间倒序 ids不为空时只取这些实体
+//@param: table string, ids []string, info request.PageInfo
+//@return: list []system.SysEntityVersion, total int64, err error
+
+func (entityVersionService *EntityVersionService) GetTableVersionList(table string, ids []string, info request.PageInfo) (list []system.SysEntityVersion, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.SysEntityVersion{}).Where("entity_table = ?", table)
+	if len(ids) > 0 {
+		db = db.Where("entity_id IN ?", ids)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
+
+//@function: DiffEntityVersion
+//@description: 比较实体两个版本之后的状态 版本0表示实体不存在
+//@param: info systemReq.EntityVersionDiff
+//@return: changes []versioning.FieldChange, err error
+
+func (entityVersionService *EntityVersionService) DiffEntityVersion(info systemReq.EntityVersionDiff) (changes []versioning.FieldChange, err error) {
+	state := func(version int) (string, error) {
+		if version == 0 {
+			return "", nil
+		}
+		var v system.SysEntityVersion
+		err := global.GVA_DB.Where("entity_table = ? AND entity_id = ? AND version = ?", info.EntityTable, info.EntityID, version).First(&v).Error
+		return v.After, err
+	}
+	from, err := state(info.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := state(info.To)
+	if err != nil {
+		return nil, err
+	}
+	return versioning.Diff([]byte(from), []byte(to))
+}
+
+//@function: RestoreEntityVersion
+//@description: 将实体恢复到指定版本之后的状态 删除版本则恢复到删除前
+//@param: ctx context.Context, id uint
+//@return: err error
+
+func (entityVersionService *EntityVersionService) RestoreEntityVersion(ctx context.Context, id uint) (err error) {
+	var v system.SysEntityVersion
+	if err = global.GVA_DB.First(&v, "id = ?", id).Error; err != nil {
+		return err
+	}
+	snapshot := v.After
+	if snapshot == "" {
+		snapshot = v.Before
+	}
+	if snapshot == "" {
+		return errors.New("该版本没有可恢复的快照")
+	}
+	err = entityVersionPlugin.Restore(global.GVA_DB.WithContext(ctx), v.EntityTable, []byte(snapshot))
+	if err != nil {
+		return err
+	}
+	// 带缓存的表恢复后通知各实例重新加载
+	switch v.EntityTable {
+	case system.SysParams{}.TableName():
+		sysParamsCache.publish()
+	case system.SysFeatureFlag{}.TableName():
+		featureFlagCache.publish()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"gorm.io/gorm"
+)
+
+func TestStoreEntityVersion(t *testing.T) {
+	db := setupPluginTestDB(t, &system.SysEntityVersion{})
+	// 模拟并发事务已写入版本1和2 本事务读到的最大版本仍是0
+	for _, v := range []int{1, 2} {
+		if err := db.Create(&system.SysEntityVersion{EntityTable: "sys_params", EntityID: "1", Version: v}).Error; err != nil {
+			t.Fatalf("准备版本失败: %v", err)
+		}
+	}
+	version := system.SysEntityVersion{EntityTable: "sys_params", EntityID: "1", Version: 1, Action: "update"}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := storeEntityVersion(tx, &version); err != nil {
+			return err
+		}
+		// 冲突回滚到保存点 外层事务仍可继续执行
+		return tx.Create(&system.SysEntityVersion{EntityTable: "sys_params", EntityID: "2", Version: 1}).Error
+	})
+	if err != nil {
+		t.Fatalf("storeEntityVersion() error = %v", err)
+	}
+	if version.Version != 3 {
+		t.Errorf("版本号 = %d, 期望 3", version.Version)
+	}
+	var versions []int
+	db.Model(&system.SysEntityVersion{}).Where("entity_table = ? AND entity_id = ?", "sys_params", "1").Order("version").Pluck("version", &versions)
+	if len(versions) != 3 || versions[2] != 3 {
+		t.Errorf("版本列表 = %v, 期望 [1 2 3]", versions)
+	}
+}
@@ -0,0 +1,192 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/featureflag"
+	"gorm.io/gorm"
+)
+
+type FeatureFlagService struct{}
+
+var FeatureFlagServiceApp = new(FeatureFlagService)
+
+// featureFlagCache 功能开关缓存 键为开关键
+var featureFlagCache = newKeyedCache("gva:feature_flags:invalidate", func() (map[string]featureflag.Flag, error) {
+	var rows []system.SysFeatureFlag
+	if err := global.GVA_DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	items := make(map[string]featureflag.Flag, len(rows))
+	for _, row := range rows {
+		items[row.Key] = row.Flag()
+	}
+	return items, nil
+})
+
+var featureFlagColumns = []string{"flag_key", "name", "description", "type", "enabled", "default_variant", "variants", "rules"}
+
+//@function: CreateFeatureFlag
+//@description: 创建功能开关
+//@param: ctx context.Context, flag *system.SysFeatureFlag
+//@return: err error
+
+func (featureFlagService *FeatureFlagService) CreateFeatureFlag(ctx context.Context, flag *system.SysFeatureFlag) (err error) {
+	if err = flag.Flag().Validate(); err != nil {
+		return err
+	}
+	err = global.GVA_DB.WithContext(ctx).Create(flag).Error
+	if err == nil {
+		featureFlagCache.publish()
+	}
+	return err
+}
+
+//@function: UpdateFeatureFlag
+//@description: 更新功能开关 取值和规则整体替换
+//@param: ctx context.Context, flag system.SysFeatureFlag
+//@return: err error
+
+func (featureFlagService *FeatureFlagService) UpdateFeatureFlag(ctx context.Context, flag system.SysFeatureFlag) (err error) {
+	if err = flag.Flag().Validate(); err != nil {
+		return err
+	}
+	err = global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old system.SysFeatureFlag
+		if err := tx.First(&old, flag.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&old).Select(featureFlagColumns).Updates(&flag).Error
+	})
+	if err == nil {
+		featureFlagCache.publish()
+	}
+	return err
+}
+
+//@function: DeleteFeatureFlag
+//@description: 删除功能开关 物理删除以便重新创建相同的键 变更历史保留
+//@param: ctx context.Context, ids []int
+//@return: err error
+
+func (featureFlagService *FeatureFlagService) DeleteFeatureFlag(ctx context.Context, ids []int) (err error) {
+	err = global.GVA_DB.WithContext(ctx).Unscoped().Delete(&[]system.SysFeatureFlag{}, "id in ?", ids).Error
+	if err == nil {
+		featureFlagCache.publish()
+	}
+	return err
+}
+
+//@function: GetFeatureFlag
+//@description: 根据id获取功能开关
+//@param: id uint
+//@return: flag system.SysFeatureFlag, err error
+
+func (featureFlagService *FeatureFlagService) GetFeatureFlag(id uint) (flag system.SysFeatureFlag, err error) {
+	err = global.GVA_DB.First(&flag, id).Error
+	return
+}
+
+//@function: GetFeatureFlagList
+//@description: 分页获取功能开关
+//@param: info systemReq.SysFeatureFlagSearch
+//@return: list []system.SysFeatureFlag, total int64, err error
+
+func (featureFlagService *FeatureFlagService) GetFeatureFlagList(info systemReq.SysFeatureFlagSearch) (list []system.SysFeatureFlag, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.SysFeatureFlag{})
+	if info.Key != "" {
+		db = db.Where("flag_key LIKE ?", "%"+info.Key+"%")
+	}
+	if info.Enabled != nil {
+		db = db.Where("enabled = ?", *info.Enabled)
+	}
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("flag_key").Find(&list).Error
+	return list, total, err
+}
+
+//@function: GetFeatureFlagAuditList
+//@description: 分页获取功能开关变更审计 取自实体变更历史 指定开关键时只取当前同键开关的历史
+//@param: info systemReq.SysFeatureFlagAuditSearch
+//@return: list []system.SysEntityVersion, total int64, err error
+
+func (featureFlagService *FeatureFlagService) GetFeatureFlagAuditList(info systemReq.SysFeatureFlagAuditSearch) (list []system.SysEntityVersion, total int64, err error) {
+	var ids []string
+	if info.FlagKey != "" {
+		var flag system.SysFeatureFlag
+		err = global.GVA_DB.Where("flag_key = ?", info.FlagKey).First(&flag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, nil
+		}
+		if err != nil {
+			return
+		}
+		ids = []string{strconv.FormatUint(uint64(flag.ID), 10)}
+	}
+	return EntityVersionServiceApp.GetTableVersionList(system.SysFeatureFlag{}.TableName(), ids, info.PageInfo)
+}
+
+//@function: Evaluate
+//@description: 对求值对象计算某个开关 开关不存在或读取失败时视为关闭
+//@param: key string, subject featureflag.Subject
+//@return: featureflag.Result
+
+func (featureFlagService *FeatureFlagService) Evaluate(key string, subject featureflag.Subject) featureflag.Result {
+	flag, ok, err := featureFlagCache.get(key)
+	if err != nil {
+		return featureflag.Result{Key: key, Variant: featureflag.VariantOff, Reason: "error"}
+	}
+	if !ok {
+		return featureflag.Result{Key: key, Variant: featureflag.VariantOff, Reason: "not_found"}
+	}
+	return flag.Evaluate(subject)
+}
+
+//@function: IsEnabled
+//@description: 开关对求值对象是否开启
+//@param: key string, subject featureflag.Subject
+//@return: bool
+
+func (featureFlagService *FeatureFlagService) IsEnabled(key string, subject featureflag.Subject) bool {
+	return featureFlagService.Evaluate(key, subject).Enabled
+}
+
+//@function: Variant
+//@description: 多值开关对求值对象的取值
+//@param: key string, subject featureflag.Subject
+//@return: string
+
+func (featureFlagService *FeatureFlagService) Variant(key string, subject featureflag.Subject) string {
+	return featureFlagService.Evaluate(key, subject).Variant
+}
+
+//@function: EvaluateAll
+//@description: 计算全部开关 供前端按开关显示功能
+//@param: subject featureflag.Subject
+//@return: results []featureflag.Result, err error
+
+func (featureFlagService *FeatureFlagService) EvaluateAll(subject featureflag.Subject) (results []featureflag.Result, err error) {
+	flags, err := featureFlagCache.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	results = make([]featureflag.Result, 0, len(flags))
+	for _, flag := range flags {
+		results = append(results, flag.Evaluate(subject))
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Key < results[j].Key })
+	return results, nil
+}
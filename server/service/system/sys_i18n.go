@@ -0,0 +1,284 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/i18n"
+	"gorm.io/gorm/clause"
+)
+
+// userLocaleSetting 用户配置 OriginSetting 中保存语言的键
+const userLocaleSetting = "locale"
+
+type I18nService struct{}
+
+var I18nServiceApp = new(I18nService)
+
+// userLocales 用户ID -> 用户配置的语言 空字符串表示未配置
+var userLocales sync.Map
+
+//@function: Reload
+//@description: 从数据库加载翻译 覆盖内置翻译
+//@return: err error
+
+func (i18nService *I18nService) Reload() error {
+	var rows []system.SysTranslation
+	if err := global.GVA_DB.Find(&rows).Error; err != nil {
+		return err
+	}
+	t := i18n.Translations{}
+	for _, row := range rows {
+		t.Set(row.Locale, row.Namespace, row.Key, row.Value)
+	}
+	i18n.Default.Replace(t)
+	return nil
+}
+
+// checkTranslation 校验语言和命名空间 并将语言规范为配置中的写法
+func checkTranslation(t *system.SysTranslation) error {
+	locale, ok := i18n.Match(t.Locale, i18n.Supported())
+	if !ok {
+		return fmt.Errorf("语言 %s 未在 i18n.locales 中配置", t.Locale)
+	}
+	t.Locale = locale
+	for _, ns := range i18n.Namespaces {
+		if ns == t.Namespace {
+			return nil
+		}
+	}
+	return fmt.Errorf("未知的命名空间 %s", t.Namespace)
+}
+
+//@function: CreateTranslation
+//@description: 创建翻译
+//@param: ctx context.Context, t *system.SysTranslation
+//@return: err error
+
+func (i18nService *I18nService) CreateTranslation(ctx context.Context, t *system.SysTranslation) error {
+	if err := checkTranslation(t); err != nil {
+		return err
+	}
+	if err := global.GVA_DB.WithContext(ctx).Create(t).Error; err != nil {
+		return err
+	}
+	return i18nService.Reload()
+}
+
+//@function: UpdateTranslation
+//@description: 更新翻译
+//@param: ctx context.Context, t system.SysTranslation
+//@return: err error
+
+func (i18nService *I18nService) UpdateTranslation(ctx context.Context, t system.SysTranslation) error {
+	if err := checkTranslation(&t); err != nil {
+		return err
+	}
+	err := global.GVA_DB.WithContext(ctx).Model(&system.SysTranslation{}).Where("id = ?", t.ID).
+		Select("locale", "namespace", "translation_key", "value").Updates(&t).Error
+	if err != nil {
+		return err
+	}
+	return i18nService.Reload()
+}
+
+//@function: DeleteTranslation
+//@description: 删除翻译 物理删除以便重新创建相同的键
+//@param: ctx context.Context, ids []int
+//@return: err error
+
+func (i18nService *I18nService) DeleteTranslation(ctx context.Context, ids []int) error {
+	if err := global.GVA_DB.WithContext(ctx).Unscoped().Delete(&[]system.SysTranslation{}, "id in ?", ids).Error; err != nil {
+		return err
+	}
+	return i18nService.Reload()
+}
+
+//@function: GetTranslationList
+//@description: 分页获取翻译
+//@param: info systemReq.SysTranslationSearch
+//@return: list []system.SysTranslation, total int64, err error
+
+func (i18nService *I18nService) GetTranslationList(info systemReq.SysTranslationSearch) (list []system.SysTranslation, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.SysTranslation{})
+	if info.Locale != "" {
+		db = db.Where("locale = ?", info.Locale)
+	}
+	if info.Namespace != "" {
+		db = db.Where("namespace = ?", info.Namespace)
+	}
+	if info.Key != "" {
+		db = db.Where("translation_key LIKE ?", "%"+info.Key+"%")
+	}
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("locale, namespace, translation_key").Find(&list).Error
+	return list, total, err
+}
+
+//@function: ImportTranslations
+//@description: 导入翻译文件 已存在的键覆盖译文
+//@param: ctx context.Context, file systemReq.TranslationFile
+//@return: count int, err error
+
+func (i18nService *I18nService) ImportTranslations(ctx context.Context, file systemReq.TranslationFile) (count int, err error) {
+	var rows []system.SysTranslation
+	for ns, kv := range file.Translations {
+		for k, v := range kv {
+			row := system.SysTranslation{Locale: file.Locale, Namespace: ns, Key: k, Value: v}
+			if err = checkTranslation(&row); err != nil {
+				return 0, err
+			}
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return 0, errors.New("翻译文件为空")
+	}
+	err = global.GVA_DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "locale"}, {Name: "namespace"}, {Name: "translation_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).CreateInBatches(&rows, 200).Error
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), i18nService.Reload()
+}
+
+//@function: ExportTranslations
+//@description: 导出某个语言的翻译 包含内置翻译和数据库维护的翻译
+//@param: locale string
+//@return: file systemReq.TranslationFile, err error
+
+func (i18nService *I18nService) ExportTranslations(locale string) (file systemReq.TranslationFile, err error) {
+	locale, ok := i18n.Match(locale, i18n.Supported())
+	if !ok {
+		return file, errors.New("语言未在 i18n.locales 中配置")
+	}
+	var rows []system.SysTranslation
+	if err = global.GVA_DB.Where("locale = ?", locale).Find(&rows).Error; err != nil {
+		return file, err
+	}
+	file = systemReq.TranslationFile{Locale: locale, Translations: map[string]map[string]string{}}
+	for ns, kv := range i18n.Default.Builtin(locale) {
+		file.Translations[ns] = kv
+	}
+	for _, row := range rows {
+		if file.Translations[row.Namespace] == nil {
+			file.Translations[row.Namespace] = map[string]string{}
+		}
+		file.Translations[row.Namespace][row.Key] = row.Value
+	}
+	return file, nil
+}
+
+//@function: ResolveLocale
+//@description: 确定请求语言 优先使用用户配置 其次 Accept-Language 最后使用默认语言
+//@param: userID uint, acceptLanguage string
+//@return: locale string
+
+func (i18nService *I18nService) ResolveLocale(userID uint, acceptLanguage string) string {
+	supported := i18n.Supported()
+	if userID != 0 {
+		if l, ok := i18n.Match(i18nService.userLocale(userID), supported); ok {
+			return l
+		}
+	}
+	if l, ok := i18n.Negotiate(acceptLanguage, supported); ok {
+		return l
+	}
+	return i18n.DefaultLocale()
+}
+
+func (i18nService *I18nService) userLocale(userID uint) string {
+	if v, ok := userLocales.Load(userID); ok {
+		return v.(string)
+	}
+	var user system.SysUser
+	if err := global.GVA_DB.Select("id", "origin_setting").First(&user, userID).Error; err != nil {
+		return ""
+	}
+	locale, _ := user.OriginSetting[userLocaleSetting].(string)
+	userLocales.Store(userID, locale)
+	return locale
+}
+
+//@function: ForgetUserLocale
+//@description: 用户配置变化后清除缓存的语言
+//@param: userID uint
+
+func (i18nService *I18nService) ForgetUserLocale(userID uint) {
+	userLocales.Delete(userID)
+}
+
+//@function: TranslateMenus
+//@description: 翻译菜单标题 翻译键为菜单name
+//@param: locale string, menus []system.SysMenu
+
+func (i18nService *I18nService) TranslateMenus(locale string, menus []system.SysMenu) {
+	if locale == i18n.SourceLocale {
+		return
+	}
+	for i := range menus {
+		menus[i].Title = i18n.Default.Or(locale, i18n.NamespaceMenu, menus[i].Name, menus[i].Title)
+		i18nService.TranslateMenus(locale, menus[i].Children)
+	}
+}
+
+//@function: TranslateBaseMenus
+//@description: 翻译基础菜单标题 翻译键为菜单name
+//@param: locale string, menus []system.SysBaseMenu
+
+func (i18nService *I18nService) TranslateBaseMenus(locale string, menus []system.SysBaseMenu) {
+	if locale == i18n.SourceLocale {
+		return
+	}
+	for i := range menus {
+		menus[i].Title = i18n.Default.Or(locale, i18n.NamespaceMenu, menus[i].Name, menus[i].Title)
+		i18nService.TranslateBaseMenus(locale, menus[i].Children)
+	}
+}
+
+//@function: TranslateDictionary
+//@description: 翻译字典详情的展示值 翻译键为 字典type.字典值
+//@param: locale string, dictionary *system.SysDictionary
+
+func (i18nService *I18nService) TranslateDictionary(locale string, dictionary *system.SysDictionary) {
+	i18nService.TranslateDictionaryDetails(locale, dictionary.Type, dictionary.SysDictionaryDetails)
+}
+
+//@function: TranslateDictionaryDetails
+//@description: 翻译字典详情的展示值 包含树形子级
+//@param: locale string, dictType string, details []system.SysDictionaryDetail
+
+func (i18nService *I18nService) TranslateDictionaryDetails(locale string, dictType string, details []system.SysDictionaryDetail) {
+	if locale == i18n.SourceLocale {
+		return
+	}
+	for i, d := range details {
+		details[i].Label = i18n.Default.Or(locale, i18n.NamespaceDict, dictType+"."+d.Value, d.Label)
+		i18nService.TranslateDictionaryDetails(locale, dictType, details[i].Children)
+	}
+}
+
+//@function: TranslateParam
+//@description: 翻译参数说明 翻译键为参数key
+//@param: locale string, param *system.SysParams
+
+func (i18nService *I18nService) TranslateParam(locale string, param *system.SysParams) {
+	if locale == i18n.SourceLocale {
+		return
+	}
+	param.Desc = i18n.Default.Or(locale, i18n.NamespaceParam, param.Key, param.Desc)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
 	"gorm.io/gorm"
 	"sort"
@@ -121,6 +122,9 @@ func (initDBService *InitDBService) InitDB(conf request.InitDB) (err error) {
 	}
 
 	db := ctx.Value("db").(*gorm.DB)
+	if err = AuditChainServiceApp.RegisterPlugin(db); err != nil {
+		return err
+	}
 	global.GVA_DB = db
 
 	if err = initHandler.InitTables(ctx, initializers); err != nil {
@@ -129,6 +133,13 @@ func (initDBService *InitDBService) InitDB(conf request.InitDB) (err error) {
 	if err = initHandler.InitData(ctx, initializers); err != nil {
 		return err
 	}
+	// 种子数据写入后再开启变更历史 避免为初始化数据生成版本
+	if err = db.AutoMigrate(&system.SysEntityVersion{}); err != nil {
+		return err
+	}
+	if err = EntityVersionServiceApp.RegisterPlugin(db); err != nil {
+		return err
+	}
 
 	if err = initHandler.WriteConfig(ctx); err != nil {
 		return err
@@ -7,6 +7,7 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	"gorm.io/gorm"
 	"strconv"
+	"strings"
 )
 
 //@author: [piexlmax](https://github.com/piexlmax)
@@ -42,7 +43,11 @@ func (menuService *MenuService) getMenuTreeMap(authorityId uint) (treeMap map[ui
 		return
 	}
 
+	hidden := hiddenPluginMenus(baseMenu, PluginServiceApp.DisabledMenuPrefixes())
 	for i := range baseMenu {
+		if hidden[baseMenu[i].ID] {
+			continue
+		}
 		allMenus = append(allMenus, system.SysMenu{
 			SysBaseMenu: baseMenu[i],
 			AuthorityId: authorityId,
@@ -69,6 +74,48 @@ func (menuService *MenuService) getMenuTreeMap(authorityId uint) (treeMap map[ui
 	return treeMap, err
 }
 
+// hiddenPluginMenus 需要隐藏的菜单 包括已停用插件的菜单(其子菜单随之隐藏)和子菜单全部被隐藏的父菜单
+func hiddenPluginMenus(menus []system.SysBaseMenu, prefixes []string) map[uint]bool {
+	hidden := make(map[uint]bool)
+	if len(prefixes) == 0 {
+		return hidden
+	}
+	children := make(map[uint]int)
+	for _, menu := range menus {
+		children[menu.ParentId]++
+		if disabledPluginMenu(menu.Component, prefixes) {
+			hidden[menu.ID] = true
+		}
+	}
+	// 逐层向上传递 直到没有新的父菜单被隐藏
+	for changed := true; changed; {
+		changed = false
+		hiddenChildren := make(map[uint]int)
+		for _, menu := range menus {
+			if hidden[menu.ID] {
+				hiddenChildren[menu.ParentId]++
+			}
+		}
+		for _, menu := range menus {
+			if !hidden[menu.ID] && children[menu.ID] > 0 && hiddenChildren[menu.ID] == children[menu.ID] {
+				hidden[menu.ID] = true
+				changed = true
+			}
+		}
+	}
+	return hidden
+}
+
+// disabledPluginMenu 菜单属于已停用的插件
+func disabledPluginMenu(component string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(component, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 //@author: [piexlmax](https://github.com/piexlmax)
 //@function: GetMenuTree
 //@description: 获取动态菜单树
@@ -0,0 +1,43 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+func TestHiddenPluginMenus(t *testing.T) {
+	menu := func(id, parentID uint, component string) system.SysBaseMenu {
+		return system.SysBaseMenu{GVA_MODEL: global.GVA_MODEL{ID: id}, ParentId: parentID, Component: component}
+	}
+	menus := []system.SysBaseMenu{
+		menu(1, 0, "view/routerHolder.vue"), // 只包含停用插件菜单的目录
+		menu(2, 1, "plugin/demo/view/a.vue"),
+		menu(3, 1, "plugin/demo/view/b.vue"),
+		menu(4, 0, "view/routerHolder.vue"), // 还有其他菜单的目录
+		menu(5, 4, "plugin/demo/view/c.vue"),
+		menu(6, 4, "view/other.vue"),
+		menu(7, 0, "view/routerHolder.vue"), // 多层目录 子目录被隐藏后一并隐藏
+		menu(8, 7, "view/routerHolder.vue"),
+		menu(9, 8, "plugin/demo/view/d.vue"),
+		menu(10, 0, "view/empty.vue"), // 本身没有子菜单
+	}
+	tests := []struct {
+		name     string
+		prefixes []string
+		want     map[uint]bool
+	}{
+		{name: "没有停用的插件", want: map[uint]bool{}},
+		{name: "停用插件", prefixes: []string{system.PluginMenuPrefix("demo")}, want: map[uint]bool{1: true, 2: true, 3: true, 5: true, 7: true, 8: true, 9: true}},
+		{name: "停用其他插件", prefixes: []string{system.PluginMenuPrefix("other")}, want: map[uint]bool{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hiddenPluginMenus(menus, tt.prefixes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hiddenPluginMenus() = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,15 +7,22 @@ import (
 	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
 )
 
+type OperationRecordService struct{}
+
+var OperationRecordServiceApp = new(OperationRecordService)
+
 //@author: [granty1](https://github.com/granty1)
 //@function: CreateSysOperationRecord
-//@description: 创建记录
+//@description: 创建记录 异步写入队列已启动时进入批量队列 否则直接落库
 //@param: sysOperationRecord model.SysOperationRecord
 //@return: err error
 
-type OperationRecordService struct{}
-
-var OperationRecordServiceApp = new(OperationRecordService)
+func (operationRecordService *OperationRecordService) CreateSysOperationRecord(sysOperationRecord system.SysOperationRecord) (err error) {
+	if operationRecordWriter == nil {
+		return global.GVA_DB.Create(&sysOperationRecord).Error
+	}
+	return operationRecordWriter.Write(sysOperationRecord)
+}
 
 //@author: [granty1](https://github.com/granty1)
 //@author: [piexlmax](https://github.com/piexlmax)
@@ -0,0 +1,453 @@
+package system
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/hashchain"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/upload"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	operationRecordArchiveLocal = "local"
+	operationRecordArchiveOss   = "oss"
+)
+
+type OperationRecordArchiveService struct{}
+
+var OperationRecordArchiveServiceApp = new(OperationRecordArchiveService)
+
+var operationRecordRetentionMu sync.Mutex
+
+// operationRecordArchiveClient 下载对象存储中的归档
+var operationRecordArchiveClient = &http.Client{Timeout: 5 * time.Minute}
+
+// archivedOperationRecord 归档行 不包含关联的用户
+type archivedOperationRecord struct {
+	system.SysOperationRecord
+	User *struct{} `json:"user,omitempty"`
+}
+
+type retentionRule struct {
+	path   string
+	method string
+	keep   time.Duration
+}
+
+type retentionPolicy struct {
+	rules []retentionRule
+	keep  time.Duration
+}
+
+func newRetentionPolicy(conf config.OperationRecordRetention) (*retentionPolicy, error) {
+	keep := conf.Keep
+	if keep == "" {
+		keep = "90d"
+	}
+	d, err := utils.ParseDuration(keep)
+	if err != nil || d <= 0 {
+		return nil, fmt.Errorf("无效的保留时长: %s", keep)
+	}
+	policy := &retentionPolicy{keep: d}
+	for _, rule := range conf.Rules {
+		if _, err = path.Match(rule.Path, ""); err != nil {
+			return nil, fmt.Errorf("无效的路径规则 %s: %w", rule.Path, err)
+		}
+		d, err = utils.ParseDuration(rule.Keep)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("路径规则 %s 的保留时长无效: %s", rule.Path, rule.Keep)
+		}
+		policy.rules = append(policy.rules, retentionRule{path: rule.Path, method: strings.ToUpper(rule.Method), keep: d})
+	}
+	return policy, nil
+}
+
+// keepFor 按顺序匹配第一条规则 未匹配时使用默认保留时长
+func (p *retentionPolicy) keepFor(method, recordPath string) time.Duration {
+	for _, rule := range p.rules {
+		if rule.method != "" && rule.method != method {
+			continue
+		}
+		if ok, _ := path.Match(rule.path, recordPath); ok {
+			return rule.keep
+		}
+	}
+	return p.keep
+}
+
+func (p *retentionPolicy) minKeep() time.Duration {
+	keep := p.keep
+	for _, rule := range p.rules {
+		if rule.keep < keep {
+			keep = rule.keep
+		}
+	}
+	return keep
+}
+
+//@function: ApplyRetention
+//@description: 按保留规则归档并删除过期的操作记录 开启审计哈希链时只删除连续的过期前缀 保证剩余记录的链可校验
+//@description: 此时按路径的保留规则无法生效 因此不允许配置 归档索引与删除在同一事务中 事务失败时删除已写入的归档文件
+//@return: archived int, deleted int, err error
+
+func (operationRecordArchiveService *OperationRecordArchiveService) ApplyRetention() (archived int, deleted int, err error) {
+	if !operationRecordRetentionMu.TryLock() {
+		return 0, 0, errors.New("保留策略正在执行")
+	}
+	defer operationRecordRetentionMu.Unlock()
+	conf := global.GVA_CONFIG.OperationRecord.Retention
+	policy, err := newRetentionPolicy(conf)
+	if err != nil {
+		return 0, 0, err
+	}
+	prefixOnly := global.GVA_CONFIG.AuditChain.Enable
+	if prefixOnly && len(policy.rules) > 0 {
+		// 只能删除连续前缀时 较短的规则会被前面保留更久的记录挡住 实际都按最长的保留时长生效
+		return 0, 0, errors.New("开启审计哈希链时只能按 keep 统一清理最早的记录 不支持 rules 按路径的保留规则")
+	}
+	held, err := operationRecordArchiveService.heldRanges(conf)
+	if err != nil {
+		return 0, 0, err
+	}
+	batchSize := conf.BatchSize
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+	now := time.Now()
+	var afterID uint
+	for {
+		var rows []system.SysOperationRecord
+		db := global.GVA_DB.Unscoped().Where("id > ?", afterID)
+		if !prefixOnly {
+			db = db.Where("created_at < ?", now.Add(-policy.minKeep()))
+		}
+		if err = db.Order("id").Limit(batchSize).Find(&rows).Error; err != nil {
+			return archived, deleted, err
+		}
+		if len(rows) == 0 {
+			return archived, deleted, nil
+		}
+		afterID = rows[len(rows)-1].ID
+		var expired []system.SysOperationRecord
+		stop := false
+		for _, row := range rows {
+			if held(row.ID) || row.CreatedAt.After(now.Add(-policy.keepFor(row.Method, row.Path))) {
+				if prefixOnly {
+					stop = true
+					break
+				}
+				continue
+			}
+			expired = append(expired, row)
+		}
+		if len(expired) > 0 {
+			n, err := operationRecordArchiveService.archiveAndDelete(conf, expired)
+			if err != nil {
+				return archived, deleted, err
+			}
+			if conf.Archive {
+				archived += len(expired)
+			}
+			deleted += n
+		}
+		if stop || len(rows) < batchSize {
+			return archived, deleted, nil
+		}
+	}
+}
+
+// heldRanges 仍在恢复保留期内的归档ID区间 其中的记录暂不清理
+func (operationRecordArchiveService *OperationRecordArchiveService) heldRanges(conf config.OperationRecordRetention) (func(id uint) bool, error) {
+	hold := conf.RestoreHold
+	if hold == "" {
+		hold = "7d"
+	}
+	d, err := utils.ParseDuration(hold)
+	if err != nil {
+		return nil, fmt.Errorf("无效的恢复保留时长: %s", hold)
+	}
+	var archives []system.SysOperationRecordArchive
+	err = global.GVA_DB.Select("first_id", "last_id").Where("restored_at > ?", time.Now().Add(-d)).Find(&archives).Error
+	return func(id uint) bool {
+		for _, a := range archives {
+			if id >= a.FirstID && id <= a.LastID {
+				return true
+			}
+		}
+		return false
+	}, err
+}
+
+// restoredRanges 已恢复到数据库的归档ID区间 开启哈希链时归档只包含连续的最早记录 区间内不会有仍在链上的记录
+func (operationRecordArchiveService *OperationRecordArchiveService) restoredRanges() (func(id uint) bool, error) {
+	var archives []system.SysOperationRecordArchive
+	err := global.GVA_DB.Select("first_id", "last_id").Where("restored_at IS NOT NULL").Find(&archives).Error
+	return func(id uint) bool {
+		for _, a := range archives {
+			if id >= a.FirstID && id <= a.LastID {
+				return true
+			}
+		}
+		return false
+	}, err
+}
+
+// archiveAndDelete 写入归档文件后在同一事务中登记归档并删除记录 事务失败时删除归档文件
+func (operationRecordArchiveService *OperationRecordArchiveService) archiveAndDelete(conf config.OperationRecordRetention, rows []system.SysOperationRecord) (deleted int, err error) {
+	var archive *system.SysOperationRecordArchive
+	if conf.Archive {
+		if archive, err = operationRecordArchiveService.writeArchive(conf, rows); err != nil {
+			return 0, err
+		}
+	}
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	err = global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if archive != nil {
+			if err := tx.Create(archive).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Unscoped().Delete(&system.SysOperationRecord{}, "id in ?", ids)
+		deleted = int(result.RowsAffected)
+		return result.Error
+	})
+	if err != nil {
+		if archive != nil {
+			operationRecordArchiveService.removeArchive(*archive)
+		}
+		return 0, err
+	}
+	if archive != nil {
+		global.GVA_LOG.Info("操作记录已归档", zap.String("file", archive.FileName), zap.Int("rows", archive.RowCount))
+	}
+	return deleted, nil
+}
+
+// removeArchive 删除未登记成功的归档文件
+func (operationRecordArchiveService *OperationRecordArchiveService) removeArchive(archive system.SysOperationRecordArchive) {
+	var err error
+	if archive.Storage == operationRecordArchiveOss {
+		err = upload.NewOss().DeleteFile(archive.Key)
+	} else {
+		err = os.Remove(archive.Key)
+	}
+	if err != nil {
+		global.GVA_LOG.Error("删除未登记的归档文件失败", zap.String("file", archive.FileName), zap.Error(err))
+	}
+}
+
+// writeArchive 将记录写入归档文件 返回待登记的归档索引
+func (operationRecordArchiveService *OperationRecordArchiveService) writeArchive(conf config.OperationRecordRetention, rows []system.SysOperationRecord) (*system.SysOperationRecordArchive, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	archive := &system.SysOperationRecordArchive{
+		FirstID:   rows[0].ID,
+		LastID:    rows[len(rows)-1].ID,
+		RowCount:  len(rows),
+		StartTime: rows[0].CreatedAt,
+		EndTime:   rows[0].CreatedAt,
+	}
+	for _, row := range rows {
+		if err := enc.Encode(archivedOperationRecord{SysOperationRecord: row}); err != nil {
+			return nil, err
+		}
+		if row.CreatedAt.Before(archive.StartTime) {
+			archive.StartTime = row.CreatedAt
+		}
+		if row.CreatedAt.After(archive.EndTime) {
+			archive.EndTime = row.CreatedAt
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	sum := sha256.Sum256(data)
+	archive.Sha256 = hex.EncodeToString(sum[:])
+	archive.Size = int64(len(data))
+	archive.FileName = fmt.Sprintf("operation_records_%d_%d_%s.jsonl.gz", archive.FirstID, archive.LastID, time.Now().Format("20060102150405"))
+
+	switch conf.Storage {
+	case operationRecordArchiveOss:
+		header, err := upload.NewFileHeader(archive.FileName, data)
+		if err != nil {
+			return nil, err
+		}
+		archive.Url, archive.Key, err = upload.NewOss().UploadFile(header)
+		if err != nil {
+			return nil, err
+		}
+		archive.Storage = operationRecordArchiveOss
+	default:
+		dir := conf.ArchivePath
+		if dir == "" {
+			dir = "./archive/operation_record"
+		}
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return nil, err
+		}
+		archive.Key = filepath.Join(dir, archive.FileName)
+		if err := os.WriteFile(archive.Key, data, 0o644); err != nil {
+			return nil, err
+		}
+		archive.Storage = operationRecordArchiveLocal
+	}
+	return archive, nil
+}
+
+// readArchive 读取归档文件并校验 sha256 后逐行回调
+func (operationRecordArchiveService *OperationRecordArchiveService) readArchive(archive system.SysOperationRecordArchive, fn func(record system.SysOperationRecord) error) error {
+	var reader io.ReadCloser
+	var err error
+	switch {
+	case archive.Storage == operationRecordArchiveOss && (strings.HasPrefix(archive.Url, "http://") || strings.HasPrefix(archive.Url, "https://")):
+		resp, err := operationRecordArchiveClient.Get(archive.Url)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("下载归档失败: %s", resp.Status)
+		}
+		reader = resp.Body
+	case archive.Storage == operationRecordArchiveOss:
+		// 本地类型的对象存储 文件位于 local.store-path
+		reader, err = os.Open(filepath.Join(global.GVA_CONFIG.Local.StorePath, archive.Key))
+	default:
+		reader, err = os.Open(archive.Key)
+	}
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	if sum := sha256.Sum256(data); archive.Sha256 != "" && hex.EncodeToString(sum[:]) != archive.Sha256 {
+		return errors.New("归档文件校验失败 内容与索引中的sha256不一致")
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	dec := json.NewDecoder(gz)
+	for dec.More() {
+		var row archivedOperationRecord
+		if err = dec.Decode(&row); err != nil {
+			return err
+		}
+		if err = fn(row.SysOperationRecord); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//@function: GetArchiveList
+//@description: 分页获取操作记录归档索引
+//@param: info request.PageInfo
+//@return: list []system.SysOperationRecordArchive, total int64, err error
+
+func (operationRecordArchiveService *OperationRecordArchiveService) GetArchiveList(info request.PageInfo) (list []system.SysOperationRecordArchive, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.SysOperationRecordArchive{})
+	if err = db.Count(&total).Error; err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
+
+//@function: QueryArchive
+//@description: 在归档文件中按条件查询操作记录 不写入数据库
+//@param: info systemReq.OperationRecordArchiveQuery
+//@return: list []system.SysOperationRecord, total int64, err error
+
+func (operationRecordArchiveService *OperationRecordArchiveService) QueryArchive(info systemReq.OperationRecordArchiveQuery) (list []system.SysOperationRecord, total int64, err error) {
+	var archive system.SysOperationRecordArchive
+	if err = global.GVA_DB.First(&archive, "id = ?", info.ID).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := info.PageSize * (info.Page - 1)
+	list = make([]system.SysOperationRecord, 0)
+	err = operationRecordArchiveService.readArchive(archive, func(record system.SysOperationRecord) error {
+		if info.Method != "" && record.Method != info.Method ||
+			info.Path != "" && !strings.Contains(record.Path, info.Path) ||
+			info.Status != 0 && record.Status != info.Status ||
+			info.UserID != 0 && record.UserID != info.UserID {
+			return nil
+		}
+		total++
+		if int(total) > offset && (info.PageSize == 0 || len(list) < info.PageSize) {
+			list = append(list, record)
+		}
+		return nil
+	})
+	return list, total, err
+}
+
+//@function: RestoreArchive
+//@description: 将归档中的记录按原ID和原哈希写回操作记录表 已存在的记录跳过 恢复保留期内不会被再次清理
+//@description: 恢复的记录不再参与审计哈希链校验
+//@param: id uint
+//@return: restored int, err error
+
+func (operationRecordArchiveService *OperationRecordArchiveService) RestoreArchive(id uint) (restored int, err error) {
+	var archive system.SysOperationRecordArchive
+	if err = global.GVA_DB.First(&archive, "id = ?", id).Error; err != nil {
+		return 0, err
+	}
+	var rows []system.SysOperationRecord
+	if err = operationRecordArchiveService.readArchive(archive, func(record system.SysOperationRecord) error {
+		rows = append(rows, record)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	err = global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(rows); start += 200 {
+			end := min(start+200, len(rows))
+			chunk := rows[start:end]
+			result := tx.Set(hashchain.SkipKey, true).Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&chunk)
+			if result.Error != nil {
+				return result.Error
+			}
+			restored += int(result.RowsAffected)
+		}
+		now := time.Now()
+		return tx.Model(&archive).Updates(map[string]any{"restored_at": &now, "restored_rows": restored}).Error
+	})
+	return restored, err
+}
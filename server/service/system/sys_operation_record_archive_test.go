@@ -0,0 +1,67 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/hashchain"
+	"gorm.io/gorm/clause"
+)
+
+func TestOperationRecordArchive_RestoreThenVerify(t *testing.T) {
+	db := setupPluginTestDB(t, &system.SysOperationRecord{}, &system.SysOperationRecordArchive{}, &system.SysAuditCheckpoint{})
+	if err := db.Use(&hashchain.Plugin{}); err != nil {
+		t.Fatalf("注册哈希链插件失败: %v", err)
+	}
+	oldChain, oldRecord := global.GVA_CONFIG.AuditChain, global.GVA_CONFIG.OperationRecord
+	global.GVA_CONFIG.AuditChain.Enable, global.GVA_CONFIG.AuditChain.SigningKey = true, ""
+	global.GVA_CONFIG.OperationRecord.Retention = config.OperationRecordRetention{Keep: "1d", Archive: true, ArchivePath: t.TempDir(), BatchSize: 2}
+	t.Cleanup(func() { global.GVA_CONFIG.AuditChain, global.GVA_CONFIG.OperationRecord = oldChain, oldRecord })
+
+	// 前4条过期 分两个归档 最后一条保留在链上
+	for i := 0; i < 5; i++ {
+		record := system.SysOperationRecord{Method: "POST", Path: "/sysParams/updateSysParams"}
+		if i < 4 {
+			record.CreatedAt = time.Now().Add(-10 * 24 * time.Hour)
+		}
+		if err := db.Omit(clause.Associations).Create(&record).Error; err != nil {
+			t.Fatalf("写入操作记录失败: %v", err)
+		}
+	}
+	archived, deleted, err := OperationRecordArchiveServiceApp.ApplyRetention()
+	if err != nil || archived != 4 || deleted != 4 {
+		t.Fatalf("ApplyRetention() = %d, %d, %v, 期望归档并删除4条", archived, deleted, err)
+	}
+
+	var archives []system.SysOperationRecordArchive
+	if err = db.Order("first_id").Find(&archives).Error; err != nil || len(archives) != 2 {
+		t.Fatalf("归档数 = %d, %v, 期望 2", len(archives), err)
+	}
+	// 只恢复较早的归档 恢复的记录与链上的记录之间缺少第二个归档
+	restored, err := OperationRecordArchiveServiceApp.RestoreArchive(archives[0].ID)
+	if err != nil || restored != 2 {
+		t.Fatalf("RestoreArchive() = %d, %v, 期望恢复2条", restored, err)
+	}
+
+	result, err := AuditChainServiceApp.Verify(system.SysOperationRecord{}.TableName())
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("恢复归档后哈希链校验失败: %+v", result.Broken)
+	}
+	if first, last := result.FirstID, result.LastID; result.Checked != 1 || first != 5 || last != 5 {
+		t.Errorf("校验范围 = %d-%d 共%d条, 期望只校验链上的记录5", first, last, result.Checked)
+	}
+
+	// 篡改链上的记录仍能发现
+	if err = db.Model(&system.SysOperationRecord{}).Where("id = ?", 5).UpdateColumn("path", "/tampered").Error; err != nil {
+		t.Fatalf("修改记录失败: %v", err)
+	}
+	if result, err = AuditChainServiceApp.Verify(system.SysOperationRecord{}.TableName()); err != nil || result.Valid {
+		t.Errorf("Verify() = %+v, %v, 期望发现篡改", result, err)
+	}
+}
@@ -0,0 +1,177 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/batch"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultOperationRecordSpillPath    = "./log/operation_record_spill.jsonl"
+	defaultOperationRecordCloseTimeout = 10 * time.Second
+)
+
+var (
+	operationRecordWriter     *batch.Writer[system.SysOperationRecord]
+	operationRecordWriterOnce sync.Once
+	operationRecordSpillMu    sync.Mutex
+
+	operationRecordQueueDepth = promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "gva_operation_record_queue_depth",
+			Help: "操作记录异步写入队列当前深度",
+		},
+		func() float64 {
+			if operationRecordWriter == nil {
+				return 0
+			}
+			return float64(operationRecordWriter.Len())
+		},
+	)
+	operationRecordDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gva_operation_record_dropped_total",
+			Help: "被丢弃的操作记录数量",
+		},
+		[]string{"reason"},
+	)
+	operationRecordSpilled = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gva_operation_record_spilled_total",
+			Help: "溢出写入本地文件的操作记录数量",
+		},
+	)
+)
+
+//@function: StartOperationRecordWriter
+//@description: 开启异步写入时回放上次停机遗留的溢出记录并启动批量写入队列 在服务启动时调用 未启动时操作记录直接落库
+//@param:
+//@return:
+
+func (operationRecordService *OperationRecordService) StartOperationRecordWriter() {
+	if !global.GVA_CONFIG.OperationRecord.Async {
+		return
+	}
+	operationRecordWriterOnce.Do(startOperationRecordWriter)
+}
+
+//@function: CloseOperationRecordWriter
+//@description: 停止接收新的操作记录并将队列中剩余记录刷入数据库 最长等待 operation-record.close-timeout
+//@param:
+//@return: err error
+
+func (operationRecordService *OperationRecordService) CloseOperationRecordWriter() (err error) {
+	if operationRecordWriter == nil {
+		return nil
+	}
+	timeout, err := utils.ParseDuration(global.GVA_CONFIG.OperationRecord.CloseTimeout)
+	if err != nil || timeout <= 0 {
+		timeout = defaultOperationRecordCloseTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return operationRecordWriter.Close(ctx)
+}
+
+func startOperationRecordWriter() {
+	cfg := global.GVA_CONFIG.OperationRecord
+	interval, err := utils.ParseDuration(cfg.FlushInterval)
+	if err != nil {
+		global.GVA_LOG.Warn("operation-record.flush-interval 配置无效, 使用默认值", zap.String("flush-interval", cfg.FlushInterval))
+		interval = 0
+	}
+	replayOperationRecordSpill()
+	operationRecordWriter = batch.NewWriter(batch.Options[system.SysOperationRecord]{
+		QueueSize:     cfg.QueueSize,
+		BatchSize:     cfg.BatchSize,
+		FlushInterval: interval,
+		Policy:        cfg.OverflowPolicy,
+		Flush:         flushOperationRecords,
+		Spill:         spillOperationRecords,
+		OnDrop: func(reason string, n int) {
+			operationRecordDropped.WithLabelValues(reason).Add(float64(n))
+		},
+	})
+}
+
+func flushOperationRecords(records []system.SysOperationRecord) error {
+	err := global.GVA_DB.Omit(clause.Associations).CreateInBatches(records, len(records)).Error
+	if err != nil {
+		global.GVA_LOG.Error("batch create operation record error:", zap.Error(err), zap.Int("count", len(records)))
+	}
+	return err
+}
+
+func operationRecordSpillPath() string {
+	if p := global.GVA_CONFIG.OperationRecord.SpillPath; p != "" {
+		return p
+	}
+	return defaultOperationRecordSpillPath
+}
+
+// spillOperationRecords 以 JSON Lines 追加写入本地溢出文件 下次启动时回放
+func spillOperationRecords(records []system.SysOperationRecord) error {
+	operationRecordSpillMu.Lock()
+	defer operationRecordSpillMu.Unlock()
+	path := operationRecordSpillPath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		global.GVA_LOG.Error("open operation record spill file error:", zap.Error(err))
+		return err
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+	for i := range records {
+		if err = encoder.Encode(&records[i]); err != nil {
+			return err
+		}
+	}
+	operationRecordSpilled.Add(float64(len(records)))
+	return nil
+}
+
+// replayOperationRecordSpill 将上次溢出到本地文件的记录回写数据库
+func replayOperationRecordSpill() {
+	operationRecordSpillMu.Lock()
+	defer operationRecordSpillMu.Unlock()
+	path := operationRecordSpillPath()
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	var records []system.SysOperationRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record system.SysOperationRecord
+		if json.Unmarshal(scanner.Bytes(), &record) == nil {
+			record.ID = 0
+			records = append(records, record)
+		}
+	}
+	file.Close()
+	if len(records) > 0 {
+		if err = global.GVA_DB.Omit(clause.Associations).CreateInBatches(records, 200).Error; err != nil {
+			global.GVA_LOG.Error("replay operation record spill error:", zap.Error(err))
+			return
+		}
+		global.GVA_LOG.Info("replay operation record spill", zap.Int("count", len(records)))
+	}
+	_ = os.Remove(path)
+}
@@ -1,38 +1,96 @@
 package system
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/jsonschema"
+	"gorm.io/gorm"
 )
 
 type SysParamsService struct{}
 
+var SysParamsServiceApp = new(SysParamsService)
+
+// sysParamsCache 参数缓存 键为参数key
+var sysParamsCache = newKeyedCache("gva:sys_params:invalidate", func() (map[string]system.SysParams, error) {
+	var rows []system.SysParams
+	if err := global.GVA_DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	items := make(map[string]system.SysParams, len(rows))
+	for _, row := range rows {
+		items[row.Key] = row
+	}
+	return items, nil
+})
+
 // CreateSysParams 创建参数记录
 // Author [Mr.奇淼](https://github.com/pixelmaxQm)
-func (sysParamsService *SysParamsService) CreateSysParams(sysParams *system.SysParams) (err error) {
-	err = global.GVA_DB.Create(sysParams).Error
+func (sysParamsService *SysParamsService) CreateSysParams(ctx context.Context, sysParams *system.SysParams) (err error) {
+	if err = validateSysParams(sysParams); err != nil {
+		return err
+	}
+	err = global.GVA_DB.WithContext(ctx).Create(sysParams).Error
+	if err == nil {
+		sysParamsCache.publish()
+	}
 	return err
 }
 
 // DeleteSysParams 删除参数记录
 // Author [Mr.奇淼](https://github.com/pixelmaxQm)
-func (sysParamsService *SysParamsService) DeleteSysParams(ID string) (err error) {
-	err = global.GVA_DB.Delete(&system.SysParams{}, "id = ?", ID).Error
-	return err
+func (sysParamsService *SysParamsService) DeleteSysParams(ctx context.Context, ID string) (err error) {
+	return sysParamsService.DeleteSysParamsByIds(ctx, []string{ID})
 }
 
 // DeleteSysParamsByIds 批量删除参数记录
 // Author [Mr.奇淼](https://github.com/pixelmaxQm)
-func (sysParamsService *SysParamsService) DeleteSysParamsByIds(IDs []string) (err error) {
-	err = global.GVA_DB.Delete(&[]system.SysParams{}, "id in ?", IDs).Error
+func (sysParamsService *SysParamsService) DeleteSysParamsByIds(ctx context.Context, IDs []string) (err error) {
+	err = global.GVA_DB.WithContext(ctx).Delete(&[]system.SysParams{}, "id in ?", IDs).Error
+	if err == nil {
+		sysParamsCache.publish()
+	}
 	return err
 }
 
-// UpdateSysParams 更新参数记录
+// UpdateSysParams 更新参数记录 未传入的类型和模式沿用原值 类型不是 json 时清除模式
 // Author [Mr.奇淼](https://github.com/pixelmaxQm)
-func (sysParamsService *SysParamsService) UpdateSysParams(sysParams system.SysParams) (err error) {
-	err = global.GVA_DB.Model(&system.SysParams{}).Where("id = ?", sysParams.ID).Updates(&sysParams).Error
+func (sysParamsService *SysParamsService) UpdateSysParams(ctx context.Context, info systemReq.SysParamsUpdate) (err error) {
+	sysParams := info.SysParams
+	err = global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old system.SysParams
+		if err := tx.Where("id = ?", sysParams.ID).First(&old).Error; err != nil {
+			return err
+		}
+		sysParams.Type, sysParams.Schema = old.Type, old.Schema
+		if info.Type != nil {
+			sysParams.Type = *info.Type
+		}
+		if info.Schema != nil {
+			sysParams.Schema = *info.Schema
+		} else if !strings.EqualFold(strings.TrimSpace(sysParams.Type), system.SysParamsTypeJSON) {
+			sysParams.Schema = ""
+		}
+		if err := validateSysParams(&sysParams); err != nil {
+			return err
+		}
+		// 显式指定列 空字符串也会写入 否则无法清空说明和模式
+		return tx.Model(&system.SysParams{}).Where("id = ?", sysParams.ID).
+			Select("name", "key", "value", "desc", "type", "schema").Updates(&sysParams).Error
+	})
+	if err == nil {
+		sysParamsCache.publish()
+	}
 	return err
 }
 
@@ -74,9 +132,124 @@ func (sysParamsService *SysParamsService) GetSysParamsInfoList(info systemReq.Sy
 	return sysParamss, total, err
 }
 
-// GetSysParam 根据key获取参数value
+// GetSysParam 根据key获取参数value 读取进程内缓存
 // Author [Mr.奇淼](https://github.com/pixelmaxQm)
 func (sysParamsService *SysParamsService) GetSysParam(key string) (param system.SysParams, err error) {
-	err = global.GVA_DB.Where(system.SysParams{Key: key}).First(&param).Error
+	param, ok, err := sysParamsCache.get(key)
+	if err == nil && !ok {
+		err = gorm.ErrRecordNotFound
+	}
 	return
 }
+
+// GetSysParamsHistory 分页获取参数变更历史 取自实体变更历史 包含该键已删除的旧参数
+func (sysParamsService *SysParamsService) GetSysParamsHistory(info systemReq.SysParamsHistorySearch) (list []system.SysEntityVersion, total int64, err error) {
+	var ids []uint
+	err = global.GVA_DB.Unscoped().Model(&system.SysParams{}).Where(map[string]any{"key": info.Key}).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return
+	}
+	entityIDs := make([]string, len(ids))
+	for i, id := range ids {
+		entityIDs[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return EntityVersionServiceApp.GetTableVersionList(system.SysParams{}.TableName(), entityIDs, info.PageInfo)
+}
+
+// GetString 获取字符串参数 参数不存在时返回 def
+func (sysParamsService *SysParamsService) GetString(key string, def string) string {
+	param, err := sysParamsService.GetSysParam(key)
+	if err != nil {
+		return def
+	}
+	return param.Value
+}
+
+// GetInt 获取 int 参数 参数不存在或无法解析时返回 def
+func (sysParamsService *SysParamsService) GetInt(key string, def int) int {
+	param, err := sysParamsService.GetSysParam(key)
+	if err != nil {
+		return def
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(param.Value))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// GetBool 获取 bool 参数 参数不存在或无法解析时返回 def
+func (sysParamsService *SysParamsService) GetBool(key string, def bool) bool {
+	param, err := sysParamsService.GetSysParam(key)
+	if err != nil {
+		return def
+	}
+	v, err := strconv.ParseBool(strings.TrimSpace(param.Value))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// GetDuration 获取时长参数 支持 1h30m、7d 等写法 参数不存在或无法解析时返回 def
+func (sysParamsService *SysParamsService) GetDuration(key string, def time.Duration) time.Duration {
+	param, err := sysParamsService.GetSysParam(key)
+	if err != nil {
+		return def
+	}
+	v, err := utils.ParseDuration(param.Value)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// GetJSON 将 json 参数解析到 out
+func (sysParamsService *SysParamsService) GetJSON(key string, out any) error {
+	param, err := sysParamsService.GetSysParam(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(param.Value), out)
+}
+
+// validateSysParams 按参数类型校验参数值 json 类型按 JSON Schema 校验
+func validateSysParams(p *system.SysParams) error {
+	p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+	if p.Type == "" {
+		p.Type = system.SysParamsTypeString
+	}
+	if p.Schema != "" && p.Type != system.SysParamsTypeJSON {
+		return errors.New("只有 json 类型的参数可以设置 JSON Schema")
+	}
+	value := strings.TrimSpace(p.Value)
+	var err error
+	switch p.Type {
+	case system.SysParamsTypeString:
+	case system.SysParamsTypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case system.SysParamsTypeBool:
+		_, err = strconv.ParseBool(value)
+	case system.SysParamsTypeDuration:
+		_, err = utils.ParseDuration(value)
+	case system.SysParamsTypeJSON:
+		if !json.Valid([]byte(value)) {
+			return errors.New("参数值不是合法的JSON")
+		}
+		if p.Schema != "" {
+			schema, err := jsonschema.Compile([]byte(p.Schema))
+			if err != nil {
+				return err
+			}
+			if err = schema.Validate([]byte(value)); err != nil {
+				return fmt.Errorf("参数值不符合 JSON Schema: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("未知的参数类型 %s", p.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("参数值不是合法的 %s: %w", p.Type, err)
+	}
+	return nil
+}
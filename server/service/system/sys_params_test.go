@@ -0,0 +1,58 @@
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+)
+
+func TestSysParamsService_UpdateSysParams(t *testing.T) {
+	const schema = `{"type": "object", "required": ["a"]}`
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name       string
+		value      string
+		typ        *string
+		schema     *string
+		wantType   string
+		wantSchema string
+		wantErr    string
+	}{
+		{name: "未传入时沿用原类型和模式", value: `{"a": 1}`, wantType: "json", wantSchema: schema},
+		{name: "沿用的模式仍然校验", value: `{"b": 1}`, wantErr: "不符合 JSON Schema"},
+		{name: "传入空字符串清空模式", value: `{"b": 1}`, schema: str(""), wantType: "json"},
+		{name: "改为非json类型时清除模式", value: "x", typ: str("string"), wantType: "string"},
+		{name: "非json类型不能设置模式", value: "x", typ: str("string"), schema: str(schema), wantErr: "只有 json 类型"},
+		{name: "不支持的关键字", value: `"x"`, schema: str(`{"format": "email"}`), wantErr: "不支持的关键字 format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupPluginTestDB(t, &system.SysParams{})
+			param := system.SysParams{Name: "配置", Key: "conf", Value: `{"a": 1}`, Desc: "说明", Type: "json", Schema: schema}
+			if err := SysParamsServiceApp.CreateSysParams(context.Background(), &param); err != nil {
+				t.Fatalf("创建参数失败: %v", err)
+			}
+			update := systemReq.SysParamsUpdate{SysParams: param, Type: tt.typ, Schema: tt.schema}
+			update.SysParams.Type, update.SysParams.Schema, update.Value, update.Desc = "", "", tt.value, ""
+			err := SysParamsServiceApp.UpdateSysParams(context.Background(), update)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("UpdateSysParams() error = %v, 期望包含 %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateSysParams() error = %v", err)
+			}
+			var got system.SysParams
+			db.First(&got, param.ID)
+			if got.Type != tt.wantType || got.Schema != tt.wantSchema || got.Value != tt.value || got.Desc != "" {
+				t.Errorf("更新后 type=%q schema=%q value=%q desc=%q, 期望 type=%q schema=%q value=%q desc为空",
+					got.Type, got.Schema, got.Value, got.Desc, tt.wantType, tt.wantSchema, tt.value)
+			}
+		})
+	}
+}
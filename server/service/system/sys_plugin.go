@@ -0,0 +1,349 @@
+package system
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/plugin/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PluginService struct{}
+
+var PluginServiceApp = new(PluginService)
+
+//@function: RegisterPlugins
+//@description: 按依赖顺序注册v2插件 声明清单的插件先检查核心版本与依赖 再执行升级步骤并登记版本 未声明清单的旧版插件直接注册 已停用的插件照常注册路由 由网关中间件拒绝访问
+//@param: ctx context.Context, engine *gin.Engine, plugins ...plugin.Plugin
+
+func (pluginService *PluginService) RegisterPlugins(ctx context.Context, engine *gin.Engine, plugins ...plugin.Plugin) {
+	manifests := make([]plugin.Manifest, 0, len(plugins))
+	described := make(map[string]plugin.Plugin, len(plugins))
+	for _, p := range plugins {
+		describer, ok := p.(plugin.Describer)
+		if !ok {
+			p.Register(engine)
+			continue
+		}
+		manifest := describer.Manifest()
+		manifests = append(manifests, manifest)
+		described[manifest.Name] = p
+	}
+	index := make(map[string]plugin.Manifest, len(manifests))
+	for _, manifest := range manifests {
+		index[manifest.Name] = manifest
+	}
+	order, failed := plugin.Resolve(global.GVA_VERSION, manifests)
+	for _, name := range order {
+		manifest := index[name]
+		for dependency := range manifest.Dependencies {
+			if _, ok := failed[dependency]; ok {
+				failed[name] = errors.Errorf("插件[%s]依赖的插件[%s]无法启用", name, dependency)
+				break
+			}
+		}
+		if _, ok := failed[name]; ok {
+			continue
+		}
+		if err := pluginService.upgrade(ctx, described[name], manifest); err != nil {
+			failed[name] = err
+			continue
+		}
+		described[name].Register(engine)
+		if !pluginService.IsEnabled(name) {
+			pluginService.applyTimer(name, false)
+		}
+	}
+	names := make([]string, 0, len(failed))
+	for name := range failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		global.GVA_LOG.Error("插件未注册!", zap.String("plugin", name), zap.Error(failed[name]))
+		if err := pluginService.fail(ctx, index[name], failed[name]); err != nil {
+			global.GVA_LOG.Error("登记插件状态失败!", zap.String("plugin", name), zap.Error(err))
+		}
+	}
+}
+
+// upgrade 在同一事务中执行已安装版本之后的升级步骤并登记当前版本 不支持降级
+func (pluginService *PluginService) upgrade(ctx context.Context, p plugin.Plugin, manifest plugin.Manifest) error {
+	return global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var entity system.SysPlugin
+		err := tx.Where("name = ?", manifest.Name).Limit(1).Find(&entity).Error
+		if err != nil {
+			return err
+		}
+		if entity.Version != "" && plugin.CompareVersion(entity.Version, manifest.Version) > 0 {
+			return errors.Errorf("插件[%s]已安装版本%s高于当前版本%s, 不支持降级!", manifest.Name, entity.Version, manifest.Version)
+		}
+		var migrations []plugin.Migration
+		if migrator, ok := p.(plugin.Migrator); ok {
+			migrations = migrator.Migrations()
+		}
+		pending, err := plugin.Pending(migrations, entity.Version, manifest.Version)
+		if err != nil {
+			return errors.Wrapf(err, "插件[%s]升级步骤错误!", manifest.Name)
+		}
+		for _, migration := range pending {
+			if migration.Migrate == nil {
+				continue
+			}
+			err = migration.Migrate(ctx, tx)
+			if err != nil {
+				return errors.Wrapf(err, "插件[%s]升级到%s失败!", manifest.Name, migration.Version)
+			}
+			global.GVA_LOG.Info("插件升级成功", zap.String("plugin", manifest.Name), zap.String("version", migration.Version))
+		}
+		entity.Name = manifest.Name
+		entity.Version = manifest.Version
+		entity.Core = manifest.Core
+		entity.Dependencies = manifest.Dependencies
+		entity.Description = manifest.Description
+		entity.State = system.SysPluginStateInstalled
+		entity.Message = ""
+		return tx.Save(&entity).Error
+	})
+}
+
+// fail 登记插件无法启用的原因 已安装版本保持不变
+func (pluginService *PluginService) fail(ctx context.Context, manifest plugin.Manifest, cause error) error {
+	var entity system.SysPlugin
+	db := global.GVA_DB.WithContext(ctx)
+	err := db.Where("name = ?", manifest.Name).Limit(1).Find(&entity).Error
+	if err != nil {
+		return err
+	}
+	if entity.ID == 0 {
+		entity.Name = manifest.Name
+		entity.Core = manifest.Core
+		entity.Dependencies = manifest.Dependencies
+		entity.Description = manifest.Description
+	}
+	entity.State = system.SysPluginStateFailed
+	entity.Message = cause.Error()
+	return db.Save(&entity).Error
+}
+
+//@function: CheckInstall
+//@description: 安装或升级插件前检查 核心版本兼容 依赖的插件已安装且版本满足 不允许降级 已安装插件对其的依赖在升级后仍然满足
+//@param: ctx context.Context, manifest plugin.Manifest
+//@return: err error
+
+func (pluginService *PluginService) CheckInstall(ctx context.Context, manifest plugin.Manifest) error {
+	err := manifest.Validate()
+	if err != nil {
+		return err
+	}
+	if ok, _ := plugin.Satisfies(global.GVA_VERSION, manifest.Core); !ok {
+		return errors.Errorf("插件[%s]要求核心版本[%s], 当前核心版本为%s!", manifest.Name, manifest.Core, global.GVA_VERSION)
+	}
+	var entities []system.SysPlugin
+	err = global.GVA_DB.WithContext(ctx).Find(&entities).Error
+	if err != nil {
+		return errors.Wrap(err, "获取已安装插件失败!")
+	}
+	index := make(map[string]system.SysPlugin, len(entities))
+	for _, entity := range entities {
+		index[entity.Name] = entity
+	}
+	if current, ok := index[manifest.Name]; ok && current.Version != "" {
+		switch compare := plugin.CompareVersion(current.Version, manifest.Version); {
+		case compare > 0:
+			return errors.Errorf("插件[%s]已安装版本%s, 不支持降级到%s!", manifest.Name, current.Version, manifest.Version)
+		case compare == 0:
+			return errors.Errorf("插件[%s]已安装相同版本%s!", manifest.Name, manifest.Version)
+		}
+	}
+	for dependency, constraint := range manifest.Dependencies {
+		target, ok := index[dependency]
+		if !ok || target.State != system.SysPluginStateInstalled {
+			return errors.Errorf("插件[%s]依赖的插件[%s]未安装, 请先安装依赖!", manifest.Name, dependency)
+		}
+		if ok, _ = plugin.Satisfies(target.Version, constraint); !ok {
+			return errors.Errorf("插件[%s]要求插件[%s]的版本为[%s], 当前版本为%s!", manifest.Name, dependency, constraint, target.Version)
+		}
+	}
+	for _, entity := range entities {
+		constraint, ok := entity.Dependencies[manifest.Name]
+		if !ok || entity.State != system.SysPluginStateInstalled {
+			continue
+		}
+		if ok, _ = plugin.Satisfies(manifest.Version, constraint); !ok {
+			return errors.Errorf("插件[%s]要求插件[%s]的版本为[%s], 安装%s后将无法启用!", entity.Name, manifest.Name, constraint, manifest.Version)
+		}
+	}
+	return nil
+}
+
+//@function: Dependents
+//@description: 依赖该插件的已安装插件 卸载前检查
+//@param: ctx context.Context, name string
+//@return: names []string, err error
+
+func (pluginService *PluginService) Dependents(ctx context.Context, name string) (names []string, err error) {
+	var entities []system.SysPlugin
+	err = global.GVA_DB.WithContext(ctx).Where("state = ?", system.SysPluginStateInstalled).Find(&entities).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		if _, ok := entity.Dependencies[name]; ok {
+			names = append(names, entity.Name)
+		}
+	}
+	return names, nil
+}
+
+//@function: GetPluginList
+//@description: 获取已登记的插件及状态
+//@param: ctx context.Context
+//@return: list []system.SysPlugin, err error
+
+func (pluginService *PluginService) GetPluginList(ctx context.Context) (list []system.SysPlugin, err error) {
+	err = global.GVA_DB.WithContext(ctx).Order("name").Find(&list).Error
+	return list, err
+}
+
+//@function: GetPlugin
+//@description: 按名称获取已登记的插件 未登记时返回空记录
+//@param: ctx context.Context, name string
+//@return: entity system.SysPlugin, err error
+
+func (pluginService *PluginService) GetPlugin(ctx context.Context, name string) (entity system.SysPlugin, err error) {
+	err = global.GVA_DB.WithContext(ctx).Where("name = ?", name).Limit(1).Find(&entity).Error
+	return entity, err
+}
+
+// pluginStateCache 已登记插件缓存 键为插件名称 供路由网关与菜单过滤使用
+var pluginStateCache = newKeyedCache("gva:plugins:invalidate", func() (map[string]system.SysPlugin, error) {
+	var rows []system.SysPlugin
+	if err := global.GVA_DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	items := make(map[string]system.SysPlugin, len(rows))
+	for _, row := range rows {
+		items[row.Name] = row
+	}
+	return items, nil
+})
+
+//@function: ReloadPluginStates
+//@description: 丢弃插件状态缓存 下次读取时从当前数据库重新加载 用于更换数据库连接(如测试)或直接修改插件表之后
+//@return:
+
+func (pluginService *PluginService) ReloadPluginStates() {
+	pluginStateCache.invalidate()
+}
+
+//@function: IsEnabled
+//@description: 插件是否启用 未登记的插件视为启用 读取失败时放行
+//@param: name string
+//@return: bool
+
+func (pluginService *PluginService) IsEnabled(name string) bool {
+	entity, ok, err := pluginStateCache.get(name)
+	if err != nil {
+		global.GVA_LOG.Error("获取插件状态失败!", zap.String("plugin", name), zap.Error(err))
+		return true
+	}
+	return !ok || !entity.Disabled
+}
+
+//@function: DisabledMenuPrefixes
+//@description: 已停用插件的菜单组件路径前缀
+//@return: prefixes []string
+
+func (pluginService *PluginService) DisabledMenuPrefixes() (prefixes []string) {
+	items, err := pluginStateCache.snapshot()
+	if err != nil {
+		global.GVA_LOG.Error("获取插件状态失败!", zap.Error(err))
+		return nil
+	}
+	for name, entity := range items {
+		if entity.Disabled {
+			prefixes = append(prefixes, system.PluginMenuPrefix(name))
+		}
+	}
+	return prefixes
+}
+
+//@function: SetPluginEnabled
+//@description: 启用或停用插件 停用前检查启用中的插件是否依赖它 启用前检查依赖的插件已启用
+//@param: ctx context.Context, name string, enabled bool
+//@return: err error
+
+func (pluginService *PluginService) SetPluginEnabled(ctx context.Context, name string, enabled bool) (err error) {
+	err = global.GVA_DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var entities []system.SysPlugin
+		if err := tx.Find(&entities).Error; err != nil {
+			return err
+		}
+		index := make(map[string]system.SysPlugin, len(entities))
+		for _, entity := range entities {
+			index[entity.Name] = entity
+		}
+		entity, ok := index[name]
+		if !ok {
+			return errors.Errorf("插件[%s]未登记, 只能启停声明了plugin.json的插件!", name)
+		}
+		if enabled {
+			for dependency := range entity.Dependencies {
+				if target, ok := index[dependency]; !ok || target.Disabled {
+					return errors.Errorf("插件[%s]依赖的插件[%s]未启用, 请先启用依赖!", name, dependency)
+				}
+			}
+		} else {
+			for _, other := range entities {
+				if _, ok := other.Dependencies[name]; ok && !other.Disabled {
+					return errors.Errorf("插件[%s]依赖该插件, 请先停用[%s]!", other.Name, other.Name)
+				}
+			}
+		}
+		return tx.Model(&entity).Update("disabled", !enabled).Error
+	})
+	if err != nil {
+		return err
+	}
+	pluginStateCache.publish()
+	pluginService.applyTimer(name, enabled)
+	return nil
+}
+
+//@function: SyncPluginTimers
+//@description: 按登记状态暂停或恢复插件的定时任务 用于同步其他实例的启停
+//@param: ctx context.Context
+
+func (pluginService *PluginService) SyncPluginTimers(ctx context.Context) {
+	var entities []system.SysPlugin
+	err := global.GVA_DB.WithContext(ctx).Find(&entities).Error
+	if err != nil {
+		global.GVA_LOG.Error("获取插件状态失败!", zap.Error(err))
+		return
+	}
+	for _, entity := range entities {
+		pluginService.applyTimer(entity.Name, !entity.Disabled)
+	}
+}
+
+// pluginTimerPaused 本实例中定时任务已暂停的插件
+var pluginTimerPaused sync.Map
+
+// applyTimer 插件的定时任务约定注册在与插件同名的cron中 停用时每次都暂停 避免插件之后添加任务时cron被重新启动
+func (pluginService *PluginService) applyTimer(name string, enabled bool) {
+	if enabled {
+		if _, paused := pluginTimerPaused.LoadAndDelete(name); paused {
+			global.GVA_Timer.StartCron(name)
+		}
+		return
+	}
+	pluginTimerPaused.Store(name, true)
+	global.GVA_Timer.StopCron(name)
+}
@@ -0,0 +1,87 @@
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// setupPluginTestDB 使用内存sqlite作为插件相关测试的数据库 并清空插件状态缓存
+func setupPluginTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取数据库连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 内存库只在同一连接内可见
+	if err = db.AutoMigrate(append([]any{&system.SysPlugin{}}, models...)...); err != nil {
+		t.Fatalf("迁移表结构失败: %v", err)
+	}
+	oldDB, oldLog := global.GVA_DB, global.GVA_LOG
+	global.GVA_DB, global.GVA_LOG = db, zap.NewNop()
+	pluginStateCache.invalidate()
+	t.Cleanup(func() {
+		global.GVA_DB, global.GVA_LOG = oldDB, oldLog
+		pluginStateCache.invalidate()
+		_ = sqlDB.Close()
+	})
+	return db
+}
+
+func TestPluginService_SetPluginEnabled(t *testing.T) {
+	db := setupPluginTestDB(t)
+	plugins := []system.SysPlugin{
+		{Name: "base", State: system.SysPluginStateInstalled},
+		{Name: "child", State: system.SysPluginStateInstalled, Dependencies: map[string]string{"base": "^1.0.0"}},
+		{Name: "orphan", State: system.SysPluginStateInstalled, Dependencies: map[string]string{"missing": "^1.0.0"}, Disabled: true},
+	}
+	if err := db.Create(&plugins).Error; err != nil {
+		t.Fatalf("准备数据失败: %v", err)
+	}
+	// 各步骤按顺序执行 后一步依赖前一步的结果
+	tests := []struct {
+		name    string
+		plugin  string
+		enabled bool
+		wantErr string
+	}{
+		{name: "未登记的插件", plugin: "legacy", enabled: false, wantErr: "未登记"},
+		{name: "被启用中的插件依赖时不能停用", plugin: "base", enabled: false, wantErr: "请先停用[child]"},
+		{name: "依赖未登记时不能启用", plugin: "orphan", enabled: true, wantErr: "[missing]未启用"},
+		{name: "停用依赖方", plugin: "child", enabled: false},
+		{name: "依赖方停用后可以停用", plugin: "base", enabled: false},
+		{name: "依赖停用时不能启用", plugin: "child", enabled: true, wantErr: "[base]未启用"},
+		{name: "先启用依赖", plugin: "base", enabled: true},
+		{name: "再启用依赖方", plugin: "child", enabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := PluginServiceApp.SetPluginEnabled(context.Background(), tt.plugin, tt.enabled)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SetPluginEnabled() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetPluginEnabled() error = %v", err)
+			}
+			if got := PluginServiceApp.IsEnabled(tt.plugin); got != tt.enabled {
+				t.Errorf("IsEnabled(%s) = %v, want %v", tt.plugin, got, tt.enabled)
+			}
+		})
+	}
+	if prefixes := PluginServiceApp.DisabledMenuPrefixes(); len(prefixes) != 1 || prefixes[0] != system.PluginMenuPrefix("orphan") {
+		t.Errorf("DisabledMenuPrefixes() = %v", prefixes)
+	}
+}
@@ -228,7 +228,7 @@ func (userService *UserService) DeleteUser(id int) (err error) {
 
 func (userService *UserService) SetUserInfo(req system.SysUser) error {
 	return global.GVA_DB.Model(&system.SysUser{}).
-		Select("updated_at", "nick_name", "header_img", "phone", "email", "enable").
+		Select("updated_at", "nick_name", "header_img", "phone", "email", "enable", "tenant").
 		Where("id=?", req.ID).
 		Updates(map[string]interface{}{
 			"updated_at": time.Now(),
@@ -237,6 +237,7 @@ func (userService *UserService) SetUserInfo(req system.SysUser) error {
 			"phone":      req.Phone,
 			"email":      req.Email,
 			"enable":     req.Enable,
+			"tenant":     req.Tenant,
 		}).Error
 }
 
@@ -259,6 +260,7 @@ func (userService *UserService) SetSelfInfo(req system.SysUser) error {
 //@return: err error
 
 func (userService *UserService) SetSelfSetting(req common.JSONMap, uid uint) error {
+	defer I18nServiceApp.ForgetUserLocale(uid)
 	return global.GVA_DB.Model(&system.SysUser{}).Where("id = ?", uid).Update("origin_setting", req).Error
 }
 
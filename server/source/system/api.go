@@ -115,7 +115,12 @@ func (i *initApi) InitializeData(ctx context.Context) (context.Context, error) {
 		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/createTemp", Description: "自动化代码"},
 		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/preview", Description: "预览自动化代码"},
 		{ApiGroup: "代码生成器", Method: "GET", Path: "/autoCode/getColumn", Description: "获取所选table的所有字段"},
+		{ApiGroup: "代码生成器", Method: "GET", Path: "/autoCode/getRelations", Description: "获取所选table的关联关系"},
+		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/generateTypeScript", Description: "生成TypeScript客户端"},
 		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/installPlugin", Description: "安装插件"},
+		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/uninstallPlugin", Description: "卸载插件"},
+		{ApiGroup: "代码生成器", Method: "GET", Path: "/autoCode/getPluginList", Description: "获取已安装的插件及状态"},
+		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/setPluginState", Description: "启用或停用插件"},
 		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/pubPlug", Description: "打包插件"},
 		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/mcp", Description: "自动生成 MCP Tool 模板"},
 		{ApiGroup: "代码生成器", Method: "POST", Path: "/autoCode/mcpTest", Description: "MCP Tool 测试"},
@@ -123,6 +128,7 @@ func (i *initApi) InitializeData(ctx context.Context) (context.Context, error) {
 
 		{ApiGroup: "模板配置", Method: "POST", Path: "/autoCode/createPackage", Description: "配置模板"},
 		{ApiGroup: "模板配置", Method: "GET", Path: "/autoCode/getTemplates", Description: "获取模板文件"},
+		{ApiGroup: "模板配置", Method: "POST", Path: "/autoCode/installTemplatePack", Description: "安装外部模板包"},
 		{ApiGroup: "模板配置", Method: "POST", Path: "/autoCode/getPackage", Description: "获取所有模板"},
 		{ApiGroup: "模板配置", Method: "POST", Path: "/autoCode/delPackage", Description: "删除模板"},
 
@@ -137,6 +143,12 @@ func (i *initApi) InitializeData(ctx context.Context) (context.Context, error) {
 		{ApiGroup: "系统字典详情", Method: "DELETE", Path: "/sysDictionaryDetail/deleteSysDictionaryDetail", Description: "删除字典内容"},
 		{ApiGroup: "系统字典详情", Method: "GET", Path: "/sysDictionaryDetail/findSysDictionaryDetail", Description: "根据ID获取字典内容"},
 		{ApiGroup: "系统字典详情", Method: "GET", Path: "/sysDictionaryDetail/getSysDictionaryDetailList", Description: "获取字典内容列表"},
+		{ApiGroup: "系统字典详情", Method: "GET", Path: "/sysDictionaryDetail/getDictionaryTreeList", Description: "按字典ID获取树形字典内容"},
+		{ApiGroup: "系统字典详情", Method: "GET", Path: "/sysDictionaryDetail/getDictionaryTreeListByType", Description: "按字典type获取树形字典内容"},
+		{ApiGroup: "系统字典详情", Method: "GET", Path: "/sysDictionaryDetail/getDictionaryDetailsByParent", Description: "获取字典内容的直接子级"},
+		{ApiGroup: "系统字典详情", Method: "GET", Path: "/sysDictionaryDetail/getDictionaryPath", Description: "按字典值获取字典内容路径"},
+		{ApiGroup: "系统字典详情", Method: "PUT", Path: "/sysDictionaryDetail/moveSysDictionaryDetail", Description: "移动字典内容"},
+		{ApiGroup: "系统字典详情", Method: "PUT", Path: "/sysDictionaryDetail/sortSysDictionaryDetail", Description: "同级字典内容排序"},
 
 		{ApiGroup: "系统字典", Method: "POST", Path: "/sysDictionary/createSysDictionary", Description: "新增字典"},
 		{ApiGroup: "系统字典", Method: "DELETE", Path: "/sysDictionary/deleteSysDictionary", Description: "删除字典"},
@@ -185,9 +197,64 @@ func (i *initApi) InitializeData(ctx context.Context) (context.Context, error) {
 		{ApiGroup: "参数管理", Method: "GET", Path: "/sysParams/findSysParams", Description: "根据ID获取参数"},
 		{ApiGroup: "参数管理", Method: "GET", Path: "/sysParams/getSysParamsList", Description: "获取参数列表"},
 		{ApiGroup: "参数管理", Method: "GET", Path: "/sysParams/getSysParam", Description: "获取参数列表"},
+		{ApiGroup: "参数管理", Method: "GET", Path: "/sysParams/getSysParamsHistory", Description: "获取参数变更历史"},
+		{ApiGroup: "审计哈希链", Method: "GET", Path: "/auditChain/getAuditChainInfo", Description: "获取哈希链信息"},
+		{ApiGroup: "审计哈希链", Method: "GET", Path: "/auditChain/verify", Description: "校验哈希链"},
+		{ApiGroup: "审计哈希链", Method: "GET", Path: "/auditChain/export", Description: "导出哈希链归档"},
+		{ApiGroup: "审计哈希链", Method: "POST", Path: "/auditChain/createCheckpoint", Description: "生成签名检查点"},
+		{ApiGroup: "审计SIEM投递", Method: "GET", Path: "/auditExport/getAuditExportStatus", Description: "获取投递状态"},
+		{ApiGroup: "审计SIEM投递", Method: "POST", Path: "/auditExport/export", Description: "立即投递"},
+		{ApiGroup: "变更历史", Method: "GET", Path: "/entityVersion/getVersionedTables", Description: "获取已开启变更历史的表"},
+		{ApiGroup: "变更历史", Method: "GET", Path: "/entityVersion/getEntityVersionList", Description: "获取实体变更历史"},
+		{ApiGroup: "变更历史", Method: "GET", Path: "/entityVersion/diff", Description: "比较版本差异"},
+		{ApiGroup: "变更历史", Method: "POST", Path: "/entityVersion/restore", Description: "恢复到指定版本"},
+		{ApiGroup: "操作记录归档", Method: "GET", Path: "/sysOperationRecordArchive/getArchiveList", Description: "获取归档列表"},
+		{ApiGroup: "操作记录归档", Method: "GET", Path: "/sysOperationRecordArchive/queryArchive", Description: "查询归档中的记录"},
+		{ApiGroup: "操作记录归档", Method: "POST", Path: "/sysOperationRecordArchive/restoreArchive", Description: "恢复归档到数据库"},
+		{ApiGroup: "操作记录归档", Method: "POST", Path: "/sysOperationRecordArchive/applyRetention", Description: "立即执行保留策略"},
+		{ApiGroup: "多语言", Method: "POST", Path: "/sysTranslation/createTranslation", Description: "创建翻译"},
+		{ApiGroup: "多语言", Method: "PUT", Path: "/sysTranslation/updateTranslation", Description: "更新翻译"},
+		{ApiGroup: "多语言", Method: "DELETE", Path: "/sysTranslation/deleteTranslation", Description: "删除翻译"},
+		{ApiGroup: "多语言", Method: "GET", Path: "/sysTranslation/getTranslationList", Description: "分页获取翻译"},
+		{ApiGroup: "多语言", Method: "POST", Path: "/sysTranslation/importTranslations", Description: "导入翻译文件"},
+		{ApiGroup: "多语言", Method: "GET", Path: "/sysTranslation/exportTranslations", Description: "导出翻译文件"},
+
+		{ApiGroup: "功能开关", Method: "POST", Path: "/featureFlag/createFeatureFlag", Description: "创建功能开关"},
+		{ApiGroup: "功能开关", Method: "PUT", Path: "/featureFlag/updateFeatureFlag", Description: "更新功能开关"},
+		{ApiGroup: "功能开关", Method: "DELETE", Path: "/featureFlag/deleteFeatureFlag", Description: "删除功能开关"},
+		{ApiGroup: "功能开关", Method: "GET", Path: "/featureFlag/findFeatureFlag", Description: "根据ID获取功能开关"},
+		{ApiGroup: "功能开关", Method: "GET", Path: "/featureFlag/getFeatureFlagList", Description: "分页获取功能开关"},
+		{ApiGroup: "功能开关", Method: "GET", Path: "/featureFlag/getFeatureFlagAuditList", Description: "获取功能开关变更审计"},
+		{ApiGroup: "功能开关", Method: "GET", Path: "/featureFlag/getEvaluatedFlags", Description: "获取当前用户的功能开关"},
+
 		{ApiGroup: "媒体库分类", Method: "GET", Path: "/attachmentCategory/getCategoryList", Description: "分类列表"},
 		{ApiGroup: "媒体库分类", Method: "POST", Path: "/attachmentCategory/addCategory", Description: "添加/编辑分类"},
 		{ApiGroup: "媒体库分类", Method: "POST", Path: "/attachmentCategory/deleteCategory", Description: "删除分类"},
+
+		{ApiGroup: "个人访问令牌", Method: "POST", Path: "/accessToken/createAccessToken", Description: "创建个人访问令牌"},
+		{ApiGroup: "个人访问令牌", Method: "GET", Path: "/accessToken/getAccessTokenList", Description: "获取个人访问令牌"},
+		{ApiGroup: "个人访问令牌", Method: "DELETE", Path: "/accessToken/deleteAccessToken", Description: "吊销个人访问令牌"},
+
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/currentTime", Description: "MCP工具 获取当前时间"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getNickname", Description: "MCP工具 根据用户名获取昵称"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getUserList", Description: "MCP工具 分页获取用户列表"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getAuthorityList", Description: "MCP工具 获取角色列表"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getApiList", Description: "MCP工具 分页获取API列表"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getPolicyPathByAuthorityId", Description: "MCP工具 获取角色的API权限"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getDictionaryList", Description: "MCP工具 获取字典列表"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/findDictionary", Description: "MCP工具 获取字典及字典项"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getParamsList", Description: "MCP工具 分页获取参数列表"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getParam", Description: "MCP工具 根据key获取参数"},
+		{ApiGroup: "MCP工具", Method: "MCP", Path: "/mcp/tools/getOperationRecordList", Description: "MCP工具 分页获取操作记录"},
+		{ApiGroup: "MCP资源", Method: "MCP", Path: "/mcp/resources/routes", Description: "MCP资源 路由目录"},
+		{ApiGroup: "MCP资源", Method: "MCP", Path: "/mcp/resources/plugins", Description: "MCP资源 已安装插件"},
+		{ApiGroup: "MCP资源", Method: "MCP", Path: "/mcp/resources/dictionaries", Description: "MCP资源 字典列表"},
+		{ApiGroup: "MCP资源", Method: "MCP", Path: "/mcp/resources/dictionary", Description: "MCP资源 字典详情"},
+		{ApiGroup: "MCP资源", Method: "MCP", Path: "/mcp/resources/packages", Description: "MCP资源 代码生成器的包"},
+		{ApiGroup: "MCP资源", Method: "MCP", Path: "/mcp/resources/packageSchema", Description: "MCP资源 包内的表结构"},
+		{ApiGroup: "MCP提示词", Method: "MCP", Path: "/mcp/prompts/designModule", Description: "MCP提示词 设计模块"},
+		{ApiGroup: "MCP提示词", Method: "MCP", Path: "/mcp/prompts/reviewAuthority", Description: "MCP提示词 审查角色权限"},
+		{ApiGroup: "MCP提示词", Method: "MCP", Path: "/mcp/prompts/explainRoute", Description: "MCP提示词 解释路由"},
 	}
 	if err := db.Create(&entities).Error; err != nil {
 		return ctx, errors.Wrap(err, sysModel.SysApi{}.TableName()+"表数据初始化失败!")
@@ -118,16 +118,22 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "888", V1: "/autoCode/preview", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/getTables", V2: "GET"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/getColumn", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/autoCode/getRelations", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/autoCode/generateTypeScript", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/rollback", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/createTemp", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/delSysHistory", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/getSysHistory", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/createPackage", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/getTemplates", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/autoCode/installTemplatePack", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/getPackage", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/delPackage", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/createPlug", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/installPlugin", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/autoCode/uninstallPlugin", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/autoCode/getPluginList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/autoCode/setPluginState", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/pubPlug", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/addFunc", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/autoCode/mcp", V2: "POST"},
@@ -139,6 +145,12 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/createSysDictionaryDetail", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/getSysDictionaryDetailList", V2: "GET"},
 		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/deleteSysDictionaryDetail", V2: "DELETE"},
+		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/getDictionaryTreeList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/getDictionaryTreeListByType", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/getDictionaryDetailsByParent", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/getDictionaryPath", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/moveSysDictionaryDetail", V2: "PUT"},
+		{Ptype: "p", V0: "888", V1: "/sysDictionaryDetail/sortSysDictionaryDetail", V2: "PUT"},
 
 		{Ptype: "p", V0: "888", V1: "/sysDictionary/findSysDictionary", V2: "GET"},
 		{Ptype: "p", V0: "888", V1: "/sysDictionary/updateSysDictionary", V2: "PUT"},
@@ -188,10 +200,65 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "888", V1: "/sysParams/findSysParams", V2: "GET"},
 		{Ptype: "p", V0: "888", V1: "/sysParams/getSysParamsList", V2: "GET"},
 		{Ptype: "p", V0: "888", V1: "/sysParams/getSysParam", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/sysParams/getSysParamsHistory", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/auditChain/getAuditChainInfo", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/auditChain/verify", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/auditChain/export", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/auditChain/createCheckpoint", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/auditExport/getAuditExportStatus", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/auditExport/export", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/entityVersion/getVersionedTables", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/entityVersion/getEntityVersionList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/entityVersion/diff", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/entityVersion/restore", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/sysOperationRecordArchive/getArchiveList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/sysOperationRecordArchive/queryArchive", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/sysOperationRecordArchive/restoreArchive", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/sysOperationRecordArchive/applyRetention", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/sysTranslation/createTranslation", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/sysTranslation/updateTranslation", V2: "PUT"},
+		{Ptype: "p", V0: "888", V1: "/sysTranslation/deleteTranslation", V2: "DELETE"},
+		{Ptype: "p", V0: "888", V1: "/sysTranslation/getTranslationList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/sysTranslation/importTranslations", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/sysTranslation/exportTranslations", V2: "GET"},
+
+		{Ptype: "p", V0: "888", V1: "/featureFlag/createFeatureFlag", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/featureFlag/updateFeatureFlag", V2: "PUT"},
+		{Ptype: "p", V0: "888", V1: "/featureFlag/deleteFeatureFlag", V2: "DELETE"},
+		{Ptype: "p", V0: "888", V1: "/featureFlag/findFeatureFlag", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/featureFlag/getFeatureFlagList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/featureFlag/getFeatureFlagAuditList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/featureFlag/getEvaluatedFlags", V2: "GET"},
+
 		{Ptype: "p", V0: "888", V1: "/attachmentCategory/getCategoryList", V2: "GET"},
 		{Ptype: "p", V0: "888", V1: "/attachmentCategory/addCategory", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/attachmentCategory/deleteCategory", V2: "POST"},
 
+		{Ptype: "p", V0: "888", V1: "/accessToken/createAccessToken", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/accessToken/getAccessTokenList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/accessToken/deleteAccessToken", V2: "DELETE"},
+
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/currentTime", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getNickname", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getUserList", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getAuthorityList", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getApiList", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getPolicyPathByAuthorityId", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getDictionaryList", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/findDictionary", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getParamsList", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getParam", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/tools/getOperationRecordList", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/resources/routes", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/resources/plugins", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/resources/dictionaries", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/resources/dictionary", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/resources/packages", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/resources/packageSchema", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/prompts/designModule", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/prompts/reviewAuthority", V2: "MCP"},
+		{Ptype: "p", V0: "888", V1: "/mcp/prompts/explainRoute", V2: "MCP"},
+
 		{Ptype: "p", V0: "8881", V1: "/user/admin_register", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/api/createApi", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/api/getApiList", V2: "POST"},
@@ -204,6 +271,7 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "8881", V1: "/authority/getAuthorityList", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/authority/setDataAuthority", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/menu/getMenu", V2: "POST"},
+		{Ptype: "p", V0: "8881", V1: "/featureFlag/getEvaluatedFlags", V2: "GET"},
 		{Ptype: "p", V0: "8881", V1: "/menu/getMenuList", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/menu/addBaseMenu", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/menu/getBaseMenuTree", V2: "POST"},
@@ -246,6 +314,7 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "9528", V1: "/authority/setDataAuthority", V2: "POST"},
 
 		{Ptype: "p", V0: "9528", V1: "/menu/getMenu", V2: "POST"},
+		{Ptype: "p", V0: "9528", V1: "/featureFlag/getEvaluatedFlags", V2: "GET"},
 		{Ptype: "p", V0: "9528", V1: "/menu/getMenuList", V2: "POST"},
 		{Ptype: "p", V0: "9528", V1: "/menu/addBaseMenu", V2: "POST"},
 		{Ptype: "p", V0: "9528", V1: "/menu/getBaseMenuTree", V2: "POST"},
@@ -3,6 +3,7 @@ package task
 import (
 	"errors"
 	"fmt"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
 	"time"
 
@@ -18,11 +19,14 @@ import (
 func ClearTable(db *gorm.DB) error {
 	var ClearTableDetail []common.ClearDB
 
-	ClearTableDetail = append(ClearTableDetail, common.ClearDB{
-		TableName:    "sys_operation_records",
-		CompareField: "created_at",
-		Interval:     "2160h",
-	})
+	// 开启保留策略后操作记录由 OperationRecordRetention 任务归档清理
+	if !global.GVA_CONFIG.OperationRecord.Retention.Enable {
+		ClearTableDetail = append(ClearTableDetail, common.ClearDB{
+			TableName:    "sys_operation_records",
+			CompareField: "created_at",
+			Interval:     "2160h",
+		})
+	}
 
 	ClearTableDetail = append(ClearTableDetail, common.ClearDB{
 		TableName:    "jwt_blacklists",
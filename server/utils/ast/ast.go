@@ -7,6 +7,7 @@ import (
 	"go/parser"
 	"go/token"
 	"log"
+	"strconv"
 )
 
 // AddImport 增加 import 方法
@@ -59,8 +60,11 @@ func FindArray(astNode ast.Node, identName, selectorExprName string) *ast.Compos
 				if exprType, ok := expr.(*ast.CompositeLit); ok {
 					if arrayType, ok := exprType.Type.(*ast.ArrayType); ok {
 						sel, ok1 := arrayType.Elt.(*ast.SelectorExpr)
+						if !ok1 {
+							continue
+						}
 						x, ok2 := sel.X.(*ast.Ident)
-						if ok1 && ok2 && x.Name == identName && sel.Sel.Name == selectorExprName {
+						if ok2 && x.Name == identName && sel.Sel.Name == selectorExprName {
 							assignStmt = exprType
 							return false
 						}
@@ -172,6 +176,36 @@ func CheckImport(file *ast.File, importPath string) bool {
 	return false
 }
 
+// FindArrayValues 读取 []identName.selectorExprName{} 数组中每个元素的字符串字段 key为字段名
+func FindArrayValues(astNode ast.Node, identName, selectorExprName string) []map[string]string {
+	array := FindArray(astNode, identName, selectorExprName)
+	if array == nil {
+		return nil
+	}
+	values := make([]map[string]string, 0, len(array.Elts))
+	for _, elt := range array.Elts {
+		lit, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		value := make(map[string]string, len(lit.Elts))
+		for _, field := range lit.Elts {
+			kv, o1 := field.(*ast.KeyValueExpr)
+			if !o1 {
+				continue
+			}
+			key, o2 := kv.Key.(*ast.Ident)
+			basic, o3 := kv.Value.(*ast.BasicLit)
+			if !o2 || !o3 || basic.Kind != token.STRING {
+				continue
+			}
+			value[key.Name], _ = strconv.Unquote(basic.Value)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
 func clearPosition(astNode ast.Node) {
 	ast.Inspect(astNode, func(n ast.Node) bool {
 		switch node := n.(type) {
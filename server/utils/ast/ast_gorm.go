@@ -8,6 +8,8 @@ import (
 	"go/printer"
 	"go/token"
 	"os"
+	"path/filepath"
+	"strconv"
 )
 
 // AddRegisterTablesAst 自动为 gorm.go 注册一个自动迁移
@@ -164,3 +166,69 @@ func NeedAppendModel(callNode ast.Node, pk string, model string) bool {
 	})
 	return flag
 }
+
+// FindAutoMigrateStructs 读取 AutoMigrate 中注册的结构体名称 支持 new(pkg.T) &pkg.T{} pkg.T{}
+func FindAutoMigrateStructs(astNode ast.Node) []string {
+	var structs []string
+	ast.Inspect(astNode, func(n ast.Node) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+		if !ok || selExpr.Sel.Name != "AutoMigrate" {
+			return true
+		}
+		for _, arg := range callExpr.Args {
+			ast.Inspect(arg, func(node ast.Node) bool {
+				sel, o := node.(*ast.SelectorExpr)
+				if o {
+					structs = append(structs, sel.Sel.Name)
+				}
+				return !o
+			})
+		}
+		return false
+	})
+	return structs
+}
+
+// FindTableNames 读取目录下 func (T) TableName() string { return "name" } 声明的自定义表名 key为结构体名称
+func FindTableNames(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".go" {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, entry.Name()), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Name.Name != "TableName" || funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 || funcDecl.Body == nil {
+				continue
+			}
+			recv := funcDecl.Recv.List[0].Type
+			if star, o := recv.(*ast.StarExpr); o {
+				recv = star.X
+			}
+			ident, o := recv.(*ast.Ident)
+			if !o || len(funcDecl.Body.List) != 1 {
+				continue
+			}
+			ret, o := funcDecl.Body.List[0].(*ast.ReturnStmt)
+			if !o || len(ret.Results) != 1 {
+				continue
+			}
+			if basic, o := ret.Results[0].(*ast.BasicLit); o && basic.Kind == token.STRING {
+				names[ident.Name], _ = strconv.Unquote(basic.Value)
+			}
+		}
+	}
+	return names, nil
+}
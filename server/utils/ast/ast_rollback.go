@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 )
 
+// Deprecated: 仅能回滚特定写法的注入代码 代码生成器的回滚请使用历史记录中的文件快照
 func RollBackAst(pk, model string) {
 	RollGormBack(pk, model)
 	RollRouterBack(pk, model)
@@ -30,3 +30,30 @@ func TestAst(t *testing.T) {
 	}
 
 }
+
+func TestFindArrayValues(t *testing.T) {
+	src := `package initialize
+
+func Api(ctx context.Context) {
+	names := []string{"skip"}
+	entities := []model.SysApi{
+		{Path: "/info/createInfo", Method: "POST", ApiGroup: "公告"},
+		{Path: "/info/deleteInfo", Method: "DELETE"},
+	}
+	utils.RegisterApis(entities...)
+	err := global.GVA_DB.AutoMigrate(new(model.Info), &model.Notice{}, model.Read{})
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := FindArrayValues(file, "model", "SysApi")
+	if len(values) != 2 || values[0]["Path"] != "/info/createInfo" || values[0]["ApiGroup"] != "公告" || values[1]["Method"] != "DELETE" {
+		t.Errorf("FindArrayValues() = %v", values)
+	}
+	structs := FindAutoMigrateStructs(file)
+	if len(structs) != 3 || structs[0] != "Info" || structs[1] != "Notice" || structs[2] != "Read" {
+		t.Errorf("FindAutoMigrateStructs() = %v", structs)
+	}
+}
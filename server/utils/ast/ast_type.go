@@ -40,6 +40,7 @@ const (
 	TypePackageServiceModuleEnter = "PackageServiceModuleEnter" // server/service/{package}/enter.go
 	TypePackageInitializeGorm     = "PackageInitializeGorm"     // server/initialize/gorm_biz.go
 	TypePackageInitializeRouter   = "PackageInitializeRouter"   // server/initialize/router_biz.go
+	TypePackageInitializeMongo    = "PackageInitializeMongo"    // server/initialize/mongo_biz.go
 	TypePluginGen                 = "PluginGen"                 // server/plugin/{package}/gen/main.go
 	TypePluginApiEnter            = "PluginApiEnter"            // server/plugin/{package}/enter.go
 	TypePluginInitializeV1        = "PluginInitializeV1"        // server/initialize/plugin_biz_v1.go
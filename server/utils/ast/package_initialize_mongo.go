@@ -0,0 +1,118 @@
+package ast
+
+import (
+	"go/ast"
+	"io"
+)
+
+// PackageInitializeMongo 包初始化mongo 在 bizMongoModel 中注册模型 启动时创建模型声明的索引
+type PackageInitializeMongo struct {
+	Base
+	Type         Type   // 类型
+	Path         string // 文件路径
+	ImportPath   string // 导包路径
+	StructName   string // 结构体名称
+	PackageName  string // 包名
+	RelativePath string // 相对路径
+}
+
+func (a *PackageInitializeMongo) Parse(filename string, writer io.Writer) (file *ast.File, err error) {
+	if filename == "" {
+		if a.RelativePath == "" {
+			filename = a.Path
+			a.RelativePath = a.Base.RelativePath(a.Path)
+			return a.Base.Parse(filename, writer)
+		}
+		a.Path = a.Base.AbsolutePath(a.RelativePath)
+		filename = a.Path
+	}
+	return a.Base.Parse(filename, writer)
+}
+
+func (a *PackageInitializeMongo) Rollback(file *ast.File) error {
+	models := a.models(file)
+	if models == nil {
+		return nil
+	}
+	packageNameNum := 0
+	for i := 0; i < len(models.Elts); i++ {
+		selector, ok := a.selector(models.Elts[i])
+		if !ok || selector.X.(*ast.Ident).Name != a.PackageName {
+			continue
+		}
+		packageNameNum++
+		if selector.Sel.Name == a.StructName {
+			models.Elts = append(models.Elts[:i], models.Elts[i+1:]...)
+			i--
+		}
+	}
+	if packageNameNum == 1 {
+		_ = NewImport(a.ImportPath).Rollback(file)
+	}
+	return nil
+}
+
+func (a *PackageInitializeMongo) Injection(file *ast.File) error {
+	models := a.models(file)
+	if models == nil {
+		return nil
+	}
+	_ = NewImport(a.ImportPath).Injection(file)
+	for _, elt := range models.Elts {
+		selector, ok := a.selector(elt)
+		if ok && selector.X.(*ast.Ident).Name == a.PackageName && selector.Sel.Name == a.StructName {
+			return nil
+		}
+	} // 已注册
+	models.Elts = append(models.Elts, &ast.CompositeLit{
+		Type: &ast.SelectorExpr{
+			X:   ast.NewIdent(a.PackageName),
+			Sel: ast.NewIdent(a.StructName),
+		},
+	})
+	return nil
+}
+
+func (a *PackageInitializeMongo) Format(filename string, writer io.Writer, file *ast.File) error {
+	if filename == "" {
+		filename = a.Path
+	}
+	return a.Base.Format(filename, writer, file)
+}
+
+// models 寻找 bizMongoModel 中返回的 []MongoModel{}
+func (a *PackageInitializeMongo) models(file *ast.File) *ast.CompositeLit {
+	funcDecl := FindFunction(file, "bizMongoModel")
+	if funcDecl == nil {
+		return nil
+	}
+	var models *ast.CompositeLit
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		returnStmt, ok := n.(*ast.ReturnStmt)
+		if !ok || len(returnStmt.Results) != 1 {
+			return true
+		}
+		if lit, ok := returnStmt.Results[0].(*ast.CompositeLit); ok {
+			models = lit
+			return false
+		}
+		return true
+	})
+	return models
+}
+
+// selector 解析 package.StructName{} 形式的元素
+func (a *PackageInitializeMongo) selector(expr ast.Expr) (*ast.SelectorExpr, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	selector, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	if _, ok = selector.X.(*ast.Ident); !ok {
+		return nil, false
+	}
+	return selector, true
+}
@@ -0,0 +1,66 @@
+package ast
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mongoBiz = `package initialize
+
+func bizMongoModel() []MongoModel {
+	return []MongoModel{}
+}
+`
+
+func TestPackageInitializeMongo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mongo_biz.go")
+	if err := os.WriteFile(path, []byte(mongoBiz), 0644); err != nil {
+		t.Fatal(err)
+	}
+	book := &PackageInitializeMongo{
+		Type:        TypePackageInitializeMongo,
+		Path:        path,
+		ImportPath:  `"github.com/flipped-aurora/gin-vue-admin/server/model/library"`,
+		StructName:  "Book",
+		PackageName: "library",
+	}
+	author := *book
+	author.StructName = "Author"
+	for _, a := range []*PackageInitializeMongo{book, &author, book} {
+		file, err := a.Parse(a.Path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = a.Injection(file)
+		if err = a.Format(a.Path, nil, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content, _ := os.ReadFile(path)
+	if strings.Count(string(content), "library.Book{}") != 1 || !strings.Contains(string(content), "library.Author{}") {
+		t.Fatalf("注入结果不正确:\n%s", content)
+	}
+	if !strings.Contains(string(content), `"github.com/flipped-aurora/gin-vue-admin/server/model/library"`) {
+		t.Fatalf("缺少导包:\n%s", content)
+	}
+
+	for i, a := range []*PackageInitializeMongo{book, &author} {
+		file, err := a.Parse(a.Path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = a.Rollback(file)
+		if err = a.Format(a.Path, nil, file); err != nil {
+			t.Fatal(err)
+		}
+		content, _ = os.ReadFile(path)
+		if hasImport := strings.Contains(string(content), "model/library"); hasImport != (i == 0) {
+			t.Fatalf("回滚后导包不正确:\n%s", content)
+		} // 同包还有其他模型时保留导包
+	}
+	if strings.Contains(string(content), "library.") {
+		t.Fatalf("回滚结果不正确:\n%s", content)
+	}
+}
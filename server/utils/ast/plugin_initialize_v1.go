@@ -0,0 +1,49 @@
+package ast
+
+import (
+	"go/ast"
+	"io"
+)
+
+// PluginInitializeV1 v1插件注册 只支持卸载时回滚 新插件统一使用v2注册
+type PluginInitializeV1 struct {
+	Base
+	Type         Type   // 类型
+	PluginPath   string // 插件路径
+	RelativePath string // 相对路径
+	ImportPath   string // 导包路径
+	PackageName  string // 包名
+}
+
+func (a *PluginInitializeV1) Parse(filename string, writer io.Writer) (file *ast.File, err error) {
+	if filename == "" {
+		if a.RelativePath == "" {
+			filename = a.PluginPath
+			a.RelativePath = a.Base.RelativePath(a.PluginPath)
+			return a.Base.Parse(filename, writer)
+		}
+		a.PluginPath = a.Base.AbsolutePath(a.RelativePath)
+		filename = a.PluginPath
+	}
+	return a.Base.Parse(filename, writer)
+}
+
+// Rollback 移除 bizPluginV1 中 PluginInit 注册该插件的参数 并移除导包
+func (a *PluginInitializeV1) Rollback(file *ast.File) error {
+	funcDecl := FindFunction(file, "bizPluginV1")
+	if funcDecl == nil {
+		return nil
+	}
+	RollbackPluginCall(funcDecl, "PluginInit", a.PackageName)
+	if !referencePackage(file, a.PackageName) {
+		_ = NewImport(a.ImportPath).Rollback(file)
+	} // 文件中仍有其他引用时保留导包
+	return nil
+}
+
+func (a *PluginInitializeV1) Format(filename string, writer io.Writer, file *ast.File) error {
+	if filename == "" {
+		filename = a.PluginPath
+	}
+	return a.Base.Format(filename, writer, file)
+}
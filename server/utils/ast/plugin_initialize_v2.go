@@ -40,7 +40,16 @@ func (a *PluginInitializeV2) Injection(file *ast.File) error {
 	return nil
 }
 
+// Rollback 移除 bizPluginV2 中注册该插件的参数 参数全部移除后删除整条语句 并移除导包
 func (a *PluginInitializeV2) Rollback(file *ast.File) error {
+	funcDecl := FindFunction(file, "bizPluginV2")
+	if funcDecl == nil {
+		return nil
+	}
+	RollbackPluginCall(funcDecl, "PluginInitV2", a.PackageName)
+	if !referencePackage(file, a.PackageName) {
+		_ = NewImport(a.ImportPath).Rollback(file)
+	} // 文件中仍有其他引用时保留导包
 	return nil
 }
 
@@ -50,3 +59,53 @@ func (a *PluginInitializeV2) Format(filename string, writer io.Writer, file *ast
 	}
 	return a.Base.Format(filename, writer, file)
 }
+
+// RollbackPluginCall 移除 funcDecl 中 callName(group, plugins...) 调用里属于 packageName 的插件参数
+// 除第一个路由参数外没有剩余插件时删除整条语句
+func RollbackPluginCall(funcDecl *ast.FuncDecl, callName string, packageName string) {
+	list := funcDecl.Body.List[:0]
+	for _, stmt := range funcDecl.Body.List {
+		exprStmt, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			list = append(list, stmt)
+			continue
+		}
+		call, ok := exprStmt.X.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			list = append(list, stmt)
+			continue
+		}
+		if ident, o := call.Fun.(*ast.Ident); !o || ident.Name != callName {
+			list = append(list, stmt)
+			continue
+		}
+		args := call.Args[:1]
+		for _, arg := range call.Args[1:] {
+			if !referencePackage(arg, packageName) {
+				args = append(args, arg)
+			}
+		}
+		if len(args) == 1 {
+			continue
+		}
+		call.Args = args
+		list = append(list, stmt)
+	}
+	funcDecl.Body.List = list
+}
+
+// referencePackage 节点中是否引用了 packageName 包
+func referencePackage(node ast.Node, packageName string) bool {
+	var has bool
+	ast.Inspect(node, func(n ast.Node) bool {
+		selector, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return !has
+		}
+		if ident, o := selector.X.(*ast.Ident); o && ident.Name == packageName {
+			has = true
+		}
+		return !has
+	})
+	return has
+}
@@ -1,7 +1,11 @@
 package ast
 
 import (
+	"bytes"
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"path/filepath"
 	"testing"
 )
@@ -98,3 +102,91 @@ func TestPluginInitialize_Rollback(t *testing.T) {
 		})
 	}
 }
+
+func TestRollbackPluginCall(t *testing.T) {
+	src := `package initialize
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/plugin/announcement"
+	"github.com/flipped-aurora/gin-vue-admin/server/plugin/gva"
+	"github.com/gin-gonic/gin"
+)
+
+func bizPluginV2(engine *gin.Engine) {
+	PluginInitV2(engine, announcement.Plugin)
+	PluginInitV2(engine, gva.Plugin, announcement.Plugin)
+}
+`
+	want := `package initialize
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/plugin/gva"
+	"github.com/gin-gonic/gin"
+)
+
+func bizPluginV2(engine *gin.Engine) {
+	PluginInitV2(engine, gva.Plugin)
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := PluginInitializeV2{
+		Type:        TypePluginInitializeV2,
+		ImportPath:  `"github.com/flipped-aurora/gin-vue-admin/server/plugin/announcement"`,
+		PackageName: "announcement",
+	}
+	_ = a.Rollback(file)
+	var buf bytes.Buffer
+	if err = a.Format("", &buf, file); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("Rollback() got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestRollbackPluginReference(t *testing.T) {
+	src := `package initialize
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/example"
+	"github.com/flipped-aurora/gin-vue-admin/server/plugin/announcement/model"
+)
+
+func tables() []any {
+	return []any{
+		example.ExaFile{},
+		model.Info{},
+	}
+}
+`
+	want := `package initialize
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/example"
+)
+
+func tables() []any {
+	return []any{
+		example.ExaFile{},
+	}
+}
+`
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !RollbackPluginReference(file, "github.com/flipped-aurora/gin-vue-admin/server/plugin/announcement") {
+		t.Fatal("RollbackPluginReference() changed = false")
+	}
+	var buf bytes.Buffer
+	if err = format.Node(&buf, fileSet, file); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("RollbackPluginReference() got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,45 @@
+package ast
+
+import (
+	"go/ast"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// RollbackPluginReference 移除文件中对插件包及其子包的列表引用 如 []any{model.Info{}} 中的元素
+// 引用全部移除后删除对应的导包 返回是否修改了文件
+func RollbackPluginReference(file *ast.File, importPath string) bool {
+	var changed bool
+	for _, spec := range file.Imports {
+		value, _ := strconv.Unquote(spec.Path.Value)
+		if value != importPath && !strings.HasPrefix(value, importPath+"/") {
+			continue
+		}
+		name := path.Base(value)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			elts := lit.Elts[:0]
+			for _, elt := range lit.Elts {
+				if referencePackage(elt, name) {
+					changed = true
+					continue
+				}
+				elts = append(elts, elt)
+			}
+			lit.Elts = elts
+			return true
+		})
+		if !referencePackage(file, name) {
+			_ = NewImport(spec.Path.Value).Rollback(file)
+			changed = true
+		}
+	}
+	return changed
+}
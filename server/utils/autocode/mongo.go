@@ -0,0 +1,120 @@
+package autocode
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+)
+
+// mongoOperators 搜索条件对应的MongoDB查询操作符 "="直接匹配
+var mongoOperators = map[string]string{
+	"<>": "$ne",
+	">":  "$gt",
+	">=": "$gte",
+	"<":  "$lt",
+	"<=": "$lte",
+}
+
+// GenerateMongoField 渲染MongoDB Model中的字段 数据库字段名作为bson名称
+func GenerateMongoField(field systemReq.AutoCodeField) string {
+	var typ, extra string
+	switch field.FieldType {
+	case "enum", "picture", "video":
+		typ = "string"
+	case "richtext":
+		typ = "*string"
+	case "pictures":
+		typ = "[]string"
+	case "file":
+		typ = "[]map[string]any"
+		extra = ` swaggertype:"array,object"`
+	case "array":
+		typ = "[]any"
+		extra = ` swaggertype:"array,object"`
+	case "json":
+		typ = "map[string]any"
+		extra = ` swaggertype:"object"`
+	default:
+		typ = "*" + field.FieldType
+	}
+	if field.Require {
+		extra += ` binding:"required"`
+	}
+	result := fmt.Sprintf("%s  %s `json:\"%s\" form:\"%s\" bson:\"%s\"%s`",
+		field.FieldName, typ, field.FieldJson, field.FieldJson, field.ColumnName, extra)
+	if field.FieldDesc != "" {
+		result += fmt.Sprintf("  //%s", field.FieldDesc)
+	}
+	return result
+}
+
+// GenerateMongoIndex 渲染字段的索引声明 uniqueIndex为唯一索引
+func GenerateMongoIndex(field systemReq.AutoCodeField) string {
+	if field.FieldIndexType == "uniqueIndex" {
+		return fmt.Sprintf(`{Key: []string{"%s"}, IndexOptions: option.Index().SetUnique(true)}`, field.ColumnName)
+	}
+	return fmt.Sprintf(`{Key: []string{"%s"}}`, field.ColumnName)
+}
+
+// GenerateMongoSearchConditions 格式化MongoDB搜索条件语句 条件写入filter
+func GenerateMongoSearchConditions(fields []*systemReq.AutoCodeField) string {
+	var conditions []string
+	for _, field := range fields {
+		if field.FieldSearchType == "" {
+			continue
+		}
+		var condition string
+		if slices.Contains([]string{"enum", "pictures", "picture", "video", "json", "richtext", "array", "file"}, field.FieldType) {
+			if field.FieldType == "enum" {
+				condition = fmt.Sprintf(`
+    if info.%s != "" {
+        filter["%s"] = %s
+    }`, field.FieldName, field.ColumnName, mongoCondition(field.FieldSearchType, "info."+field.FieldName))
+			} else {
+				condition = fmt.Sprintf(`
+    if info.%s != "" {
+        // 数据类型为复杂类型，请根据业务需求自行实现复杂类型的查询业务
+    }`, field.FieldName)
+			}
+		} else if field.FieldSearchType == "BETWEEN" || field.FieldSearchType == "NOT BETWEEN" {
+			start, end := "*info.Start"+field.FieldName, "*info.End"+field.FieldName
+			check := fmt.Sprintf("info.Start%s != nil && info.End%s != nil", field.FieldName, field.FieldName)
+			if field.FieldType == "time.Time" {
+				start, end = fmt.Sprintf("info.%sRange[0]", field.FieldName), fmt.Sprintf("info.%sRange[1]", field.FieldName)
+				check = fmt.Sprintf("len(info.%sRange) == 2", field.FieldName)
+			}
+			between := fmt.Sprintf(`bson.M{"$gte": %s, "$lte": %s}`, start, end)
+			if field.FieldSearchType == "NOT BETWEEN" {
+				between = fmt.Sprintf(`bson.M{"$not": %s}`, between)
+			}
+			condition = fmt.Sprintf(`
+    if %s {
+        filter["%s"] = %s
+    }`, check, field.ColumnName, between)
+		} else {
+			check := "info." + field.FieldName + " != nil"
+			if field.FieldType == "string" {
+				check += fmt.Sprintf(` && *info.%s != ""`, field.FieldName)
+			}
+			condition = fmt.Sprintf(`
+    if %s {
+        filter["%s"] = %s
+    }`, check, field.ColumnName, mongoCondition(field.FieldSearchType, "*info."+field.FieldName))
+		}
+		conditions = append(conditions, condition)
+	}
+	return strings.Join(conditions, "")
+}
+
+// mongoCondition 单个值的查询条件 LIKE转为转义后的正则
+func mongoCondition(searchType string, value string) string {
+	if searchType == "LIKE" {
+		return fmt.Sprintf(`bson.M{"$regex": regexp.QuoteMeta(%s)}`, value)
+	}
+	if operator, ok := mongoOperators[searchType]; ok {
+		return fmt.Sprintf(`bson.M{"%s": %s}`, operator, value)
+	}
+	return value
+}
@@ -1,9 +1,12 @@
 package autocode
 
 import (
+	"errors"
 	"fmt"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -11,18 +14,46 @@ import (
 // GetTemplateFuncMap 返回模板函数映射，用于在模板中使用
 func GetTemplateFuncMap() template.FuncMap {
 	return template.FuncMap{
-		"title":                    strings.Title,
-		"GenerateField":            GenerateField,
-		"GenerateSearchField":      GenerateSearchField,
-		"GenerateSearchConditions": GenerateSearchConditions,
-		"GenerateSearchFormItem":   GenerateSearchFormItem,
-		"GenerateTableColumn":      GenerateTableColumn,
-		"GenerateFormItem":         GenerateFormItem,
-		"GenerateDescriptionItem":  GenerateDescriptionItem,
-		"GenerateDefaultFormValue": GenerateDefaultFormValue,
+		"title":                         strings.Title,
+		"GenerateField":                 GenerateField,
+		"GenerateRelationField":         GenerateRelationField,
+		"GenerateSearchField":           GenerateSearchField,
+		"GenerateSearchConditions":      GenerateSearchConditions,
+		"GenerateSearchFormItem":        GenerateSearchFormItem,
+		"GenerateTableColumn":           GenerateTableColumn,
+		"GenerateFormItem":              GenerateFormItem,
+		"GenerateDescriptionItem":       GenerateDescriptionItem,
+		"GenerateDefaultFormValue":      GenerateDefaultFormValue,
+		"GenerateTestValue":             GenerateTestValue,
+		"GenerateTestPrimaryKey":        GenerateTestPrimaryKey,
+		"GenerateTsType":                GenerateTsType,
+		"GenerateTsField":               GenerateTsField,
+		"GenerateTsSearchField":         GenerateTsSearchField,
+		"GenerateTsRelationField":       GenerateTsRelationField,
+		"GenerateMongoField":            GenerateMongoField,
+		"GenerateMongoIndex":            GenerateMongoIndex,
+		"GenerateMongoSearchConditions": GenerateMongoSearchConditions,
+		"coreVersion":                   func() string { return global.GVA_VERSION },
+		"dict":                          dict,
 	}
 }
 
+// dict 将键值对组成map 用于向 {{template}} 传入多个参数
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict 参数必须成对出现")
+	}
+	m := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict 的键必须是字符串: %v", pairs[i])
+		}
+		m[key] = pairs[i+1]
+	}
+	return m, nil
+}
+
 // 渲染Model中的字段
 func GenerateField(field systemReq.AutoCodeField) string {
 	// 构建gorm标签
@@ -101,6 +132,39 @@ func GenerateField(field systemReq.AutoCodeField) string {
 	return result
 }
 
+// 渲染Model中的关联字段 pkg为当前模块所在包 关联模型在其他包时需要带包名
+func GenerateRelationField(relation systemReq.AutoCodeRelation, pkg string) string {
+	typ := relation.StructName
+	if relation.Package != "" && relation.Package != pkg {
+		typ = relation.Package + "." + typ
+	}
+	var gormTag []string
+	switch relation.Type {
+	case systemReq.RelationBelongsTo:
+		typ = "*" + typ
+		gormTag = append(gormTag, "foreignKey:"+relation.ForeignKey)
+	case systemReq.RelationHasMany:
+		typ = "[]" + typ
+		gormTag = append(gormTag, "foreignKey:"+relation.ForeignKey)
+	case systemReq.RelationMany2Many:
+		typ = "[]" + typ
+		gormTag = append(gormTag, "many2many:"+relation.JoinTable, "foreignKey:"+relation.ForeignKey, "joinForeignKey:"+relation.JoinForeignKey)
+	}
+	if relation.References != "" {
+		gormTag = append(gormTag, "references:"+relation.References)
+	}
+	if relation.Type == systemReq.RelationMany2Many {
+		gormTag = append(gormTag, "joinReferences:"+relation.JoinReferences)
+	}
+
+	result := fmt.Sprintf(`%s  %s `+"`"+`json:"%s,omitempty" form:"-" gorm:"%s"`+"`",
+		relation.FieldName, typ, relation.FieldJson, strings.Join(gormTag, ";"))
+	if relation.FieldDesc != "" {
+		result += fmt.Sprintf("  //%s", relation.FieldDesc)
+	}
+	return result
+}
+
 // 格式化搜索条件语句
 func GenerateSearchConditions(fields []*systemReq.AutoCodeField) string {
 	var conditions []string
@@ -701,3 +765,43 @@ func GenerateSearchField(field systemReq.AutoCodeField) string {
 
 	return result
 }
+
+// GenerateTestValue 生成测试数据中字段的取值表达式
+// 表达式中的 n 为测试代码中数据的序号 ptr 为测试代码中取指针的辅助函数名
+func GenerateTestValue(field systemReq.AutoCodeField, ptr string) string {
+	switch field.FieldType {
+	case "enum":
+		values := strings.Split(field.DataTypeLong, ",")
+		for i := range values {
+			values[i] = strconv.Quote(strings.Trim(strings.TrimSpace(values[i]), `'"`))
+		}
+		return fmt.Sprintf("[]string{%s}[n%%%d]", strings.Join(values, ", "), len(values)) // 按序号轮流取合法的枚举值
+	case "picture", "video":
+		return fmt.Sprintf(`fmt.Sprintf("uploads/file/%s_%%d", n)`, field.FieldJson)
+	case "file", "pictures", "array":
+		return `datatypes.JSON("[]")`
+	case "json":
+		return `datatypes.JSON("{}")`
+	case "string", "richtext":
+		return fmt.Sprintf(`%s(fmt.Sprintf("%s_%%d", n))`, ptr, field.FieldJson)
+	case "int":
+		return ptr + "(n)"
+	case "float64":
+		return ptr + "(float64(n) + 0.5)"
+	case "bool":
+		return ptr + "(n%2 == 1)"
+	case "time.Time":
+		return ptr + "(time.Now().Add(time.Duration(n) * time.Hour).Truncate(time.Second))"
+	default:
+		return fmt.Sprintf("new(%s)", field.FieldType)
+	}
+}
+
+// GenerateTestPrimaryKey 生成测试代码中读取自定义主键的表达式 与 GenerateField 保持一致 基础类型字段为指针
+func GenerateTestPrimaryKey(field systemReq.AutoCodeField, variable string) string {
+	switch field.FieldType {
+	case "enum", "picture", "video", "file", "pictures", "array", "json":
+		return variable + "." + field.FieldName
+	}
+	return "*" + variable + "." + field.FieldName
+}
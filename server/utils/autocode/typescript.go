@@ -0,0 +1,89 @@
+package autocode
+
+import (
+	"fmt"
+	"strings"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+)
+
+// GenerateTsType 将字段类型转换为TypeScript类型 与 GenerateField 生成的Go类型的json序列化结果保持一致
+func GenerateTsType(field systemReq.AutoCodeField) string {
+	switch field.FieldType {
+	case "string", "richtext", "picture", "video", "time.Time":
+		return "string"
+	case "enum":
+		var values []string
+		for _, value := range strings.Split(field.DataTypeLong, ",") {
+			value = strings.Trim(strings.TrimSpace(value), `'"`)
+			if value != "" {
+				values = append(values, fmt.Sprintf("'%s'", value))
+			}
+		}
+		if len(values) == 0 {
+			return "string"
+		}
+		return strings.Join(values, " | ")
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return "number"
+	case "pictures":
+		return "string[]"
+	case "file":
+		return "Array<{ name: string, url: string }>"
+	case "array":
+		return "any[]"
+	case "json":
+		return "Record<string, any>"
+	default:
+		return "any"
+	}
+}
+
+// GenerateTsField 渲染TypeScript接口中的字段 必填字段不可省略
+func GenerateTsField(field systemReq.AutoCodeField) string {
+	optional := "?"
+	if field.Require {
+		optional = ""
+	}
+	result := fmt.Sprintf("%s%s: %s", field.FieldJson, optional, GenerateTsType(field))
+	if field.FieldDesc != "" {
+		result += " // " + field.FieldDesc
+	}
+	return result
+}
+
+// GenerateTsSearchField 渲染TypeScript搜索接口中的字段 与 GenerateSearchField 保持一致
+func GenerateTsSearchField(field systemReq.AutoCodeField) string {
+	if field.FieldSearchType == "" {
+		return ""
+	}
+	if field.FieldSearchType == "BETWEEN" || field.FieldSearchType == "NOT BETWEEN" {
+		if field.FieldType == "time.Time" {
+			return fmt.Sprintf("%sRange?: string[]", field.FieldJson)
+		}
+		typ := GenerateTsType(field)
+		return fmt.Sprintf("start%s?: %s\n  end%s?: %s", field.FieldName, typ, field.FieldName, typ)
+	}
+	switch field.FieldType {
+	case "enum", "picture", "pictures", "video", "json", "richtext", "array":
+		return fmt.Sprintf("%s?: string", field.FieldJson)
+	}
+	return fmt.Sprintf("%s?: %s", field.FieldJson, GenerateTsType(field))
+}
+
+// GenerateTsRelationField 渲染TypeScript接口中的关联字段 关联模型可能位于其他包 使用宽松的对象类型
+func GenerateTsRelationField(relation systemReq.AutoCodeRelation) string {
+	typ := "Record<string, any>"
+	if relation.Type != systemReq.RelationBelongsTo {
+		typ += "[]"
+	}
+	result := fmt.Sprintf("%s?: %s", relation.FieldJson, typ)
+	if relation.FieldDesc != "" {
+		result += " // " + relation.FieldDesc
+	} else if relation.StructName != "" {
+		result += " // " + relation.StructName
+	}
+	return result
+}
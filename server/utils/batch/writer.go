@@ -0,0 +1,173 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// 队列满时的溢出策略
+const (
+	PolicyBlock      = "block"       // 阻塞调用方直到队列有空位
+	PolicyDropOldest = "drop-oldest" // 丢弃队列中最旧的一条
+	PolicySpill      = "spill"       // 溢出写入本地文件
+)
+
+var ErrClosed = errors.New("batch writer closed")
+
+// Options 批量写入器配置
+type Options[T any] struct {
+	QueueSize     int           // 队列容量
+	BatchSize     int           // 单批最大条数
+	FlushInterval time.Duration // 最长刷写间隔
+	Policy        string        // 溢出策略 block|drop-oldest|spill
+
+	// Flush 批量落库 返回错误时该批次交给 Spill 处理(若配置)
+	Flush func(items []T) error
+	// Spill 溢出/刷写失败时的兜底写入 为空时直接丢弃
+	Spill func(items []T) error
+	// OnDrop 记录被丢弃时回调 reason: overflow|flush|closed
+	OnDrop func(reason string, n int)
+}
+
+// Writer 有界队列 + 批量刷写 按条数或时间间隔触发
+type Writer[T any] struct {
+	opts   Options[T]
+	queue  chan T
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// NewWriter 创建并启动批量写入器
+func NewWriter[T any](opts Options[T]) *Writer[T] {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.Policy == "" {
+		opts.Policy = PolicyBlock
+	}
+	w := &Writer[T]{
+		opts:  opts,
+		queue: make(chan T, opts.QueueSize),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Len 当前队列深度
+func (w *Writer[T]) Len() int {
+	return len(w.queue)
+}
+
+// Write 入队 根据溢出策略处理队列已满的情况
+func (w *Writer[T]) Write(item T) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		w.drop("closed", 1)
+		return ErrClosed
+	}
+	select {
+	case w.queue <- item:
+		return nil
+	default:
+	}
+	switch w.opts.Policy {
+	case PolicyDropOldest:
+		for {
+			select {
+			case w.queue <- item:
+				return nil
+			default:
+			}
+			select {
+			case <-w.queue:
+				w.drop("overflow", 1)
+			default:
+			}
+		}
+	case PolicySpill:
+		if w.opts.Spill == nil {
+			w.drop("overflow", 1)
+			return nil
+		}
+		if err := w.opts.Spill([]T{item}); err != nil {
+			w.drop("overflow", 1)
+			return err
+		}
+		return nil
+	default:
+		w.queue <- item
+		return nil
+	}
+}
+
+// Close 停止接收新数据 刷写队列中剩余数据 直到完成或ctx超时
+func (w *Writer[T]) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Writer[T]) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+	buf := make([]T, 0, w.opts.BatchSize)
+	for {
+		select {
+		case item, ok := <-w.queue:
+			if !ok {
+				w.flush(buf)
+				return
+			}
+			buf = append(buf, item)
+			if len(buf) >= w.opts.BatchSize {
+				w.flush(buf)
+				buf = make([]T, 0, w.opts.BatchSize)
+			}
+		case <-ticker.C:
+			if len(buf) > 0 {
+				w.flush(buf)
+				buf = make([]T, 0, w.opts.BatchSize)
+			}
+		}
+	}
+}
+
+func (w *Writer[T]) flush(items []T) {
+	if len(items) == 0 {
+		return
+	}
+	if err := w.opts.Flush(items); err == nil {
+		return
+	}
+	if w.opts.Spill != nil && w.opts.Spill(items) == nil {
+		return
+	}
+	w.drop("flush", len(items))
+}
+
+func (w *Writer[T]) drop(reason string, n int) {
+	if w.opts.OnDrop != nil {
+		w.opts.OnDrop(reason, n)
+	}
+}
@@ -0,0 +1,140 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sink struct {
+	sync.Mutex
+	batches [][]int
+	spilled []int
+	dropped map[string]int
+}
+
+func (s *sink) total() int {
+	s.Lock()
+	defer s.Unlock()
+	n := 0
+	for _, b := range s.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func newSink() *sink {
+	return &sink{dropped: map[string]int{}}
+}
+
+func (s *sink) options(policy string, queueSize, batchSize int, interval time.Duration) Options[int] {
+	return Options[int]{
+		QueueSize:     queueSize,
+		BatchSize:     batchSize,
+		FlushInterval: interval,
+		Policy:        policy,
+		Flush: func(items []int) error {
+			s.Lock()
+			defer s.Unlock()
+			s.batches = append(s.batches, append([]int(nil), items...))
+			return nil
+		},
+		Spill: func(items []int) error {
+			s.Lock()
+			defer s.Unlock()
+			s.spilled = append(s.spilled, items...)
+			return nil
+		},
+		OnDrop: func(reason string, n int) {
+			s.Lock()
+			defer s.Unlock()
+			s.dropped[reason] += n
+		},
+	}
+}
+
+func TestWriter_FlushBySize(t *testing.T) {
+	s := newSink()
+	w := NewWriter(s.options(PolicyBlock, 100, 10, time.Hour))
+	for i := 0; i < 25; i++ {
+		assert.Nil(t, w.Write(i))
+	}
+	assert.Eventually(t, func() bool { return s.total() == 20 }, time.Second, 5*time.Millisecond)
+	assert.Nil(t, w.Close(context.Background()))
+	assert.Equal(t, 25, s.total())
+	assert.Len(t, s.batches, 3)
+}
+
+func TestWriter_FlushByInterval(t *testing.T) {
+	s := newSink()
+	w := NewWriter(s.options(PolicyBlock, 100, 50, 10*time.Millisecond))
+	defer w.Close(context.Background())
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, w.Write(i))
+	}
+	assert.Eventually(t, func() bool { return s.total() == 3 }, time.Second, 5*time.Millisecond)
+}
+
+func TestWriter_Overflow(t *testing.T) {
+	block := make(chan struct{})
+	tests := []struct {
+		name    string
+		policy  string
+		spilled int
+		dropped int
+	}{
+		{name: "drop-oldest", policy: PolicyDropOldest, dropped: 3},
+		{name: "spill", policy: PolicySpill, spilled: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSink()
+			opts := s.options(tt.policy, 2, 1, time.Hour)
+			flush := opts.Flush
+			started := make(chan struct{}, 1)
+			opts.Flush = func(items []int) error {
+				select {
+				case started <- struct{}{}:
+					<-block
+				default:
+				}
+				return flush(items)
+			}
+			w := NewWriter(opts)
+			// 第一条被消费者取走并阻塞在 Flush 中
+			assert.Nil(t, w.Write(0))
+			<-started
+			for i := 1; i <= 5; i++ {
+				assert.Nil(t, w.Write(i))
+			}
+			s.Lock()
+			assert.Equal(t, tt.spilled, len(s.spilled))
+			assert.Equal(t, tt.dropped, s.dropped["overflow"])
+			s.Unlock()
+		})
+	}
+	close(block)
+}
+
+func TestWriter_FlushErrorSpills(t *testing.T) {
+	s := newSink()
+	opts := s.options(PolicyBlock, 10, 2, time.Hour)
+	opts.Flush = func(items []int) error { return errors.New("db down") }
+	w := NewWriter(opts)
+	assert.Nil(t, w.Write(1))
+	assert.Nil(t, w.Write(2))
+	assert.Nil(t, w.Close(context.Background()))
+	assert.Equal(t, []int{1, 2}, s.spilled)
+}
+
+func TestWriter_WriteAfterClose(t *testing.T) {
+	s := newSink()
+	w := NewWriter(s.options(PolicyBlock, 10, 2, time.Hour))
+	assert.Nil(t, w.Close(context.Background()))
+	assert.ErrorIs(t, w.Write(1), ErrClosed)
+	assert.Equal(t, 1, s.dropped["closed"])
+}
@@ -142,6 +142,7 @@ func LoginToken(user system.Login) (token string, claims systemReq.CustomClaims,
 		NickName:    user.GetNickname(),
 		Username:    user.GetUsername(),
 		AuthorityId: user.GetAuthorityId(),
+		Tenant:      user.GetTenant(),
 	})
 	token, err = j.CreateToken(claims)
 	return
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/featureflag"
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSubject 从登录信息中获取功能开关的求值对象 租户取自令牌签发时的用户资料 未登录时为空对象
+func FeatureSubject(c *gin.Context) featureflag.Subject {
+	var subject featureflag.Subject
+	if claims := GetUserInfo(c); claims != nil {
+		subject.UserID = claims.BaseClaims.ID
+		subject.AuthorityID = claims.AuthorityId
+		subject.Tenant = claims.Tenant
+	}
+	return subject
+}
@@ -0,0 +1,203 @@
+// Package featureflag 功能开关的规则求值 不依赖存储 便于在服务、中间件和测试中复用
+package featureflag
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+)
+
+const (
+	TypeBoolean = "boolean"
+	TypeVariant = "variant"
+
+	VariantOn  = "on"
+	VariantOff = "off"
+)
+
+var keyPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.:-]{0,99}$`)
+
+// Variant 多值开关的取值及其权重
+type Variant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"` // 未被规则指定取值时 按权重分配
+}
+
+// Rule 定向规则 已设置的条件需要同时满足 未设置的条件不参与判断
+type Rule struct {
+	Name         string   `json:"name"`
+	AuthorityIDs []uint   `json:"authorityIds"` // 角色ID
+	UserIDs      []uint   `json:"userIds"`      // 用户ID
+	Tenants      []string `json:"tenants"`      // 租户
+	Percentage   *int     `json:"percentage"`   // 按用户哈希放量的百分比 0-100 为空表示全部
+	Variant      string   `json:"variant"`      // 命中后的取值 为空时按权重分配
+}
+
+// Flag 功能开关
+type Flag struct {
+	Key            string    `json:"key"`
+	Type           string    `json:"type"`
+	Enabled        bool      `json:"enabled"`        // 总开关 关闭时所有人都不命中
+	DefaultVariant string    `json:"defaultVariant"` // 未命中时的取值 仅多值开关使用
+	Variants       []Variant `json:"variants"`
+	Rules          []Rule    `json:"rules"` // 按顺序匹配 没有规则时对所有人开启
+}
+
+// Subject 求值对象
+type Subject struct {
+	UserID      uint
+	AuthorityID uint
+	Tenant      string
+}
+
+// stickyKey 百分比放量的分桶依据 同一用户始终落在同一个桶
+func (s Subject) stickyKey() string {
+	if s.UserID != 0 {
+		return "u:" + strconv.FormatUint(uint64(s.UserID), 10)
+	}
+	return "t:" + s.Tenant
+}
+
+// Result 求值结果
+type Result struct {
+	Key     string `json:"key"`
+	Enabled bool   `json:"enabled"`
+	Variant string `json:"variant"`
+	Reason  string `json:"reason"` // disabled / default / rule:<名称或序号> / no_match
+}
+
+// Validate 检查开关定义
+func (f Flag) Validate() error {
+	if !keyPattern.MatchString(f.Key) {
+		return errors.New("开关键只能包含字母、数字和 _ . : - 且以字母开头")
+	}
+	variants := map[string]bool{}
+	switch f.Type {
+	case TypeBoolean:
+		if len(f.Variants) > 0 {
+			return errors.New("布尔开关不能设置取值")
+		}
+	case TypeVariant:
+		if len(f.Variants) == 0 {
+			return errors.New("多值开关至少需要一个取值")
+		}
+		for _, v := range f.Variants {
+			if v.Name == "" || variants[v.Name] {
+				return fmt.Errorf("取值名称为空或重复: %q", v.Name)
+			}
+			if v.Weight < 0 {
+				return fmt.Errorf("取值 %s 的权重不能为负数", v.Name)
+			}
+			variants[v.Name] = true
+		}
+		if f.DefaultVariant != "" && !variants[f.DefaultVariant] {
+			return fmt.Errorf("默认取值 %s 不存在", f.DefaultVariant)
+		}
+	default:
+		return fmt.Errorf("未知的开关类型 %s", f.Type)
+	}
+	for i, r := range f.Rules {
+		if r.Percentage != nil && (*r.Percentage < 0 || *r.Percentage > 100) {
+			return fmt.Errorf("规则 %d 的百分比必须在 0-100 之间", i+1)
+		}
+		if r.Variant != "" && (f.Type == TypeBoolean || !variants[r.Variant]) {
+			return fmt.Errorf("规则 %d 的取值 %s 不存在", i+1, r.Variant)
+		}
+	}
+	return nil
+}
+
+// Evaluate 对求值对象计算开关结果
+func (f Flag) Evaluate(s Subject) Result {
+	off := Result{Key: f.Key, Variant: f.offVariant()}
+	if !f.Enabled {
+		off.Reason = "disabled"
+		return off
+	}
+	if len(f.Rules) == 0 {
+		return Result{Key: f.Key, Enabled: true, Variant: f.pickVariant(s, ""), Reason: "default"}
+	}
+	for i, r := range f.Rules {
+		if r.matches(f.Key, s) {
+			name := r.Name
+			if name == "" {
+				name = strconv.Itoa(i + 1)
+			}
+			return Result{Key: f.Key, Enabled: true, Variant: f.pickVariant(s, r.Variant), Reason: "rule:" + name}
+		}
+	}
+	off.Reason = "no_match"
+	return off
+}
+
+func (f Flag) offVariant() string {
+	if f.Type == TypeVariant {
+		return f.DefaultVariant
+	}
+	return VariantOff
+}
+
+func (f Flag) pickVariant(s Subject, preferred string) string {
+	if f.Type != TypeVariant {
+		return VariantOn
+	}
+	if preferred != "" {
+		return preferred
+	}
+	total := 0
+	for _, v := range f.Variants {
+		total += v.Weight
+	}
+	if total == 0 {
+		if f.DefaultVariant != "" {
+			return f.DefaultVariant
+		}
+		return f.Variants[0].Name
+	}
+	bucket := int(hash(f.Key+":variant:"+s.stickyKey()) % uint32(total))
+	for _, v := range f.Variants {
+		if bucket < v.Weight {
+			return v.Name
+		}
+		bucket -= v.Weight
+	}
+	return f.Variants[len(f.Variants)-1].Name
+}
+
+func (r Rule) matches(key string, s Subject) bool {
+	if len(r.AuthorityIDs) > 0 && !contains(r.AuthorityIDs, s.AuthorityID) {
+		return false
+	}
+	if len(r.UserIDs) > 0 && !contains(r.UserIDs, s.UserID) {
+		return false
+	}
+	if len(r.Tenants) > 0 && !contains(r.Tenants, s.Tenant) {
+		return false
+	}
+	if r.Percentage != nil {
+		return Bucket(key, s) < *r.Percentage
+	}
+	return true
+}
+
+// Bucket 返回求值对象在某个开关上的分桶 0-99
+func Bucket(key string, s Subject) int {
+	return int(hash(key+":"+s.stickyKey()) % 100)
+}
+
+func hash(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func contains[T comparable](list []T, v T) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
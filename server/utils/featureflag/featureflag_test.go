@@ -0,0 +1,78 @@
+package featureflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestFlag_Evaluate(t *testing.T) {
+	f := Flag{Key: "new-dashboard", Type: TypeBoolean, Enabled: true, Rules: []Rule{
+		{Name: "admins", AuthorityIDs: []uint{888}},
+		{Name: "tenant-beta", Tenants: []string{"beta"}, Percentage: intPtr(100)},
+		{UserIDs: []uint{42}},
+	}}
+	assert.Nil(t, f.Validate())
+
+	assert.Equal(t, Result{Key: "new-dashboard", Enabled: true, Variant: VariantOn, Reason: "rule:admins"}, f.Evaluate(Subject{UserID: 1, AuthorityID: 888}))
+	assert.Equal(t, "rule:tenant-beta", f.Evaluate(Subject{UserID: 2, Tenant: "beta"}).Reason)
+	assert.Equal(t, "rule:3", f.Evaluate(Subject{UserID: 42}).Reason)
+	assert.Equal(t, Result{Key: "new-dashboard", Variant: VariantOff, Reason: "no_match"}, f.Evaluate(Subject{UserID: 2}))
+
+	f.Enabled = false
+	assert.Equal(t, "disabled", f.Evaluate(Subject{AuthorityID: 888}).Reason)
+
+	f = Flag{Key: "everyone", Type: TypeBoolean, Enabled: true}
+	assert.True(t, f.Evaluate(Subject{}).Enabled)
+}
+
+func TestFlag_Percentage(t *testing.T) {
+	f := Flag{Key: "rollout", Type: TypeBoolean, Enabled: true, Rules: []Rule{{Percentage: intPtr(30)}}}
+	on := 0
+	for id := uint(1); id <= 2000; id++ {
+		s := Subject{UserID: id}
+		r := f.Evaluate(s)
+		assert.Equal(t, r, f.Evaluate(s)) // 同一用户结果稳定
+		assert.Equal(t, Bucket("rollout", s) < 30, r.Enabled)
+		if r.Enabled {
+			on++
+		}
+	}
+	assert.InDelta(t, 600, on, 100)
+}
+
+func TestFlag_Variants(t *testing.T) {
+	f := Flag{Key: "checkout", Type: TypeVariant, Enabled: true, DefaultVariant: "control",
+		Variants: []Variant{{Name: "control", Weight: 50}, {Name: "treatment", Weight: 50}},
+		Rules:    []Rule{{AuthorityIDs: []uint{888}, Variant: "treatment"}, {Percentage: intPtr(100)}},
+	}
+	assert.Nil(t, f.Validate())
+	assert.Equal(t, "treatment", f.Evaluate(Subject{UserID: 1, AuthorityID: 888}).Variant)
+	seen := map[string]int{}
+	for id := uint(1); id <= 1000; id++ {
+		seen[f.Evaluate(Subject{UserID: id}).Variant]++
+	}
+	assert.InDelta(t, 500, seen["control"], 100)
+	assert.InDelta(t, 500, seen["treatment"], 100)
+
+	f.Enabled = false
+	assert.Equal(t, "control", f.Evaluate(Subject{UserID: 1}).Variant)
+}
+
+func TestFlag_Validate(t *testing.T) {
+	invalid := []Flag{
+		{Key: "1bad", Type: TypeBoolean},
+		{Key: "a", Type: "percent"},
+		{Key: "a", Type: TypeBoolean, Variants: []Variant{{Name: "x"}}},
+		{Key: "a", Type: TypeVariant},
+		{Key: "a", Type: TypeVariant, Variants: []Variant{{Name: "x"}, {Name: "x"}}},
+		{Key: "a", Type: TypeVariant, Variants: []Variant{{Name: "x"}}, DefaultVariant: "y"},
+		{Key: "a", Type: TypeBoolean, Rules: []Rule{{Percentage: intPtr(101)}}},
+		{Key: "a", Type: TypeBoolean, Rules: []Rule{{Variant: "on"}}},
+	}
+	for _, f := range invalid {
+		assert.NotNil(t, f.Validate(), f)
+	}
+}
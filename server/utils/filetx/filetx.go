@@ -0,0 +1,203 @@
+// Package filetx 文件事务 第一次修改文件前记录原始内容 出错时将涉及的文件全部恢复
+package filetx
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type original struct {
+	exists  bool
+	content []byte
+	mode    os.FileMode
+}
+
+// Tx 文件事务 非并发安全
+type Tx struct {
+	originals map[string]original
+	order     []string
+	dirs      []string // 事务中新建的目录
+}
+
+func Begin() *Tx {
+	return &Tx{originals: make(map[string]original)}
+}
+
+// Snapshot 记录文件修改前的内容 同一文件只记录第一次 文件不存在时回滚会删除该文件
+func (tx *Tx) Snapshot(path string) error {
+	if _, ok := tx.originals[path]; ok {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		tx.remember(path, original{})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("[filepath:%s]不是文件", path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tx.remember(path, original{exists: true, content: content, mode: info.Mode().Perm()})
+	return nil
+}
+
+// WriteFile 记录快照后原子地写入文件 先写入同目录下的临时文件再重命名 已存在的文件保留原有权限
+func (tx *Tx) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := tx.Snapshot(path); err != nil {
+		return err
+	}
+	if err := tx.mkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if o := tx.originals[path]; o.exists {
+		perm = o.mode
+	}
+	return writeAtomic(path, data, perm)
+}
+
+// RemoveAll 记录目录下全部文件的快照后删除目录 回滚时按快照重建文件 空目录不会恢复
+func (tx *Tx) RemoveAll(path string) error {
+	err := filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		return tx.Snapshot(path)
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// Original 获取文件在事务中第一次修改前的内容 ok为false表示事务未涉及该文件
+func (tx *Tx) Original(path string) (content []byte, exists bool, ok bool) {
+	o, ok := tx.originals[path]
+	return o.content, o.exists, ok
+}
+
+// Files 事务中修改过的文件 按第一次修改的顺序
+func (tx *Tx) Files() []string {
+	return append([]string(nil), tx.order...)
+}
+
+// Validate 使用go/parser校验事务中写入的go文件
+func (tx *Tx) Validate() error {
+	for _, path := range tx.order {
+		if filepath.Ext(path) != ".go" {
+			continue
+		}
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.AllErrors); err != nil {
+			return fmt.Errorf("[filepath:%s]语法校验失败: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Rollback 按修改的相反顺序恢复全部文件 事务中新建的文件和空目录会被删除
+func (tx *Tx) Rollback() error {
+	var errs []error
+	for i := len(tx.order) - 1; i >= 0; i-- {
+		path := tx.order[i]
+		o := tx.originals[path]
+		if o.exists {
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				errs = append(errs, fmt.Errorf("[filepath:%s]恢复失败: %w", path, err))
+				continue
+			} // 所在目录可能已被 RemoveAll 删除
+			if err := writeAtomic(path, o.content, o.mode); err != nil {
+				errs = append(errs, fmt.Errorf("[filepath:%s]恢复失败: %w", path, err))
+			}
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("[filepath:%s]删除失败: %w", path, err))
+		}
+	}
+	sort.Slice(tx.dirs, func(i, j int) bool {
+		return len(tx.dirs[i]) > len(tx.dirs[j])
+	}) // 先删除更深的目录
+	for _, dir := range tx.dirs {
+		_ = os.Remove(dir) // 目录中还有其他文件时保留
+	}
+	tx.reset()
+	return errors.Join(errs...)
+}
+
+// Commit 提交事务 丢弃快照
+func (tx *Tx) Commit() {
+	tx.reset()
+}
+
+func (tx *Tx) remember(path string, o original) {
+	tx.originals[path] = o
+	tx.order = append(tx.order, path)
+}
+
+func (tx *Tx) reset() {
+	tx.originals = make(map[string]original)
+	tx.order = nil
+	tx.dirs = nil
+}
+
+func (tx *Tx) mkdirAll(dir string) error {
+	var created []string
+	for d := dir; ; d = filepath.Dir(d) {
+		_, err := os.Stat(d)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		created = append(created, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if len(created) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	tx.dirs = append(tx.dirs, created...)
+	return nil
+}
+
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	name := temp.Name()
+	defer os.Remove(name) // 重命名成功后临时文件已不存在
+	if _, err = temp.Write(data); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err = temp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(name, perm); err != nil {
+		return err
+	}
+	return os.Rename(name, path)
+}
@@ -0,0 +1,87 @@
+package filetx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_Rollback(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "enter.go")
+	assert.NoError(t, os.WriteFile(existing, []byte("package demo\n"), 0640))
+	created := filepath.Join(root, "service", "demo", "demo.go")
+
+	tx := Begin()
+	assert.NoError(t, tx.WriteFile(existing, []byte("package demo\n\nvar A = 1\n"), 0644))
+	assert.NoError(t, tx.WriteFile(existing, []byte("package demo\n\nvar A = 2\n"), 0644))
+	assert.NoError(t, tx.WriteFile(created, []byte("package demo\n"), 0644))
+	assert.Equal(t, []string{existing, created}, tx.Files())
+
+	content, exists, ok := tx.Original(existing)
+	assert.True(t, ok)
+	assert.True(t, exists)
+	assert.Equal(t, "package demo\n", string(content))
+	info, _ := os.Stat(existing)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm()) // 保留原有权限
+
+	assert.NoError(t, tx.Rollback())
+	content, _ = os.ReadFile(existing)
+	assert.Equal(t, "package demo\n", string(content))
+	_, err := os.Stat(created)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(root, "service"))
+	assert.True(t, os.IsNotExist(err)) // 新建的目录一并删除
+	entries, _ := os.ReadDir(root)
+	assert.Len(t, entries, 1) // 没有遗留临时文件
+}
+
+func TestTx_Validate(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{name: "合法的go文件", file: "ok.go", content: "package demo\n\nfunc A() {}\n"},
+		{name: "语法错误", file: "broken.go", content: "package demo\n\nfunc A() {\n", wantErr: true},
+		{name: "非go文件不校验", file: "index.vue", content: "<template>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := Begin()
+			assert.NoError(t, tx.WriteFile(filepath.Join(root, tt.file), []byte(tt.content), 0644))
+			if tt.wantErr {
+				assert.Error(t, tx.Validate())
+			} else {
+				assert.NoError(t, tx.Validate())
+			}
+			tx.Commit()
+			assert.Empty(t, tx.Files())
+		})
+	}
+}
+
+func TestTx_RemoveAll(t *testing.T) {
+	root := t.TempDir()
+	plugin := filepath.Join(root, "plugin", "demo")
+	file := filepath.Join(plugin, "api", "demo.go")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(file), os.ModePerm))
+	assert.NoError(t, os.WriteFile(file, []byte("package api\n"), 0640))
+
+	tx := Begin()
+	assert.NoError(t, tx.RemoveAll(plugin))
+	assert.NoError(t, tx.RemoveAll(filepath.Join(root, "missing"))) // 不存在的目录直接忽略
+	_, err := os.Stat(plugin)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, tx.Rollback())
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "package api\n", string(content))
+	info, _ := os.Stat(file)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+}
@@ -0,0 +1,124 @@
+package hashchain
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// 导出归档为 JSON Lines 格式:
+// 第一行 header, 随后若干 checkpoint 行, 最后按ID升序的 row 行
+const (
+	LineHeader     = "header"
+	LineCheckpoint = "checkpoint"
+	LineRow        = "row"
+)
+
+type ArchiveHeader struct {
+	Table      string `json:"table"`
+	PublicKey  string `json:"public_key"`
+	ExportedAt string `json:"exported_at"`
+}
+
+type archiveLine struct {
+	Type       string         `json:"type"`
+	Header     *ArchiveHeader `json:"header,omitempty"`
+	Checkpoint *Checkpoint    `json:"checkpoint,omitempty"`
+	Row        *Record        `json:"row,omitempty"`
+}
+
+// ArchiveWriter 写出可离线校验的归档
+type ArchiveWriter struct {
+	enc *json.Encoder
+}
+
+func NewArchiveWriter(w io.Writer) *ArchiveWriter {
+	return &ArchiveWriter{enc: json.NewEncoder(w)}
+}
+
+func (a *ArchiveWriter) WriteHeader(h ArchiveHeader) error {
+	return a.enc.Encode(archiveLine{Type: LineHeader, Header: &h})
+}
+
+func (a *ArchiveWriter) WriteCheckpoint(c Checkpoint) error {
+	return a.enc.Encode(archiveLine{Type: LineCheckpoint, Checkpoint: &c})
+}
+
+func (a *ArchiveWriter) WriteRow(r Record) error {
+	return a.enc.Encode(archiveLine{Type: LineRow, Row: &r})
+}
+
+// VerifyResult 校验结果
+type VerifyResult struct {
+	Table       string  `json:"table"`
+	Valid       bool    `json:"valid"`
+	Checked     int64   `json:"checked"`
+	FirstID     uint    `json:"first_id"`
+	LastID      uint    `json:"last_id"`
+	Checkpoints int     `json:"checkpoints"`
+	Broken      *Broken `json:"broken,omitempty"`
+}
+
+// VerifyArchive 使用受信任的公钥离线校验导出的归档
+// 归档头中的公钥可能与归档一同被替换 仅供参考 不用于校验
+func VerifyArchive(r io.Reader, pub ed25519.PublicKey) (result VerifyResult, err error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return result, errors.New("未指定受信任的检查点签名公钥")
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	var (
+		header      *ArchiveHeader
+		checkpoints []Checkpoint
+		verifier    *Verifier
+	)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		var line archiveLine
+		if err = json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return result, fmt.Errorf("第%d行解析失败: %w", lineNo, err)
+		}
+		switch line.Type {
+		case LineHeader:
+			if header != nil || line.Header == nil {
+				return result, fmt.Errorf("第%d行: 重复或无效的header", lineNo)
+			}
+			header = line.Header
+			result.Table = header.Table
+		case LineCheckpoint:
+			if header == nil || verifier != nil || line.Checkpoint == nil {
+				return result, fmt.Errorf("第%d行: checkpoint 必须位于 header 之后、row 之前", lineNo)
+			}
+			checkpoints = append(checkpoints, *line.Checkpoint)
+		case LineRow:
+			if header == nil || line.Row == nil {
+				return result, fmt.Errorf("第%d行: row 之前缺少 header", lineNo)
+			}
+			if verifier == nil {
+				verifier = NewVerifier(pub, checkpoints)
+			}
+			if broken := verifier.Next(*line.Row); broken != nil {
+				result.Broken = broken
+			}
+		default:
+			return result, fmt.Errorf("第%d行: 未知类型 %q", lineNo, line.Type)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return result, err
+	}
+	if header == nil {
+		return result, errors.New("归档缺少 header")
+	}
+	if verifier == nil {
+		verifier = NewVerifier(pub, checkpoints)
+	}
+	result.Broken = verifier.Finish()
+	result.Valid = result.Broken == nil
+	result.Checked = verifier.Checked()
+	result.FirstID, result.LastID = verifier.Range()
+	result.Checkpoints = len(checkpoints)
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package hashchain
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Chained 参与哈希链的模型需要实现该接口
+type Chained interface {
+	// ChainPayload 返回参与哈希计算的内容 不能包含自增ID、更新时间等落库后才确定或会变化的字段
+	ChainPayload() any
+	SetChain(prevHash, rowHash string)
+}
+
+// Record 哈希链中的一行 Payload 为规范化后的JSON
+type Record struct {
+	ID       uint            `json:"id"`
+	Payload  json.RawMessage `json:"payload"`
+	PrevHash string          `json:"prev_hash"`
+	RowHash  string          `json:"row_hash"`
+}
+
+// Checkpoint 对某张表某一行哈希的签名快照 用于发现尾部截断
+type Checkpoint struct {
+	Table     string `json:"table"`
+	LastID    uint   `json:"last_id"`
+	RowHash   string `json:"row_hash"`
+	Signature string `json:"signature"`
+}
+
+// Broken 第一处断链位置
+type Broken struct {
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// Canonical 将内容序列化为确定性的JSON
+func Canonical(payload any) ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// Hash 计算本行哈希 sha256(prevHash + "|" + payload)
+func Hash(prevHash string, payload []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prevHash))
+	h.Write([]byte("|"))
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Link 计算 item 的哈希并回写到 item 返回本行哈希
+func Link(prevHash string, item Chained) (string, error) {
+	payload, err := Canonical(item.ChainPayload())
+	if err != nil {
+		return "", err
+	}
+	rowHash := Hash(prevHash, payload)
+	item.SetChain(prevHash, rowHash)
+	return rowHash, nil
+}
+
+func checkpointMessage(c Checkpoint) []byte {
+	return []byte(c.Table + "|" + strconv.FormatUint(uint64(c.LastID), 10) + "|" + c.RowHash)
+}
+
+// Sign 使用 ed25519 私钥签名检查点
+func Sign(key ed25519.PrivateKey, c Checkpoint) Checkpoint {
+	c.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, checkpointMessage(c)))
+	return c
+}
+
+// ParseSigningKey 解析 base64 编码的 ed25519 私钥种子
+func ParseSigningKey(seed string) (ed25519.PrivateKey, error) {
+	if seed == "" {
+		return nil, errors.New("未配置检查点签名私钥")
+	}
+	raw, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil || len(raw) != ed25519.SeedSize {
+		return nil, errors.New("检查点签名私钥无效, 需要 base64 编码的32字节种子")
+	}
+	return ed25519.NewKeyFromSeed(raw), nil
+}
+
+// ParsePublicKey 解析 base64 编码的 ed25519 公钥
+func ParsePublicKey(key string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, errors.New("检查点签名公钥无效")
+	}
+	return raw, nil
+}
+
+// VerifySignature 校验检查点签名
+func VerifySignature(pub ed25519.PublicKey, c Checkpoint) bool {
+	sig, err := base64.StdEncoding.DecodeString(c.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, checkpointMessage(c), sig)
+}
+
+// Verifier 按ID升序逐行校验哈希链
+// 第一行作为锚点 其 PrevHash 不做校验(更早的数据可能已按保留策略清理)
+// 锚点之前没有哈希的行是开启哈希链前写入的历史数据 跳过; 锚点之后没有哈希的行视为断链
+type Verifier struct {
+	pub         ed25519.PublicKey
+	checkpoints map[uint]Checkpoint
+	prev        string
+	firstID     uint
+	lastID      uint
+	checked     int64
+	broken      *Broken
+}
+
+// NewVerifier 创建校验器 pub 为空时跳过签名校验
+func NewVerifier(pub ed25519.PublicKey, checkpoints []Checkpoint) *Verifier {
+	v := &Verifier{pub: pub, checkpoints: make(map[uint]Checkpoint, len(checkpoints))}
+	for _, c := range checkpoints {
+		v.checkpoints[c.LastID] = c
+	}
+	return v
+}
+
+// Next 校验下一行 返回第一处断链 之后的调用直接返回该结果
+func (v *Verifier) Next(r Record) *Broken {
+	if v.broken != nil {
+		return v.broken
+	}
+	if r.RowHash == "" {
+		if v.checked == 0 {
+			return nil
+		}
+		return v.fail(r.ID, "row_hash 为空, 记录可能绕过哈希链写入")
+	}
+	if v.checked > 0 && r.PrevHash != v.prev {
+		return v.fail(r.ID, "prev_hash 与上一行哈希不一致, 中间记录可能被删除或篡改")
+	}
+	if Hash(r.PrevHash, r.Payload) != r.RowHash {
+		return v.fail(r.ID, "row_hash 与内容不一致, 记录可能被修改")
+	}
+	if c, ok := v.checkpoints[r.ID]; ok {
+		if c.RowHash != r.RowHash {
+			return v.fail(r.ID, "row_hash 与检查点不一致")
+		}
+		if v.pub != nil && !VerifySignature(v.pub, c) {
+			return v.fail(r.ID, "检查点签名无效")
+		}
+		delete(v.checkpoints, r.ID)
+	}
+	if v.checked == 0 {
+		v.firstID = r.ID
+	}
+	v.prev = r.RowHash
+	v.lastID = r.ID
+	v.checked++
+	return nil
+}
+
+// Finish 结束校验 检查是否存在未匹配的检查点(说明对应行已被删除)
+func (v *Verifier) Finish() *Broken {
+	if v.broken != nil {
+		return v.broken
+	}
+	var missing *Checkpoint
+	for id, c := range v.checkpoints {
+		if v.checked > 0 && id < v.firstID {
+			continue
+		}
+		if missing == nil || id < missing.LastID {
+			c := c
+			missing = &c
+		}
+	}
+	if missing != nil {
+		return v.fail(missing.LastID, fmt.Sprintf("检查点引用的记录不存在, 记录可能已被删除 (row_hash=%s)", missing.RowHash))
+	}
+	return nil
+}
+
+// Checked 已校验的行数
+func (v *Verifier) Checked() int64 {
+	return v.checked
+}
+
+// Range 已校验的ID范围
+func (v *Verifier) Range() (first, last uint) {
+	return v.firstID, v.lastID
+}
+
+func (v *Verifier) fail(id uint, reason string) *Broken {
+	v.broken = &Broken{ID: id, Reason: reason}
+	return v.broken
+}
@@ -0,0 +1,253 @@
+package hashchain
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type chainedRow struct {
+	ID       uint `gorm:"primarykey"`
+	Content  string
+	Level    string `gorm:"default:info"`
+	PrevHash string
+	RowHash  string
+}
+
+func (r *chainedRow) ChainPayload() any {
+	return map[string]any{"content": r.Content, "level": r.Level}
+}
+
+func (r *chainedRow) SetChain(prevHash, rowHash string) {
+	r.PrevHash, r.RowHash = prevHash, rowHash
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	assert.Nil(t, db.Use(&Plugin{}))
+	assert.Nil(t, db.AutoMigrate(&chainedRow{}))
+	return db
+}
+
+func loadRecords(t *testing.T, db *gorm.DB) (records []Record) {
+	var rows []chainedRow
+	assert.Nil(t, db.Order("id").Find(&rows).Error)
+	for i := range rows {
+		payload, err := Canonical(rows[i].ChainPayload())
+		assert.Nil(t, err)
+		records = append(records, Record{ID: rows[i].ID, Payload: payload, PrevHash: rows[i].PrevHash, RowHash: rows[i].RowHash})
+	}
+	return records
+}
+
+func verify(records []Record, pub ed25519.PublicKey, checkpoints []Checkpoint) *Broken {
+	v := NewVerifier(pub, checkpoints)
+	for _, r := range records {
+		if broken := v.Next(r); broken != nil {
+			return broken
+		}
+	}
+	return v.Finish()
+}
+
+func TestPlugin_LinksRows(t *testing.T) {
+	db := newTestDB(t)
+	assert.Nil(t, db.Create(&chainedRow{Content: "a"}).Error)
+	assert.Nil(t, db.CreateInBatches([]chainedRow{{Content: "b"}, {Content: "c", Level: "warn"}}, 10).Error)
+
+	records := loadRecords(t, db)
+	assert.Len(t, records, 3)
+	assert.Equal(t, "", records[0].PrevHash)
+	assert.Equal(t, records[0].RowHash, records[1].PrevHash)
+	assert.Equal(t, records[1].RowHash, records[2].PrevHash)
+	assert.Nil(t, verify(records, nil, nil))
+}
+
+func TestVerifier_DetectsTampering(t *testing.T) {
+	db := newTestDB(t)
+	for _, c := range []string{"a", "b", "c", "d"} {
+		assert.Nil(t, db.Create(&chainedRow{Content: c}).Error)
+	}
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.Nil(t, err)
+
+	records := loadRecords(t, db)
+	last := records[len(records)-1]
+	checkpoint := Sign(priv, Checkpoint{Table: "chained_rows", LastID: last.ID, RowHash: last.RowHash})
+
+	t.Run("modified", func(t *testing.T) {
+		tampered := append([]Record(nil), records...)
+		tampered[1].Payload = []byte(`{"content":"x","level":"info"}`)
+		broken := verify(tampered, pub, []Checkpoint{checkpoint})
+		assert.NotNil(t, broken)
+		assert.Equal(t, records[1].ID, broken.ID)
+	})
+	t.Run("deleted", func(t *testing.T) {
+		tampered := append(append([]Record(nil), records[:1]...), records[2:]...)
+		broken := verify(tampered, pub, []Checkpoint{checkpoint})
+		assert.NotNil(t, broken)
+		assert.Equal(t, records[2].ID, broken.ID)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		broken := verify(records[:3], pub, []Checkpoint{checkpoint})
+		assert.NotNil(t, broken)
+		assert.Equal(t, last.ID, broken.ID)
+	})
+	t.Run("forged checkpoint", func(t *testing.T) {
+		forged := checkpoint
+		forged.Signature = base64.StdEncoding.EncodeToString(make([]byte, ed25519.SignatureSize))
+		broken := verify(records, pub, []Checkpoint{forged})
+		assert.NotNil(t, broken)
+	})
+	t.Run("unhashed row", func(t *testing.T) {
+		tampered := append([]Record(nil), records...)
+		tampered[2].PrevHash, tampered[2].RowHash = "", ""
+		broken := verify(tampered, pub, []Checkpoint{checkpoint})
+		assert.NotNil(t, broken)
+		assert.Equal(t, records[2].ID, broken.ID)
+	})
+	t.Run("legacy rows before anchor", func(t *testing.T) {
+		legacy := append([]Record{{ID: 0, Payload: []byte(`{}`)}}, records...)
+		assert.Nil(t, verify(legacy, pub, []Checkpoint{checkpoint}))
+	})
+	t.Run("intact", func(t *testing.T) {
+		assert.Nil(t, verify(records, pub, []Checkpoint{checkpoint}))
+	})
+}
+
+func TestVerifyArchive(t *testing.T) {
+	db := newTestDB(t)
+	for _, c := range []string{"<a>", "b&c", "d"} {
+		assert.Nil(t, db.Create(&chainedRow{Content: c}).Error)
+	}
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.Nil(t, err)
+	records := loadRecords(t, db)
+	last := records[len(records)-1]
+
+	var buf bytes.Buffer
+	w := NewArchiveWriter(&buf)
+	assert.Nil(t, w.WriteHeader(ArchiveHeader{Table: "chained_rows", PublicKey: base64.StdEncoding.EncodeToString(pub)}))
+	assert.Nil(t, w.WriteCheckpoint(Sign(priv, Checkpoint{Table: "chained_rows", LastID: last.ID, RowHash: last.RowHash})))
+	for _, r := range records {
+		assert.Nil(t, w.WriteRow(r))
+	}
+
+	_, err = VerifyArchive(bytes.NewReader(buf.Bytes()), nil)
+	assert.NotNil(t, err, "不能信任归档头中的公钥")
+
+	result, err := VerifyArchive(bytes.NewReader(buf.Bytes()), pub)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(3), result.Checked)
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	result, err = VerifyArchive(bytes.NewReader(buf.Bytes()), otherPub)
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+}
+
+func TestPlugin_Skip(t *testing.T) {
+	db := newTestDB(t)
+	assert.Nil(t, db.Create(&chainedRow{Content: "a"}).Error)
+	restored := chainedRow{ID: 10, Content: "b", PrevHash: "p", RowHash: "r"}
+	assert.Nil(t, db.Set(SkipKey, true).Create(&restored).Error)
+	var got chainedRow
+	assert.Nil(t, db.First(&got, 10).Error)
+	assert.Equal(t, "p", got.PrevHash)
+	assert.Equal(t, "r", got.RowHash)
+}
+
+type timedRow struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	Content   string
+	PrevHash  string
+	RowHash   string
+}
+
+func (r *timedRow) ChainPayload() any {
+	r.CreatedAt = r.CreatedAt.Truncate(time.Second)
+	return map[string]any{"content": r.Content, "created_at": r.CreatedAt.Unix()}
+}
+
+func (r *timedRow) SetChain(prevHash, rowHash string) {
+	r.PrevHash, r.RowHash = prevHash, rowHash
+}
+
+func TestPlugin_HeadFollowsCommits(t *testing.T) {
+	db := newTestDB(t)
+	assert.Nil(t, db.Create(&chainedRow{Content: "a"}).Error)
+	assert.Nil(t, db.Create(&chainedRow{Content: "b"}).Error)
+
+	// 回滚的写入不能推进链头
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		assert.Nil(t, tx.Create(&chainedRow{Content: "rolled back"}).Error)
+		return errors.New("rollback")
+	})
+	assert.Nil(t, db.Create(&chainedRow{Content: "c"}).Error)
+
+	records := loadRecords(t, db)
+	assert.Len(t, records, 3)
+	assert.Nil(t, verify(records, nil, nil))
+	var head Head
+	assert.Nil(t, db.First(&head, "chain_table = ?", "chained_rows").Error)
+	assert.Equal(t, records[2].RowHash, head.RowHash)
+}
+
+func TestPlugin_HeadInitializedFromExistingRows(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	assert.Nil(t, db.AutoMigrate(&chainedRow{}))
+	// 开启插件前已有的链
+	first := chainedRow{Content: "a", Level: "info"}
+	_, err = Link("", &first)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Create(&first).Error)
+
+	assert.Nil(t, db.Use(&Plugin{}))
+	assert.Nil(t, db.Create(&chainedRow{Content: "b"}).Error)
+	records := loadRecords(t, db)
+	assert.Equal(t, first.RowHash, records[1].PrevHash)
+	assert.Nil(t, verify(records, nil, nil))
+}
+
+func TestPlugin_CreateTimeInPayload(t *testing.T) {
+	db := newTestDB(t)
+	assert.Nil(t, db.AutoMigrate(&timedRow{}))
+	row := timedRow{Content: "a"}
+	assert.Nil(t, db.Create(&row).Error)
+	assert.False(t, row.CreatedAt.IsZero())
+
+	load := func() []Record {
+		var rows []timedRow
+		assert.Nil(t, db.Order("id").Find(&rows).Error)
+		var records []Record
+		for i := range rows {
+			payload, err := Canonical(rows[i].ChainPayload())
+			assert.Nil(t, err)
+			records = append(records, Record{ID: rows[i].ID, Payload: payload, PrevHash: rows[i].PrevHash, RowHash: rows[i].RowHash})
+		}
+		return records
+	}
+	assert.Nil(t, verify(load(), nil, nil))
+
+	// 修改创建时间会破坏哈希链
+	assert.Nil(t, db.Model(&timedRow{}).Where("id = ?", row.ID).UpdateColumn("created_at", row.CreatedAt.Add(-time.Hour)).Error)
+	broken := verify(load(), nil, nil)
+	if assert.NotNil(t, broken) {
+		assert.Equal(t, row.ID, broken.ID)
+	}
+}
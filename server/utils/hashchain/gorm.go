@@ -0,0 +1,192 @@
+package hashchain
+
+import (
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	lockKey = "gva:hash_chain_lock"
+	// SkipKey 通过 db.Set(SkipKey, true) 跳过链接 用于按原哈希恢复归档的行
+	SkipKey = "gva:hash_chain_skip"
+)
+
+// Head 每张表哈希链的链头 写入时在同一事务内对链头加行锁 多实例部署时同一张表的写入同样串行
+type Head struct {
+	Table   string `gorm:"column:chain_table;primaryKey;size:64;comment:表名"`
+	RowHash string `gorm:"column:row_hash;size:64;comment:最后一行的哈希"`
+}
+
+func (Head) TableName() string {
+	return "sys_hash_chain_heads"
+}
+
+// Plugin 在写入实现 Chained 接口的模型前计算哈希链
+// 链头行锁保证事务提交前其他写入者读不到旧的链头 进程内的互斥锁减少同一实例内的锁等待 两者都在事务提交后释放
+type Plugin struct {
+	locks sync.Map
+}
+
+func (p *Plugin) Name() string {
+	return "gva:hash_chain"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	if err := db.AutoMigrate(&Head{}); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:begin_transaction").Before("gorm:create").Register("gva:hash_chain_link", p.link); err != nil {
+		return err
+	}
+	return db.Callback().Create().After("gorm:commit_or_rollback_transaction").Register("gva:hash_chain_unlock", p.unlock)
+}
+
+func (p *Plugin) lock(table string) *sync.Mutex {
+	mu, _ := p.locks.LoadOrStore(table, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+func (p *Plugin) link(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	if skip, ok := db.Get(SkipKey); ok && skip == true {
+		return
+	}
+	items := chainedItems(db.Statement.ReflectValue)
+	if len(items) == 0 {
+		return
+	}
+	applyDefaults(db)
+	applyCreateTime(db)
+	table := db.Statement.Table
+	mu := p.lock(table)
+	mu.Lock()
+	db.InstanceSet(lockKey, mu)
+
+	// 与本次写入同一事务 链头的行锁在提交或回滚时释放
+	tx := db.Session(&gorm.Session{NewDB: true})
+	head, err := lockHead(tx, table)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	prevHash := head.RowHash
+	for _, item := range items {
+		if prevHash, err = Link(prevHash, item); err != nil {
+			_ = db.AddError(err)
+			return
+		}
+	}
+	if err = tx.Model(&Head{}).Where("chain_table = ?", table).Update("row_hash", prevHash).Error; err != nil {
+		_ = db.AddError(err)
+	}
+}
+
+// lockHead 锁定并返回表的链头 首次写入时以表中最后一行的哈希初始化
+func lockHead(tx *gorm.DB, table string) (head Head, err error) {
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("chain_table = ?", table).Limit(1).Find(&head).Error
+	if err != nil || head.Table != "" {
+		return head, err
+	}
+	last, err := LastHash(tx, table)
+	if err != nil {
+		return head, err
+	}
+	err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Head{Table: table, RowHash: last}).Error
+	if err != nil {
+		return head, err
+	}
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("chain_table = ?", table).Limit(1).Find(&head).Error
+	return head, err
+}
+
+func (p *Plugin) unlock(db *gorm.DB) {
+	if mu, ok := db.InstanceGet(lockKey); ok {
+		mu.(*sync.Mutex).Unlock()
+	}
+}
+
+// LastHash 查询表中最后一行的哈希 包含软删除的行
+func LastHash(db *gorm.DB, table string) (hash string, err error) {
+	var hashes []string
+	err = db.Unscoped().Table(table).Where("row_hash <> ''").Order("id desc").Limit(1).Pluck("row_hash", &hashes).Error
+	if err != nil || len(hashes) == 0 {
+		return "", err
+	}
+	return hashes[0], nil
+}
+
+// applyDefaults 提前填充 gorm default 标签的值 保证哈希内容与落库内容一致
+func applyDefaults(db *gorm.DB) {
+	ctx := db.Statement.Context
+	setDefaults := func(rv reflect.Value) {
+		for _, field := range db.Statement.Schema.Fields {
+			if field.DefaultValueInterface == nil {
+				continue
+			}
+			if _, isZero := field.ValueOf(ctx, rv); isZero {
+				_ = field.Set(ctx, rv, field.DefaultValueInterface)
+			}
+		}
+	}
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			setDefaults(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		setDefaults(rv)
+	}
+}
+
+// applyCreateTime 提前填充创建时间 使创建时间可以参与哈希计算
+func applyCreateTime(db *gorm.DB) {
+	ctx := db.Statement.Context
+	now := db.Statement.DB.NowFunc()
+	setCreateTime := func(rv reflect.Value) {
+		for _, field := range db.Statement.Schema.Fields {
+			if field.AutoCreateTime == 0 {
+				continue
+			}
+			if _, isZero := field.ValueOf(ctx, rv); isZero {
+				_ = field.Set(ctx, rv, now)
+			}
+		}
+	}
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			setCreateTime(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		setCreateTime(rv)
+	}
+}
+
+func chainedItems(rv reflect.Value) (items []Chained) {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, chainedItems(rv.Index(i))...)
+		}
+	case reflect.Ptr:
+		if !rv.IsNil() {
+			if item, ok := rv.Interface().(Chained); ok {
+				items = append(items, item)
+			}
+		}
+	case reflect.Struct:
+		if rv.CanAddr() {
+			if item, ok := rv.Addr().Interface().(Chained); ok {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,130 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"path"
+	"strings"
+	"sync"
+)
+
+const (
+	NamespaceMenu    = "menu"    // 菜单标题 key 为菜单 name
+	NamespaceDict    = "dict"    // 字典详情标签 key 为 字典type.字典值
+	NamespaceParam   = "param"   // 参数说明 key 为参数key
+	NamespaceMessage = "message" // 服务端消息 key 为中文原文
+)
+
+// Namespaces 支持的翻译命名空间
+var Namespaces = []string{NamespaceMenu, NamespaceDict, NamespaceParam, NamespaceMessage}
+
+// Translations locale -> namespace -> key -> value
+type Translations map[string]map[string]map[string]string
+
+func (t Translations) Set(locale, namespace, key, value string) {
+	if t[locale] == nil {
+		t[locale] = map[string]map[string]string{}
+	}
+	if t[locale][namespace] == nil {
+		t[locale][namespace] = map[string]string{}
+	}
+	t[locale][namespace][key] = value
+}
+
+func (t Translations) get(locale, namespace, key string) (string, bool) {
+	v, ok := t[locale][namespace][key]
+	return v, ok
+}
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// Bundle 翻译包 优先使用数据库中维护的翻译 其次使用内置翻译
+type Bundle struct {
+	mu       sync.RWMutex
+	builtin  Translations
+	override Translations
+}
+
+// Default 全局翻译包 内置翻译来自 locales/*.json
+var Default = NewBundle()
+
+func NewBundle() *Bundle {
+	b := &Bundle{builtin: Translations{}, override: Translations{}}
+	entries, _ := localeFS.ReadDir("locales")
+	for _, entry := range entries {
+		data, err := localeFS.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			continue
+		}
+		var namespaces map[string]map[string]string
+		if json.Unmarshal(data, &namespaces) != nil {
+			continue
+		}
+		locale := strings.TrimSuffix(entry.Name(), ".json")
+		for ns, kv := range namespaces {
+			for k, v := range kv {
+				b.builtin.Set(locale, ns, k, v)
+			}
+		}
+	}
+	return b
+}
+
+// Replace 替换数据库维护的翻译
+func (b *Bundle) Replace(t Translations) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.override = t
+}
+
+// T 查找翻译
+func (b *Bundle) T(locale, namespace, key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if v, ok := b.override.get(locale, namespace, key); ok {
+		return v, true
+	}
+	return b.builtin.get(locale, namespace, key)
+}
+
+// Builtin 返回某个语言内置翻译的副本
+func (b *Bundle) Builtin(locale string) map[string]map[string]string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	out := make(map[string]map[string]string, len(b.builtin[locale]))
+	for ns, kv := range b.builtin[locale] {
+		out[ns] = make(map[string]string, len(kv))
+		for k, v := range kv {
+			out[ns][k] = v
+		}
+	}
+	return out
+}
+
+// Or 查找翻译 未找到时返回原文
+func (b *Bundle) Or(locale, namespace, key, fallback string) string {
+	if v, ok := b.T(locale, namespace, key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// Message 翻译服务端消息 "获取失败:原因" 形式的消息只翻译冒号前的部分
+func (b *Bundle) Message(locale, msg string) string {
+	if msg == "" {
+		return msg
+	}
+	if v, ok := b.T(locale, NamespaceMessage, msg); ok {
+		return v
+	}
+	for _, sep := range []string{":", "："} {
+		if i := strings.Index(msg, sep); i > 0 {
+			prefix := strings.TrimSpace(msg[:i])
+			if v, ok := b.T(locale, NamespaceMessage, prefix); ok {
+				return v + ": " + strings.TrimSpace(msg[i+len(sep):])
+			}
+		}
+	}
+	return msg
+}
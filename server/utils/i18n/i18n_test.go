@@ -0,0 +1,38 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	supported := []string{"zh-CN", "en-US"}
+	cases := map[string]string{
+		"en-US,en;q=0.9":          "en-US",
+		"en":                      "en-US",
+		"fr-FR,zh;q=0.8,en;q=0.5": "zh-CN",
+		"en;q=0.4, zh-cn;q=0.9":   "zh-CN",
+		"en_us":                   "en-US",
+		"fr-FR, *;q=0.1":          "",
+		"":                        "",
+	}
+	for header, want := range cases {
+		got, ok := Negotiate(header, supported)
+		assert.Equal(t, want != "", ok, header)
+		assert.Equal(t, want, got, header)
+	}
+}
+
+func TestBundle_Message(t *testing.T) {
+	b := NewBundle()
+	assert.Equal(t, "Failed to fetch", b.Message("en-US", "获取失败"))
+	assert.Equal(t, "Failed to fetch: record not found", b.Message("en-US", "获取失败:record not found"))
+	assert.Equal(t, "Failed to fetch: x", b.Message("en-US", "获取失败：x"))
+	assert.Equal(t, "未翻译的消息", b.Message("en-US", "未翻译的消息"))
+
+	b.Replace(Translations{"en-US": {NamespaceMessage: {"获取失败": "Fetch failed"}}})
+	assert.Equal(t, "Fetch failed", b.Message("en-US", "获取失败"))
+	assert.Equal(t, "Dashboard", b.Or("en-US", NamespaceMenu, "dashboard", "仪表盘"))
+	assert.Equal(t, "仪表盘", b.Or("ja-JP", NamespaceMenu, "dashboard", "仪表盘"))
+}
@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/gin-gonic/gin"
+)
+
+// ContextKey 中间件解析出的语言存放在 gin.Context 中的键
+const ContextKey = "gva_locale"
+
+// SourceLocale 代码中硬编码文案使用的语言
+const SourceLocale = "zh-CN"
+
+// DefaultLocale 配置的默认语言
+func DefaultLocale() string {
+	if l := global.GVA_CONFIG.I18n.Default; l != "" {
+		return l
+	}
+	return SourceLocale
+}
+
+// Supported 配置的可用语言
+func Supported() []string {
+	if len(global.GVA_CONFIG.I18n.Locales) > 0 {
+		return global.GVA_CONFIG.I18n.Locales
+	}
+	return []string{SourceLocale}
+}
+
+// Match 将任意写法的语言标签匹配到可用语言 先完全匹配再按主语言匹配 如 en -> en-US
+func Match(tag string, supported []string) (string, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" {
+		return "", false
+	}
+	for _, s := range supported {
+		if strings.EqualFold(s, tag) {
+			return s, true
+		}
+	}
+	base := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+	for _, s := range supported {
+		if strings.ToLower(strings.SplitN(s, "-", 2)[0]) == base {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate 按 Accept-Language 的权重选择可用语言
+func Negotiate(acceptLanguage string, supported []string) (string, bool) {
+	type pref struct {
+		tag string
+		q   float64
+	}
+	var prefs []pref
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		p := pref{tag: strings.TrimSpace(fields[0]), q: 1}
+		for _, f := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(f), "q="); ok {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					p.q = q
+				}
+			}
+		}
+		if p.tag != "" && p.tag != "*" && p.q > 0 {
+			prefs = append(prefs, p)
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+	for _, p := range prefs {
+		if l, ok := Match(p.tag, supported); ok {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Locale 获取请求的语言 未经过中间件时按 Accept-Language 协商
+func Locale(c *gin.Context) string {
+	if l := c.GetString(ContextKey); l != "" {
+		return l
+	}
+	if l, ok := Negotiate(c.GetHeader("Accept-Language"), Supported()); ok {
+		return l
+	}
+	return DefaultLocale()
+}
+
+// Translate 按请求语言翻译服务端消息
+func Translate(c *gin.Context, msg string) string {
+	locale := Locale(c)
+	if locale == SourceLocale {
+		return msg
+	}
+	return Default.Message(locale, msg)
+}
@@ -0,0 +1,362 @@
+// Package jsonschema 实现 JSON Schema 的常用子集 用于校验参数等配置型数据
+// 支持 type enum const properties required additionalProperties items minItems maxItems
+// minimum maximum exclusiveMinimum exclusiveMaximum minLength maxLength pattern allOf anyOf oneOf not
+// 以及不影响校验的注释类关键字 其余关键字在解析时报错 避免误以为生效
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var knownTypes = map[string]bool{"string": true, "number": true, "integer": true, "boolean": true, "object": true, "array": true, "null": true}
+
+// keywords 支持的关键字 值为 true 的关键字包含子模式
+var keywords = map[string]bool{
+	"type": false, "enum": false, "const": false, "properties": true, "required": false, "additionalProperties": true,
+	"items": true, "minItems": false, "maxItems": false, "minimum": false, "maximum": false,
+	"exclusiveMinimum": false, "exclusiveMaximum": false, "minLength": false, "maxLength": false, "pattern": false,
+	"allOf": true, "anyOf": true, "oneOf": true, "not": true,
+	// 注释类关键字 不参与校验
+	"$schema": false, "$id": false, "$comment": false, "title": false, "description": false,
+	"default": false, "examples": false, "deprecated": false, "readOnly": false, "writeOnly": false,
+}
+
+// Types type 关键字 可以是单个类型或类型数组
+type Types []string
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return errors.New("type 必须是字符串或字符串数组")
+	}
+	*t = many
+	return nil
+}
+
+// Additional additionalProperties 关键字 可以是布尔值或子模式
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+type Schema struct {
+	Type                 Types              `json:"type"`
+	Enum                 []any              `json:"enum"`
+	Const                json.RawMessage    `json:"const"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *Additional        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	AllOf                []*Schema          `json:"allOf"`
+	AnyOf                []*Schema          `json:"anyOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+	Not                  *Schema            `json:"not"`
+
+	pattern  *regexp.Regexp
+	constVal any
+}
+
+// ValidationError 校验失败的位置和原因
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Compile 解析并检查模式
+func Compile(raw []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("JSON Schema 格式错误: %w", err)
+	}
+	if err := checkKeywords("$", raw); err != nil {
+		return nil, err
+	}
+	if err := s.compile("$"); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// checkKeywords 检查模式及其子模式中是否有不支持的关键字
+func checkKeywords(path string, raw json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil // 布尔值等非对象 由 Schema 解析报错
+	}
+	children := map[string]json.RawMessage{}
+	for name, value := range fields {
+		nested, ok := keywords[name]
+		if !ok {
+			return fmt.Errorf("%s: 不支持的关键字 %s", path, name)
+		}
+		if !nested {
+			continue
+		}
+		switch name {
+		case "properties":
+			var props map[string]json.RawMessage
+			_ = json.Unmarshal(value, &props)
+			for prop, sub := range props {
+				children[path+"."+prop] = sub
+			}
+		case "allOf", "anyOf", "oneOf":
+			var group []json.RawMessage
+			_ = json.Unmarshal(value, &group)
+			for i, sub := range group {
+				children[fmt.Sprintf("%s(%s[%d])", path, name, i)] = sub
+			}
+		case "items":
+			children[path+"[]"] = value
+		case "additionalProperties":
+			children[path+".*"] = value
+		case "not":
+			children[path+"(not)"] = value
+		}
+	}
+	for p, child := range children {
+		if err := checkKeywords(p, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) compile(path string) error {
+	for _, t := range s.Type {
+		if !knownTypes[t] {
+			return fmt.Errorf("%s: 未知类型 %s", path, t)
+		}
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: pattern 无效: %w", path, err)
+		}
+		s.pattern = re
+	}
+	if len(s.Const) > 0 {
+		if err := json.Unmarshal(s.Const, &s.constVal); err != nil {
+			return fmt.Errorf("%s: const 无效: %w", path, err)
+		}
+	}
+	children := map[string]*Schema{}
+	for name, p := range s.Properties {
+		children[path+"."+name] = p
+	}
+	if s.Items != nil {
+		children[path+"[]"] = s.Items
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		children[path+".*"] = s.AdditionalProperties.Schema
+	}
+	if s.Not != nil {
+		children[path+"(not)"] = s.Not
+	}
+	for name, group := range map[string][]*Schema{"allOf": s.AllOf, "anyOf": s.AnyOf, "oneOf": s.OneOf} {
+		for i, sub := range group {
+			children[fmt.Sprintf("%s(%s[%d])", path, name, i)] = sub
+		}
+	}
+	for p, child := range children {
+		if child == nil {
+			return fmt.Errorf("%s: 子模式为空", p)
+		}
+		if err := child.compile(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate 校验 JSON 文档
+func (s *Schema) Validate(doc []byte) error {
+	var v any
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return &ValidationError{Path: "$", Message: "不是合法的JSON: " + err.Error()}
+	}
+	return s.ValidateValue(v)
+}
+
+// ValidateValue 校验 json.Unmarshal 得到的值
+func (s *Schema) ValidateValue(v any) error {
+	return s.validate("$", v)
+}
+
+func (s *Schema) validate(path string, v any) error {
+	fail := func(format string, args ...any) error {
+		return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+	if len(s.Type) > 0 {
+		matched := false
+		for _, t := range s.Type {
+			matched = matched || isType(v, t)
+		}
+		if !matched {
+			return fail("类型应为 %s", strings.Join(s.Type, "/"))
+		}
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			found = found || reflect.DeepEqual(e, v)
+		}
+		if !found {
+			return fail("不在可选值范围内")
+		}
+	}
+	if s.Const != nil && !reflect.DeepEqual(s.constVal, v) {
+		return fail("应等于 %s", string(s.Const))
+	}
+	switch val := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(val)
+		if s.MinLength != nil && n < *s.MinLength {
+			return fail("长度不能小于 %d", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fail("长度不能大于 %d", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			return fail("不匹配 %s", s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && val < *s.Minimum {
+			return fail("不能小于 %v", *s.Minimum)
+		}
+		if s.Maximum != nil && val > *s.Maximum {
+			return fail("不能大于 %v", *s.Maximum)
+		}
+		if s.ExclusiveMinimum != nil && val <= *s.ExclusiveMinimum {
+			return fail("必须大于 %v", *s.ExclusiveMinimum)
+		}
+		if s.ExclusiveMaximum != nil && val >= *s.ExclusiveMaximum {
+			return fail("必须小于 %v", *s.ExclusiveMaximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			return fail("元素不能少于 %d 个", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			return fail("元素不能多于 %d 个", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range val {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				return fail("缺少必填字段 %s", name)
+			}
+		}
+		for name, item := range val {
+			if p, ok := s.Properties[name]; ok {
+				if err := p.validate(path+"."+name, item); err != nil {
+					return err
+				}
+				continue
+			}
+			if a := s.AdditionalProperties; a != nil {
+				if !a.Allowed {
+					return fail("不允许字段 %s", name)
+				}
+				if a.Schema != nil {
+					if err := a.Schema.validate(path+"."+name, item); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	for _, sub := range s.AllOf {
+		if err := sub.validate(path, v); err != nil {
+			return err
+		}
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			matched = matched || sub.validate(path, v) == nil
+		}
+		if !matched {
+			return fail("不满足 anyOf 中任何一个模式")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if sub.validate(path, v) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fail("应恰好满足 oneOf 中的一个模式 实际满足 %d 个", matched)
+		}
+	}
+	if s.Not != nil && s.Not.validate(path, v) == nil {
+		return fail("不应满足 not 中的模式")
+	}
+	return nil
+}
+
+func isType(v any, t string) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := Compile([]byte(`{
+		"type": "object",
+		"required": ["host", "port"],
+		"additionalProperties": false,
+		"properties": {
+			"host": {"type": "string", "minLength": 1, "pattern": "^[a-z0-9.-]+$"},
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+			"mode": {"enum": ["tcp", "udp"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+			"timeout": {"anyOf": [{"type": "integer"}, {"type": "string", "pattern": "^[0-9]+s$"}]}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	valid := []string{
+		`{"host": "siem.local", "port": 514}`,
+		`{"host": "a", "port": 1, "mode": "udp", "tags": ["x"], "timeout": "5s"}`,
+		`{"host": "a", "port": 1, "timeout": 5}`,
+	}
+	for _, doc := range valid {
+		assert.Nil(t, s.Validate([]byte(doc)), doc)
+	}
+	invalid := map[string]string{
+		`{"host": "a"}`:                             "$",
+		`{"host": "A!", "port": 1}`:                 "$.host",
+		`{"host": "a", "port": 1.5}`:                "$.port",
+		`{"host": "a", "port": 70000}`:              "$.port",
+		`{"host": "a", "port": 1, "mode": "http"}`:  "$.mode",
+		`{"host": "a", "port": 1, "tags": [1]}`:     "$.tags[0]",
+		`{"host": "a", "port": 1, "extra": true}`:   "$",
+		`{"host": "a", "port": 1, "timeout": "5m"}`: "$.timeout",
+		`[]`: "$",
+	}
+	for doc, path := range invalid {
+		err := s.Validate([]byte(doc))
+		if assert.NotNil(t, err, doc) {
+			assert.Equal(t, path, err.(*ValidationError).Path, doc)
+		}
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for _, raw := range []string{`{"type": "map"}`, `{"pattern": "("}`, `{"properties": {"a": {"type": 1}}}`, `not json`,
+		`{"type": "string", "format": "email"}`, `{"properties": {"a": {"type": "string", "minLenght": 1}}}`, `{"items": {"uniqueItems": true}}`,
+		`{"additionalProperties": {"patternProperties": {}}}`, `{"anyOf": [{"type": "string"}, {"multipleOf": 2}]}`} {
+		_, err := Compile([]byte(raw))
+		assert.NotNil(t, err, raw)
+	}
+}
+
+func TestCompile_Annotations(t *testing.T) {
+	_, err := Compile([]byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "title": "配置", "description": "说明", "type": "object",
+		"properties": {"a": {"type": "string", "default": "x", "examples": ["y"]}}, "additionalProperties": false}`))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,261 @@
+// Package merge 以行为单位的三方合并 用于重新生成代码时保留本地修改
+// base 为上次生成的原始文件 ours 为本地修改后的文件 theirs 为本次重新生成的文件
+package merge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	ResolveOurs   = "ours"   // 保留本地修改
+	ResolveTheirs = "theirs" // 采用重新生成的内容
+	ResolveBoth   = "both"   // 本地修改在前 重新生成的内容在后
+	ResolveBase   = "base"   // 恢复为上次生成的内容
+
+	markerOurs   = "<<<<<<< 本地修改\n"
+	markerBase   = "||||||| 上次生成\n"
+	markerSplit  = "=======\n"
+	markerTheirs = ">>>>>>> 重新生成\n"
+)
+
+// Conflict 合并冲突 Index 为冲突在文件中的序号 从0开始
+type Conflict struct {
+	Index  int    `json:"index"`
+	Base   string `json:"base"`
+	Ours   string `json:"ours"`
+	Theirs string `json:"theirs"`
+}
+
+type chunk struct {
+	conflict bool
+	lines    []string // 无冲突时的内容
+	base     []string
+	ours     []string
+	theirs   []string
+}
+
+// Result 合并结果 由无冲突的片段和冲突片段依次组成
+type Result struct {
+	chunks []chunk
+}
+
+// hunk base[baseStart:baseEnd] 被替换为 side[start:end]
+type hunk struct {
+	baseStart, baseEnd int
+	start, end         int
+	ours               bool
+}
+
+// Merge3 三方合并 双方修改了base中相同或相邻的行且内容不一致时产生冲突
+func Merge3(base, ours, theirs string) *Result {
+	b, o, t := splitLines(base), splitLines(ours), splitLines(theirs)
+	hunks := diff(b, o, true)
+	hunks = append(hunks, diff(b, t, false)...)
+	sort.SliceStable(hunks, func(i, j int) bool {
+		return hunks[i].baseStart < hunks[j].baseStart
+	})
+
+	result := &Result{}
+	pos := 0
+	for k := 0; k < len(hunks); {
+		lo, hi := hunks[k].baseStart, hunks[k].baseEnd
+		group := []hunk{hunks[k]}
+		for k++; k < len(hunks) && hunks[k].baseStart <= hi; k++ {
+			group = append(group, hunks[k])
+			hi = max(hi, hunks[k].baseEnd)
+		}
+		result.stable(b[pos:lo])
+		pos = hi
+
+		var hasOurs, hasTheirs bool
+		for _, h := range group {
+			hasOurs = hasOurs || h.ours
+			hasTheirs = hasTheirs || !h.ours
+		}
+		oursLines := apply(group, true, b, o, lo, hi)
+		theirsLines := apply(group, false, b, t, lo, hi)
+		switch {
+		case !hasTheirs:
+			result.stable(oursLines)
+		case !hasOurs, equal(oursLines, theirsLines):
+			result.stable(theirsLines)
+		default:
+			result.chunks = append(result.chunks, chunk{conflict: true, base: b[lo:hi], ours: oursLines, theirs: theirsLines})
+		}
+	}
+	result.stable(b[pos:])
+	return result
+}
+
+// Conflicts 获取全部冲突
+func (r *Result) Conflicts() []Conflict {
+	conflicts := make([]Conflict, 0)
+	for _, c := range r.chunks {
+		if c.conflict {
+			conflicts = append(conflicts, Conflict{
+				Index:  len(conflicts),
+				Base:   strings.Join(c.base, ""),
+				Ours:   strings.Join(c.ours, ""),
+				Theirs: strings.Join(c.theirs, ""),
+			})
+		}
+	}
+	return conflicts
+}
+
+// String 合并后的内容 冲突处使用git风格的冲突标记
+func (r *Result) String() string {
+	var builder strings.Builder
+	for _, c := range r.chunks {
+		if !c.conflict {
+			builder.WriteString(strings.Join(c.lines, ""))
+			continue
+		}
+		builder.WriteString(markerOurs)
+		writeBlock(&builder, c.ours)
+		builder.WriteString(markerBase)
+		writeBlock(&builder, c.base)
+		builder.WriteString(markerSplit)
+		writeBlock(&builder, c.theirs)
+		builder.WriteString(markerTheirs)
+	}
+	return builder.String()
+}
+
+// Resolve 按冲突序号选择处理方式生成最终内容 存在未处理的冲突时返回错误
+func (r *Result) Resolve(resolutions map[int]string) (string, error) {
+	var builder strings.Builder
+	index := 0
+	for _, c := range r.chunks {
+		if !c.conflict {
+			builder.WriteString(strings.Join(c.lines, ""))
+			continue
+		}
+		switch resolutions[index] {
+		case ResolveOurs:
+			builder.WriteString(strings.Join(c.ours, ""))
+		case ResolveTheirs:
+			builder.WriteString(strings.Join(c.theirs, ""))
+		case ResolveBoth:
+			writeBlock(&builder, c.ours)
+			builder.WriteString(strings.Join(c.theirs, ""))
+		case ResolveBase:
+			builder.WriteString(strings.Join(c.base, ""))
+		case "":
+			return "", fmt.Errorf("冲突%d未处理", index)
+		default:
+			return "", fmt.Errorf("冲突%d的处理方式%s不支持", index, resolutions[index])
+		}
+		index++
+	}
+	return builder.String(), nil
+}
+
+func (r *Result) stable(lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	if n := len(r.chunks); n > 0 && !r.chunks[n-1].conflict {
+		r.chunks[n-1].lines = append(r.chunks[n-1].lines, lines...)
+		return
+	}
+	r.chunks = append(r.chunks, chunk{lines: append([]string(nil), lines...)})
+}
+
+// diff 基于最长公共子序列计算 a 变为 b 需要替换的区间
+func diff(a, b []string, ours bool) []hunk {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(a), len(b)
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var hunks []hunk
+	flush := func(si, i, sj, j int) {
+		if i > si || j > sj {
+			hunks = append(hunks, hunk{baseStart: prefix + si, baseEnd: prefix + i, start: prefix + sj, end: prefix + j, ours: ours})
+		}
+	}
+	i, j, si, sj := 0, 0, 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			flush(si, i, sj, j)
+			i, j = i+1, j+1
+			si, sj = i, j
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			j++
+		default:
+			i++
+		}
+	}
+	flush(si, i, sj, j)
+	return hunks
+}
+
+// apply 将同一方的修改应用到 base[lo:hi]
+func apply(group []hunk, ours bool, base, side []string, lo, hi int) []string {
+	var lines []string
+	pos := lo
+	for _, h := range group {
+		if h.ours != ours {
+			continue
+		}
+		lines = append(lines, base[pos:h.baseStart]...)
+		lines = append(lines, side[h.start:h.end]...)
+		pos = h.baseEnd
+	}
+	return append(lines, base[pos:hi]...)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeBlock 写入冲突块 保证以换行结尾 避免冲突标记与内容连在同一行
+func writeBlock(builder *strings.Builder, lines []string) {
+	block := strings.Join(lines, "")
+	builder.WriteString(block)
+	if block != "" && !strings.HasSuffix(block, "\n") {
+		builder.WriteString("\n")
+	}
+}
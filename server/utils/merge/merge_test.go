@@ -0,0 +1,101 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	base := "package demo\n\ntype Demo struct {\n\tName string\n}\n\nfunc Get() {\n\treturn\n}\n"
+	tests := []struct {
+		name      string
+		ours      string
+		theirs    string
+		want      string
+		conflicts int
+	}{
+		{
+			name:   "仅重新生成有变化",
+			ours:   base,
+			theirs: "package demo\n\ntype Demo struct {\n\tName string\n\tAge int\n}\n\nfunc Get() {\n\treturn\n}\n",
+			want:   "package demo\n\ntype Demo struct {\n\tName string\n\tAge int\n}\n\nfunc Get() {\n\treturn\n}\n",
+		},
+		{
+			name:   "本地修改与新增字段互不影响",
+			ours:   "package demo\n\ntype Demo struct {\n\tName string\n}\n\nfunc Get() {\n\tlog()\n\treturn\n}\n",
+			theirs: "package demo\n\ntype Demo struct {\n\tName string\n\tAge int\n}\n\nfunc Get() {\n\treturn\n}\n",
+			want:   "package demo\n\ntype Demo struct {\n\tName string\n\tAge int\n}\n\nfunc Get() {\n\tlog()\n\treturn\n}\n",
+		},
+		{
+			name:   "双方修改一致",
+			ours:   "package demo\n\ntype Demo struct {\n\tTitle string\n}\n\nfunc Get() {\n\treturn\n}\n",
+			theirs: "package demo\n\ntype Demo struct {\n\tTitle string\n}\n\nfunc Get() {\n\treturn\n}\n",
+			want:   "package demo\n\ntype Demo struct {\n\tTitle string\n}\n\nfunc Get() {\n\treturn\n}\n",
+		},
+		{
+			name:      "双方修改同一行",
+			ours:      "package demo\n\ntype Demo struct {\n\tName string `json:\"name\"`\n}\n\nfunc Get() {\n\treturn\n}\n",
+			theirs:    "package demo\n\ntype Demo struct {\n\tName *string\n}\n\nfunc Get() {\n\treturn\n}\n",
+			want:      "package demo\n\ntype Demo struct {\n<<<<<<< 本地修改\n\tName string `json:\"name\"`\n||||||| 上次生成\n\tName string\n=======\n\tName *string\n>>>>>>> 重新生成\n}\n\nfunc Get() {\n\treturn\n}\n",
+			conflicts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Merge3(base, tt.ours, tt.theirs)
+			assert.Equal(t, tt.want, result.String())
+			assert.Len(t, result.Conflicts(), tt.conflicts)
+		})
+	}
+}
+
+func TestMerge3_NoBase(t *testing.T) {
+	result := Merge3("", "a\nb\n", "a\nb\n")
+	assert.Empty(t, result.Conflicts())
+	assert.Equal(t, "a\nb\n", result.String())
+
+	result = Merge3("", "a\n", "b")
+	assert.Equal(t, []Conflict{{Index: 0, Ours: "a\n", Theirs: "b"}}, result.Conflicts())
+	assert.Equal(t, "<<<<<<< 本地修改\na\n||||||| 上次生成\n=======\nb\n>>>>>>> 重新生成\n", result.String())
+}
+
+func TestResult_Resolve(t *testing.T) {
+	result := Merge3("x\n1\ny\n2\nz\n", "x\n1a\ny\n2a\nz\n", "x\n1b\ny\n2b\nz\n")
+	assert.Len(t, result.Conflicts(), 2)
+
+	tests := []struct {
+		name        string
+		resolutions map[int]string
+		want        string
+		wantErr     bool
+	}{
+		{name: "逐个选择", resolutions: map[int]string{0: ResolveOurs, 1: ResolveTheirs}, want: "x\n1a\ny\n2b\nz\n"},
+		{name: "保留双方和原始内容", resolutions: map[int]string{0: ResolveBoth, 1: ResolveBase}, want: "x\n1a\n1b\ny\n2\nz\n"},
+		{name: "存在未处理的冲突", resolutions: map[int]string{0: ResolveOurs}, wantErr: true},
+		{name: "不支持的处理方式", resolutions: map[int]string{0: "mine", 1: ResolveOurs}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := result.Resolve(tt.resolutions)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	assert.Empty(t, Unified("a.go", "a\nb\n", "a\nb\n"))
+
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	updated := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n"
+	assert.Equal(t, "--- a/a.go\n+++ b/a.go\n"+
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n"+
+		"@@ -13,3 +13,4 @@\n 13\n 14\n 15\n+16\n", Unified("a.go", old, updated))
+
+	assert.Equal(t, "--- a/new.go\n+++ b/new.go\n@@ -0,0 +1,2 @@\n+a\n+b\n\\ No newline at end of file\n", Unified("new.go", "", "a\nb"))
+}
@@ -0,0 +1,62 @@
+package merge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unifiedContext 差异前后保留的上下文行数
+const unifiedContext = 3
+
+// Unified 以unified diff格式输出 a 变为 b 的差异 name为文件路径 无差异时返回空字符串
+func Unified(name, a, b string) string {
+	x, y := splitLines(a), splitLines(b)
+	hunks := diff(x, y, true)
+	if len(hunks) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- a/%s\n+++ b/%s\n", name, name)
+	for i := 0; i < len(hunks); {
+		j := i
+		for j+1 < len(hunks) && hunks[j+1].baseStart-hunks[j].baseEnd <= 2*unifiedContext {
+			j++
+		} // 上下文重叠的差异合并为一段
+		aStart := max(hunks[i].baseStart-unifiedContext, 0)
+		aEnd := min(hunks[j].baseEnd+unifiedContext, len(x))
+		bStart := hunks[i].start - (hunks[i].baseStart - aStart)
+		bEnd := hunks[j].end + (aEnd - hunks[j].baseEnd)
+		fmt.Fprintf(&builder, "@@ -%s +%s @@\n", unifiedRange(aStart, aEnd-aStart), unifiedRange(bStart, bEnd-bStart))
+		pos := aStart
+		for _, h := range hunks[i : j+1] {
+			writeUnified(&builder, " ", x[pos:h.baseStart])
+			writeUnified(&builder, "-", x[h.baseStart:h.baseEnd])
+			writeUnified(&builder, "+", y[h.start:h.end])
+			pos = h.baseEnd
+		}
+		writeUnified(&builder, " ", x[pos:aEnd])
+		i = j + 1
+	}
+	return builder.String()
+}
+
+// unifiedRange 起始行从1开始 行数为0时起始行为前一行
+func unifiedRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+func writeUnified(builder *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		builder.WriteString(prefix)
+		builder.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			builder.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package archive 插件安装包的读取、签名校验与解压
+//
+// 安装包结构与 /autoCode/pubPlug 打包结果一致:
+//
+//	{name}/server/plugin/{name}/...
+//	{name}/web/plugin/{name}/...
+//	{name}/plugin.sig
+//
+// 除插件自身目录与签名文件外不允许出现其他文件 避免安装包覆盖插件目录之外的核心文件
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	SignatureFile = "plugin.sig" // 签名文件 位于安装包顶层目录
+
+	SideServer = "server"
+	SideWeb    = "web"
+
+	DefaultMaxSize  int64 = 50 << 20 // 解压后总大小默认上限
+	DefaultMaxFiles       = 2000     // 文件数量默认上限
+)
+
+// Limits 解压限制 为0时使用默认值
+type Limits struct {
+	MaxSize  int64 // 解压后总大小上限(字节)
+	MaxFiles int   // 文件数量上限
+}
+
+func (l Limits) maxSize() int64 {
+	if l.MaxSize > 0 {
+		return l.MaxSize
+	}
+	return DefaultMaxSize
+}
+
+func (l Limits) maxFiles() int {
+	if l.MaxFiles > 0 {
+		return l.MaxFiles
+	}
+	return DefaultMaxFiles
+}
+
+// File 安装包中的插件文件
+type File struct {
+	Name    string // 安装包中的完整路径
+	Side    string // server/web
+	Path    string // 相对插件目录的路径 使用/分隔
+	Size    int64
+	Digest  string // sha256 十六进制
+	Content []byte
+}
+
+// Package 已读取并通过结构检查的安装包
+type Package struct {
+	Top       string // 顶层目录
+	Plugin    string // 插件名称
+	Files     []File // 按Name排序
+	Signature []byte // 签名文件内容 未签名为nil
+}
+
+// Read 读取安装包 检查路径、文件类型、大小与数量 不写入磁盘
+func Read(r io.ReaderAt, size int64, limits Limits) (*Package, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取插件安装包失败!")
+	}
+	pkg := &Package{}
+	var total int64
+	for _, f := range reader.File {
+		name, err := cleanName(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if skip(name) {
+			continue
+		}
+		mode := f.Mode()
+		if !mode.IsDir() && !mode.IsRegular() {
+			return nil, errors.Errorf("[%s]不是普通文件, 插件安装包不允许包含符号链接或设备文件!", f.Name)
+		}
+		parts := strings.Split(name, "/")
+		if pkg.Top == "" {
+			pkg.Top = parts[0]
+		}
+		if parts[0] != pkg.Top {
+			return nil, errors.Errorf("[%s]不在顶层目录[%s]中, 插件安装包只能包含一个顶层目录!", f.Name, pkg.Top)
+		}
+		if mode.IsDir() {
+			if !allowedDir(parts[1:]) {
+				return nil, errors.Errorf("[%s]不在插件目录中, 插件安装包不允许写入插件目录之外的文件!", f.Name)
+			}
+			continue
+		}
+		if len(parts) == 2 && parts[1] == SignatureFile {
+			if pkg.Signature != nil {
+				return nil, errors.Errorf("[%s]重复!", f.Name)
+			}
+			pkg.Signature, err = readLimited(f, 64<<10)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(parts) < 5 || parts[2] != "plugin" || (parts[1] != SideServer && parts[1] != SideWeb) {
+			return nil, errors.Errorf("[%s]不在插件目录中, 插件安装包不允许写入插件目录之外的文件!", f.Name)
+		}
+		if pkg.Plugin == "" {
+			pkg.Plugin = parts[3]
+		}
+		if parts[3] != pkg.Plugin {
+			return nil, errors.Errorf("[%s]属于插件[%s], 插件安装包只能包含一个插件[%s]!", f.Name, parts[3], pkg.Plugin)
+		}
+		if len(pkg.Files) >= limits.maxFiles() {
+			return nil, errors.Errorf("插件安装包文件数量超过上限%d!", limits.maxFiles())
+		}
+		content, err := readLimited(f, limits.maxSize()-total)
+		if err != nil {
+			return nil, err
+		}
+		total += int64(len(content))
+		sum := sha256.Sum256(content)
+		pkg.Files = append(pkg.Files, File{
+			Name:    name,
+			Side:    parts[1],
+			Path:    strings.Join(parts[4:], "/"),
+			Size:    int64(len(content)),
+			Digest:  hex.EncodeToString(sum[:]),
+			Content: content,
+		})
+	}
+	if pkg.Plugin == "" {
+		return nil, errors.New("非标准插件, 安装包中没有server/plugin或web/plugin目录!")
+	}
+	sort.Slice(pkg.Files, func(i, j int) bool { return pkg.Files[i].Name < pkg.Files[j].Name })
+	for i := 1; i < len(pkg.Files); i++ {
+		if pkg.Files[i].Name == pkg.Files[i-1].Name {
+			return nil, errors.Errorf("[%s]重复!", pkg.Files[i].Name)
+		}
+	}
+	return pkg, nil
+}
+
+// Side 获取某一端的文件
+func (p *Package) Side(side string) []File {
+	var files []File
+	for _, f := range p.Files {
+		if f.Side == side {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// Lookup 按端与相对路径查找文件
+func (p *Package) Lookup(side, path string) (File, bool) {
+	for _, f := range p.Files {
+		if f.Side == side && f.Path == path {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+// cleanName 拒绝绝对路径、反斜杠与..等可能逃逸出解压目录的路径
+func cleanName(name string) (string, error) {
+	if name == "" || strings.Contains(name, "\\") || strings.HasPrefix(name, "/") || strings.Contains(name, ":") {
+		return "", errors.Errorf("[%s]文件名不合法!", name)
+	}
+	trimmed := strings.TrimSuffix(name, "/")
+	for _, part := range strings.Split(trimmed, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", errors.Errorf("[%s]文件名不合法!", name)
+		}
+	}
+	if path.Clean(trimmed) != trimmed {
+		return "", errors.Errorf("[%s]文件名不合法!", name)
+	}
+	return trimmed, nil
+}
+
+// skip 跳过mac压缩时附带的文件
+func skip(name string) bool {
+	return strings.HasPrefix(name, "__MACOSX") || path.Base(name) == ".DS_Store"
+}
+
+// allowedDir 目录只能是插件目录本身或其上级
+func allowedDir(parts []string) bool {
+	want := []string{"", "plugin", ""}
+	for i, part := range parts {
+		if i == 0 {
+			if part != SideServer && part != SideWeb {
+				return false
+			}
+			continue
+		}
+		if i < len(want) && want[i] != "" && part != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// readLimited 读取文件内容 超过limit时报错 不信任压缩包中声明的大小
+func readLimited(f *zip.File, limit int64) ([]byte, error) {
+	if limit <= 0 || f.UncompressedSize64 > uint64(limit) {
+		return nil, errors.New("压缩包解压后大小超过上限!")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "[%s]读取失败!", f.Name)
+	}
+	defer rc.Close()
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "[%s]读取失败!", f.Name)
+	}
+	if n > limit {
+		return nil, errors.New("压缩包解压后大小超过上限!")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,217 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type entry struct {
+	name    string
+	content string
+	mode    fs.FileMode
+}
+
+func build(t *testing.T, entries ...entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		if e.mode != 0 {
+			header.SetMode(e.mode)
+		}
+		f, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func read(content []byte, limits Limits) (*Package, error) {
+	return Read(bytes.NewReader(content), int64(len(content)), limits)
+}
+
+func valid() []entry {
+	return []entry{
+		{name: "demo/"},
+		{name: "demo/server/plugin/"},
+		{name: "demo/server/plugin/demo/plugin.go", content: "package demo"},
+		{name: "demo/server/plugin/demo/plugin.json", content: `{"name":"demo"}`},
+		{name: "demo/web/plugin/demo/view/index.vue", content: "<template/>"},
+		{name: "__MACOSX/demo/._plugin.go", content: "x"},
+		{name: "demo/server/plugin/demo/.DS_Store", content: "x"},
+	}
+}
+
+func TestRead(t *testing.T) {
+	pkg, err := read(build(t, valid()...), Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Top != "demo" || pkg.Plugin != "demo" || len(pkg.Files) != 3 {
+		t.Fatalf("Read() = %+v", pkg)
+	}
+	if f, ok := pkg.Lookup(SideServer, "plugin.json"); !ok || string(f.Content) != `{"name":"demo"}` {
+		t.Errorf("Lookup() = %+v, %v", f, ok)
+	}
+	if files := pkg.Side(SideWeb); len(files) != 1 || files[0].Path != "view/index.vue" {
+		t.Errorf("Side() = %+v", files)
+	}
+
+	tests := []struct {
+		name    string
+		entries []entry
+		limits  Limits
+		want    string
+	}{
+		{name: "zip slip", entries: []entry{{name: "demo/server/plugin/demo/../../../initialize/gorm.go"}}, want: "文件名不合法"},
+		{name: "absolute", entries: []entry{{name: "/etc/passwd"}}, want: "文件名不合法"},
+		{name: "backslash", entries: []entry{{name: "demo\\..\\x.go"}}, want: "文件名不合法"},
+		{name: "symlink", entries: []entry{{name: "demo/server/plugin/demo/link", content: "/etc/passwd", mode: fs.ModeSymlink | 0777}}, want: "符号链接"},
+		{name: "core file", entries: []entry{{name: "demo/server/initialize/gorm.go", content: "package initialize"}}, want: "插件目录之外"},
+		{name: "core dir", entries: []entry{{name: "demo/server/initialize/"}}, want: "插件目录之外"},
+		{name: "plugin root file", entries: []entry{{name: "demo/server/plugin/x.go"}}, want: "插件目录之外"},
+		{name: "two plugins", entries: append(valid(), entry{name: "demo/server/plugin/other/plugin.go"}), want: "只能包含一个插件"},
+		{name: "two tops", entries: append(valid(), entry{name: "other/server/plugin/demo/plugin.go"}), want: "顶层目录"},
+		{name: "size", entries: valid(), limits: Limits{MaxSize: 20}, want: "大小超过上限"},
+		{name: "files", entries: valid(), limits: Limits{MaxFiles: 2}, want: "数量超过上限"},
+		{name: "empty", entries: []entry{{name: "demo/plugin.sig", content: "x"}}, want: "非标准插件"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := read(build(t, tt.entries...), tt.limits)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Read() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	signed, err := Sign(build(t, valid()...), private, Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := read(signed, Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, err := pkg.Verify([]ed25519.PublicKey{other, public}); err != nil || !key.Equal(public) {
+		t.Errorf("Verify() = %v, %v", key, err)
+	}
+	if _, err = pkg.Verify([]ed25519.PublicKey{other}); err == nil {
+		t.Error("Verify() with untrusted key succeeded")
+	}
+	resigned, err := Sign(signed, private, Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg, err = read(resigned, Limits{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pkg.Verify([]ed25519.PublicKey{public}); err != nil {
+		t.Errorf("Verify() after resign error = %v", err)
+	}
+
+	pkg.Files[0].Digest = strings.Repeat("0", 64)
+	if _, err = pkg.Verify([]ed25519.PublicKey{public}); err == nil {
+		t.Error("Verify() of tampered package succeeded")
+	}
+	unsigned, _ := read(build(t, valid()...), Limits{})
+	if _, err = unsigned.Verify([]ed25519.PublicKey{public}); err == nil || !strings.Contains(err.Error(), "未签名") {
+		t.Errorf("Verify() of unsigned package error = %v", err)
+	}
+}
+
+func TestTargets(t *testing.T) {
+	dir := t.TempDir()
+	dirs := map[string]string{
+		SideServer: filepath.Join(dir, "server", "plugin"),
+		SideWeb:    filepath.Join(dir, "web", "plugin"),
+	}
+	pkg, err := read(build(t, valid()...), Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(filepath.Join(dirs[SideServer], "demo"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dirs[SideServer], "demo", "plugin.go"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	targets, err := pkg.Targets(dirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 3 || !targets[0].Exists || targets[1].Exists ||
+		targets[2].Dest != filepath.Join(dirs[SideWeb], "demo", "view", "index.vue") {
+		t.Errorf("Targets() = %+v", targets)
+	}
+
+	if err = os.MkdirAll(dirs[SideWeb], os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Symlink(dir, filepath.Join(dirs[SideWeb], "demo")); err != nil {
+		t.Skip(err)
+	}
+	if _, err = pkg.Targets(dirs); err == nil || !strings.Contains(err.Error(), "符号链接") {
+		t.Errorf("Targets() through symlink error = %v", err)
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pack")
+	content := build(t,
+		entry{name: "pack/"},
+		entry{name: "pack/template.json", content: `{"name":"pack"}`},
+		entry{name: "pack/server/model.go.tpl", content: "package {{.Package}}"},
+		entry{name: "__MACOSX/pack/._template.json", content: "x"},
+	)
+	if err := Unzip(bytes.NewReader(content), int64(len(content)), dir, Limits{}); err != nil {
+		t.Fatal(err)
+	}
+	if raw, err := os.ReadFile(filepath.Join(dir, "pack", "server", "model.go.tpl")); err != nil || string(raw) != "package {{.Package}}" {
+		t.Errorf("Unzip() file = %q, %v", raw, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "__MACOSX")); !os.IsNotExist(err) {
+		t.Errorf("Unzip() extracted __MACOSX, err = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		entries []entry
+		limits  Limits
+		want    string
+	}{
+		{name: "zip slip", entries: []entry{{name: "pack/../../x.go", content: "x"}}, want: "文件名不合法"},
+		{name: "absolute", entries: []entry{{name: "/etc/passwd", content: "x"}}, want: "文件名不合法"},
+		{name: "symlink", entries: []entry{{name: "pack/link", content: "/etc/passwd", mode: fs.ModeSymlink | 0777}}, want: "符号链接"},
+		{name: "size", entries: []entry{{name: "pack/a", content: strings.Repeat("x", 32)}}, limits: Limits{MaxSize: 20}, want: "大小超过上限"},
+		{name: "files", entries: []entry{{name: "pack/a"}, {name: "pack/b"}, {name: "pack/c"}}, limits: Limits{MaxFiles: 2}, want: "数量超过上限"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := build(t, tt.entries...)
+			err := Unzip(bytes.NewReader(content), int64(len(content)), t.TempDir(), tt.limits)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Unzip() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
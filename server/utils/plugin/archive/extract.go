@@ -0,0 +1,122 @@
+package archive
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Target 安装包文件的写入位置
+type Target struct {
+	File
+	Dest   string // 目标文件路径
+	Exists bool   // 目标文件已存在 安装时将被覆盖
+}
+
+// Targets 计算全部文件的写入位置 dirs为各端插件目录的上级目录(如 server/plugin)
+// 目标路径必须位于插件自身目录中 且路径上已存在的部分不能是符号链接
+func (p *Package) Targets(dirs map[string]string) ([]Target, error) {
+	targets := make([]Target, 0, len(p.Files))
+	for _, f := range p.Files {
+		base, ok := dirs[f.Side]
+		if !ok {
+			return nil, errors.Errorf("未配置%s端插件目录!", f.Side)
+		}
+		root := filepath.Join(base, p.Plugin)
+		dest := filepath.Join(root, filepath.FromSlash(f.Path))
+		rel, err := filepath.Rel(root, dest)
+		if err != nil || rel == "." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || rel == ".." {
+			return nil, errors.Errorf("[%s]不在插件目录中!", f.Name)
+		}
+		exists, err := checkPath(base, dest)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, Target{File: f, Dest: dest, Exists: exists})
+	}
+	return targets, nil
+}
+
+// checkPath 从base开始逐级检查dest 已存在的部分不能是符号链接 dest本身已存在时必须是普通文件
+func checkPath(base, dest string) (exists bool, err error) {
+	rel, err := filepath.Rel(base, dest)
+	if err != nil {
+		return false, err
+	}
+	current := base
+	parts := strings.Split(rel, string(filepath.Separator))
+	for i, part := range parts {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return false, errors.Errorf("[%s]是符号链接, 拒绝写入!", current)
+		}
+		if i == len(parts)-1 {
+			if !info.Mode().IsRegular() {
+				return false, errors.Errorf("[%s]已存在且不是普通文件, 拒绝覆盖!", current)
+			}
+			return true, nil
+		}
+		if !info.IsDir() {
+			return false, errors.Errorf("[%s]已存在且不是目录!", current)
+		}
+	}
+	return false, nil
+}
+
+// Unzip 按与安装包相同的文件名检查和大小、数量限制解压普通压缩包 不要求插件目录结构 用于模板包等
+// dest应为新建的空目录 压缩包中只允许普通文件和目录
+func Unzip(r io.ReaderAt, size int64, dest string, limits Limits) error {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return errors.Wrap(err, "读取压缩包失败!")
+	}
+	var total int64
+	var count int
+	for _, f := range reader.File {
+		name, err := cleanName(f.Name)
+		if err != nil {
+			return err
+		}
+		if skip(name) {
+			continue
+		}
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		mode := f.Mode()
+		if mode.IsDir() {
+			if err = os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+		if !mode.IsRegular() {
+			return errors.Errorf("[%s]不是普通文件, 压缩包不允许包含符号链接或设备文件!", f.Name)
+		}
+		if count++; count > limits.maxFiles() {
+			return errors.Errorf("压缩包文件数量超过上限%d!", limits.maxFiles())
+		}
+		content, err := readLimited(f, limits.maxSize()-total)
+		if err != nil {
+			return err
+		}
+		total += int64(len(content))
+		if err = os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		if err = os.WriteFile(target, content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const signaturePrefix = "gva-plugin-package/1\n"
+
+// Message 签名的内容 插件名称与按路径排序的全部文件摘要 签名文件本身不参与
+func (p *Package) Message() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(signaturePrefix)
+	buf.WriteString(p.Plugin)
+	buf.WriteByte('\n')
+	for _, f := range p.Files {
+		buf.WriteString(f.Digest)
+		buf.WriteString("  ")
+		buf.WriteString(f.Name)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// Verify 使用受信任的公钥校验签名 返回签名对应的公钥
+func (p *Package) Verify(keys []ed25519.PublicKey) (ed25519.PublicKey, error) {
+	if p.Signature == nil {
+		return nil, errors.New("插件安装包未签名!")
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(p.Signature)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, errors.New("插件安装包签名格式无效!")
+	}
+	message := p.Message()
+	for _, key := range keys {
+		if ed25519.Verify(key, message, signature) {
+			return key, nil
+		}
+	}
+	return nil, errors.New("插件安装包签名校验失败, 签名无效或签名者不在受信任公钥列表中!")
+}
+
+// Sign 签名安装包 返回写入了签名文件的新安装包 已有的签名文件会被替换
+func Sign(content []byte, key ed25519.PrivateKey, limits Limits) ([]byte, error) {
+	pkg, err := Read(bytes.NewReader(content), int64(len(content)), limits)
+	if err != nil {
+		return nil, err
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, pkg.Message()))
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	name := pkg.Top + "/" + SignatureFile
+	for _, f := range reader.File {
+		if strings.TrimSuffix(f.Name, "/") == name {
+			continue
+		}
+		if err = writer.Copy(f); err != nil {
+			return nil, err
+		}
+	}
+	w, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write([]byte(signature + "\n")); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ParsePublicKeys 解析base64编码的ed25519公钥
+func ParsePublicKeys(encoded []string) ([]ed25519.PublicKey, error) {
+	keys := make([]ed25519.PublicKey, 0, len(encoded))
+	for _, s := range encoded {
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, errors.Errorf("受信任公钥[%s]格式无效!", s)
+		}
+		keys = append(keys, raw)
+	}
+	return keys, nil
+}
+
+// ParsePrivateKey 解析base64编码的ed25519私钥种子
+func ParsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, errors.New("签名私钥格式无效, 应为base64编码的32字节ed25519私钥种子!")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
@@ -0,0 +1,204 @@
+package plugin
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// DataFile 插件数据清单文件名 位于插件server目录下 打包时生成 安装时写入菜单/API/权限/字典/参数
+const DataFile = "data.json"
+
+// Data 插件数据清单 记录之间通过name/path/type/key关联 不依赖数据库ID 安装时已存在的记录保持不变
+type Data struct {
+	Menus        []DataMenu       `json:"menus,omitempty"`
+	Apis         []DataApi        `json:"apis,omitempty"`
+	Dictionaries []DataDictionary `json:"dictionaries,omitempty"`
+	Params       []DataParam      `json:"params,omitempty"`
+}
+
+type DataMenu struct {
+	Parent     string              `json:"parent,omitempty"` // 父菜单name 为空或目标系统中不存在时为顶级菜单
+	Name       string              `json:"name"`
+	Path       string              `json:"path"`
+	Component  string              `json:"component"`
+	Hidden     bool                `json:"hidden,omitempty"`
+	Sort       int                 `json:"sort,omitempty"`
+	Meta       DataMenuMeta        `json:"meta"`
+	Buttons    []DataMenuButton    `json:"buttons,omitempty"`
+	Parameters []DataMenuParameter `json:"parameters,omitempty"`
+}
+
+type DataMenuMeta struct {
+	ActiveName     string `json:"activeName,omitempty"`
+	KeepAlive      bool   `json:"keepAlive,omitempty"`
+	DefaultMenu    bool   `json:"defaultMenu,omitempty"`
+	Title          string `json:"title"`
+	Icon           string `json:"icon,omitempty"`
+	CloseTab       bool   `json:"closeTab,omitempty"`
+	TransitionType string `json:"transitionType,omitempty"`
+}
+
+type DataMenuButton struct {
+	Name string `json:"name"`
+	Desc string `json:"desc,omitempty"`
+}
+
+type DataMenuParameter struct {
+	Type  string `json:"type"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type DataApi struct {
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	ApiGroup    string `json:"apiGroup"`
+	Description string `json:"description"`
+	Grant       bool   `json:"grant,omitempty"` // 安装时授权给默认角色
+}
+
+type DataDictionary struct {
+	Type    string                 `json:"type"`
+	Name    string                 `json:"name"`
+	Desc    string                 `json:"desc,omitempty"`
+	Status  *bool                  `json:"status,omitempty"`
+	Details []DataDictionaryDetail `json:"details,omitempty"`
+}
+
+type DataDictionaryDetail struct {
+	Label    string                 `json:"label"`
+	Value    string                 `json:"value"`
+	Extend   string                 `json:"extend,omitempty"`
+	Status   *bool                  `json:"status,omitempty"`
+	Sort     int                    `json:"sort,omitempty"`
+	Children []DataDictionaryDetail `json:"children,omitempty"`
+}
+
+type DataParam struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Desc   string `json:"desc,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Schema string `json:"schema,omitempty"`
+}
+
+// ParseData 解析并校验插件数据清单
+func ParseData(content []byte) (data Data, err error) {
+	err = json.Unmarshal(content, &data)
+	if err != nil {
+		return data, errors.Wrap(err, "解析插件数据清单失败!")
+	}
+	return data, data.Validate()
+}
+
+// Empty 清单中没有任何数据
+func (d Data) Empty() bool {
+	return len(d.Menus) == 0 && len(d.Apis) == 0 && len(d.Dictionaries) == 0 && len(d.Params) == 0
+}
+
+// Validate 校验必填项与唯一性 清单内的菜单父子关系不能成环
+func (d Data) Validate() error {
+	menus := make(map[string]bool, len(d.Menus))
+	for _, menu := range d.Menus {
+		if menu.Name == "" || menu.Path == "" {
+			return errors.New("插件数据清单中的菜单name和path不能为空!")
+		}
+		if menus[menu.Name] {
+			return errors.Errorf("插件数据清单中的菜单[%s]重复!", menu.Name)
+		}
+		menus[menu.Name] = true
+		buttons := make(map[string]bool, len(menu.Buttons))
+		for _, button := range menu.Buttons {
+			if button.Name == "" || buttons[button.Name] {
+				return errors.Errorf("插件数据清单中菜单[%s]的按钮[%s]为空或重复!", menu.Name, button.Name)
+			}
+			buttons[button.Name] = true
+		}
+	}
+	if _, err := d.SortedMenus(); err != nil {
+		return err
+	}
+	apis := make(map[string]bool, len(d.Apis))
+	for _, api := range d.Apis {
+		if api.Path == "" || api.Method == "" {
+			return errors.New("插件数据清单中的API path和method不能为空!")
+		}
+		key := api.Method + " " + api.Path
+		if apis[key] {
+			return errors.Errorf("插件数据清单中的API[%s]重复!", key)
+		}
+		apis[key] = true
+	}
+	dictionaries := make(map[string]bool, len(d.Dictionaries))
+	for _, dictionary := range d.Dictionaries {
+		if dictionary.Type == "" || dictionaries[dictionary.Type] {
+			return errors.Errorf("插件数据清单中的字典[%s]为空或重复!", dictionary.Type)
+		}
+		dictionaries[dictionary.Type] = true
+		if err := validateDetails(dictionary.Type, dictionary.Details); err != nil {
+			return err
+		}
+	}
+	params := make(map[string]bool, len(d.Params))
+	for _, param := range d.Params {
+		if param.Key == "" || params[param.Key] {
+			return errors.Errorf("插件数据清单中的参数[%s]为空或重复!", param.Key)
+		}
+		params[param.Key] = true
+	}
+	return nil
+}
+
+func validateDetails(dictionary string, details []DataDictionaryDetail) error {
+	values := make(map[string]bool, len(details))
+	for _, detail := range details {
+		if values[detail.Value] {
+			return errors.Errorf("插件数据清单中字典[%s]的字典值[%s]重复!", dictionary, detail.Value)
+		}
+		values[detail.Value] = true
+		if err := validateDetails(dictionary, detail.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortedMenus 父菜单在前的菜单顺序 父菜单不在清单中的视为顶级
+func (d Data) SortedMenus() ([]DataMenu, error) {
+	index := make(map[string]DataMenu, len(d.Menus))
+	for _, menu := range d.Menus {
+		index[menu.Name] = menu
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(d.Menus))
+	sorted := make([]DataMenu, 0, len(d.Menus))
+	var visit func(menu DataMenu) error
+	visit = func(menu DataMenu) error {
+		switch state[menu.Name] {
+		case visiting:
+			return errors.Errorf("插件数据清单中的菜单[%s]父子关系成环!", menu.Name)
+		case done:
+			return nil
+		}
+		state[menu.Name] = visiting
+		if parent, ok := index[menu.Parent]; ok {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		state[menu.Name] = done
+		sorted = append(sorted, menu)
+		return nil
+	}
+	for _, menu := range d.Menus {
+		if err := visit(menu); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
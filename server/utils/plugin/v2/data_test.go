@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseData(t *testing.T) {
+	data, err := ParseData([]byte(`{
+		"menus": [
+			{"parent": "demoMenu", "name": "demoInfo", "path": "demoInfo", "component": "plugin/demo/view/info.vue", "meta": {"title": "信息"}, "buttons": [{"name": "add"}]},
+			{"parent": "superAdmin", "name": "demoMenu", "path": "demoMenu", "component": "view/routerHolder.vue", "meta": {"title": "演示"}}
+		],
+		"apis": [{"path": "/demo/info", "method": "GET", "grant": true}],
+		"dictionaries": [{"type": "demo_state", "name": "状态", "details": [{"label": "开", "value": "1", "children": [{"label": "子", "value": "1"}]}]}],
+		"params": [{"key": "demo.size", "name": "大小", "value": "10", "type": "int"}]
+	}`))
+	assert.NoError(t, err)
+	assert.False(t, data.Empty())
+	menus, err := data.SortedMenus()
+	assert.NoError(t, err)
+	assert.Equal(t, "demoMenu", menus[0].Name)
+	assert.Equal(t, "demoInfo", menus[1].Name)
+
+	invalid := []string{
+		`{"menus": [{"name": "a", "path": "a"}, {"name": "a", "path": "b"}]}`,
+		`{"menus": [{"parent": "b", "name": "a", "path": "a"}, {"parent": "a", "name": "b", "path": "b"}]}`,
+		`{"menus": [{"name": "a", "path": "a", "buttons": [{"name": "add"}, {"name": "add"}]}]}`,
+		`{"apis": [{"path": "/a"}]}`,
+		`{"apis": [{"path": "/a", "method": "GET"}, {"path": "/a", "method": "GET"}]}`,
+		`{"dictionaries": [{"type": "a", "details": [{"value": "1"}, {"value": "1"}]}]}`,
+		`{"params": [{"key": ""}]}`,
+	}
+	for _, content := range invalid {
+		_, err = ParseData([]byte(content))
+		assert.Error(t, err, content)
+	}
+}
@@ -0,0 +1,81 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// ManifestFile 插件清单文件名 位于 server/plugin/{name}/ 下 安装时由安装器读取 运行时通过 go:embed 嵌入插件
+const ManifestFile = "plugin.json"
+
+var manifestName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// Manifest 插件清单
+type Manifest struct {
+	Name         string            `json:"name"`         // 插件名称 与 server/plugin 下的目录名一致
+	Version      string            `json:"version"`      // 插件版本 语义化版本 如 1.2.0
+	Core         string            `json:"core"`         // 兼容的核心版本范围 如 ">=2.8.0 <3.0.0" 为空表示不限制
+	Dependencies map[string]string `json:"dependencies"` // 依赖的插件及版本范围
+	Description  string            `json:"description"`  // 插件描述
+}
+
+// ParseManifest 解析并校验插件清单
+func ParseManifest(data []byte) (Manifest, error) {
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("解析插件清单失败: %w", err)
+	}
+	return manifest, manifest.Validate()
+}
+
+// MustManifest 解析嵌入插件的清单 清单错误属于开发期问题 直接panic
+func MustManifest(data []byte) Manifest {
+	manifest, err := ParseManifest(data)
+	if err != nil {
+		panic(err)
+	}
+	return manifest
+}
+
+// Validate 校验清单字段与版本范围格式
+func (m Manifest) Validate() error {
+	if !manifestName.MatchString(m.Name) {
+		return fmt.Errorf("插件名称[%s]不合法", m.Name)
+	}
+	if !ValidVersion(m.Version) {
+		return fmt.Errorf("插件[%s]版本[%s]不是合法的语义化版本", m.Name, m.Version)
+	}
+	if _, err := Satisfies(m.Version, m.Core); err != nil {
+		return fmt.Errorf("插件[%s]核心版本范围错误: %w", m.Name, err)
+	}
+	for name, constraint := range m.Dependencies {
+		if name == m.Name {
+			return fmt.Errorf("插件[%s]不能依赖自身", m.Name)
+		}
+		if _, err := Satisfies(m.Version, constraint); err != nil {
+			return fmt.Errorf("插件[%s]依赖[%s]版本范围错误: %w", m.Name, name, err)
+		}
+	}
+	return nil
+}
+
+// Describer 声明清单的插件 未实现的插件视为旧版插件 不参与版本登记与依赖检查
+type Describer interface {
+	Manifest() Manifest
+}
+
+// Migration 插件升级步骤 升级到 Version 时执行 用于调整表结构与初始化数据
+type Migration struct {
+	Version string
+	Migrate func(ctx context.Context, tx *gorm.DB) error
+}
+
+// Migrator 提供升级步骤的插件 升级时在同一事务中依次执行已安装版本之后 到当前版本为止的步骤
+// MySQL 等数据库的DDL会隐式提交事务 涉及表结构的步骤应当可以重复执行
+type Migrator interface {
+	Migrations() []Migration
+}
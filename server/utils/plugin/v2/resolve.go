@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Resolve 检查核心版本兼容性与插件依赖
+// 返回可启用插件按依赖排序后的名称 被依赖的插件在前 以及无法启用的插件及原因
+// 依赖了无法启用插件的插件同样无法启用
+func Resolve(core string, manifests []Manifest) (order []string, failed map[string]error) {
+	failed = make(map[string]error)
+	index := make(map[string]Manifest, len(manifests))
+	for _, manifest := range manifests {
+		if _, ok := index[manifest.Name]; ok {
+			failed[manifest.Name] = fmt.Errorf("插件[%s]重复注册", manifest.Name)
+			continue
+		}
+		index[manifest.Name] = manifest
+	}
+	for name, manifest := range index {
+		if _, ok := failed[name]; ok {
+			continue
+		}
+		if err := manifest.Validate(); err != nil {
+			failed[name] = err
+			continue
+		}
+		if ok, _ := Satisfies(core, manifest.Core); !ok {
+			failed[name] = fmt.Errorf("插件[%s]要求核心版本[%s] 当前核心版本为%s", name, manifest.Core, core)
+			continue
+		}
+		for dependency, constraint := range manifest.Dependencies {
+			target, ok := index[dependency]
+			if !ok {
+				failed[name] = fmt.Errorf("插件[%s]依赖的插件[%s]未安装", name, dependency)
+				break
+			}
+			if ok, _ = Satisfies(target.Version, constraint); !ok {
+				failed[name] = fmt.Errorf("插件[%s]要求插件[%s]的版本为[%s] 当前版本为%s", name, dependency, constraint, target.Version)
+				break
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, manifest := range index {
+			if _, ok := failed[name]; ok {
+				continue
+			}
+			for dependency := range manifest.Dependencies {
+				if _, ok := failed[dependency]; ok {
+					failed[name] = fmt.Errorf("插件[%s]依赖的插件[%s]无法启用", name, dependency)
+					changed = true
+					break
+				}
+			}
+		}
+	} // 依赖失败向上传递
+
+	names := make([]string, 0, len(index))
+	for name := range index {
+		if _, ok := failed[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	visited := make(map[string]bool, len(names))
+	for len(names) > 0 {
+		var rest []string
+		for _, name := range names {
+			ready := true
+			for dependency := range index[name].Dependencies {
+				if !visited[dependency] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				visited[name] = true
+				order = append(order, name)
+				continue
+			}
+			rest = append(rest, name)
+		}
+		if len(rest) == len(names) {
+			for _, name := range rest {
+				failed[name] = fmt.Errorf("插件[%s]存在循环依赖", name)
+			}
+			break
+		}
+		names = rest
+	}
+	return order, failed
+}
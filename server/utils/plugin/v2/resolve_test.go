@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	order, failed := Resolve("2.8.2", []Manifest{
+		{Name: "report", Version: "1.0.0", Dependencies: map[string]string{"announcement": "^1.0.0", "email": ">=1.1.0"}},
+		{Name: "email", Version: "1.1.0", Core: ">=2.8.0"},
+		{Name: "announcement", Version: "1.2.0", Dependencies: map[string]string{"email": "^1.0.0"}},
+		{Name: "legacy", Version: "1.0.0", Core: "<2.0.0"},
+		{Name: "chart", Version: "1.0.0", Dependencies: map[string]string{"legacy": "*"}},
+		{Name: "missing", Version: "1.0.0", Dependencies: map[string]string{"sms": "*"}},
+		{Name: "old", Version: "1.0.0", Dependencies: map[string]string{"email": "^2.0.0"}},
+		{Name: "a", Version: "1.0.0", Dependencies: map[string]string{"b": "*"}},
+		{Name: "b", Version: "1.0.0", Dependencies: map[string]string{"a": "*"}},
+	})
+	assert.Equal(t, []string{"email", "announcement", "report"}, order)
+	assert.ElementsMatch(t, []string{"legacy", "chart", "missing", "old", "a", "b"}, keys(failed))
+	assert.Contains(t, failed["legacy"].Error(), "核心版本")
+	assert.Contains(t, failed["chart"].Error(), "[legacy]无法启用")
+	assert.Contains(t, failed["missing"].Error(), "未安装")
+	assert.Contains(t, failed["a"].Error(), "循环依赖")
+}
+
+func TestParseManifest(t *testing.T) {
+	manifest, err := ParseManifest([]byte(`{"name":"announcement","version":"1.0.0","core":">=2.8.0","dependencies":{"email":"^1.0.0"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "^1.0.0", manifest.Dependencies["email"])
+
+	_, err = ParseManifest([]byte(`{"name":"announcement","version":"1.0"}`))
+	assert.Error(t, err)
+	_, err = ParseManifest([]byte(`{"name":"../x","version":"1.0.0"}`))
+	assert.Error(t, err)
+	_, err = ParseManifest([]byte(`{"name":"a","version":"1.0.0","dependencies":{"a":"*"}}`))
+	assert.Error(t, err)
+}
+
+func keys(m map[string]error) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// operators 版本范围支持的比较符 两个字符的需要排在前面
+var operators = []string{">=", "<=", ">", "<", "=", "^", "~"}
+
+// canonical 补全v前缀 转为 golang.org/x/mod/semver 使用的格式
+func canonical(version string) string {
+	version = strings.TrimSpace(version)
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return version
+}
+
+// ValidVersion 是否为合法的语义化版本 v前缀可省略 必须包含完整的 主版本.次版本.修订号
+func ValidVersion(version string) bool {
+	version = canonical(version)
+	if !semver.IsValid(version) {
+		return false
+	}
+	core, _, _ := strings.Cut(strings.TrimSuffix(version, semver.Build(version)), "-")
+	return strings.Count(core, ".") == 2
+}
+
+// CompareVersion 比较两个版本 返回 -1 0 1
+func CompareVersion(a, b string) int {
+	return semver.Compare(canonical(a), canonical(b))
+}
+
+// Satisfies 版本是否满足范围
+// 范围由空格分隔的条件组成 条件之间为且的关系 支持 = > >= < <= ^ ~ 前缀 为空或*表示不限制
+// ^1.2.0 表示 >=1.2.0 <2.0.0 ~1.2.0 表示 >=1.2.0 <1.3.0
+func Satisfies(version, constraint string) (bool, error) {
+	if !ValidVersion(version) {
+		return false, fmt.Errorf("版本[%s]不合法", version)
+	}
+	version = canonical(version)
+	ok := true
+	for _, condition := range strings.Fields(constraint) {
+		if condition == "*" {
+			continue
+		}
+		var operator string
+		for _, prefix := range operators {
+			if strings.HasPrefix(condition, prefix) {
+				operator = prefix
+				break
+			}
+		}
+		target := canonical(strings.TrimPrefix(condition, operator))
+		if !ValidVersion(target) {
+			return false, fmt.Errorf("版本范围[%s]不合法", condition)
+		}
+		compare := semver.Compare(version, target)
+		switch operator {
+		case "", "=":
+			ok = ok && compare == 0
+		case ">":
+			ok = ok && compare > 0
+		case ">=":
+			ok = ok && compare >= 0
+		case "<":
+			ok = ok && compare < 0
+		case "<=":
+			ok = ok && compare <= 0
+		case "^":
+			ok = ok && compare >= 0 && semver.Major(version) == semver.Major(target)
+		case "~":
+			ok = ok && compare >= 0 && semver.MajorMinor(version) == semver.MajorMinor(target)
+		}
+	}
+	return ok, nil
+}
+
+// Pending 已安装版本 from 升级到 to 时需要执行的步骤 按版本排序
+// from 为空表示首次安装 首次安装由插件的初始化直接建立最新的表结构 不执行升级步骤
+func Pending(migrations []Migration, from, to string) ([]Migration, error) {
+	if from == "" {
+		return nil, nil
+	}
+	pending := make([]Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if !ValidVersion(migration.Version) {
+			return nil, fmt.Errorf("升级步骤版本[%s]不合法", migration.Version)
+		}
+		if CompareVersion(migration.Version, from) > 0 && CompareVersion(migration.Version, to) <= 0 {
+			pending = append(pending, migration)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return CompareVersion(pending[i].Version, pending[j].Version) < 0
+	})
+	return pending, nil
+}
@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"2.8.2", "", true},
+		{"2.8.2", "*", true},
+		{"v2.8.2", ">=2.8.0 <3.0.0", true},
+		{"3.0.0", ">=2.8.0 <3.0.0", false},
+		{"1.4.1", "^1.2.0", true},
+		{"2.0.0", "^1.2.0", false},
+		{"1.2.9", "~1.2.0", true},
+		{"1.3.0", "~1.2.0", false},
+		{"1.0.0", "1.0.0", true},
+		{"1.0.1", "=1.0.0", false},
+		{"1.0.0-beta", "<1.0.0", true},
+	}
+	for _, tt := range tests {
+		got, err := Satisfies(tt.version, tt.constraint)
+		assert.NoError(t, err, tt.constraint)
+		assert.Equal(t, tt.want, got, "%s %s", tt.version, tt.constraint)
+	}
+	_, err := Satisfies("1.0", ">=1.0.0")
+	assert.Error(t, err)
+	_, err = Satisfies("1.0.0", ">=x")
+	assert.Error(t, err)
+}
+
+func TestPending(t *testing.T) {
+	migrations := []Migration{{Version: "1.2.0"}, {Version: "1.1.0"}, {Version: "1.3.0"}, {Version: "1.0.0"}}
+	pending, err := Pending(migrations, "1.0.0", "1.2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []Migration{{Version: "1.1.0"}, {Version: "1.2.0"}}, pending)
+
+	pending, err = Pending(migrations, "", "1.3.0")
+	assert.NoError(t, err)
+	assert.Empty(t, pending) // 首次安装不执行升级步骤
+
+	_, err = Pending([]Migration{{Version: "next"}}, "1.0.0", "1.3.0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package siem
+
+import (
+	"path"
+	"time"
+)
+
+// Event 统一的审计事件 由操作记录和NFC审计日志转换而来
+type Event struct {
+	ID         uint              `json:"id"`
+	Source     string            `json:"source"`   // 事件来源 operation|nfc_audit
+	Type       string            `json:"type"`     // 事件类型 如 operation.POST、nfc.session_established
+	Time       time.Time         `json:"time"`     // 事件时间
+	Severity   int               `json:"severity"` // 严重程度 0-10 (CEF)
+	Name       string            `json:"name"`     // 事件名称
+	UserID     string            `json:"user_id,omitempty"`
+	SourceIP   string            `json:"source_ip,omitempty"`
+	Action     string            `json:"action,omitempty"`
+	Outcome    string            `json:"outcome,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Extensions map[string]string `json:"extensions,omitempty"`
+}
+
+// Filter 按事件类型过滤 支持 path.Match 通配符 如 nfc.* 为空表示全部放行
+type Filter []string
+
+func (f Filter) Match(e Event) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, pattern := range f {
+		if ok, _ := path.Match(pattern, e.Type); ok {
+			return true
+		}
+	}
+	return false
+}
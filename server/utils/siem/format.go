@@ -0,0 +1,205 @@
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCEF    = "cef"
+	FormatSyslog = "syslog" // RFC 5424
+	FormatJSONL  = "jsonl"
+)
+
+const (
+	vendor  = "gin-vue-admin"
+	product = "gva"
+	// syslog facility 13: log audit
+	facilityLogAudit = 13
+	// RFC 5424 结构化数据私有企业号占位
+	sdID = "gva@32473"
+)
+
+// Formatter 将事件渲染为单条消息(不含换行)
+type Formatter interface {
+	Format(e Event) ([]byte, error)
+}
+
+// NewFormatter 根据名称创建格式化器
+func NewFormatter(name, version string) (Formatter, error) {
+	switch name {
+	case FormatCEF:
+		return cefFormatter{version: version}, nil
+	case FormatSyslog:
+		hostname, _ := os.Hostname()
+		return syslogFormatter{hostname: hostname, procID: strconv.Itoa(os.Getpid())}, nil
+	case FormatJSONL, "":
+		return jsonFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("不支持的格式: %s", name)
+	}
+}
+
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(e Event) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+type cefFormatter struct {
+	version string
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`)
+)
+
+// Format CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension
+func (f cefFormatter) Format(e Event) ([]byte, error) {
+	ext := map[string]string{
+		"rt":            strconv.FormatInt(e.Time.UnixMilli(), 10),
+		"externalId":    strconv.FormatUint(uint64(e.ID), 10),
+		"cat":           e.Source,
+		"suser":         e.UserID,
+		"src":           e.SourceIP,
+		"act":           e.Action,
+		"outcome":       e.Outcome,
+		"msg":           e.Message,
+		"deviceProcess": product,
+	}
+	for k, v := range e.Extensions {
+		ext[k] = v
+	}
+	keys := make([]string, 0, len(ext))
+	for k, v := range ext {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	fmt.Fprintf(&b, "CEF:0|%s|%s|%s|%s|%s|%d|",
+		cefHeaderEscaper.Replace(vendor),
+		cefHeaderEscaper.Replace(product),
+		cefHeaderEscaper.Replace(f.version),
+		cefHeaderEscaper.Replace(e.Type),
+		cefHeaderEscaper.Replace(e.Name),
+		clampSeverity(e.Severity),
+	)
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(cefExtensionEscaper.Replace(ext[k]))
+	}
+	return []byte(b.String()), nil
+}
+
+type syslogFormatter struct {
+	hostname string
+	procID   string
+}
+
+var sdParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// Format <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ELEMENT] MSG
+func (f syslogFormatter) Format(e Event) ([]byte, error) {
+	pri := facilityLogAudit*8 + syslogSeverity(e.Severity)
+	params := map[string]string{
+		"id":      strconv.FormatUint(uint64(e.ID), 10),
+		"source":  e.Source,
+		"type":    e.Type,
+		"user":    e.UserID,
+		"src":     e.SourceIP,
+		"action":  e.Action,
+		"outcome": e.Outcome,
+	}
+	for k, v := range e.Extensions {
+		params[k] = v
+	}
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s %s [%s",
+		pri,
+		e.Time.UTC().Format(time.RFC3339Nano),
+		nilValue(printable(f.hostname, 255)),
+		product,
+		nilValue(printable(f.procID, 128)),
+		nilValue(printable(e.Type, 32)),
+		sdID,
+	)
+	for _, k := range keys {
+		fmt.Fprintf(&b, ` %s="%s"`, k, sdParamEscaper.Replace(params[k]))
+	}
+	b.WriteByte(']')
+	msg := e.Message
+	if msg == "" {
+		msg = e.Name
+	}
+	if msg != "" {
+		b.WriteByte(' ')
+		b.WriteString(strings.NewReplacer("\r", " ", "\n", " ").Replace(msg))
+	}
+	return []byte(b.String()), nil
+}
+
+func clampSeverity(s int) int {
+	if s < 0 {
+		return 0
+	}
+	if s > 10 {
+		return 10
+	}
+	return s
+}
+
+// syslogSeverity 将 CEF 0-10 映射为 syslog 严重程度
+func syslogSeverity(s int) int {
+	switch {
+	case s >= 9:
+		return 2 // critical
+	case s >= 7:
+		return 3 // error
+	case s >= 5:
+		return 4 // warning
+	case s >= 4:
+		return 5 // notice
+	default:
+		return 6 // informational
+	}
+}
+
+// printable RFC 5424 header 字段仅允许可打印ASCII且有长度限制
+func printable(s string, max int) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+		if b.Len() >= max {
+			break
+		}
+	}
+	return b.String()
+}
+
+func nilValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,190 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testEvent = Event{
+	ID:       42,
+	Source:   "operation",
+	Type:     "operation.POST",
+	Time:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	Severity: 5,
+	Name:     "POST /api|create",
+	UserID:   "1",
+	SourceIP: "10.0.0.1",
+	Action:   "/api/createApi",
+	Outcome:  "400",
+	Message:  "a=b\nc",
+}
+
+func TestFormatters(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{
+			format: FormatCEF,
+			want: []string{
+				`CEF:0|gin-vue-admin|gva|v1|operation.POST|POST /api\|create|5|`,
+				`msg=a\=b\nc`,
+				`rt=1735787045000`,
+				`externalId=42`,
+			},
+		},
+		{
+			format: FormatSyslog,
+			want: []string{
+				`<108>1 2025-01-02T03:04:05Z `,
+				` gva `,
+				` operation.POST [gva@32473 `,
+				`action="/api/createApi"`,
+				`] a=b c`,
+			},
+		},
+		{
+			format: FormatJSONL,
+			want:   []string{`"id":42`, `"type":"operation.POST"`, `"message":"a=b\nc"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			f, err := NewFormatter(tt.format, "v1")
+			assert.Nil(t, err)
+			out, err := f.Format(testEvent)
+			assert.Nil(t, err)
+			assert.NotContains(t, string(out), "\n")
+			for _, w := range tt.want {
+				assert.Contains(t, string(out), w)
+			}
+		})
+	}
+	_, err := NewFormatter("leef", "v1")
+	assert.NotNil(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	assert.True(t, Filter(nil).Match(testEvent))
+	assert.True(t, Filter{"nfc.*", "operation.*"}.Match(testEvent))
+	assert.False(t, Filter{"nfc.*"}.Match(testEvent))
+}
+
+func TestTCPSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var lines []string
+		for i := 0; i < 2; i++ {
+			line, _ := r.ReadString('\n')
+			lines = append(lines, line)
+		}
+		received <- strings.Join(lines, "")
+	}()
+
+	sink, err := NewSink(SinkOptions{Type: SinkTCP, Address: ln.Addr().String(), Format: FormatCEF})
+	assert.Nil(t, err)
+	defer sink.Close()
+	assert.Nil(t, sink.Send(context.Background(), [][]byte{[]byte("one"), []byte("two")}))
+	assert.Equal(t, "one\ntwo\n", <-received)
+}
+
+func TestTCPSink_OctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, len("5 hello"))
+		_, _ = io.ReadFull(conn, buf)
+		received <- string(buf)
+	}()
+
+	sink, err := NewSink(SinkOptions{Type: SinkTCP, Address: ln.Addr().String(), Format: FormatSyslog})
+	assert.Nil(t, err)
+	defer sink.Close()
+	assert.Nil(t, sink.Send(context.Background(), [][]byte{[]byte("hello")}))
+	assert.Equal(t, "5 hello", <-received)
+}
+
+func TestUDPSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	sink, err := NewSink(SinkOptions{Type: SinkUDP, Address: conn.LocalAddr().String()})
+	assert.Nil(t, err)
+	defer sink.Close()
+	assert.Nil(t, sink.Send(context.Background(), [][]byte{[]byte("<110>1 - - - - - - hi")}))
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "<110>1 - - - - - - hi", string(buf[:n]))
+}
+
+func TestHTTPSink(t *testing.T) {
+	var body, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, auth = string(b), r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	sink, err := NewSink(SinkOptions{Type: SinkHTTP, URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer x"}})
+	assert.Nil(t, err)
+	assert.Nil(t, sink.Send(context.Background(), [][]byte{[]byte(`{"a":1}`), []byte(`{"a":2}`)}))
+	assert.Equal(t, "{\"a\":1}\n{\"a\":2}\n", body)
+	assert.Equal(t, "Bearer x", auth)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	sink, _ = NewSink(SinkOptions{Type: SinkHTTP, URL: failing.URL})
+	assert.NotNil(t, sink.Send(context.Background(), [][]byte{[]byte("x")}))
+}
+
+func TestFileSink_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "siem.log")
+	sink, err := NewSink(SinkOptions{Type: SinkFile, Path: path, MaxSize: 8, MaxBackups: 2})
+	assert.Nil(t, err)
+	defer sink.Close()
+	for _, msg := range []string{"aaaa", "bbbb", "cccc", "dddd"} {
+		assert.Nil(t, sink.Send(context.Background(), [][]byte{[]byte(msg)}))
+	}
+	read := func(p string) string {
+		b, _ := os.ReadFile(p)
+		return string(b)
+	}
+	assert.Equal(t, "dddd\n", read(path))
+	assert.Equal(t, "cccc\n", read(path+".1"))
+	assert.Equal(t, "bbbb\n", read(path+".2"))
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,246 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	SinkTCP  = "tcp"
+	SinkUDP  = "udp"
+	SinkHTTP = "http"
+	SinkFile = "file"
+)
+
+// Sink 事件投递目标 Send 返回 nil 表示整批已投递成功
+type Sink interface {
+	Send(ctx context.Context, messages [][]byte) error
+	Close() error
+}
+
+// SinkOptions 投递目标配置
+type SinkOptions struct {
+	Type       string            // tcp|udp|http|file
+	Format     string            // cef|syslog|jsonl 决定 TCP 分帧方式
+	Address    string            // tcp/udp 地址 host:port
+	URL        string            // http 批量投递地址
+	Headers    map[string]string // http 额外请求头
+	Path       string            // 文件路径
+	MaxSize    int64             // 单文件最大字节数 超出后轮转
+	MaxBackups int               // 保留的轮转文件数量
+	Timeout    time.Duration     // 网络超时
+}
+
+// NewSink 根据配置创建投递目标
+func NewSink(opts SinkOptions) (Sink, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	switch opts.Type {
+	case SinkTCP, SinkUDP:
+		if opts.Address == "" {
+			return nil, fmt.Errorf("%s sink 缺少 address", opts.Type)
+		}
+		return &netSink{network: opts.Type, address: opts.Address, timeout: opts.Timeout, octetCounting: opts.Format == FormatSyslog}, nil
+	case SinkHTTP:
+		if opts.URL == "" {
+			return nil, fmt.Errorf("http sink 缺少 url")
+		}
+		return &httpSink{url: opts.URL, headers: opts.Headers, client: &http.Client{Timeout: opts.Timeout}}, nil
+	case SinkFile:
+		if opts.Path == "" {
+			return nil, fmt.Errorf("file sink 缺少 path")
+		}
+		return &fileSink{path: opts.Path, maxSize: opts.MaxSize, maxBackups: opts.MaxBackups}, nil
+	default:
+		return nil, fmt.Errorf("不支持的 sink 类型: %s", opts.Type)
+	}
+}
+
+// netSink syslog over TCP/UDP
+// TCP 下 syslog 格式使用 RFC 6587 octet-counting 分帧 其余格式以换行分隔
+type netSink struct {
+	network       string
+	address       string
+	timeout       time.Duration
+	octetCounting bool
+	mu            sync.Mutex
+	conn          net.Conn
+}
+
+func (s *netSink) Send(ctx context.Context, messages [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		dialer := net.Dialer{Timeout: s.timeout}
+		conn, err := dialer.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	for _, msg := range messages {
+		var err error
+		if s.network == SinkUDP {
+			_, err = s.conn.Write(msg)
+		} else {
+			_, err = s.conn.Write(s.frame(msg))
+		}
+		if err != nil {
+			// 连接异常时丢弃连接 下次重连后整批重发
+			_ = s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *netSink) frame(msg []byte) []byte {
+	if s.octetCounting {
+		return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+	return append(msg, '\n')
+}
+
+func (s *netSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// httpSink 以 application/x-ndjson 批量 POST
+type httpSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (s *httpSink) Send(ctx context.Context, messages [][]byte) error {
+	var body bytes.Buffer
+	for _, msg := range messages {
+		body.Write(msg)
+		body.WriteByte('\n')
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("http sink 返回状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// fileSink 按大小轮转的文件 path -> path.1 -> path.2 ...
+type fileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	mu         sync.Mutex
+	file       *os.File
+	size       int64
+}
+
+func (s *fileSink) Send(_ context.Context, messages [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.open(); err != nil {
+		return err
+	}
+	for _, msg := range messages {
+		if s.maxSize > 0 && s.size > 0 && s.size+int64(len(msg))+1 > s.maxSize {
+			if err := s.rotate(); err != nil {
+				return err
+			}
+		}
+		n, err := s.file.Write(append(msg, '\n'))
+		s.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return s.file.Sync()
+}
+
+func (s *fileSink) open() error {
+	if s.file != nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	s.file, s.size = file, info.Size()
+	return nil
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+	if s.maxBackups <= 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.open()
+	}
+	_ = os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxBackups))
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,289 @@
+// Package templatepack 代码生成器的外部模板包
+// 模板包为包含 manifest.json 的目录 清单中声明名称、版本、额外变量的JSON Schema以及模板文件与生成路径的对应关系
+package templatepack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/jsonschema"
+)
+
+// ManifestName 模板包清单文件名
+const ManifestName = "manifest.json"
+
+var (
+	namePattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?$`)
+	// Extends 可以继承的内置模板
+	Extends = []string{"package", "plugin"}
+)
+
+// Manifest 模板包清单
+type Manifest struct {
+	Name        string          `json:"name"`        // 名称 小写字母、数字、-和_
+	Version     string          `json:"version"`     // 版本 形如1.0.0
+	Description string          `json:"description"` // 描述
+	Extends     string          `json:"extends"`     // 继承的内置模板 package/plugin 模板包中的文件覆盖生成路径相同的内置模板
+	Variables   json.RawMessage `json:"variables"`   // 额外变量的JSON Schema 顶层为object 模板中通过 .Variables.xxx 使用
+	Files       []File          `json:"files"`
+}
+
+// File 模板文件与生成路径
+type File struct {
+	Template string `json:"template"` // 相对于模板包目录的模板文件 以.tpl结尾
+	Target   string `json:"target"`   // 生成文件相对于项目根目录的路径 支持模板语法
+}
+
+// Pack 已加载的模板包
+type Pack struct {
+	Manifest
+	Dir      string
+	schema   *jsonschema.Schema
+	defaults map[string]any
+	types    map[string][]string
+}
+
+type variablesSchema struct {
+	Type       jsonschema.Types `json:"type"`
+	Properties map[string]struct {
+		Type    jsonschema.Types `json:"type"`
+		Default json.RawMessage  `json:"default"`
+	} `json:"properties"`
+}
+
+// Load 读取并检查模板包目录
+func Load(dir string) (*Pack, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("读取模板包清单失败: %w", err)
+	}
+	pack := &Pack{Dir: dir, defaults: make(map[string]any), types: make(map[string][]string)}
+	if err = json.Unmarshal(content, &pack.Manifest); err != nil {
+		return nil, fmt.Errorf("模板包清单格式错误: %w", err)
+	}
+	if !namePattern.MatchString(pack.Name) {
+		return nil, fmt.Errorf("模板包名称[%s]不合法 只能包含小写字母、数字、-和_", pack.Name)
+	}
+	if slices.Contains(Extends, pack.Name) {
+		return nil, fmt.Errorf("模板包名称[%s]与内置模板重复", pack.Name)
+	}
+	if !versionPattern.MatchString(pack.Version) {
+		return nil, fmt.Errorf("模板包版本[%s]不合法 请使用形如1.0.0的版本号", pack.Version)
+	}
+	if !slices.Contains(Extends, pack.Extends) {
+		return nil, fmt.Errorf("模板包只能继承%s", strings.Join(Extends, "/"))
+	}
+	if len(pack.Files) == 0 {
+		return nil, fmt.Errorf("模板包没有声明模板文件")
+	}
+	for _, file := range pack.Files {
+		if err = pack.checkFile(file); err != nil {
+			return nil, err
+		}
+	}
+	if err = pack.compileVariables(); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+func (p *Pack) checkFile(file File) error {
+	if file.Template == "" || file.Target == "" {
+		return fmt.Errorf("模板文件和生成路径不能为空")
+	}
+	if filepath.Ext(file.Template) != ".tpl" {
+		return fmt.Errorf("[template:%s]模板文件需以.tpl结尾", file.Template)
+	}
+	if !filepath.IsLocal(filepath.FromSlash(file.Template)) {
+		return fmt.Errorf("[template:%s]模板文件必须位于模板包目录内", file.Template)
+	}
+	info, err := os.Stat(filepath.Join(p.Dir, file.Template))
+	if err != nil {
+		return fmt.Errorf("[template:%s]模板文件不存在", file.Template)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("[template:%s]不是文件", file.Template)
+	}
+	return nil
+}
+
+func (p *Pack) compileVariables() error {
+	if len(p.Variables) == 0 || string(p.Variables) == "null" {
+		return nil
+	}
+	schema, err := jsonschema.Compile(p.Variables)
+	if err != nil {
+		return fmt.Errorf("模板变量: %w", err)
+	}
+	var raw variablesSchema
+	_ = json.Unmarshal(p.Variables, &raw)
+	if len(raw.Type) != 1 || raw.Type[0] != "object" {
+		return fmt.Errorf("模板变量的JSON Schema顶层需为object")
+	}
+	for name, property := range raw.Properties {
+		p.types[name] = property.Type
+		if len(property.Default) == 0 {
+			continue
+		}
+		var value any
+		if err = json.Unmarshal(property.Default, &value); err != nil {
+			return fmt.Errorf("模板变量%s的默认值格式错误: %w", name, err)
+		}
+		p.defaults[name] = value
+	}
+	p.schema = schema
+	return nil
+}
+
+// Resolve 补全默认值并按JSON Schema校验额外变量
+func (p *Pack) Resolve(variables map[string]any) (map[string]any, error) {
+	resolved := make(map[string]any, len(variables)+len(p.defaults))
+	for name, value := range p.defaults {
+		resolved[name] = value
+	}
+	for name, value := range variables {
+		resolved[name] = value
+	}
+	if p.schema == nil {
+		return resolved, nil
+	}
+	// 统一为json.Unmarshal的结果 便于按JSON Schema校验
+	content, err := json.Marshal(resolved)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	_ = json.Unmarshal(content, &value)
+	if err = p.schema.ValidateValue(value); err != nil {
+		return nil, fmt.Errorf("模板变量校验失败: %w", err)
+	}
+	return value.(map[string]any), nil
+}
+
+// Sample 用于校验模板的变量 没有默认值时使用对应类型的零值
+func (p *Pack) Sample() map[string]any {
+	sample := make(map[string]any, len(p.types))
+	for name, types := range p.types {
+		if value, ok := p.defaults[name]; ok {
+			sample[name] = value
+			continue
+		}
+		var typ string
+		if len(types) > 0 {
+			typ = types[0]
+		}
+		switch typ {
+		case "integer", "number":
+			sample[name] = float64(0)
+		case "boolean":
+			sample[name] = false
+		case "array":
+			sample[name] = []any{}
+		case "object":
+			sample[name] = map[string]any{}
+		default:
+			sample[name] = ""
+		}
+	}
+	return sample
+}
+
+// Parser 解析模板文件 与内置模板使用同一解析方式 以便模板包引用公共模板定义
+type Parser func(path string) (*template.Template, error)
+
+// Targets 渲染生成路径 返回模板文件绝对路径=>生成文件相对于项目根目录的路径
+// 生成路径必须位于roots中的某个目录内 roots为生成模块自身的目录 相对于项目根目录
+func (p *Pack) Targets(funcs template.FuncMap, data any, roots []string) (map[string]string, error) {
+	targets := make(map[string]string, len(p.Files))
+	for _, file := range p.Files {
+		target, err := p.render(file.Target, funcs, data)
+		if err != nil {
+			return nil, fmt.Errorf("[target:%s]生成路径渲染失败: %w", file.Target, err)
+		}
+		target = filepath.Clean(filepath.FromSlash(strings.TrimSpace(target)))
+		if !filepath.IsLocal(target) {
+			return nil, fmt.Errorf("[target:%s]生成路径必须位于项目目录内", file.Target)
+		}
+		if !within(target, roots) {
+			return nil, fmt.Errorf("[target:%s]生成路径必须位于模块目录内: %s", file.Target, strings.Join(roots, ", "))
+		}
+		targets[filepath.Join(p.Dir, file.Template)] = target
+	}
+	return targets, nil
+}
+
+// within target是否位于roots中某个目录之下
+func within(target string, roots []string) bool {
+	for _, root := range roots {
+		rel, err := filepath.Rel(filepath.Clean(root), target)
+		if err == nil && rel != "." && filepath.IsLocal(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 使用示例数据渲染全部模板文件和生成路径 info为模板数据 layout与roots同 Targets
+func (p *Pack) Validate(parse Parser, funcs template.FuncMap, info any, layout any, roots []string) error {
+	if _, err := p.Targets(funcs, layout, roots); err != nil {
+		return err
+	}
+	for _, file := range p.Files {
+		files, err := parse(filepath.Join(p.Dir, file.Template))
+		if err != nil {
+			return fmt.Errorf("[template:%s]模板解析失败: %w", file.Template, err)
+		}
+		if err = files.Execute(io.Discard, info); err != nil {
+			return fmt.Errorf("[template:%s]模板渲染失败: %w", file.Template, err)
+		}
+	}
+	return nil
+}
+
+func (p *Pack) render(text string, funcs template.FuncMap, data any) (string, error) {
+	tpl, err := template.New("target").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	if err = tpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// Compare 比较版本号 a<b返回-1 a==b返回0 a>b返回1 带预发布标识的版本小于正式版本
+func Compare(a, b string) int {
+	ma, mb := versionPattern.FindStringSubmatch(a), versionPattern.FindStringSubmatch(b)
+	if ma == nil || mb == nil {
+		return strings.Compare(a, b)
+	}
+	for i := 1; i <= 3; i++ {
+		x, _ := strconv.Atoi(ma[i])
+		y, _ := strconv.Atoi(mb[i])
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case ma[4] == mb[4]:
+		return 0
+	case ma[4] == "":
+		return 1
+	case mb[4] == "":
+		return -1
+	}
+	return strings.Compare(ma[4], mb[4])
+}
@@ -0,0 +1,116 @@
+package templatepack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parse 测试用的模板解析 载入公共定义 partial
+func parse(path string) (*template.Template, error) {
+	files, err := template.New(filepath.Base(path)).ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	return files.Parse(`{{define "partial"}}{{.Package}}{{end}}`)
+}
+
+func writePack(t *testing.T, manifest string, files map[string]string) string {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ManifestName), []byte(manifest), 0644))
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+const manifest = `{
+  "name": "audit",
+  "version": "1.2.0",
+  "extends": "package",
+  "variables": {
+    "type": "object",
+    "properties": {
+      "author": {"type": "string", "title": "作者", "default": "gva"},
+      "level": {"type": "integer", "minimum": 1}
+    },
+    "required": ["author", "level"]
+  },
+  "files": [{"template": "server/model.go.tpl", "target": "{{.Server}}/model/{{.Package}}/{{.Name}}.go"}]
+}`
+
+func TestLoad(t *testing.T) {
+	dir := writePack(t, manifest, map[string]string{"server/model.go.tpl": `// {{.Variables.author}} {{template "partial" .}}`})
+	pack, err := Load(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "audit", pack.Name)
+
+	variables, err := pack.Resolve(map[string]any{"level": 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"author": "gva", "level": float64(2)}, variables)
+	_, err = pack.Resolve(nil)
+	assert.Error(t, err) // level 必填
+	_, err = pack.Resolve(map[string]any{"level": 0})
+	assert.Error(t, err)
+	assert.Equal(t, map[string]any{"author": "gva", "level": float64(0)}, pack.Sample())
+
+	layout := map[string]string{"Server": "server", "Package": "demo", "Name": "book"}
+	roots := []string{filepath.Join("server", "model", "demo")}
+	targets, err := pack.Targets(template.FuncMap{}, layout, roots)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{filepath.Join(dir, "server/model.go.tpl"): filepath.Join("server", "model", "demo", "book.go")}, targets)
+
+	info := map[string]any{"Package": "demo", "Variables": pack.Sample()}
+	assert.NoError(t, pack.Validate(parse, template.FuncMap{}, info, layout, roots))
+	_, err = pack.Targets(template.FuncMap{}, map[string]string{"Server": "server"}, roots)
+	assert.Error(t, err) // 生成路径中的变量不存在
+	_, err = pack.Targets(template.FuncMap{}, map[string]string{"Server": "..", "Package": "..", "Name": "x"}, roots)
+	assert.Error(t, err) // 跳出项目目录
+	_, err = pack.Targets(template.FuncMap{}, map[string]string{"Server": "server", "Package": "system", "Name": "sys_user"}, roots)
+	assert.Error(t, err) // 不在模块目录内
+	_, err = pack.Targets(template.FuncMap{}, layout, []string{filepath.Join("server", "model", "demo", "book.go")})
+	assert.Error(t, err) // 生成路径不能是模块目录本身
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		files    map[string]string
+	}{
+		{name: "名称不合法", manifest: `{"name": "Audit", "version": "1.0.0", "extends": "package", "files": [{"template": "a.tpl", "target": "a"}]}`, files: map[string]string{"a.tpl": ""}},
+		{name: "名称与内置模板重复", manifest: `{"name": "plugin", "version": "1.0.0", "extends": "plugin", "files": [{"template": "a.tpl", "target": "a"}]}`, files: map[string]string{"a.tpl": ""}},
+		{name: "版本不合法", manifest: `{"name": "audit", "version": "latest", "extends": "package", "files": [{"template": "a.tpl", "target": "a"}]}`, files: map[string]string{"a.tpl": ""}},
+		{name: "继承不存在的模板", manifest: `{"name": "audit", "version": "1.0.0", "extends": "page", "files": [{"template": "a.tpl", "target": "a"}]}`, files: map[string]string{"a.tpl": ""}},
+		{name: "模板文件不存在", manifest: `{"name": "audit", "version": "1.0.0", "extends": "package", "files": [{"template": "a.tpl", "target": "a"}]}`},
+		{name: "模板文件在包外", manifest: `{"name": "audit", "version": "1.0.0", "extends": "package", "files": [{"template": "../a.tpl", "target": "a"}]}`},
+		{name: "变量顶层不是object", manifest: `{"name": "audit", "version": "1.0.0", "extends": "package", "variables": {"type": "string"}, "files": [{"template": "a.tpl", "target": "a"}]}`, files: map[string]string{"a.tpl": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writePack(t, tt.manifest, tt.files))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPack_Validate(t *testing.T) {
+	dir := writePack(t, `{"name": "audit", "version": "1.0.0", "extends": "plugin", "files": [{"template": "a.go.tpl", "target": "a.go"}]}`,
+		map[string]string{"a.go.tpl": "{{.Missing.Field}}"})
+	pack, err := Load(dir)
+	assert.NoError(t, err)
+	assert.Error(t, pack.Validate(parse, template.FuncMap{}, struct{ Package string }{}, nil, []string{"."}))
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, -1, Compare("1.2.0", "1.10.0"))
+	assert.Equal(t, 1, Compare("2.0.0", "1.99.99"))
+	assert.Equal(t, 0, Compare("1.0.0", "v1.0.0"))
+	assert.Equal(t, -1, Compare("1.0.0-beta", "1.0.0"))
+	assert.Equal(t, -1, Compare("1.0.0-alpha", "1.0.0-beta"))
+}
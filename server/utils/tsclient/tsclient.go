@@ -0,0 +1,457 @@
+// Package tsclient 根据swagger文档为接口生成带类型的TypeScript客户端
+// 只有在swagger文档中能找到请求定义的接口才会生成 其余接口作为跳过项返回
+package tsclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Route 需要生成客户端的接口 一般来源于 SysApi
+type Route struct {
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	Description string `json:"description"`
+	ApiGroup    string `json:"apiGroup"`
+}
+
+type document struct {
+	Paths       map[string]map[string]*operation `json:"paths"`
+	Definitions map[string]*schema               `json:"definitions"`
+}
+
+type operation struct {
+	Summary    string               `json:"summary"`
+	Consumes   []string             `json:"consumes"`
+	Parameters []*parameter         `json:"parameters"`
+	Responses  map[string]*response `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Type        string  `json:"type"`
+	Items       *schema `json:"items"`
+	Enum        []any   `json:"enum"`
+	Schema      *schema `json:"schema"`
+}
+
+type response struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Description          string             `json:"description"`
+	Items                *schema            `json:"items"`
+	AllOf                []*schema          `json:"allOf"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Required             []string           `json:"required"`
+	Enum                 []any              `json:"enum"`
+}
+
+const responseDefinition = "response.Response"
+
+var (
+	identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	pathParam  = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+	separator  = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	keywords   = map[string]bool{
+		"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+		"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+		"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+		"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+		"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+		"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+		"let": true, "static": true, "yield": true, "await": true, "interface": true, "package": true,
+		"private": true, "protected": true, "public": true, "implements": true,
+	}
+)
+
+type generator struct {
+	doc         document
+	definitions map[string]bool // 需要输出的definition
+	interfaces  []string        // 请求参数等内联接口
+	names       map[string]bool // 已使用的导出名称
+}
+
+// Generate 为routes生成TypeScript客户端 返回生成的代码和swagger中找不到定义的接口
+func Generate(swagger []byte, routes []Route) (code string, skipped []Route, err error) {
+	g := &generator{definitions: make(map[string]bool), names: make(map[string]bool)}
+	if err = json.Unmarshal(swagger, &g.doc); err != nil {
+		return "", nil, fmt.Errorf("解析swagger文档失败: %w", err)
+	}
+	routes = append([]Route(nil), routes...)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	var functions []string
+	for _, route := range routes {
+		op := g.operation(route)
+		if op == nil {
+			skipped = append(skipped, route)
+			continue
+		}
+		functions = append(functions, g.function(route, op))
+	}
+
+	var builder strings.Builder
+	builder.WriteString("// Code generated by gin-vue-admin. DO NOT EDIT.\n")
+	builder.WriteString("// 根据swagger文档生成 接口变更后请重新执行 swag init 并重新生成\n")
+	builder.WriteString("import service from '@/utils/request'\n")
+	builder.WriteString("import type { ApiResponse } from '@/api/types'\n")
+	for _, name := range g.sortedDefinitions() {
+		builder.WriteString("\n")
+		builder.WriteString(g.definition(name))
+	}
+	for _, i := range g.interfaces {
+		builder.WriteString("\n")
+		builder.WriteString(i)
+	}
+	for _, f := range functions {
+		builder.WriteString("\n")
+		builder.WriteString(f)
+	}
+	return builder.String(), skipped, nil
+}
+
+// operation 查找接口对应的swagger定义 SysApi中的:param对应swagger中的{param}
+func (g *generator) operation(route Route) *operation {
+	path := pathParam.ReplaceAllString(route.Path, "{$1}")
+	methods, ok := g.doc.Paths[path]
+	if !ok {
+		return nil
+	}
+	return methods[strings.ToLower(route.Method)]
+}
+
+func (g *generator) function(route Route, op *operation) string {
+	name := g.functionName(route.Path)
+	var args, options []string
+	var queries []*parameter
+	url := "'" + route.Path + "'"
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			arg := safeIdentifier(p.Name)
+			args = append(args, fmt.Sprintf("%s: %s", arg, g.parameterType(p)))
+			url = strings.ReplaceAll(url, ":"+p.Name, "${"+arg+"}")
+		case "query":
+			queries = append(queries, p)
+		}
+	}
+	if strings.Contains(url, "${") {
+		url = "`" + strings.Trim(url, "'") + "`"
+	}
+	for _, p := range op.Parameters {
+		if p.In == "body" {
+			args = append(args, "data: "+g.schemaType(p.Schema))
+			options = append(options, "data")
+			break
+		}
+	}
+	if hasFormData(op) {
+		args = append(args, "data: FormData")
+		options = append(options, "data")
+	}
+	if len(queries) > 0 {
+		params := upperFirst(name) + "Params"
+		g.interfaces = append(g.interfaces, g.queryInterface(params, queries))
+		optional := "?"
+		for _, q := range queries {
+			if q.Required {
+				optional = ""
+			}
+		}
+		args = append(args, fmt.Sprintf("params%s: %s", optional, params))
+		options = append(options, "params")
+	}
+
+	var builder strings.Builder
+	comment := route.Description
+	if comment == "" {
+		comment = op.Summary
+	}
+	if comment != "" {
+		builder.WriteString("// " + comment + "\n")
+	}
+	builder.WriteString(fmt.Sprintf("// @Router %s [%s]\n", route.Path, strings.ToLower(route.Method)))
+	builder.WriteString(fmt.Sprintf("export const %s = (%s): Promise<ApiResponse<%s>> => {\n", name, strings.Join(args, ", "), g.responseType(op)))
+	builder.WriteString("  return service({\n")
+	builder.WriteString(fmt.Sprintf("    url: %s,\n", url))
+	builder.WriteString(fmt.Sprintf("    method: '%s'", strings.ToLower(route.Method)))
+	for _, option := range options {
+		builder.WriteString(",\n    " + option)
+	}
+	builder.WriteString("\n  })\n}\n")
+	return builder.String()
+}
+
+// functionName 默认使用路径的最后一段 重名或为关键字时依次拼接前面的路径
+func (g *generator) functionName(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && !strings.HasPrefix(segment, ":") {
+			segments = append(segments, segment)
+		}
+	}
+	name := "request"
+	for i := len(segments) - 1; i >= 0; i-- {
+		name = camel(segments[i:])
+		if !g.names[name] && !keywords[name] && identifier.MatchString(name) {
+			break
+		}
+	}
+	for base, n := name, 2; g.names[name] || keywords[name] || !identifier.MatchString(name); n++ {
+		name = fmt.Sprintf("%s%d", safeIdentifier(base), n)
+	}
+	g.names[name] = true
+	return name
+}
+
+func (g *generator) queryInterface(name string, queries []*parameter) string {
+	var builder strings.Builder
+	builder.WriteString("export interface " + name + " {\n")
+	for _, q := range queries {
+		key := strings.TrimSuffix(q.Name, "[]")
+		optional := "?"
+		if q.Required {
+			optional = ""
+		}
+		builder.WriteString(fmt.Sprintf("  %s%s: %s", quoteProperty(key), optional, g.parameterType(q)))
+		if q.Description != "" {
+			builder.WriteString(" // " + oneLine(q.Description))
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func (g *generator) parameterType(p *parameter) string {
+	if p.Schema != nil {
+		return g.schemaType(p.Schema)
+	}
+	return g.schemaType(&schema{Type: p.Type, Items: p.Items, Enum: p.Enum})
+}
+
+// responseType swag 的 response.Response{data=X} 会生成 allOf 此时取出 data 的类型
+func (g *generator) responseType(op *operation) string {
+	res, ok := op.Responses["200"]
+	if !ok || res.Schema == nil {
+		return "any"
+	}
+	s := res.Schema
+	if len(s.AllOf) > 0 && s.AllOf[0].Ref == "#/definitions/"+responseDefinition {
+		for _, part := range s.AllOf[1:] {
+			if data, ok := part.Properties["data"]; ok {
+				return g.schemaType(data)
+			}
+		}
+		return "any"
+	}
+	if s.Ref == "#/definitions/"+responseDefinition {
+		return "any"
+	}
+	return g.schemaType(s)
+}
+
+func (g *generator) schemaType(s *schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/definitions/")
+		if _, ok := g.doc.Definitions[name]; !ok {
+			return "any"
+		}
+		g.use(name)
+		return typeName(name)
+	}
+	if len(s.AllOf) > 0 {
+		var parts []string
+		for _, part := range s.AllOf {
+			parts = append(parts, g.schemaType(part))
+		}
+		return strings.Join(parts, " & ")
+	}
+	if len(s.Enum) > 0 {
+		var values []string
+		for _, value := range s.Enum {
+			literal, _ := json.Marshal(value)
+			values = append(values, strings.ReplaceAll(string(literal), `"`, "'"))
+		}
+		return strings.Join(values, " | ")
+	}
+	switch s.Type {
+	case "integer", "number":
+		return "number"
+	case "string", "file":
+		return "string"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := g.schemaType(s.Items)
+		if strings.ContainsAny(item, " &|") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object", "":
+		if len(s.Properties) > 0 {
+			return g.objectType(s, "")
+		}
+		if value := s.additional(); value != nil {
+			return "Record<string, " + g.schemaType(value) + ">"
+		}
+		return "Record<string, any>"
+	}
+	return "any"
+}
+
+// additional additionalProperties 为 true 或 {} 时等价于any 返回nil
+func (s *schema) additional() *schema {
+	if len(s.AdditionalProperties) == 0 || string(s.AdditionalProperties) == "false" || string(s.AdditionalProperties) == "true" {
+		return nil
+	}
+	var value schema
+	if json.Unmarshal(s.AdditionalProperties, &value) != nil {
+		return nil
+	}
+	if value.Type == "" && value.Ref == "" && len(value.AllOf) == 0 && len(value.Properties) == 0 {
+		return nil
+	}
+	return &value
+}
+
+func (g *generator) objectType(s *schema, indent string) string {
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var builder strings.Builder
+	builder.WriteString("{\n")
+	for _, key := range keys {
+		property := s.Properties[key]
+		optional := "?"
+		if required[key] {
+			optional = ""
+		}
+		typ := g.schemaType(property)
+		builder.WriteString(fmt.Sprintf("%s  %s%s: %s", indent, quoteProperty(key), optional, strings.ReplaceAll(typ, "\n", "\n"+indent+"  ")))
+		if property.Description != "" {
+			builder.WriteString(" // " + oneLine(property.Description))
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString(indent + "}")
+	return builder.String()
+}
+
+func (g *generator) use(name string) {
+	if g.definitions[name] {
+		return
+	}
+	g.definitions[name] = true
+	g.schemaType(g.doc.Definitions[name]) // 递归收集引用的definition
+}
+
+func (g *generator) sortedDefinitions() []string {
+	names := make([]string, 0, len(g.definitions))
+	for name := range g.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (g *generator) definition(name string) string {
+	s := g.doc.Definitions[name]
+	if len(s.Properties) > 0 && (s.Type == "object" || s.Type == "") {
+		return fmt.Sprintf("export interface %s %s\n", typeName(name), g.objectType(s, ""))
+	}
+	return fmt.Sprintf("export type %s = %s\n", typeName(name), g.schemaType(&schema{
+		Type: s.Type, Items: s.Items, AllOf: s.AllOf, Enum: s.Enum, AdditionalProperties: s.AdditionalProperties,
+	}))
+}
+
+func hasFormData(op *operation) bool {
+	for _, p := range op.Parameters {
+		if p.In == "formData" {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName system.SysUser => SystemSysUser
+func typeName(definition string) string {
+	return upperFirst(camel(separator.Split(definition, -1)))
+}
+
+// camel 将路径片段拼接为小驼峰 片段内的 - _ 也视为分隔
+func camel(segments []string) string {
+	var words []string
+	for _, segment := range segments {
+		words = append(words, strings.FieldsFunc(segment, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+		})...)
+	}
+	var builder strings.Builder
+	for i, word := range words {
+		if i == 0 {
+			builder.WriteString(lowerFirst(word))
+			continue
+		}
+		builder.WriteString(upperFirst(word))
+	}
+	return builder.String()
+}
+
+func safeIdentifier(name string) string {
+	name = camel([]string{name})
+	if name == "" || !identifier.MatchString(name) || keywords[name] {
+		return "_" + name
+	}
+	return name
+}
+
+func quoteProperty(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return "'" + strings.ReplaceAll(name, "'", `\'`) + "'"
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
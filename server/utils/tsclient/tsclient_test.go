@@ -0,0 +1,75 @@
+package tsclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const swagger = `{
+  "paths": {
+    "/user/getUserList": {
+      "post": {
+        "summary": "分页获取用户列表",
+        "parameters": [{"name": "data", "in": "body", "required": true, "schema": {"$ref": "#/definitions/request.PageInfo"}}],
+        "responses": {"200": {"schema": {"allOf": [
+          {"$ref": "#/definitions/response.Response"},
+          {"type": "object", "properties": {"data": {"$ref": "#/definitions/response.PageResult"}, "msg": {"type": "string"}}}
+        ]}}}
+      }
+    },
+    "/user/{id}": {
+      "delete": {
+        "summary": "删除用户",
+        "parameters": [{"name": "id", "in": "path", "required": true, "type": "integer"}],
+        "responses": {"200": {"schema": {"$ref": "#/definitions/response.Response"}}}
+      }
+    },
+    "/api/delete": {
+      "get": {
+        "parameters": [
+          {"name": "ids[]", "in": "query", "type": "array", "items": {"type": "integer"}, "required": true},
+          {"name": "status", "in": "query", "type": "string", "enum": ["on", "off"]}
+        ],
+        "responses": {"200": {"schema": {"allOf": [
+          {"$ref": "#/definitions/response.Response"},
+          {"type": "object", "properties": {"data": {"type": "object", "additionalProperties": true}}}
+        ]}}}
+      }
+    }
+  },
+  "definitions": {
+    "request.PageInfo": {"type": "object", "properties": {"page": {"type": "integer", "description": "页码"}, "keyword": {"type": "string"}}},
+    "response.PageResult": {"type": "object", "properties": {"list": {"type": "array", "items": {"$ref": "#/definitions/system.SysUser"}}, "total": {"type": "integer"}}},
+    "response.Response": {"type": "object", "properties": {"code": {"type": "integer"}}},
+    "system.SysUser": {"type": "object", "required": ["userName"], "properties": {"userName": {"type": "string"}, "authorities": {"type": "array", "items": {"$ref": "#/definitions/system.SysUser"}}}}
+  }
+}`
+
+func TestGenerate(t *testing.T) {
+	code, skipped, err := Generate([]byte(swagger), []Route{
+		{Path: "/user/getUserList", Method: "POST", Description: "分页获取用户列表"},
+		{Path: "/user/:id", Method: "DELETE"},
+		{Path: "/api/delete", Method: "GET", Description: "删除api"},
+		{Path: "/user/unknown", Method: "GET"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Route{{Path: "/user/unknown", Method: "GET"}}, skipped)
+
+	// 只输出被引用的definition 自引用不会死循环
+	assert.Contains(t, code, "export interface SystemSysUser {\n  authorities?: SystemSysUser[]\n  userName: string\n}\n")
+	assert.Contains(t, code, "export interface RequestPageInfo {\n  keyword?: string\n  page?: number // 页码\n}\n")
+	assert.NotContains(t, code, "interface ResponseResponse")
+
+	// body参数与 response.Response{data=X} 的响应
+	assert.Contains(t, code, "export const getUserList = (data: RequestPageInfo): Promise<ApiResponse<ResponsePageResult>> => {\n  return service({\n    url: '/user/getUserList',\n    method: 'post',\n    data\n  })\n}\n")
+	// 路径参数 函数名重名或为关键字时拼接前面的路径
+	assert.Contains(t, code, "export const user = (id: number): Promise<ApiResponse<any>> => {\n  return service({\n    url: `/user/${id}`,\n    method: 'delete'\n  })\n}\n")
+	assert.Contains(t, code, "export const apiDelete = (params: ApiDeleteParams): Promise<ApiResponse<Record<string, any>>> => {")
+	assert.Contains(t, code, "export interface ApiDeleteParams {\n  ids: number[]\n  status?: 'on' | 'off'\n}\n")
+}
+
+func TestGenerate_InvalidDocument(t *testing.T) {
+	_, _, err := Generate([]byte("{"), nil)
+	assert.Error(t, err)
+}
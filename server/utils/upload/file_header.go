@@ -0,0 +1,27 @@
+package upload
+
+import (
+	"bytes"
+	"mime/multipart"
+)
+
+// NewFileHeader 将内存中的数据包装为 multipart.FileHeader 便于服务端生成的文件复用 OSS.UploadFile
+func NewFileHeader(filename string, data []byte) (*multipart.FileHeader, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = part.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(int64(len(data)) + 1024)
+	if err != nil {
+		return nil, err
+	}
+	return form.File["file"][0], nil
+}
@@ -0,0 +1,313 @@
+package versioning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	beforeKey = "gva:version_before"
+	// ActionKey 通过 db.Set(ActionKey, ActionRestore) 覆盖记录的变更类型
+	ActionKey = "gva:version_action"
+)
+
+// Store 持久化变更 tx 与业务语句处于同一事务
+type Store func(tx *gorm.DB, changes []Change) error
+
+// Plugin 为注册过的模型在创建、更新、删除时记录前后快照
+// 更新和删除前按语句条件加载受影响的行 只有通过 gorm 的 Create/Update/Save/Delete 写入才会被记录 原生 SQL 不在此列
+type Plugin struct {
+	Store Store
+
+	mu     sync.RWMutex
+	models map[string]*schema.Schema
+	cache  sync.Map
+	namer  schema.Namer
+}
+
+func (p *Plugin) Name() string {
+	return "gva:versioning"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	p.mu.Lock()
+	p.namer = db.NamingStrategy
+	p.mu.Unlock()
+	cb := db.Callback()
+	if err := cb.Update().Before("gorm:update").Register("gva:version_before_update", p.before); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("gva:version_before_delete", p.before); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("gva:version_after_create", p.after(ActionCreate)); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("gva:version_after_update", p.after(ActionUpdate)); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("gva:version_after_delete", p.after(ActionDelete))
+}
+
+// Register 为模型开启版本记录 模型必须有主键
+func (p *Plugin) Register(models ...any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.models == nil {
+		p.models = map[string]*schema.Schema{}
+	}
+	namer := p.namer
+	if namer == nil {
+		namer = schema.NamingStrategy{}
+	}
+	for _, model := range models {
+		s, err := schema.Parse(model, &p.cache, namer)
+		if err != nil {
+			return err
+		}
+		if s.PrioritizedPrimaryField == nil {
+			return fmt.Errorf("%s 没有主键 无法记录版本", s.Table)
+		}
+		p.models[s.Table] = s
+	}
+	return nil
+}
+
+// Tables 已开启版本记录的表
+func (p *Plugin) Tables() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	tables := make([]string, 0, len(p.models))
+	for table := range p.models {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+func (p *Plugin) schema(table string) (*schema.Schema, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	s, ok := p.models[table]
+	return s, ok
+}
+
+// Restore 将快照写回对应的表 软删除的行会被恢复 该写入本身会记录为 restore 版本
+func (p *Plugin) Restore(db *gorm.DB, table string, snapshot json.RawMessage) error {
+	s, ok := p.schema(table)
+	if !ok {
+		return fmt.Errorf("%s 未开启版本记录", table)
+	}
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(snapshot, &present); err != nil || len(present) == 0 {
+		return errors.New("快照为空 无法恢复")
+	}
+	value := reflect.New(s.ModelType)
+	if err := json.Unmarshal(snapshot, value.Interface()); err != nil {
+		return err
+	}
+	if _, isZero := s.PrioritizedPrimaryField.ValueOf(db.Statement.Context, value.Elem()); isZero {
+		return errors.New("快照缺少主键")
+	}
+	// 只写回快照中存在的字段 避免覆盖 json:"-" 等未进入快照的列
+	var columns []string
+	for _, field := range s.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		if _, ok := present[jsonName(field)]; ok || isSoftDelete(field) {
+			columns = append(columns, field.DBName)
+		}
+	}
+	return db.Set(ActionKey, ActionRestore).Unscoped().Table(table).Select(columns).Omit(clause.Associations).Save(value.Interface()).Error
+}
+
+func (p *Plugin) before(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	s, ok := p.schema(db.Statement.Table)
+	if !ok {
+		return
+	}
+	snapshots, err := p.load(db, s, conditions(db, s))
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	db.InstanceSet(beforeKey, snapshots)
+}
+
+func (p *Plugin) after(action string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Schema == nil || p.Store == nil {
+			return
+		}
+		s, ok := p.schema(db.Statement.Table)
+		if !ok {
+			return
+		}
+		if override, ok := db.Get(ActionKey); ok {
+			action, _ = override.(string)
+		}
+		if db.Statement.ReflectValue.Kind() == reflect.Map {
+			return
+		}
+		var before, after rowSet
+		v, loaded := db.InstanceGet(beforeKey)
+		if loaded {
+			before = v.(rowSet)
+		}
+		var err error
+		switch {
+		case !loaded:
+			// 新建 直接取写入后的模型
+			after, err = p.snapshots(db, s, reflect.Indirect(db.Statement.ReflectValue))
+		case action != ActionDelete && len(before.ids) > 0:
+			after, err = p.load(db, s, []clause.Expression{clause.IN{
+				Column: clause.Column{Table: clause.CurrentTable, Name: s.PrioritizedPrimaryField.DBName},
+				Values: before.ids,
+			}})
+		}
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+		actor := ActorFrom(db.Statement.Context)
+		ids := make(map[string]struct{}, len(before.rows)+len(after.rows))
+		for id := range before.rows {
+			ids[id] = struct{}{}
+		}
+		for id := range after.rows {
+			ids[id] = struct{}{}
+		}
+		changes := make([]Change, 0, len(ids))
+		for id := range ids {
+			change := Change{Table: s.Table, EntityID: id, Action: action, Before: before.rows[id], After: after.rows[id], Actor: actor}
+			change.Fields = ChangedFields(change.Before, change.After)
+			if len(change.Fields) == 0 {
+				continue
+			}
+			changes = append(changes, change)
+		}
+		if len(changes) == 0 {
+			return
+		}
+		sort.Slice(changes, func(i, j int) bool { return changes[i].EntityID < changes[j].EntityID })
+		if err = p.Store(db.Session(&gorm.Session{NewDB: true}), changes); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+}
+
+// conditions 复制语句的 WHERE 条件 并补上模型主键条件
+func conditions(db *gorm.DB, s *schema.Schema) (exprs []clause.Expression) {
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			exprs = append(exprs, where.Exprs...)
+		}
+	}
+	var ids []any
+	pk := s.PrioritizedPrimaryField
+	collect := func(rv reflect.Value) {
+		if rv.Kind() != reflect.Struct || rv.Type() != s.ModelType {
+			return
+		}
+		if v, isZero := pk.ValueOf(db.Statement.Context, rv); !isZero {
+			ids = append(ids, v)
+		}
+	}
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			collect(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		collect(rv)
+	}
+	if len(ids) > 0 {
+		exprs = append(exprs, clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: ids})
+	}
+	return exprs
+}
+
+// rowSet 一组行快照 键为主键的字符串形式
+type rowSet struct {
+	ids  []any
+	rows map[string]json.RawMessage
+}
+
+// load 按条件加载当前行快照 没有任何条件时不加载(gorm 默认也会拒绝全表更新/删除)
+func (p *Plugin) load(db *gorm.DB, s *schema.Schema, exprs []clause.Expression) (rowSet, error) {
+	if len(exprs) == 0 {
+		return rowSet{}, nil
+	}
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(db.Statement.Table)
+	if db.Statement.Unscoped {
+		tx = tx.Unscoped()
+	}
+	rows := reflect.New(reflect.SliceOf(s.ModelType))
+	if err := tx.Clauses(clause.Where{Exprs: exprs}).Find(rows.Interface()).Error; err != nil {
+		return rowSet{}, err
+	}
+	return p.snapshots(db, s, rows.Elem())
+}
+
+func (p *Plugin) snapshots(db *gorm.DB, s *schema.Schema, rv reflect.Value) (rowSet, error) {
+	result := rowSet{rows: map[string]json.RawMessage{}}
+	add := func(item reflect.Value) error {
+		if item.Kind() != reflect.Struct || item.Type() != s.ModelType {
+			return nil
+		}
+		id, isZero := s.PrioritizedPrimaryField.ValueOf(db.Statement.Context, item)
+		if isZero {
+			return nil
+		}
+		raw, err := json.Marshal(item.Addr().Interface())
+		if err != nil {
+			return err
+		}
+		result.ids = append(result.ids, id)
+		result.rows[fmt.Sprint(id)] = raw
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			item := reflect.Indirect(rv.Index(i))
+			if !item.CanAddr() {
+				continue
+			}
+			if err := add(item); err != nil {
+				return rowSet{}, err
+			}
+		}
+	case reflect.Struct:
+		if rv.CanAddr() {
+			return result, add(rv)
+		}
+	}
+	return result, nil
+}
+
+func jsonName(field *schema.Field) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func isSoftDelete(field *schema.Field) bool {
+	return field.FieldType == reflect.TypeOf(gorm.DeletedAt{})
+}
@@ -0,0 +1,118 @@
+package versioning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// Actor 变更的操作人 由中间件写入请求上下文 通过 db.WithContext(ctx) 传递给回调
+type Actor struct {
+	UserID    uint
+	Username  string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor 将操作人写入上下文
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom 从上下文读取操作人
+func ActorFrom(ctx context.Context) Actor {
+	if ctx == nil {
+		return Actor{}
+	}
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// Change 单个实体的一次变更 Before/After 为模型的 JSON 快照 新建时 Before 为空 删除时 After 为空
+type Change struct {
+	Table    string
+	EntityID string
+	Action   string
+	Before   json.RawMessage
+	After    json.RawMessage
+	Fields   []string // 发生变化的字段
+	Actor    Actor
+}
+
+// FieldChange 字段级差异
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// Diff 比较两个 JSON 对象快照的顶层字段 返回按字段名排序的差异
+func Diff(from, to json.RawMessage) ([]FieldChange, error) {
+	a, err := decodeObject(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := decodeObject(to)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	changes := make([]FieldChange, 0)
+	for k := range keys {
+		if !jsonEqual(a[k], b[k]) {
+			changes = append(changes, FieldChange{Field: k, From: a[k], To: b[k]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// ChangedFields 返回两个快照间发生变化的字段名
+func ChangedFields(from, to json.RawMessage) []string {
+	changes, err := Diff(from, to)
+	if err != nil {
+		return nil
+	}
+	fields := make([]string, 0, len(changes))
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	return fields
+}
+
+func decodeObject(raw json.RawMessage) (map[string]json.RawMessage, error) {
+	obj := map[string]json.RawMessage{}
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return obj, nil
+	}
+	err := json.Unmarshal(raw, &obj)
+	return obj, err
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return bytes.Equal(a, b)
+	}
+	ca, _ := json.Marshal(x)
+	cb, _ := json.Marshal(y)
+	return bytes.Equal(ca, cb)
+}
@@ -0,0 +1,102 @@
+package versioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type versionedRow struct {
+	ID        uint `gorm:"primarykey"`
+	Name      string
+	Secret    string `json:"-"`
+	DeletedAt gorm.DeletedAt
+}
+
+type plainRow struct {
+	ID   uint `gorm:"primarykey"`
+	Name string
+}
+
+func newTestDB(t *testing.T) (*gorm.DB, *Plugin, *[]Change) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	var stored []Change
+	plugin := &Plugin{Store: func(tx *gorm.DB, changes []Change) error {
+		stored = append(stored, changes...)
+		return nil
+	}}
+	assert.Nil(t, db.Use(plugin))
+	assert.Nil(t, plugin.Register(&versionedRow{}))
+	assert.Nil(t, db.AutoMigrate(&versionedRow{}, &plainRow{}))
+	return db, plugin, &stored
+}
+
+func TestPlugin_RecordsChanges(t *testing.T) {
+	db, _, stored := newTestDB(t)
+	ctx := WithActor(context.Background(), Actor{UserID: 1, Username: "admin", RequestID: "req-1"})
+	tx := db.WithContext(ctx)
+
+	row := versionedRow{Name: "a", Secret: "s"}
+	assert.Nil(t, tx.Create(&row).Error)
+	assert.Nil(t, tx.Model(&row).Update("name", "b").Error)
+	assert.Nil(t, tx.Model(&versionedRow{}).Where("name = ?", "b").Updates(map[string]any{"name": "c"}).Error)
+	assert.Nil(t, tx.Model(&row).Update("name", "c").Error) // 未变化 不记录
+	assert.Nil(t, tx.Delete(&versionedRow{}, row.ID).Error)
+	assert.Nil(t, tx.Create(&plainRow{Name: "x"}).Error) // 未注册 不记录
+
+	if !assert.Len(t, *stored, 4) {
+		return
+	}
+	actions := []string{ActionCreate, ActionUpdate, ActionUpdate, ActionDelete}
+	for i, c := range *stored {
+		assert.Equal(t, actions[i], c.Action)
+		assert.Equal(t, "1", c.EntityID)
+		assert.Equal(t, "req-1", c.Actor.RequestID)
+		assert.NotContains(t, string(c.Before)+string(c.After), `"s"`)
+	}
+	assert.Nil(t, (*stored)[0].Before)
+	assert.JSONEq(t, `{"ID":1,"Name":"b","DeletedAt":null}`, string((*stored)[1].After))
+	assert.Equal(t, []string{"Name"}, (*stored)[2].Fields)
+	assert.Nil(t, (*stored)[3].After)
+}
+
+func TestPlugin_Restore(t *testing.T) {
+	db, plugin, stored := newTestDB(t)
+	row := versionedRow{Name: "a", Secret: "s"}
+	assert.Nil(t, db.Create(&row).Error)
+	assert.Nil(t, db.Model(&row).Update("name", "b").Error)
+	assert.Nil(t, db.Delete(&row).Error)
+
+	// 恢复到创建时的状态 同时撤销软删除 未进入快照的列保持不变
+	assert.Nil(t, plugin.Restore(db, "versioned_rows", (*stored)[0].After))
+	var got versionedRow
+	assert.Nil(t, db.First(&got, row.ID).Error)
+	assert.Equal(t, "a", got.Name)
+	assert.Equal(t, "s", got.Secret)
+
+	last := (*stored)[len(*stored)-1]
+	assert.Equal(t, ActionRestore, last.Action)
+	assert.ElementsMatch(t, []string{"DeletedAt", "Name"}, last.Fields)
+
+	assert.NotNil(t, plugin.Restore(db, "plain_rows", json.RawMessage(`{"ID":1}`)))
+}
+
+func TestDiff(t *testing.T) {
+	changes, err := Diff(json.RawMessage(`{"a":1,"b":{"x":1,"y":2},"c":"z"}`), json.RawMessage(`{"a":1,"b":{"y":2,"x":1},"d":true}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []FieldChange{
+		{Field: "c", From: json.RawMessage(`"z"`)},
+		{Field: "d", To: json.RawMessage(`true`)},
+	}, changes)
+
+	changes, err = Diff(nil, json.RawMessage(`{"a":1}`))
+	assert.Nil(t, err)
+	assert.Len(t, changes, 1)
+}
@@ -0,0 +1,45 @@
+import service from '@/utils/request'
+// @Tags AccessToken
+// @Summary 创建个人访问令牌
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body request.CreateAccessToken true "令牌名称, 每分钟请求上限, 过期时间"
+// @Success 200 {string} string "{"success":true,"data":{},"msg":"创建成功"}"
+// @Router /accessToken/createAccessToken [post]
+export const createAccessToken = (data) => {
+  return service({
+    url: '/accessToken/createAccessToken',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags AccessToken
+// @Summary 获取当前用户的个人访问令牌
+// @Security ApiKeyAuth
+// @Produce application/json
+// @Success 200 {string} string "{"success":true,"data":{},"msg":"获取成功"}"
+// @Router /accessToken/getAccessTokenList [get]
+export const getAccessTokenList = () => {
+  return service({
+    url: '/accessToken/getAccessTokenList',
+    method: 'get'
+  })
+}
+
+// @Tags AccessToken
+// @Summary 吊销个人访问令牌
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body request.GetById true "令牌ID"
+// @Success 200 {string} string "{"success":true,"data":{},"msg":"吊销成功"}"
+// @Router /accessToken/deleteAccessToken [delete]
+export const deleteAccessToken = (data) => {
+  return service({
+    url: '/accessToken/deleteAccessToken',
+    method: 'delete',
+    data
+  })
+}
@@ -61,6 +61,35 @@ export const getColumn = (params) => {
   })
 }
 
+// @Tags SysApi
+// @Summary 根据外键推断所选table的关联关系
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Success 200 {string} string "{"success":true,"data":{},"msg":"获取成功"}"
+// @Router /autoCode/getRelations [get]
+export const getRelations = (params) => {
+  return service({
+    url: '/autoCode/getRelations',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags AutoCode
+// @Summary 根据swagger文档为全部已注册的api生成TypeScript客户端
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Success 200 {string} string "{"success":true,"data":{},"msg":"生成成功"}"
+// @Router /autoCode/generateTypeScript [post]
+export const generateTypeScript = () => {
+  return service({
+    url: '/autoCode/generateTypeScript',
+    method: 'post'
+  })
+}
+
 export const getSysHistory = (data) => {
   return service({
     url: '/autoCode/getSysHistory',
@@ -125,17 +154,41 @@ export const getTemplatesApi = () => {
 
 export const installPlug = (data) => {
   return service({
-    url: '/autoCode/installPlug',
+    url: '/autoCode/installPlugin',
     method: 'post',
+    headers: { 'Content-Type': 'multipart/form-data' },
     data
   })
 }
 
-export const pubPlug = (params) => {
+export const uninstallPlug = (data) => {
+  return service({
+    url: '/autoCode/uninstallPlugin',
+    method: 'post',
+    data
+  })
+}
+
+export const getPluginList = () => {
+  return service({
+    url: '/autoCode/getPluginList',
+    method: 'get'
+  })
+}
+
+export const setPluginState = (data) => {
+  return service({
+    url: '/autoCode/setPluginState',
+    method: 'post',
+    data
+  })
+}
+
+export const pubPlug = (data) => {
   return service({
     url: '/autoCode/pubPlug',
     method: 'post',
-    params
+    data
   })
 }
 
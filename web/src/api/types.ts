@@ -0,0 +1,21 @@
+// 服务端统一响应 model/common/response.Response
+export interface ApiResponse<T = any> {
+  code: number
+  data: T
+  msg: string
+}
+
+// 分页请求 model/common/request.PageInfo
+export interface PageInfo {
+  page?: number
+  pageSize?: number
+  keyword?: string
+}
+
+// 分页结果 model/common/response.PageResult
+export interface PageResult<T = any> {
+  list: T[]
+  total: number
+  page: number
+  pageSize: number
+}